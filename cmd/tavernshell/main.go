@@ -1,24 +1,119 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 
+	"github.com/angusmclean/tavernshell/core/config"
 	"github.com/angusmclean/tavernshell/core/dice"
 	"github.com/angusmclean/tavernshell/tui"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
+// stringList is a flag that can be given more than once
+type stringList []string
+
+func (s *stringList) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringList) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
+// options holds state to pre-load into the interactive TUI
+type options struct {
+	encounter  string
+	tables     stringList
+	historyLog string
+	campaign   string
+	macroFile  string
+	party      string
+	checklists string
+	presets    string
+	session    string
+	ascii      bool
+	d10s       bool
+	verbosity  string
+	title      bool
+	noRecap    bool
+	statusFile string
+	serve      string
+	disable    string
+	limits     config.Limits
+}
+
 func main() {
+	var opts options
+	limits, err := config.LimitsFromEnv(os.Getenv)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		os.Exit(1)
+	}
+	opts.limits = limits
+	roller := flag.String("roller", os.Getenv("TAVERNSHELL_ROLLER"), "dice entropy source (crypto, seeded:<n> or sequence:<n>,<n>,...)")
+	seed := flag.String("seed", "", "roll from a seeded sequence, so a demo or bug report can be replayed (same as --roller seeded:<n>)")
+	strict := flag.Bool("strict", false, "cap the size of rolls and simulations, for notation from someone else (e.g. a chat bot running tavernshell)")
+	flag.StringVar(&opts.encounter, "encounter", "", "start initiative from an encounter JSON file")
+	flag.Var(&opts.tables, "table", "load a random table file (repeatable)")
+	flag.StringVar(&opts.historyLog, "history-log", os.Getenv("TAVERNSHELL_HISTORY_LOG"), "write history that scrolls out of memory to this file")
+	flag.StringVar(&opts.campaign, "campaign", os.Getenv("TAVERNSHELL_CAMPAIGN"), "keep the campaign record (initiative history) in this JSON file")
+	flag.StringVar(&opts.macroFile, "macro-file", os.Getenv("TAVERNSHELL_MACROS"), "load macros from, and save new ones to, this JSON file")
+	flag.StringVar(&opts.party, "party", os.Getenv("TAVERNSHELL_PARTY"), "import player characters from a JSON character sheet file")
+	flag.StringVar(&opts.checklists, "checklists", os.Getenv("TAVERNSHELL_CHECKLISTS"), "load checklists from this JSON file")
+	flag.StringVar(&opts.presets, "presets", os.Getenv("TAVERNSHELL_PRESETS"), "load spell and ability presets for 'cast' from this JSON file")
+	flag.StringVar(&opts.session, "session", os.Getenv("TAVERNSHELL_SESSION"), "save trackers and initiative to this JSON file, offering to resume them on the next start")
+	flag.BoolVar(&opts.ascii, "ascii", false, "draw plain ASCII symbols instead of Unicode and emoji")
+	flag.BoolVar(&opts.d10s, "percentile-d10s", false, "show d100 rolls as the tens and ones d10s too")
+	flag.StringVar(&opts.verbosity, "verbosity", os.Getenv("TAVERNSHELL_VERBOSITY"), "how much of each roll to show: terse, normal or verbose")
+	flag.BoolVar(&opts.title, "title", false, "show the round and next alarm in the terminal title")
+	flag.BoolVar(&opts.noRecap, "no-recap", false, "don't start each initiative round with a one-line recap")
+	flag.StringVar(&opts.statusFile, "status-file", os.Getenv("TAVERNSHELL_STATUS_FILE"), "keep the round and next alarm in this file (e.g. for a tmux status bar)")
+	flag.StringVar(&opts.serve, "serve", "", "serve a read-only web view of initiative, trackers and rolls for players at this address (e.g. :8080)")
+	flag.StringVar(&opts.disable, "disable", os.Getenv("TAVERNSHELL_DISABLE"), "turn off subsystems: timers, trackers, initiative, network, or all for a dice-only shell (comma-separated)")
+	flag.IntVar(&opts.limits.HistoryLines, "history-lines", opts.limits.HistoryLines, "output lines (and commands) kept in memory for scrollback")
+	flag.IntVar(&opts.limits.RollLogSize, "roll-log-size", opts.limits.RollLogSize, "rolls kept in the roll log")
+	flag.IntVar(&opts.limits.HistoryLogRotate, "history-log-rotate", opts.limits.HistoryLogRotate, "rotate the history log after this many lines (0 = never)")
+	flag.Usage = printHelp
+	flag.Parse()
+
+	// Select the dice roller (crypto/rand unless configured otherwise)
+	if *seed != "" {
+		*roller = "seeded:" + *seed
+	}
+	if *roller != "" {
+		r, err := dice.ParseRoller(*roller)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			os.Exit(1)
+		}
+		dice.SetRoller(r)
+	}
+
 	// If arguments provided, run in single-command mode
-	if len(os.Args) > 1 {
-		runSingleCommand(os.Args[1:])
+	if *strict {
+		rollLimits = dice.StrictLimits
+	}
+	if flag.NArg() > 0 {
+		runSingleCommand(flag.Args())
 		return
 	}
 
 	// Otherwise, launch interactive TUI
-	runInteractive()
+	runInteractive(opts)
+}
+
+// rollLimits caps the rolls single commands make (none unless --strict)
+var rollLimits dice.Limits
+
+// parseNotation parses notation for a single command, within rollLimits
+func parseNotation(notation string) (*dice.Expression, error) {
+	return dice.ParseLimited(notation, rollLimits)
 }
 
 // runSingleCommand executes a single command and exits
@@ -37,33 +132,108 @@ func runSingleCommand(args []string) {
 			fmt.Println("Examples: tavernshell roll 2d6+3, tavernshell roll d20!, tavernshell roll 4d6kh3")
 			os.Exit(1)
 		}
+		runRoll(strings.Join(args[1:], " "))
 
-		notation := args[1]
+	case strings.HasPrefix("stats", cmd) && len(cmd) >= 3:
+		if len(args) < 2 {
+			fmt.Println("Usage: tavernshell stats <dice>")
+			os.Exit(1)
+		}
+		expr, err := parseNotation(strings.Join(args[1:], " "))
+		if err != nil {
+			printParseError(err)
+			os.Exit(1)
+		}
+		stats, err := dice.Analyze(expr)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("📊 %s\n", stats.Summary())
+		for _, line := range stats.Chart(20, 40) {
+			fmt.Println("  " + line)
+		}
 
-		// Parse the expression
-		expr, err := dice.Parse(notation)
+	case cmd == "avg" || cmd == "min" || cmd == "max":
+		if len(args) < 2 {
+			fmt.Printf("Usage: tavernshell %s <dice>\n", cmd)
+			os.Exit(1)
+		}
+		expr, err := parseNotation(strings.Join(args[1:], " "))
+		if err != nil {
+			printParseError(err)
+			os.Exit(1)
+		}
+		stats, err := dice.Analyze(expr)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
 			os.Exit(1)
 		}
+		switch cmd {
+		case "avg":
+			fmt.Println(stats.Average())
+		case "min":
+			fmt.Println(stats.Min)
+		case "max":
+			fmt.Println(stats.Max)
+		}
 
-		// Roll the dice
-		result, err := dice.RollExpression(expr)
+	case cmd == "sim" || cmd == "simulate":
+		if len(args) < 2 {
+			fmt.Println("Usage: tavernshell sim [count] <dice> [vs <target>]")
+			os.Exit(1)
+		}
+		runSim(args[1:])
+
+	case cmd == "choose" || cmd == "pick":
+		options := dice.SplitChoices(strings.Join(args[1:], " "))
+		if len(options) < 2 {
+			fmt.Println("Usage: tavernshell choose <option> <option>... (e.g., goblin orc kobold)")
+			os.Exit(1)
+		}
+		choice, err := dice.Choose(options)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
 			os.Exit(1)
 		}
+		fmt.Println(choice)
 
-		fmt.Printf("🎲 %s\n", result.String())
+	case cmd == "flip":
+		side, err := dice.Flip()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(side)
+
+	case cmd == "gurps":
+		if len(args) < 2 {
+			fmt.Println("Usage: tavernshell gurps <skill> (e.g., 14 or 12-2)")
+			os.Exit(1)
+		}
+		runGurps(strings.Join(args[1:], " "))
 
 	case strings.HasPrefix("help", cmd) || strings.HasPrefix("h", cmd):
 		printHelp()
 
 	default:
+		// "tavernshell d20+7 vs 15" is a roll against a target number
+		if notation, _, check, _ := dice.SplitCheck(strings.Join(args, " ")); check {
+			if _, err := parseNotation(notation); err == nil {
+				runRoll(strings.Join(args, " "))
+				return
+			}
+		}
+
 		// Try to parse the first argument as a dice roll
 		notation := args[0]
-		expr, err := dice.Parse(notation)
+		expr, err := parseNotation(notation)
 		if err != nil {
+			var limitErr *dice.LimitError
+			if errors.As(err, &limitErr) {
+				fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+				os.Exit(1)
+			}
 			// Not a valid dice roll, show unknown command error
 			fmt.Fprintf(os.Stderr, "Unknown command: %s\n", cmd)
 			fmt.Fprintln(os.Stderr, "Run 'tavernshell help' for usage information")
@@ -77,18 +247,280 @@ func runSingleCommand(args []string) {
 			os.Exit(1)
 		}
 
-		fmt.Printf("🎲 %s\n", result.String())
+		printResult(result)
 	}
 }
 
-// runInteractive starts the interactive TUI
-func runInteractive() {
+// exitCheckFailed is the exit status when a roll misses its target number,
+// so scripts can branch on "tavernshell r d20+5 vs 15"
+const exitCheckFailed = 2
+
+// runRoll rolls notation for the roll command: repeated ("6x4d6kh3") and
+// against a target number ("d20+7 vs 15"), exiting with exitCheckFailed if
+// any roll misses its target
+func runRoll(notation string) {
+	// "2d6+3 # greatsword damage" labels the roll
+	notation, label := dice.SplitLabel(notation)
+
+	// "d20+7 vs 15" rolls against a target number
+	notation, dc, check, err := dice.SplitCheck(notation)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		os.Exit(1)
+	}
+
+	// "6x4d6kh3" rolls the same dice several times
+	count, notation, err := dice.SplitRepeat(notation)
+	if err == nil {
+		err = rollLimits.CheckRepeat(count)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		os.Exit(1)
+	}
+
+	// Parse the expression
+	expr, err := parseNotation(notation)
+	if err != nil {
+		printParseError(err)
+		os.Exit(1)
+	}
+	expr.Label = label
+
+	// Roll the dice
+	total, successes := 0, 0
+	for i := 0; i < count; i++ {
+		result, err := dice.RollExpression(expr)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			os.Exit(1)
+		}
+		printResult(result)
+		total += result.Total
+		if check {
+			c := dice.Check{Result: result, Target: dc}
+			fmt.Println(c.String())
+			if c.Success() {
+				successes++
+			}
+		}
+	}
+	if count > 1 {
+		fmt.Printf("Total: %d\n", total)
+		if check {
+			fmt.Printf("Succeeded: %d of %d\n", successes, count)
+		}
+	}
+	if check && successes < count {
+		os.Exit(exitCheckFailed)
+	}
+}
+
+// runSim rolls dice many times and prints the success rate and spread
+func runSim(args []string) {
+	count, counted := dice.DefaultSimulations, false
+	if n, err := strconv.Atoi(strings.ReplaceAll(args[0], ",", "")); err == nil && len(args) > 1 {
+		count, counted = n, true
+		args = args[1:]
+	}
+	notation, target, check, err := dice.SplitCheck(strings.Join(args, " "))
+	if err == nil {
+		err = rollLimits.CheckSimulations(count)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		os.Exit(1)
+	}
+	expr, err := parseNotation(notation)
+	if err != nil {
+		printParseError(err)
+		os.Exit(1)
+	}
+	if !counted {
+		// Fewer rolls by default for a roll of many dice, to keep it quick
+		count = min(count, dice.MaxRolls(expr))
+	}
+	sim, err := dice.Simulate(expr, count, dice.SimulationRoller(), target, check)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("📊 %s\n", sim.Summary())
+	for _, line := range sim.Chart(20, 40) {
+		fmt.Println("  " + line)
+	}
+}
+
+// runGurps rolls 3d6 under a skill, exiting with exitCheckFailed if it fails
+func runGurps(input string) {
+	input, label := dice.SplitLabel(input)
+	skill, err := dice.ParseSkill(input)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		os.Exit(1)
+	}
+	roll, err := dice.RollAgainst(skill)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		os.Exit(1)
+	}
+	roll.Result.Label = label
+	fmt.Printf("🎲 %s\n", roll.Result.String())
+	fmt.Println(roll.String())
+	fmt.Println(dice.RollUnderThresholds(skill))
+	if roll.Degree() < dice.Success {
+		os.Exit(exitCheckFailed)
+	}
+}
+
+// printParseError prints an error reading dice notation, with a caret under
+// the mistake when the parser knows where it is
+func printParseError(err error) {
+	fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+	var parseErr *dice.ParseError
+	if errors.As(err, &parseErr) {
+		for _, line := range parseErr.Caret() {
+			fmt.Fprintln(os.Stderr, "  "+line)
+		}
+	}
+}
+
+// printResult prints a roll, calling out natural 20s and 1s
+func printResult(result *dice.Result) {
+	fmt.Printf("🎲 %s\n", result.String())
+	if result.Crit {
+		fmt.Println("Critical! (natural 20)")
+	}
+	if result.Fumble {
+		fmt.Println("Fumble! (natural 1)")
+	}
+}
+
+// runInteractive starts the interactive TUI, pre-loaded with any prep from the flags
+func runInteractive(opts options) {
+	model := tui.NewModel()
+	defer model.Close()
+	if err := model.SetLimits(opts.limits); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		os.Exit(1)
+	}
+	disabled, err := config.ParseDisabled(opts.disable)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: --disable: %s\n", err)
+		os.Exit(1)
+	}
+	if disabled[config.Network] && opts.serve != "" {
+		fmt.Fprintln(os.Stderr, "Error: --serve needs the network subsystem, which is turned off")
+		os.Exit(1)
+	}
+	if disabled[config.Initiative] && opts.encounter != "" {
+		fmt.Fprintln(os.Stderr, "Error: --encounter needs the initiative subsystem, which is turned off")
+		os.Exit(1)
+	}
+	model.Disable(disabled)
+	if opts.ascii {
+		model.SetASCII(true)
+	}
+	if opts.d10s {
+		model.SetPercentileD10s(true)
+	}
+	if opts.verbosity != "" {
+		if err := model.SetVerbosity(opts.verbosity); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			os.Exit(1)
+		}
+	}
+	if opts.title {
+		model.ShowStatusInTitle()
+	}
+	if opts.noRecap {
+		model.SetRoundRecap(false)
+	}
+	if opts.statusFile != "" {
+		if err := model.WriteStatusTo(opts.statusFile); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			os.Exit(1)
+		}
+	}
+	if opts.serve != "" {
+		if _, err := model.ServeWebView(opts.serve); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			os.Exit(1)
+		}
+	}
+
+	// Optionally keep history that scrolls out of memory in a log file
+	if opts.historyLog != "" {
+		if err := model.LogHistoryTo(opts.historyLog); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			os.Exit(1)
+		}
+	}
+	if opts.campaign != "" {
+		if err := model.OpenCampaign(opts.campaign); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			os.Exit(1)
+		}
+	}
+	if opts.macroFile != "" {
+		if err := model.LoadMacros(opts.macroFile); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			os.Exit(1)
+		}
+	}
+	if opts.party != "" {
+		if err := model.ImportParty(opts.party); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: loading party %s: %s\n", opts.party, err)
+			os.Exit(1)
+		}
+	}
+	if opts.checklists != "" {
+		if err := model.LoadChecklists(opts.checklists); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			os.Exit(1)
+		}
+	}
+	if opts.presets != "" {
+		if err := model.LoadPresets(opts.presets); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			os.Exit(1)
+		}
+	}
+	for _, path := range opts.tables {
+		if err := model.LoadTable(path); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: loading table %s: %s\n", path, err)
+			os.Exit(1)
+		}
+	}
+	if opts.encounter != "" {
+		if err := model.LoadEncounter(opts.encounter); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: loading encounter %s: %s\n", opts.encounter, err)
+			os.Exit(1)
+		}
+	}
+
+	if opts.session != "" {
+		if err := model.OpenSession(opts.session); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			os.Exit(1)
+		}
+	}
+
 	p := tea.NewProgram(
-		tui.NewModel(),
+		model,
 		tea.WithAltScreen(),       // Use alternate screen buffer
 		tea.WithMouseCellMotion(), // Enable mouse support
 	)
-	if _, err := p.Run(); err != nil {
+	final, err := p.Run()
+
+	// However it quit (command, Ctrl+C, SIGTERM), mark the session as ended
+	// cleanly so the next start doesn't report it interrupted
+	if m, ok := final.(tui.Model); ok {
+		if err := m.EndSession(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: saving session: %s\n", err)
+		}
+	}
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
@@ -99,29 +531,74 @@ func printHelp() {
 	help := `TavernShell - D&D Tools CLI
 
 USAGE:
-  tavernshell              Start interactive mode
+  tavernshell [flags]      Start interactive mode
   tavernshell <command>    Run a single command
 
+FLAGS:
+  --encounter <file>    Start initiative from an encounter JSON file
+  --table <file>        Load a random table (repeatable)
+  --history-log <file>  Keep history that scrolls out of memory in a file
+  --history-lines <n>   Lines of history kept in memory (default 100, 10-100000)
+  --history-log-rotate <n>  Move the history log to <file>.1 after n lines (default never)
+  --roll-log-size <n>   Rolls kept in the roll log (default 1000)
+  --campaign <file>     Keep the campaign record (initiative history) in a file
+  --macro-file <file>   Load macros from, and save new ones to, a file
+  --party <file>        Import player characters from a JSON character sheet file
+  --checklists <file>   Load checklists (e.g. session-start) from a JSON file
+  --presets <file>      Load spell and ability presets for 'cast' from a JSON file
+  --session <file>      Save trackers and initiative, offering to resume them next time
+  --ascii               Draw plain ASCII symbols instead of Unicode and emoji
+  --percentile-d10s     Show d100 rolls as the tens and ones d10s too
+  --verbosity <level>   Roll output: terse (just totals), normal or verbose (every die itemized)
+  --title               Show the round and next alarm in the terminal title
+  --no-recap            Don't start each initiative round with a one-line recap
+  --status-file <file>  Keep the round and next alarm in a file (for tmux)
+  --serve <addr>        Serve a read-only web view of initiative, trackers and rolls (e.g. :8080)
+  --disable <list>      Turn off subsystems: timers, trackers, initiative, network, or all (dice only)
+  --roller <spec>       Dice entropy source: crypto (default), seeded:<n> or sequence:<n>,<n>,...
+  --seed <n>            Reproducible rolls, e.g. for demos and bug reports (same as --roller seeded:<n>)
+  --strict              Cap roll and simulation sizes for single commands, for notation from
+                        someone else (e.g. a chat bot passing on players' rolls)
+
 COMMANDS:
-  roll <dice>   Roll dice with modifiers, advantage, keep/drop
+  roll <dice>   Roll dice with modifiers, (dis)advantage, keep/drop
+  stats <dice>  Show the min, max, mean and spread of totals as a bar chart
+  avg <dice>    Print the average total, rounded down as in 5e (min and max work too)
+  sim <dice>    Roll 100,000 times (sim [count] <dice> [vs N]) for the success rate and spread
+  gurps <skill> Roll 3d6 under a skill (e.g., 14 or 12-2), GURPS-style
+  choose <opts> Pick one option at random (flip: heads or tails)
   help          Show this help message
 
 EXAMPLES:
   tavernshell              # Start interactive shell
+  tavernshell --encounter crypt.json --table loot.txt
   tavernshell roll 2d6     # Roll 2 six-sided dice
   tavernshell r d20+5      # Roll d20 and add 5
   tavernshell r d20!       # Roll d20 with advantage
   tavernshell r 4d6kh3     # Roll 4d6, keep highest 3
+  tavernshell r 6x4d6kh3   # Roll 4d6kh3 six times
   tavernshell r 4d6dl1     # Roll 4d6, drop lowest 1
+  tavernshell r d20+7 vs 15  # Exits 0 on SUCCESS, 2 on FAILURE (for scripts)
+  tavernshell stats d20!   # How much does advantage help?
+  tavernshell gurps 12-2   # Exits 0 on SUCCESS, 2 on FAILURE
 
 DICE NOTATION:
   XdY       - Roll X dice with Y sides each
   XdY+Z     - Add modifier Z to the total
   XdY!      - Advantage (roll each die twice, keep highest)
+  XdY?      - Disadvantage (roll each die twice, keep lowest)
+  XdY!!!    - Triple advantage (roll each die three times, keep highest; or adv3)
+  XdYr1     - Reroll 1s until they aren't (ro<3: reroll under 3, once)
+  Xd%       - Percentile dice (same as Xd100)
   XdYkhN    - Keep highest N dice
   XdYklN    - Keep lowest N dice
   XdYdhN    - Drop highest N dice
   XdYdlN    - Drop lowest N dice
+  XdYdl1kh4 - Chain keep/drop, applied left to right
+  XdY+AdB   - Combine dice groups and constants (e.g., 2d6+1d8+3)
+  half(...) - Halve the total, rounding down (or .../2; double(...) or ...*2 doubles)
+  ... vs N  - Roll against a target number (or dcN): SUCCESS/FAILURE and the margin
+  ... # txt - Label the roll, e.g. 2d6+3 # greatsword damage
 
 Dropped dice are shown in angle brackets ‹like this›
 
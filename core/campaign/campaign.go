@@ -0,0 +1,108 @@
+package campaign
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// InitiativeRoll is one initiative result recorded for a participant
+type InitiativeRoll struct {
+	Initiative int       `json:"initiative"`
+	Time       time.Time `json:"time"`
+}
+
+// Log is a campaign's persistent record, kept as JSON on disk
+type Log struct {
+	Initiatives map[string][]InitiativeRoll `json:"initiatives"` // keyed by lowercase name
+	Encounters  []EncounterResult           `json:"encounters,omitempty"`
+	RuleSet     Rules                       `json:"rules"`
+	Sessions    []Session                   `json:"sessions,omitempty"`
+	Naturals    map[string]Naturals         `json:"naturals,omitempty"` // keyed by lowercase name
+	Rolls       int                         `json:"rolls,omitempty"`
+
+	path    string // file the log is saved to ("" keeps it in memory only)
+	unsaved bool   // rolls recorded since the last save
+	mu      sync.RWMutex
+}
+
+// NewLog creates an in-memory campaign log
+func NewLog() *Log {
+	return &Log{
+		Initiatives: make(map[string][]InitiativeRoll),
+		RuleSet:     DefaultRules(),
+	}
+}
+
+// Open loads a campaign log from path, starting a new one if the file does not exist
+// Changes are saved back to the same path
+func Open(path string) (*Log, error) {
+	l := NewLog()
+	l.path = path
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return l, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, l); err != nil {
+		return nil, fmt.Errorf("invalid campaign file %s: %w", path, err)
+	}
+	if l.Initiatives == nil {
+		l.Initiatives = make(map[string][]InitiativeRoll)
+	}
+	return l, nil
+}
+
+// Path returns the file the log is saved to ("" if in memory only)
+func (l *Log) Path() string {
+	return l.path
+}
+
+// RecordInitiative records an initiative result and saves the log
+func (l *Log) RecordInitiative(name string, initiative int) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	key := strings.ToLower(name)
+	l.Initiatives[key] = append(l.Initiatives[key], InitiativeRoll{Initiative: initiative, Time: time.Now()})
+	return l.save()
+}
+
+// InitiativeAverage returns a participant's average initiative and how many rolls it covers
+func (l *Log) InitiativeAverage(name string) (float64, int) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	rolls := l.Initiatives[strings.ToLower(name)]
+	if len(rolls) == 0 {
+		return 0, 0
+	}
+	total := 0
+	for _, r := range rolls {
+		total += r.Initiative
+	}
+	return float64(total) / float64(len(rolls)), len(rolls)
+}
+
+// save writes the log to disk, if it has a path (caller holds the lock)
+func (l *Log) save() error {
+	if l.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(l.path, data, 0644); err != nil {
+		return err
+	}
+	l.unsaved = false
+	return nil
+}
@@ -0,0 +1,215 @@
+package campaign
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestInitiativeAverage(t *testing.T) {
+	log := NewLog()
+	if _, count := log.InitiativeAverage("Aria"); count != 0 {
+		t.Errorf("Expected no rolls, got %d", count)
+	}
+
+	log.RecordInitiative("Aria", 10)
+	log.RecordInitiative("aria", 14)
+
+	avg, count := log.InitiativeAverage("ARIA")
+	if count != 2 || avg != 12 {
+		t.Errorf("Expected average 12 over 2 rolls, got %.1f over %d", avg, count)
+	}
+}
+
+func TestOpenAndSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "strahd.json")
+
+	log, err := Open(path)
+	if err != nil {
+		t.Fatalf("Unexpected error opening a new campaign: %v", err)
+	}
+	if err := log.RecordInitiative("Aria", 17); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	reopened, err := Open(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if avg, count := reopened.InitiativeAverage("Aria"); count != 1 || avg != 17 {
+		t.Errorf("Expected saved roll of 17, got %.1f over %d", avg, count)
+	}
+}
+
+func TestRateDifficulty(t *testing.T) {
+	tests := []struct {
+		spent float64
+		want  string
+	}{
+		{0, "easy"},
+		{0.14, "easy"},
+		{0.15, "medium"},
+		{0.3, "hard"},
+		{0.6, "deadly"},
+	}
+	for _, tt := range tests {
+		if got := RateDifficulty(tt.spent); got != tt.want {
+			t.Errorf("Expected %.2f spent to rate %s, got %s", tt.spent, tt.want, got)
+		}
+	}
+}
+
+func TestEncounterVerdict(t *testing.T) {
+	tests := []struct {
+		intended, actual, want string
+	}{
+		{"hard", "medium", "under-performed"},
+		{"hard", "hard", "on target"},
+		{"easy", "deadly", "over-performed"},
+		{"", "hard", ""},
+		{"hard", "", ""},
+	}
+	for _, tt := range tests {
+		r := EncounterResult{Intended: tt.intended, Actual: tt.actual}
+		if got := r.Verdict(); got != tt.want {
+			t.Errorf("Expected %s built as %q played as %q, got %q", tt.want, tt.intended, tt.actual, got)
+		}
+	}
+}
+
+func TestPlayedAs(t *testing.T) {
+	log := NewLog()
+	log.RecordEncounter(EncounterResult{Intended: "hard", Actual: "hard"})
+	log.RecordEncounter(EncounterResult{Intended: "hard", Actual: "medium"})
+	log.RecordEncounter(EncounterResult{Intended: "hard", Actual: "medium"})
+	log.RecordEncounter(EncounterResult{Intended: "easy", Actual: "easy"})
+
+	summary, count := log.PlayedAs("hard")
+	if count != 3 || summary != "medium ×2, hard ×1" {
+		t.Errorf("Expected 'medium ×2, hard ×1' over 3, got %q over %d", summary, count)
+	}
+
+	if _, err := ParseDifficulty("dead"); err != nil {
+		t.Errorf("Unexpected error parsing 'dead': %v", err)
+	}
+	if _, err := ParseDifficulty("trivial"); err == nil {
+		t.Error("Expected error parsing 'trivial'")
+	}
+}
+
+func TestIsMassiveDamage(t *testing.T) {
+	tests := []struct {
+		hp, damage, maxHP int
+		expected          bool
+	}{
+		{6, 18, 12, true},   // 12 left over after 0
+		{6, 17, 12, false},  // 11 left over
+		{12, 12, 12, false}, // just drops to 0
+		{0, 12, 12, true},   // already at 0
+		{-3, 12, 12, true},  // below 0 counts as 0
+		{6, 18, 0, false},   // no maximum to compare against
+		{6, -18, 12, false}, // healing
+	}
+	for _, tt := range tests {
+		if got := IsMassiveDamage(tt.hp, tt.damage, tt.maxHP); got != tt.expected {
+			t.Errorf("IsMassiveDamage(%d, %d, %d): expected %v, got %v", tt.hp, tt.damage, tt.maxHP, tt.expected, got)
+		}
+	}
+}
+
+func TestRulesSaved(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "strahd.json")
+
+	log, err := Open(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if rules := log.Rules(); !rules.MassiveDamage || rules.Ruleset != "5e" {
+		t.Errorf("Expected 5e with massive damage by default, got %+v", rules)
+	}
+	if err := log.SetRules(Rules{Ruleset: "osr", MassiveDamage: false}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	reopened, err := Open(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if rules := reopened.Rules(); rules.MassiveDamage || rules.Ruleset != "osr" {
+		t.Errorf("Expected osr without massive damage after reopening, got %+v", rules)
+	}
+}
+
+func TestRecap(t *testing.T) {
+	log := NewLog()
+	start := time.Date(2024, 3, 1, 19, 0, 0, 0, time.UTC)
+	log.StartSession(start)
+	log.EndSession(start.Add(3 * time.Hour))
+	log.StartSession(start.Add(7 * 24 * time.Hour)) // crashed: not counted
+	log.StartSession(start.Add(14 * 24 * time.Hour))
+
+	log.RecordEncounter(EncounterResult{Rounds: 3, DamageByRound: []int{10, 12, 5}, DamageTaken: 9, Duration: 40 * time.Minute})
+	log.RecordEncounter(EncounterResult{Rounds: 2, DamageByRound: []int{8, 0}, Duration: 20 * time.Minute})
+	log.RecordInitiative("Aria", 12)
+	log.RecordInitiative("Brom", 7)
+	log.RecordRoll("Aria", true, false)
+	log.RecordRoll("aria", true, false)
+	log.RecordRoll("Brom", false, true)
+	log.RecordRoll("Brom", false, false) // neither: only the roll counts
+	log.RecordRoll("", true, false)
+
+	r := log.Recap(start.Add(14*24*time.Hour + time.Hour))
+	if r.Sessions != 3 || r.PlayTime != 4*time.Hour {
+		t.Errorf("Expected 3 sessions with 4h played, got %d with %s", r.Sessions, r.PlayTime)
+	}
+	if r.Encounters != 2 || r.Rounds != 5 || r.DamageDealt != 26 || r.DamageTaken != 9 {
+		t.Errorf("Expected 2 encounters, 5 rounds, 26 damage dealt and 9 taken, got %d, %d, %d, %d", r.Encounters, r.Rounds, r.DamageDealt, r.DamageTaken)
+	}
+	if r.CombatTime != time.Hour || r.TalkTime() != 3*time.Hour {
+		t.Errorf("Expected 1h of combat and 3h of talk, got %s and %s", r.CombatTime, r.TalkTime())
+	}
+	if r.Initiatives != 2 || r.Rolls != 5 || r.Crits != 3 || r.Fumbles != 1 {
+		t.Errorf("Expected 2 initiatives, 5 rolls, 3 crits, 1 fumble, got %d, %d, %d, %d", r.Initiatives, r.Rolls, r.Crits, r.Fumbles)
+	}
+	expected := []Naturals{{Name: "Aria", Crits: 2}, {Name: "Brom", Fumbles: 1}}
+	if len(r.Naturals) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, r.Naturals)
+	}
+	for i := range expected {
+		if r.Naturals[i] != expected[i] {
+			t.Errorf("Expected %v, got %v", expected[i], r.Naturals[i])
+		}
+	}
+}
+
+func TestRollsSavedOnFlush(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "strahd.json")
+
+	log, err := Open(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	log.StartSession(time.Now())
+	log.RecordRoll("Aria", true, false)
+	log.RecordRoll("Aria", false, false)
+
+	// Counted, but not written until flushed
+	reopened, err := Open(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if r := reopened.Recap(time.Now()); r.Rolls != 0 || r.Crits != 0 {
+		t.Errorf("Expected no rolls saved before Flush, got %d rolls, %d crits", r.Rolls, r.Crits)
+	}
+
+	if err := log.Flush(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	reopened, err = Open(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if r := reopened.Recap(time.Now()); r.Rolls != 2 || r.Crits != 1 {
+		t.Errorf("Expected 2 rolls and 1 crit saved, got %d rolls, %d crits", r.Rolls, r.Crits)
+	}
+}
@@ -0,0 +1,117 @@
+package campaign
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Difficulties are the encounter difficulty levels, easiest first
+var Difficulties = []string{"easy", "medium", "hard", "deadly"}
+
+// difficultyBands is the share of the party's resources an encounter of each
+// difficulty is expected to use up; spending more than a band's share rates
+// the encounter as the next difficulty up
+var difficultyBands = []float64{0.15, 0.30, 0.45}
+
+// EncounterResult is the telemetry recorded for one finished encounter
+type EncounterResult struct {
+	Time          time.Time     `json:"time"`
+	Intended      string        `json:"intended,omitempty"` // difficulty the encounter was built as
+	Actual        string        `json:"actual,omitempty"`   // difficulty it played at ("" if the party wasn't tracked)
+	Rounds        int           `json:"rounds"`
+	Duration      time.Duration `json:"duration,omitempty"` // from initiative starting to ending
+	DamageByRound []int         `json:"damage_by_round"`    // HP lost by everyone in initiative in each round
+	DamageTaken   int           `json:"damage_taken"`       // the party's share of those drops
+	PartySpent    float64       `json:"party_spent"`        // share of the party's tracked resources used up (0-1)
+}
+
+// ParseDifficulty parses a difficulty level (case-insensitive, prefixes allowed)
+func ParseDifficulty(s string) (string, error) {
+	s = strings.ToLower(s)
+	if s != "" {
+		for _, d := range Difficulties {
+			if strings.HasPrefix(d, s) {
+				return d, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("unknown difficulty '%s' (expected %s)", s, strings.Join(Difficulties, ", "))
+}
+
+// RateDifficulty returns the difficulty an encounter played at, from the share
+// of the party's resources it used up
+func RateDifficulty(spent float64) string {
+	for i, band := range difficultyBands {
+		if spent < band {
+			return Difficulties[i]
+		}
+	}
+	return Difficulties[len(Difficulties)-1]
+}
+
+// Verdict compares how the encounter played against how it was built:
+// "on target", "under-performed" (easier than intended) or "over-performed"
+// It returns "" if either difficulty is unknown
+func (r EncounterResult) Verdict() string {
+	intended, actual := difficultyIndex(r.Intended), difficultyIndex(r.Actual)
+	switch {
+	case intended < 0 || actual < 0:
+		return ""
+	case actual < intended:
+		return "under-performed"
+	case actual > intended:
+		return "over-performed"
+	default:
+		return "on target"
+	}
+}
+
+// difficultyIndex returns a difficulty's position in Difficulties, or -1
+func difficultyIndex(d string) int {
+	for i, level := range Difficulties {
+		if level == d {
+			return i
+		}
+	}
+	return -1
+}
+
+// RecordEncounter adds a finished encounter's telemetry and saves the log
+func (l *Log) RecordEncounter(r EncounterResult) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.Encounters = append(l.Encounters, r)
+	return l.save()
+}
+
+// PlayedAs summarizes how past encounters built at a difficulty actually played,
+// e.g. "medium ×2, hard ×1", and how many there were
+func (l *Log) PlayedAs(intended string) (string, int) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	counts := make(map[string]int)
+	total := 0
+	for _, e := range l.Encounters {
+		if e.Intended == intended && e.Actual != "" {
+			counts[e.Actual]++
+			total++
+		}
+	}
+
+	actuals := make([]string, 0, len(counts))
+	for actual := range counts {
+		actuals = append(actuals, actual)
+	}
+	sort.Slice(actuals, func(i, j int) bool {
+		return difficultyIndex(actuals[i]) < difficultyIndex(actuals[j])
+	})
+	parts := make([]string, len(actuals))
+	for i, actual := range actuals {
+		parts[i] = fmt.Sprintf("%s ×%d", actual, counts[actual])
+	}
+	return strings.Join(parts, ", "), total
+}
@@ -0,0 +1,149 @@
+package campaign
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// Session is one sitting at the table, from starting TavernShell to quitting
+type Session struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end,omitempty"` // zero if it didn't quit cleanly
+}
+
+// Naturals counts the natural 20s and 1s rolled on someone's d20s
+type Naturals struct {
+	Name    string `json:"name"` // as first recorded ("" for rolls nobody made)
+	Crits   int    `json:"crits"`
+	Fumbles int    `json:"fumbles"`
+}
+
+// Recap totals up a whole campaign, for an end-of-arc look back
+type Recap struct {
+	Sessions    int
+	PlayTime    time.Duration // across sessions that quit cleanly, and this one
+	Encounters  int
+	Rounds      int
+	DamageDealt int           // HP the party's foes lost in every encounter
+	DamageTaken int           // HP the party lost in every encounter
+	CombatTime  time.Duration // in encounters timed from start to end
+	Initiatives int           // initiative results on record
+	Rolls       int
+	Crits       int
+	Fumbles     int
+	Naturals    []Naturals // by name, most crits first
+}
+
+// TalkTime returns the play time spent out of combat (0 if it can't be told)
+func (r Recap) TalkTime() time.Duration {
+	return max(r.PlayTime-r.CombatTime, 0)
+}
+
+// StartSession records a new session starting at the given time and saves the log
+func (l *Log) StartSession(at time.Time) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.Sessions = append(l.Sessions, Session{Start: at})
+	return l.save()
+}
+
+// EndSession records the current session ending at the given time and saves the log
+func (l *Log) EndSession(at time.Time) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(l.Sessions) == 0 {
+		return nil
+	}
+	l.Sessions[len(l.Sessions)-1].End = at
+	return l.save()
+}
+
+// RecordRoll counts a roll, and its natural 20 or 1 for name ("" if nobody
+// in particular rolled it)
+// Rolls aren't saved as they come, which would rewrite the file for every
+// one; Flush saves them, as does any other change to the log
+func (l *Log) RecordRoll(name string, crit, fumble bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.Rolls++
+	l.unsaved = true
+	if !crit && !fumble {
+		return
+	}
+	if l.Naturals == nil {
+		l.Naturals = make(map[string]Naturals)
+	}
+	key := strings.ToLower(name)
+	n, ok := l.Naturals[key]
+	if !ok {
+		n.Name = name
+	}
+	if crit {
+		n.Crits++
+	}
+	if fumble {
+		n.Fumbles++
+	}
+	l.Naturals[key] = n
+}
+
+// Flush saves the rolls recorded since the log was last saved, if any
+func (l *Log) Flush() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.unsaved {
+		return nil
+	}
+	return l.save()
+}
+
+// Recap totals up everything the campaign has on record, counting the
+// current session as running until now
+func (l *Log) Recap(now time.Time) Recap {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	r := Recap{Sessions: len(l.Sessions), Encounters: len(l.Encounters), Rolls: l.Rolls}
+	for i, s := range l.Sessions {
+		end := s.End
+		if end.IsZero() && i == len(l.Sessions)-1 {
+			end = now
+		}
+		if end.After(s.Start) {
+			r.PlayTime += end.Sub(s.Start)
+		}
+	}
+	for _, e := range l.Encounters {
+		r.Rounds += e.Rounds
+		r.CombatTime += e.Duration
+		// Encounters recorded before damage was split by side count as dealt
+		for _, d := range e.DamageByRound {
+			r.DamageDealt += d
+		}
+		r.DamageDealt -= e.DamageTaken
+		r.DamageTaken += e.DamageTaken
+	}
+	for _, rolls := range l.Initiatives {
+		r.Initiatives += len(rolls)
+	}
+	for _, n := range l.Naturals {
+		r.Crits += n.Crits
+		r.Fumbles += n.Fumbles
+		if n.Name != "" {
+			r.Naturals = append(r.Naturals, n)
+		}
+	}
+	sort.Slice(r.Naturals, func(i, j int) bool {
+		a, b := r.Naturals[i], r.Naturals[j]
+		if a.Crits != b.Crits {
+			return a.Crits > b.Crits
+		}
+		return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+	})
+	return r
+}
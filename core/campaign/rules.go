@@ -0,0 +1,42 @@
+package campaign
+
+import "github.com/angusmclean/tavernshell/core/ruleset"
+
+// Rules are the game system and optional rules a campaign plays with, saved in its log
+type Rules struct {
+	Ruleset string `json:"ruleset"` // name of the game system (see the ruleset package)
+
+	// MassiveDamage kills outright when damage left over after dropping to
+	// 0 HP is at least the creature's HP maximum (if the ruleset has the rule)
+	MassiveDamage bool `json:"massive_damage"`
+}
+
+// DefaultRules are the rules a new campaign starts with
+func DefaultRules() Rules {
+	return Rules{Ruleset: ruleset.Default, MassiveDamage: true}
+}
+
+// IsMassiveDamage returns true if damage taken at hp (out of maxHP) leaves at
+// least maxHP over after reaching 0, which is instant death under the
+// massive damage rule
+func IsMassiveDamage(hp, damage, maxHP int) bool {
+	if damage <= 0 || maxHP <= 0 {
+		return false
+	}
+	return damage-max(hp, 0) >= maxHP
+}
+
+// Rules returns the campaign's rules
+func (l *Log) Rules() Rules {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.RuleSet
+}
+
+// SetRules changes the campaign's rules and saves the log
+func (l *Log) SetRules(r Rules) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.RuleSet = r
+	return l.save()
+}
@@ -0,0 +1,174 @@
+package checklist
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Step is one reminder in a checklist, with an optional command run when it's confirmed
+type Step struct {
+	Text    string `json:"text"`
+	Command string `json:"command,omitempty"`
+}
+
+// UnmarshalJSON accepts a step as a plain string or as {"text": ..., "command": ...}
+func (s *Step) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		s.Text = text
+		return nil
+	}
+	type plain Step
+	var p plain
+	if err := json.Unmarshal(data, &p); err != nil {
+		return err
+	}
+	*s = Step(p)
+	return nil
+}
+
+// Checklist is a named list of steps to confirm in order
+type Checklist struct {
+	Name  string
+	Steps []Step
+}
+
+// sessionStart is the built-in checklist, used unless a file replaces it
+var sessionStart = &Checklist{
+	Name: "session-start",
+	Steps: []Step{
+		{Text: "Set the in-game clock"},
+		{Text: "Load tonight's encounter"},
+		{Text: "Start the session timer", Command: "a 4h session"},
+	},
+}
+
+// Manager holds the available checklists
+type Manager struct {
+	checklists map[string]*Checklist   // keyed by lowercase name
+	files      map[string][]*Checklist // the checklists each file loaded, keyed by path
+	mu         sync.RWMutex
+}
+
+// NewManager creates a manager holding the built-in checklists
+func NewManager() *Manager {
+	return &Manager{
+		checklists: map[string]*Checklist{sessionStart.Name: sessionStart},
+		files:      make(map[string][]*Checklist),
+	}
+}
+
+// Load reads checklists from a JSON file mapping names to steps:
+//
+//	{"session-start": ["Set the in-game clock", {"text": "Start the session timer", "command": "a 4h session"}]}
+//
+// Checklists in the file replace built-in ones with the same name. Loading a
+// file again replaces what it loaded before, so a checklist taken out of the
+// file is gone (or back to the built-in one)
+func (m *Manager) Load(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var lists map[string][]Step
+	if err := json.Unmarshal(data, &lists); err != nil {
+		return fmt.Errorf("invalid checklist file %s: %w", path, err)
+	}
+
+	for name, steps := range lists {
+		if len(steps) == 0 {
+			return fmt.Errorf("checklist '%s' has no steps", name)
+		}
+		for i, step := range steps {
+			if strings.TrimSpace(step.Text) == "" {
+				return fmt.Errorf("checklist '%s' step %d has no text", name, i+1)
+			}
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, old := range m.files[path] {
+		key := strings.ToLower(old.Name)
+		if m.checklists[key] != old {
+			continue // since replaced by another file
+		}
+		delete(m.checklists, key)
+		if key == sessionStart.Name {
+			m.checklists[key] = sessionStart
+		}
+	}
+	loaded := make([]*Checklist, 0, len(lists))
+	for name, steps := range lists {
+		c := &Checklist{Name: name, Steps: steps}
+		m.checklists[strings.ToLower(name)] = c
+		loaded = append(loaded, c)
+	}
+	m.files[path] = loaded
+	return nil
+}
+
+// Get retrieves a checklist by name (case-insensitive)
+func (m *Manager) Get(name string) *Checklist {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.checklists[strings.ToLower(name)]
+}
+
+// List returns all checklists sorted by name
+func (m *Manager) List() []*Checklist {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	lists := make([]*Checklist, 0, len(m.checklists))
+	for _, c := range m.checklists {
+		lists = append(lists, c)
+	}
+	sort.Slice(lists, func(i, j int) bool {
+		return lists[i].Name < lists[j].Name
+	})
+	return lists
+}
+
+// Run steps through a checklist, recording which steps were done or skipped
+type Run struct {
+	Checklist *Checklist
+	Done      int
+	Skipped   int
+	index     int
+}
+
+// Start begins a run through the checklist
+func (c *Checklist) Start() *Run {
+	return &Run{Checklist: c}
+}
+
+// Current returns the step awaiting an answer, its 1-based number, and false once the run is finished
+func (r *Run) Current() (Step, int, bool) {
+	if r.Finished() {
+		return Step{}, 0, false
+	}
+	return r.Checklist.Steps[r.index], r.index + 1, true
+}
+
+// Answer confirms (yes) or skips the current step and moves on to the next
+func (r *Run) Answer(yes bool) {
+	if r.Finished() {
+		return
+	}
+	if yes {
+		r.Done++
+	} else {
+		r.Skipped++
+	}
+	r.index++
+}
+
+// Finished returns true once every step has been answered
+func (r *Run) Finished() bool {
+	return r.index >= len(r.Checklist.Steps)
+}
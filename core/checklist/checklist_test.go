@@ -0,0 +1,98 @@
+package checklist
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	run := NewManager().Get("Session-Start").Start()
+
+	step, n, ok := run.Current()
+	if !ok || n != 1 || step.Text != "Set the in-game clock" {
+		t.Fatalf("Expected step 1 'Set the in-game clock', got %d %q (ok %v)", n, step.Text, ok)
+	}
+
+	run.Answer(true)
+	run.Answer(false)
+	step, n, _ = run.Current()
+	if n != 3 || step.Command == "" {
+		t.Errorf("Expected step 3 with a command, got %d %q", n, step.Command)
+	}
+	run.Answer(true)
+
+	if !run.Finished() || run.Done != 2 || run.Skipped != 1 {
+		t.Errorf("Expected finished with 2 done and 1 skipped, got %v, %d, %d", run.Finished(), run.Done, run.Skipped)
+	}
+	if _, _, ok := run.Current(); ok {
+		t.Error("Expected no current step after finishing")
+	}
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr bool
+	}{
+		{"mixed steps", `{"session-start": ["Set the clock", {"text": "Start timer", "command": "a 4h"}], "wrap-up": ["Award XP"]}`, false},
+		{"empty checklist", `{"wrap-up": []}`, true},
+		{"step without text", `{"wrap-up": [{"command": "a 1m"}]}`, true},
+		{"invalid json", `{"wrap-up": [`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "checklists.json")
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			m := NewManager()
+			err := m.Load(path)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected error loading %s", tt.content)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			start := m.Get("session-start")
+			if len(start.Steps) != 2 || start.Steps[1].Command != "a 4h" {
+				t.Errorf("Expected the file to replace session-start, got %+v", start.Steps)
+			}
+			if len(m.List()) != 2 {
+				t.Errorf("Expected 2 checklists, got %d", len(m.List()))
+			}
+		})
+	}
+}
+
+func TestLoadAgain(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checklists.json")
+	m := NewManager()
+	for _, content := range []string{
+		`{"session-start": ["Set the clock"], "wrap-up": ["Award XP"]}`,
+		`{"travel": ["Roll weather"]}`,
+	} {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := m.Load(path); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	if m.Get("wrap-up") != nil {
+		t.Errorf("Expected wrap-up to be gone once taken out of the file")
+	}
+	if start := m.Get("session-start"); start != sessionStart {
+		t.Errorf("Expected the built-in session-start back, got %+v", start)
+	}
+	if len(m.List()) != 2 {
+		t.Errorf("Expected 2 checklists, got %d", len(m.List()))
+	}
+}
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Defaults and bounds for the memory limits
+const (
+	DefaultHistoryLines = 100  // output lines (and recalled commands) kept in memory
+	DefaultRollLogSize  = 1000 // rolls kept in the roll log
+
+	minHistoryLines     = 10
+	maxHistoryLines     = 100000
+	minRollLogSize      = 10
+	maxRollLogSize      = 100000
+	minHistoryLogRotate = 100
+)
+
+// Limits caps how much a long session keeps, so raising the scrollback
+// on a small device doesn't let memory grow without bound
+type Limits struct {
+	HistoryLines     int // output lines (and recalled commands) kept in memory
+	RollLogSize      int // rolls kept in the roll log
+	HistoryLogRotate int // lines written to the history log before it is rotated (0 = never)
+}
+
+// DefaultLimits returns the limits used when nothing is configured
+func DefaultLimits() Limits {
+	return Limits{
+		HistoryLines: DefaultHistoryLines,
+		RollLogSize:  DefaultRollLogSize,
+	}
+}
+
+// LimitsFromEnv returns the default limits overridden by any of
+// TAVERNSHELL_HISTORY_LINES, TAVERNSHELL_ROLL_LOG_SIZE and
+// TAVERNSHELL_HISTORY_LOG_ROTATE, reading variables with getenv
+func LimitsFromEnv(getenv func(string) string) (Limits, error) {
+	l := DefaultLimits()
+	for _, v := range []struct {
+		name  string
+		value *int
+	}{
+		{"TAVERNSHELL_HISTORY_LINES", &l.HistoryLines},
+		{"TAVERNSHELL_ROLL_LOG_SIZE", &l.RollLogSize},
+		{"TAVERNSHELL_HISTORY_LOG_ROTATE", &l.HistoryLogRotate},
+	} {
+		s := getenv(v.name)
+		if s == "" {
+			continue
+		}
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return l, fmt.Errorf("%s must be a number, got '%s'", v.name, s)
+		}
+		*v.value = n
+	}
+	return l, nil
+}
+
+// Validate checks every limit is within sane bounds
+func (l Limits) Validate() error {
+	if l.HistoryLines < minHistoryLines || l.HistoryLines > maxHistoryLines {
+		return fmt.Errorf("history lines must be between %d and %d, got %d", minHistoryLines, maxHistoryLines, l.HistoryLines)
+	}
+	if l.RollLogSize < minRollLogSize || l.RollLogSize > maxRollLogSize {
+		return fmt.Errorf("roll log size must be between %d and %d, got %d", minRollLogSize, maxRollLogSize, l.RollLogSize)
+	}
+	if l.HistoryLogRotate != 0 && l.HistoryLogRotate < minHistoryLogRotate {
+		return fmt.Errorf("history log rotation must be 0 (never) or at least %d lines, got %d", minHistoryLogRotate, l.HistoryLogRotate)
+	}
+	return nil
+}
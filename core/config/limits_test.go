@@ -0,0 +1,43 @@
+package config
+
+import "testing"
+
+func TestLimitsFromEnv(t *testing.T) {
+	env := map[string]string{
+		"TAVERNSHELL_HISTORY_LINES":      "5000",
+		"TAVERNSHELL_HISTORY_LOG_ROTATE": "20000",
+	}
+	l, err := LimitsFromEnv(func(name string) string { return env[name] })
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if l.HistoryLines != 5000 || l.RollLogSize != DefaultRollLogSize || l.HistoryLogRotate != 20000 {
+		t.Errorf("Expected 5000/%d/20000, got %d/%d/%d", DefaultRollLogSize, l.HistoryLines, l.RollLogSize, l.HistoryLogRotate)
+	}
+
+	env["TAVERNSHELL_ROLL_LOG_SIZE"] = "lots"
+	if _, err := LimitsFromEnv(func(name string) string { return env[name] }); err == nil {
+		t.Error("Expected error for a non-numeric roll log size")
+	}
+}
+
+func TestLimitsValidate(t *testing.T) {
+	if err := DefaultLimits().Validate(); err != nil {
+		t.Errorf("Expected the defaults to be valid, got %v", err)
+	}
+
+	tests := []struct {
+		limits Limits
+		desc   string
+	}{
+		{Limits{HistoryLines: 5, RollLogSize: 100}, "history too small"},
+		{Limits{HistoryLines: 1000000, RollLogSize: 100}, "history too large"},
+		{Limits{HistoryLines: 100, RollLogSize: 0}, "roll log too small"},
+		{Limits{HistoryLines: 100, RollLogSize: 100, HistoryLogRotate: 5}, "rotating too often"},
+	}
+	for _, tt := range tests {
+		if err := tt.limits.Validate(); err == nil {
+			t.Errorf("Expected error for %s", tt.desc)
+		}
+	}
+}
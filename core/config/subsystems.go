@@ -0,0 +1,66 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Subsystem is a part of TavernShell that can be turned off, leaving a leaner
+// shell for a small device or a dice-only session
+type Subsystem string
+
+const (
+	Timers     Subsystem = "timers"     // alarms, buffs and the timer bar
+	Trackers   Subsystem = "trackers"   // number trackers and the tracker bar
+	Initiative Subsystem = "initiative" // initiative and its panel
+	Network    Subsystem = "network"    // the web view for players (--serve)
+)
+
+// Subsystems lists every subsystem that can be turned off
+var Subsystems = []Subsystem{Timers, Trackers, Initiative, Network}
+
+// Disabled is the set of subsystems turned off
+type Disabled map[Subsystem]bool
+
+// ParseDisabled reads a comma-separated list of subsystems to turn off, e.g.
+// "timers,network", or "all" for a dice-only shell
+func ParseDisabled(list string) (Disabled, error) {
+	d := make(Disabled)
+	for _, name := range strings.Split(list, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		switch {
+		case name == "":
+			continue
+		case name == "all":
+			for _, s := range Subsystems {
+				d[s] = true
+			}
+			continue
+		}
+		found := false
+		for _, s := range Subsystems {
+			if string(s) == name {
+				d[s], found = true, true
+			}
+		}
+		if !found {
+			names := make([]string, len(Subsystems))
+			for i, s := range Subsystems {
+				names[i] = string(s)
+			}
+			return nil, fmt.Errorf("unknown subsystem '%s' (expected %s, or all)", name, strings.Join(names, ", "))
+		}
+	}
+	return d, nil
+}
+
+// String lists the subsystems turned off, in the order of Subsystems
+func (d Disabled) String() string {
+	var names []string
+	for _, s := range Subsystems {
+		if d[s] {
+			names = append(names, string(s))
+		}
+	}
+	return strings.Join(names, ",")
+}
@@ -0,0 +1,30 @@
+package config
+
+import "testing"
+
+func TestParseDisabled(t *testing.T) {
+	tests := []struct {
+		list     string
+		expected string
+	}{
+		{"", ""},
+		{"timers", "timers"},
+		{"network, Timers", "timers,network"},
+		{"all", "timers,trackers,initiative,network"},
+		{"initiative,,", "initiative"},
+	}
+	for _, tt := range tests {
+		d, err := ParseDisabled(tt.list)
+		if err != nil {
+			t.Errorf("ParseDisabled(%q): unexpected error: %v", tt.list, err)
+			continue
+		}
+		if d.String() != tt.expected {
+			t.Errorf("ParseDisabled(%q): expected %q, got %q", tt.list, tt.expected, d.String())
+		}
+	}
+
+	if _, err := ParseDisabled("timers,dice"); err == nil {
+		t.Error("Expected error for an unknown subsystem")
+	}
+}
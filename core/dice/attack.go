@@ -0,0 +1,142 @@
+package dice
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Attack is an attack roll made together with its damage: damage is only
+// rolled on a hit, with its dice doubled on a critical hit
+type Attack struct {
+	Check    Check   // The to-hit roll against the target's AC
+	Naturals bool    // A natural 20 always hits (and crits) and a natural 1 always misses
+	Damage   *Result // The damage roll; nil on a miss
+}
+
+// RollAttack rolls to hit against a target number and, on a hit, the damage
+// Degrees and naturals are the ruleset's (see Check and Attack)
+func RollAttack(toHit, damage *Expression, target int, degrees, naturals bool) (*Attack, error) {
+	hit, err := RollExpression(toHit)
+	if err != nil {
+		return nil, err
+	}
+	a := &Attack{Check: Check{Result: hit, Target: target, Degrees: degrees}, Naturals: naturals}
+	if !a.Hit() {
+		return a, nil
+	}
+	if a.CriticalHit() {
+		damage = DoubleDice(damage)
+	}
+	if a.Damage, err = RollExpression(damage); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// Hit reports whether the attack hit
+func (a Attack) Hit() bool {
+	if a.Naturals && !a.Check.Degrees && a.Check.Result.Crit != a.Check.Result.Fumble {
+		return a.Check.Result.Crit // a natural 20 always hits and a natural 1 always misses
+	}
+	return a.Check.Success()
+}
+
+// CriticalHit reports whether the attack was a critical hit: a critical
+// success with degrees of success, otherwise a natural 20
+func (a Attack) CriticalHit() bool {
+	if a.Check.Degrees {
+		return a.Check.Degree() == CriticalSuccess
+	}
+	return a.Naturals && a.Hit() && a.Check.Result.Crit
+}
+
+// Degree is how well the attack went, for coloring: a critical hit, a hit,
+// a miss, or a natural 1 or critical failure
+func (a Attack) Degree() Degree {
+	switch {
+	case a.CriticalHit():
+		return CriticalSuccess
+	case a.Hit():
+		return Success
+	case a.Check.Degrees:
+		return a.Check.Degree()
+	case a.Naturals && a.Check.Result.Fumble:
+		return CriticalFailure
+	default:
+		return Failure
+	}
+}
+
+// String describes the to-hit outcome, e.g. "HIT by 4 (vs 15)" or
+// "CRITICAL HIT despite missing by 2 (vs 15, natural 20)"
+func (a Attack) String() string {
+	outcome := "MISS"
+	if a.CriticalHit() {
+		outcome = "CRITICAL HIT"
+	} else if a.Hit() {
+		outcome = "HIT"
+	}
+
+	margin := a.Check.Margin()
+	by := fmt.Sprintf("by %d", margin)
+	switch {
+	case margin < 0 && a.Hit():
+		by = fmt.Sprintf("despite missing by %d", -margin)
+	case margin < 0:
+		by = fmt.Sprintf("by %d", -margin)
+	case !a.Hit():
+		by = fmt.Sprintf("despite beating it by %d", margin)
+	}
+
+	note := ""
+	if a.Naturals && a.Check.Result.Crit && !a.Check.Result.Fumble {
+		note = ", natural 20"
+	} else if a.Naturals && a.Check.Result.Fumble && !a.Check.Result.Crit {
+		note = ", natural 1"
+	}
+	return fmt.Sprintf("%s %s (vs %d%s)", outcome, by, a.Check.Target, note)
+}
+
+// DoubleDice returns a copy of an expression with every dice group's count
+// doubled and the modifier left alone, e.g. 2d8+1d6+4 becomes 4d8+2d6+4
+func DoubleDice(expr *Expression) *Expression {
+	doubled := *expr
+	doubled.Count *= 2
+	doubled.Groups = make([]*Group, len(expr.Groups))
+	for i, g := range expr.Groups {
+		group := *g
+		group.Count *= 2
+		doubled.Groups[i] = &group
+	}
+	return &doubled
+}
+
+// SplitAttack splits an attack, "d20+7 vs 15 dmg 1d8+4", into the to-hit
+// notation, the target number and the damage notation
+func SplitAttack(notation string) (toHit string, target int, damage string, err error) {
+	lower := strings.ToLower(notation)
+	keyword := ""
+	at := -1
+	for _, k := range []string{" damage ", " dmg "} {
+		if i := strings.LastIndex(lower+" ", k); i >= 0 {
+			keyword, at = k, i
+			break
+		}
+	}
+	if at < 0 {
+		return "", 0, "", fmt.Errorf("expected damage after the to-hit roll, e.g. 'd20+7 vs 15 dmg 1d8+4'")
+	}
+	damage = strings.TrimSpace(notation[min(at+len(keyword), len(notation)):])
+	if damage == "" {
+		return "", 0, "", fmt.Errorf("expected dice after '%s'", strings.TrimSpace(keyword))
+	}
+
+	toHit, target, ok, err := SplitCheck(notation[:at])
+	if err != nil {
+		return "", 0, "", err
+	}
+	if !ok {
+		return "", 0, "", fmt.Errorf("expected a target to hit, e.g. 'd20+7 vs 15 dmg 1d8+4'")
+	}
+	return toHit, target, damage, nil
+}
@@ -0,0 +1,93 @@
+package dice
+
+import "testing"
+
+func TestSplitAttack(t *testing.T) {
+	tests := []struct {
+		notation string
+		toHit    string
+		target   int
+		damage   string
+		wantErr  bool
+	}{
+		{"d20+7 vs 15 dmg 1d8+4", "d20+7", 15, "1d8+4", false},
+		{"d20+7 ac15 DMG 2d6+3", "", 0, "", true},
+		{"d20+7 dc 15 damage 2d6 + 3", "d20+7", 15, "2d6 + 3", false},
+		{"d20+7 vs 15", "", 0, "", true},
+		{"d20+7 vs 15 dmg", "", 0, "", true},
+		{"d20+7 dmg 1d8", "", 0, "", true},
+	}
+	for _, tt := range tests {
+		toHit, target, damage, err := SplitAttack(tt.notation)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("SplitAttack(%q): expected error, got nil", tt.notation)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("SplitAttack(%q): unexpected error: %v", tt.notation, err)
+			continue
+		}
+		if toHit != tt.toHit || target != tt.target || damage != tt.damage {
+			t.Errorf("SplitAttack(%q) = %q, %d, %q, want %q, %d, %q", tt.notation, toHit, target, damage, tt.toHit, tt.target, tt.damage)
+		}
+	}
+}
+
+func TestDoubleDice(t *testing.T) {
+	expr, err := Parse("2d8+1d6+4")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if got := DoubleDice(expr).String(); got != "4d8+2d6+4" {
+		t.Errorf("Expected '4d8+2d6+4', got '%s'", got)
+	}
+	if got := expr.String(); got != "2d8+1d6+4" {
+		t.Errorf("Expected the original left as '2d8+1d6+4', got '%s'", got)
+	}
+}
+
+func TestRollAttack(t *testing.T) {
+	defer SetRoller(CurrentRoller())
+
+	tests := []struct {
+		name     string
+		toHit    string
+		degrees  bool
+		naturals bool
+		values   []int
+		expected string
+		damage   int // -1 for no damage roll
+	}{
+		{"hit", "d20+7", false, true, []int{10, 5}, "HIT by 2 (vs 15)", 9},
+		{"miss", "d20+7", false, true, []int{6}, "MISS by 2 (vs 15)", -1},
+		{"natural 20 doubles dice", "d20+7", false, true, []int{20, 3, 6}, "CRITICAL HIT by 12 (vs 15, natural 20)", 13},
+		{"natural 1 misses", "d20+7", false, true, []int{1}, "MISS by 7 (vs 15, natural 1)", -1},
+		{"no naturals", "d20+7", false, false, []int{20, 3}, "HIT by 12 (vs 15)", 7},
+		{"degrees crit", "d20+7", true, true, []int{18, 2, 2}, "CRITICAL HIT by 10 (vs 15)", 8},
+		{"degrees natural 20", "d20-8", true, true, []int{20, 4}, "HIT despite missing by 3 (vs 15, natural 20)", 8}, // stepped up a degree
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			toHit, _ := Parse(tt.toHit)
+			damage, _ := Parse("1d8+4")
+			SetRoller(&scriptedRoller{values: tt.values})
+			a, err := RollAttack(toHit, damage, 15, tt.degrees, tt.naturals)
+			if err != nil {
+				t.Fatalf("RollAttack failed: %v", err)
+			}
+			if got := a.String(); got != tt.expected {
+				t.Errorf("Expected '%s', got '%s'", tt.expected, got)
+			}
+			switch {
+			case tt.damage < 0 && a.Damage != nil:
+				t.Errorf("Expected no damage on a miss, got %s", a.Damage)
+			case tt.damage >= 0 && a.Damage == nil:
+				t.Errorf("Expected %d damage, got none", tt.damage)
+			case tt.damage >= 0 && a.Damage.Total != tt.damage:
+				t.Errorf("Expected %d damage, got %d", tt.damage, a.Damage.Total)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package dice
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Check is a roll made against a target number, like an attack against AC 15
+// or a save against DC 13
+type Check struct {
+	Result  *Result // The roll
+	Target  int     // Number to meet or beat
+	Degrees bool    // Report degrees of success (Pathfinder 2e style critical successes and failures)
+}
+
+// Degree is how well a check went
+type Degree int
+
+const (
+	CriticalFailure Degree = iota // Missed by 10 or more (or a natural 1 on a failure)
+	Failure                       // Missed the target
+	Success                       // Met or beat the target
+	CriticalSuccess               // Beat it by 10 or more (or a natural 20 on a success)
+)
+
+// String names the degree, e.g. "CRITICAL SUCCESS"
+func (d Degree) String() string {
+	switch d {
+	case CriticalFailure:
+		return "CRITICAL FAILURE"
+	case Failure:
+		return "FAILURE"
+	case CriticalSuccess:
+		return "CRITICAL SUCCESS"
+	default:
+		return "SUCCESS"
+	}
+}
+
+// Degree works out how well the check went
+// Without Degrees a check only succeeds or fails. With them, beating the
+// target by 10 is a critical success and missing it by 10 a critical
+// failure, then a natural 20 improves the result a step and a natural 1
+// worsens it a step
+func (c Check) Degree() Degree {
+	margin := c.Margin()
+	if !c.Degrees {
+		if margin >= 0 {
+			return Success
+		}
+		return Failure
+	}
+
+	var d Degree
+	switch {
+	case margin >= 10:
+		d = CriticalSuccess
+	case margin >= 0:
+		d = Success
+	case margin > -10:
+		d = Failure
+	default:
+		d = CriticalFailure
+	}
+	if c.Result.Crit && d < CriticalSuccess {
+		d++
+	}
+	if c.Result.Fumble && d > CriticalFailure {
+		d--
+	}
+	return d
+}
+
+// Success reports whether the check succeeded (critically or not)
+func (c Check) Success() bool {
+	return c.Degree() >= Success
+}
+
+// Critical reports whether the check was a critical success or failure
+func (c Check) Critical() bool {
+	d := c.Degree()
+	return d == CriticalSuccess || d == CriticalFailure
+}
+
+// Margin is how far the roll beat the target by (negative if it fell short)
+func (c Check) Margin() int {
+	return c.Result.Total - c.Target
+}
+
+// String describes the outcome, e.g. "SUCCESS by 4 (vs 15)", noting any
+// natural 20 or 1 when it shifts the degree of success
+func (c Check) String() string {
+	margin := c.Margin()
+	by := fmt.Sprintf("by %d", margin)
+	switch {
+	case margin < 0 && c.Success():
+		by = fmt.Sprintf("despite missing by %d", -margin)
+	case margin < 0:
+		by = fmt.Sprintf("by %d", -margin)
+	case !c.Success():
+		by = fmt.Sprintf("despite beating it by %d", margin)
+	}
+
+	note := ""
+	if c.Degrees && c.Result.Crit && !c.Result.Fumble {
+		note = ", natural 20"
+	} else if c.Degrees && c.Result.Fumble && !c.Result.Crit {
+		note = ", natural 1"
+	}
+	return fmt.Sprintf("%s %s (vs %d%s)", c.Degree(), by, c.Target, note)
+}
+
+// SplitCheck splits a roll against a target number, "d20+7 vs 15" or
+// "d20+7 dc15", into the notation to roll and the target
+// Notation without a target is returned as is, with ok false
+func SplitCheck(notation string) (rest string, target int, ok bool, err error) {
+	trimmed := strings.TrimRightFunc(notation, unicode.IsSpace)
+	end := len(trimmed)
+	for end > 0 && trimmed[end-1] >= '0' && trimmed[end-1] <= '9' {
+		end--
+	}
+	digits := trimmed[end:]
+	before := strings.TrimRightFunc(trimmed[:end], unicode.IsSpace)
+	lower := strings.ToLower(before)
+
+	keyword := ""
+	for _, k := range []string{"vs.", "vs", "dc"} {
+		if strings.HasSuffix(lower, k) {
+			keyword = k
+			break
+		}
+	}
+	if keyword == "" {
+		return notation, 0, false, nil
+	}
+	if digits == "" {
+		return "", 0, false, fmt.Errorf("expected a target number after '%s'", keyword)
+	}
+	rest = strings.TrimSpace(before[:len(before)-len(keyword)])
+	if rest == "" {
+		return "", 0, false, fmt.Errorf("expected dice before '%s'", keyword)
+	}
+	target, err = strconv.Atoi(digits)
+	if err != nil {
+		return "", 0, false, fmt.Errorf("invalid target number '%s'", digits)
+	}
+	return rest, target, true, nil
+}
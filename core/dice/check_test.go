@@ -0,0 +1,97 @@
+package dice
+
+import "testing"
+
+func TestSplitCheck(t *testing.T) {
+	tests := []struct {
+		notation string
+		rest     string
+		target   int
+		ok       bool
+		wantErr  bool
+	}{
+		{"d20+7 vs 15", "d20+7", 15, true, false},
+		{"d20+7 VS. 15", "d20+7", 15, true, false},
+		{"d20+7vs15", "d20+7", 15, true, false},
+		{"d20+7 dc15", "d20+7", 15, true, false},
+		{"d20+7dc 15", "d20+7", 15, true, false},
+		{"3x d20+5 vs 12", "3x d20+5", 12, true, false},
+		{"d20+7", "d20+7", 0, false, false},
+		{"4d6dh1", "4d6dh1", 0, false, false},
+		{"d20+7 vs", "", 0, false, true},
+		{"vs 15", "", 0, false, true},
+	}
+	for _, tt := range tests {
+		rest, target, ok, err := SplitCheck(tt.notation)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("SplitCheck(%q): expected error, got nil", tt.notation)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("SplitCheck(%q): unexpected error: %v", tt.notation, err)
+			continue
+		}
+		if rest != tt.rest || target != tt.target || ok != tt.ok {
+			t.Errorf("SplitCheck(%q) = %q, %d, %v, want %q, %d, %v", tt.notation, rest, target, ok, tt.rest, tt.target, tt.ok)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		total    int
+		target   int
+		success  bool
+		expected string
+	}{
+		{19, 15, true, "SUCCESS by 4 (vs 15)"},
+		{15, 15, true, "SUCCESS by 0 (vs 15)"},
+		{13, 15, false, "FAILURE by 2 (vs 15)"},
+	}
+	for _, tt := range tests {
+		check := Check{Result: &Result{Total: tt.total}, Target: tt.target}
+		if check.Success() != tt.success {
+			t.Errorf("%d vs %d: expected success %v", tt.total, tt.target, tt.success)
+		}
+		if got := check.String(); got != tt.expected {
+			t.Errorf("Expected '%s', got '%s'", tt.expected, got)
+		}
+	}
+}
+
+func TestCheckDegrees(t *testing.T) {
+	tests := []struct {
+		total    int
+		crit     bool
+		fumble   bool
+		degree   Degree
+		expected string
+	}{
+		{25, false, false, CriticalSuccess, "CRITICAL SUCCESS by 10 (vs 15)"},
+		{16, false, false, Success, "SUCCESS by 1 (vs 15)"},
+		{14, false, false, Failure, "FAILURE by 1 (vs 15)"},
+		{5, false, false, CriticalFailure, "CRITICAL FAILURE by 10 (vs 15)"},
+		{17, true, false, CriticalSuccess, "CRITICAL SUCCESS by 2 (vs 15, natural 20)"},
+		{13, true, false, Success, "SUCCESS despite missing by 2 (vs 15, natural 20)"},
+		{30, true, false, CriticalSuccess, "CRITICAL SUCCESS by 15 (vs 15, natural 20)"},
+		{16, false, true, Failure, "FAILURE despite beating it by 1 (vs 15, natural 1)"},
+		{8, false, true, CriticalFailure, "CRITICAL FAILURE by 7 (vs 15, natural 1)"},
+	}
+	for _, tt := range tests {
+		check := Check{Result: &Result{Total: tt.total, Crit: tt.crit, Fumble: tt.fumble}, Target: 15, Degrees: true}
+		if got := check.Degree(); got != tt.degree {
+			t.Errorf("%d vs 15: expected %s, got %s", tt.total, tt.degree, got)
+		}
+		if got := check.String(); got != tt.expected {
+			t.Errorf("Expected '%s', got '%s'", tt.expected, got)
+		}
+	}
+
+	// Without degrees, beating the target by 10 is just a success
+	check := Check{Result: &Result{Total: 25, Crit: true}, Target: 15}
+	if check.Degree() != Success || check.Critical() {
+		t.Errorf("Expected a plain success without degrees, got %s", check.Degree())
+	}
+}
@@ -0,0 +1,38 @@
+package dice
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Choose picks one of options at random, with the same roller as the dice
+func Choose(options []string) (string, error) {
+	if len(options) < 2 {
+		return "", fmt.Errorf("give at least two options to choose from")
+	}
+	n, err := rollDie(len(options))
+	if err != nil {
+		return "", err
+	}
+	return options[n-1], nil
+}
+
+// Flip flips a coin, returning "heads" or "tails"
+func Flip() (string, error) {
+	return Choose([]string{"heads", "tails"})
+}
+
+// SplitChoices splits the options for a choice, separated by commas if
+// there are any ("ancient red dragon, lich") or else by spaces
+func SplitChoices(input string) []string {
+	var options []string
+	if strings.Contains(input, ",") {
+		for _, option := range strings.Split(input, ",") {
+			if option = strings.TrimSpace(option); option != "" {
+				options = append(options, option)
+			}
+		}
+		return options
+	}
+	return strings.Fields(input)
+}
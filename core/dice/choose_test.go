@@ -0,0 +1,58 @@
+package dice
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestChoose(t *testing.T) {
+	defer SetRoller(CurrentRoller())
+
+	SetRoller(&scriptedRoller{values: []int{2, 3, 1}})
+	options := []string{"goblin", "orc", "kobold"}
+	for _, want := range []string{"orc", "kobold", "goblin"} {
+		got, err := Choose(options)
+		if err != nil {
+			t.Fatalf("Choose failed: %v", err)
+		}
+		if got != want {
+			t.Errorf("Expected '%s', got '%s'", want, got)
+		}
+	}
+
+	if _, err := Choose([]string{"goblin"}); err == nil {
+		t.Error("Expected an error choosing from one option")
+	}
+}
+
+func TestFlip(t *testing.T) {
+	defer SetRoller(CurrentRoller())
+
+	SetRoller(&scriptedRoller{values: []int{1, 2}})
+	for _, want := range []string{"heads", "tails"} {
+		got, err := Flip()
+		if err != nil {
+			t.Fatalf("Flip failed: %v", err)
+		}
+		if got != want {
+			t.Errorf("Expected '%s', got '%s'", want, got)
+		}
+	}
+}
+
+func TestSplitChoices(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{"heads tails", []string{"heads", "tails"}},
+		{"goblin  orc kobold", []string{"goblin", "orc", "kobold"}},
+		{"ancient red dragon, lich,, beholder", []string{"ancient red dragon", "lich", "beholder"}},
+		{"", []string{}},
+	}
+	for _, tt := range tests {
+		if got := SplitChoices(tt.input); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SplitChoices(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package dice
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// maxCustomDice caps how many custom dice one roll can throw
+const maxCustomDice = 100
+
+// CustomDie is a die with labeled faces, e.g. a scatter die with compass points
+type CustomDie struct {
+	Name  string
+	Faces []string
+}
+
+// NewCustomDie checks a custom die's name and faces
+// Names start with a letter (so "d20" stays a d20) and faces must not be empty
+func NewCustomDie(name string, faces []string) (*CustomDie, error) {
+	if name == "" || !unicode.IsLetter(rune(name[0])) {
+		return nil, fmt.Errorf("custom die names must start with a letter")
+	}
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '-' {
+			return nil, fmt.Errorf("custom die names can only have letters, digits, - and _")
+		}
+	}
+	if len(faces) < 2 {
+		return nil, fmt.Errorf("a die needs at least 2 faces")
+	}
+	for i, face := range faces {
+		faces[i] = strings.TrimSpace(face)
+		if faces[i] == "" {
+			return nil, fmt.Errorf("face %d is empty", i+1)
+		}
+	}
+	return &CustomDie{Name: name, Faces: faces}, nil
+}
+
+// FaceResult is the outcome of rolling custom dice: the faces that came up
+type FaceResult struct {
+	Die   *CustomDie
+	Rolls []int    // face numbers rolled (1-based)
+	Faces []string // the labels on those faces
+}
+
+// Roll throws count of the die
+func (d *CustomDie) Roll(count int) (*FaceResult, error) {
+	if count < 1 || count > maxCustomDice {
+		return nil, fmt.Errorf("can't roll %d custom dice (expected 1-%d)", count, maxCustomDice)
+	}
+	result := &FaceResult{Die: d}
+	for i := 0; i < count; i++ {
+		n, err := rollDie(len(d.Faces))
+		if err != nil {
+			return nil, err
+		}
+		result.Rolls = append(result.Rolls, n)
+		result.Faces = append(result.Faces, d.Faces[n-1])
+	}
+	return result, nil
+}
+
+// Notation returns how the roll was written, e.g. "2dhitdir" (or "dhitdir" for one)
+func (r *FaceResult) Notation() string {
+	if len(r.Rolls) == 1 {
+		return "d" + r.Die.Name
+	}
+	return fmt.Sprintf("%dd%s", len(r.Rolls), r.Die.Name)
+}
+
+// String formats the roll, e.g. "2dhitdir: [NE, S]"
+func (r *FaceResult) String() string {
+	return fmt.Sprintf("%s: [%s]", r.Notation(), strings.Join(r.Faces, ", "))
+}
+
+// Result returns the roll as a Result, so it can go in the roll log like any
+// other: each die shows its face, and the total adds up the face numbers
+func (r *FaceResult) Result() *Result {
+	result := &Result{
+		Expression: &Expression{Count: len(r.Rolls), Sides: len(r.Die.Faces), Custom: r.Die.Name},
+	}
+	for i, n := range r.Rolls {
+		result.Rolls = append(result.Rolls, Die{Value: n, Sides: len(r.Die.Faces), Kept: true, Face: r.Faces[i]})
+		result.KeptTotal += n
+	}
+	result.Total = result.KeptTotal
+	return result
+}
+
+// ParseCustom splits custom dice notation like "dhitdir" or "2dhitdir" into
+// the count and the die's name; ok is false if it isn't custom notation
+func ParseCustom(notation string) (count int, name string, ok bool) {
+	notation = strings.TrimSpace(notation)
+	i := 0
+	for i < len(notation) && unicode.IsDigit(rune(notation[i])) {
+		i++
+	}
+	if i+1 >= len(notation) || (notation[i] != 'd' && notation[i] != 'D') || !unicode.IsLetter(rune(notation[i+1])) {
+		return 0, "", false
+	}
+	count = 1
+	if i > 0 {
+		var err error
+		if count, err = strconv.Atoi(notation[:i]); err != nil {
+			return 0, "", false
+		}
+	}
+	return count, notation[i+1:], true
+}
+
+// CustomDice holds the custom dice defined this session
+type CustomDice struct {
+	dice map[string]*CustomDie // keyed by lowercase name
+	mu   sync.RWMutex
+}
+
+// NewCustomDice creates an empty set of custom dice
+func NewCustomDice() *CustomDice {
+	return &CustomDice{
+		dice: make(map[string]*CustomDie),
+	}
+}
+
+// Define adds (or replaces) a custom die
+func (c *CustomDice) Define(name string, faces []string) (*CustomDie, error) {
+	die, err := NewCustomDie(name, faces)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.dice[strings.ToLower(name)] = die
+	return die, nil
+}
+
+// Get retrieves a custom die by name (case-insensitive)
+func (c *CustomDice) Get(name string) *CustomDie {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.dice[strings.ToLower(name)]
+}
+
+// Delete removes a custom die
+func (c *CustomDice) Delete(name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := strings.ToLower(name)
+	if _, ok := c.dice[key]; !ok {
+		return fmt.Errorf("custom die '%s' not found", name)
+	}
+	delete(c.dice, key)
+	return nil
+}
+
+// List returns all custom dice sorted by name
+func (c *CustomDice) List() []*CustomDie {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	dice := make([]*CustomDie, 0, len(c.dice))
+	for _, d := range c.dice {
+		dice = append(dice, d)
+	}
+	sort.Slice(dice, func(i, j int) bool {
+		return strings.ToLower(dice[i].Name) < strings.ToLower(dice[j].Name)
+	})
+	return dice
+}
+
+// Roll rolls custom dice notation like "2dhitdir"
+func (c *CustomDice) Roll(notation string) (*FaceResult, error) {
+	count, name, ok := ParseCustom(notation)
+	if !ok {
+		return nil, fmt.Errorf("'%s' isn't custom dice notation (expected d<name> or <count>d<name>)", notation)
+	}
+	die := c.Get(name)
+	if die == nil {
+		return nil, fmt.Errorf("custom die '%s' not found (define it with 'dice define %s <face>,<face>,...')", name, name)
+	}
+	return die.Roll(count)
+}
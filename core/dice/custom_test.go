@@ -0,0 +1,72 @@
+package dice
+
+import "testing"
+
+func TestParseCustom(t *testing.T) {
+	tests := []struct {
+		notation string
+		count    int
+		name     string
+		ok       bool
+	}{
+		{"dhitdir", 1, "hitdir", true},
+		{"3dFate", 3, "Fate", true},
+		{"d20", 0, "", false},
+		{"d%", 0, "", false},
+		{"2d", 0, "", false},
+		{"hitdir", 0, "", false},
+	}
+	for _, tt := range tests {
+		count, name, ok := ParseCustom(tt.notation)
+		if count != tt.count || name != tt.name || ok != tt.ok {
+			t.Errorf("ParseCustom(%q) = %d, %q, %v, want %d, %q, %v", tt.notation, count, name, ok, tt.count, tt.name, tt.ok)
+		}
+	}
+}
+
+func TestCustomDice(t *testing.T) {
+	defer SetRoller(CurrentRoller())
+	SetRoller(NewSequenceRoller(2, 8))
+
+	c := NewCustomDice()
+	if _, err := c.Define("hitdir", []string{"N", " NE", "E", "SE", "S", "SW", "W", "NW"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	result, err := c.Roll("2dHitDir")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := result.String(); got != "2dhitdir: [NE, NW]" {
+		t.Errorf("Expected '2dhitdir: [NE, NW]', got '%s'", got)
+	}
+	if r := result.Result(); r.Expression.String() != "2dhitdir" || r.DiceString() != "[NE, NW]" || r.Total != 10 {
+		t.Errorf("Expected 2dhitdir [NE, NW] for 10, got %s %s for %d", r.Expression, r.DiceString(), r.Total)
+	}
+
+	if _, err := c.Roll("dscatter"); err == nil {
+		t.Error("Expected error for an undefined die")
+	}
+	if _, err := c.Roll("0dhitdir"); err == nil {
+		t.Error("Expected error for zero dice")
+	}
+	if err := c.Delete("HITDIR"); err != nil || c.Get("hitdir") != nil {
+		t.Errorf("Expected the die to be deleted, got %v", err)
+	}
+}
+
+func TestNewCustomDieErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		faces []string
+	}{
+		{"6", []string{"a", "b"}},
+		{"hit dir", []string{"a", "b"}},
+		{"coin", []string{"heads"}},
+		{"coin", []string{"heads", " "}},
+	}
+	for _, tt := range tests {
+		if _, err := NewCustomDie(tt.name, tt.faces); err == nil {
+			t.Errorf("Expected error for %q with faces %v", tt.name, tt.faces)
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 )
 
 // String returns a formatted string representation of the result
+// Each dice group's rolls are shown separately, e.g. "2d6+1d8+3: [4, 2] + [7] +3 = 16"
 func (r *Result) String() string {
 	if r == nil {
 		return "<nil result>"
@@ -13,77 +14,310 @@ func (r *Result) String() string {
 
 	var b strings.Builder
 
-	// Build the dice list with kept/dropped indication
-	keptDice := []string{}
-	droppedDice := []string{}
-	for _, die := range r.Rolls {
-		dieStr := fmt.Sprintf("%d", die.Value)
-		if die.Kept {
-			keptDice = append(keptDice, dieStr)
+	b.WriteString(r.Expression.String())
+	if r.Label != "" {
+		b.WriteString(fmt.Sprintf(" (%s)", r.Label))
+	}
+	b.WriteString(": ")
+
+	// Show all dice (kept and dropped), interleaved in the order they were rolled
+	b.WriteString(formatAllRolls(r))
+
+	// Show modifier if present
+	if r.Expression.Modifier != 0 {
+		if r.Expression.Modifier > 0 {
+			b.WriteString(fmt.Sprintf(" +%d", r.Expression.Modifier))
 		} else {
-			droppedDice = append(droppedDice, dieStr)
+			b.WriteString(fmt.Sprintf(" %d", r.Expression.Modifier))
 		}
 	}
 
-	// Reconstruct notation
-	notation := fmt.Sprintf("%dd%d", r.Expression.Count, r.Expression.Sides)
-	if r.Expression.Advantage {
-		notation += "!"
+	// Show total, after the total before halving or doubling and any bonus
+	if note := r.ScaleNote(); note != "" {
+		b.WriteString(" (" + note + ")")
 	}
-	if r.Expression.Operation != nil {
-		notation += formatOperation(r.Expression.Operation)
+	if bonus := r.BonusString(); bonus != "" {
+		b.WriteString(" " + bonus)
 	}
-	if r.Expression.Modifier != 0 {
-		if r.Expression.Modifier > 0 {
-			notation += fmt.Sprintf("+%d", r.Expression.Modifier)
+	b.WriteString(fmt.Sprintf(" = %d", r.Total))
+
+	// Add description if there are dropped dice
+	if notes := r.DropNotes(); len(notes) > 0 {
+		b.WriteString(" (")
+		b.WriteString(strings.Join(notes, "; "))
+		b.WriteString(")")
+	}
+
+	return b.String()
+}
+
+// BonusString describes a bonus from outside the notation, e.g.
+// "+2 (Aria +2 attack)", or "" when there's none
+func (r *Result) BonusString() string {
+	if r.Bonus == 0 {
+		return ""
+	}
+	if r.BonusNote == "" {
+		return fmt.Sprintf("%+d", r.Bonus)
+	}
+	return fmt.Sprintf("%+d (%s)", r.Bonus, r.BonusNote)
+}
+
+// ScaleNote describes the halving or doubling of a result's total, e.g.
+// "29 halved" for half(8d6), or "" when the total wasn't scaled
+func (r *Result) ScaleNote() string {
+	switch {
+	case r.Expression.Halve:
+		return fmt.Sprintf("%d halved", r.KeptTotal+r.Expression.Modifier)
+	case r.Expression.Double:
+		return fmt.Sprintf("%d doubled", r.KeptTotal+r.Expression.Modifier)
+	default:
+		return ""
+	}
+}
+
+// DiceString formats every group's dice, e.g. "[4, ‹2›] + [7]"
+func (r *Result) DiceString() string {
+	return formatAllRolls(r)
+}
+
+// formatAllRolls formats every group's dice, joined by the groups' signs
+func formatAllRolls(r *Result) string {
+	var b strings.Builder
+	b.WriteString(formatRolls(r.Rolls))
+	for _, g := range r.Groups {
+		if g.Group.Negative {
+			b.WriteString(" - ")
 		} else {
-			notation += fmt.Sprintf("%d", r.Expression.Modifier)
+			b.WriteString(" + ")
 		}
+		b.WriteString(formatRolls(g.Rolls))
 	}
+	return b.String()
+}
 
-	b.WriteString(notation)
-	b.WriteString(": ")
-
-	// Show all dice (kept and dropped)
-	b.WriteString("[")
-	
-	// Interleave kept and dropped dice in order as they were rolled
-	diceStrs := make([]string, len(r.Rolls))
-	for i, die := range r.Rolls {
+// formatRolls formats one group's dice, marking dropped dice with angle brackets
+// and raised dice with the value rolled ("1→2")
+func formatRolls(rolls []Die) string {
+	diceStrs := make([]string, len(rolls))
+	for i, die := range rolls {
+		value := die.ValueString()
+		if die.Original != 0 {
+			value = fmt.Sprintf("%d→%d", die.Original, die.Value)
+		}
 		if die.Kept {
-			diceStrs[i] = fmt.Sprintf("%d", die.Value)
+			diceStrs[i] = value
 		} else {
-			// Mark dropped dice with angle brackets
-			diceStrs[i] = fmt.Sprintf("‹%d›", die.Value)
+			diceStrs[i] = "‹" + value + "›"
 		}
 	}
-	b.WriteString(strings.Join(diceStrs, ", "))
-	b.WriteString("]")
+	return "[" + strings.Join(diceStrs, ", ") + "]"
+}
 
-	// Show modifier if present
-	if r.Expression.Modifier != 0 {
-		if r.Expression.Modifier > 0 {
-			b.WriteString(fmt.Sprintf(" +%d", r.Expression.Modifier))
+// ValueString formats a die's value, with the digits that read it for digit
+// dice, e.g. "35 (3·5)" for a d66, or the face's label for custom dice
+func (d Die) ValueString() string {
+	if d.Face != "" {
+		return d.Face
+	}
+	if len(d.Digits) == 0 {
+		return fmt.Sprintf("%d", d.Value)
+	}
+	digits := make([]string, len(d.Digits))
+	for i, digit := range d.Digits {
+		digits[i] = fmt.Sprintf("%d", digit)
+	}
+	return fmt.Sprintf("%d (%s)", d.Value, strings.Join(digits, "·"))
+}
+
+// PercentileDice splits a d100 value into the tens and ones d10s that show it,
+// the way percentile dice are read at the table (00 and 0 together make 100)
+func PercentileDice(value int) (tens, ones int) {
+	return value / 10 % 10 * 10, value % 10
+}
+
+// DropNotes describes why dice were dropped ("advantage", "kept highest 3"),
+// one note per dice group that dropped any
+func (r *Result) DropNotes() []string {
+	var notes []string
+	notes = append(notes, dropNotes(r.Rolls, r.Expression.firstGroup())...)
+	for _, g := range r.Groups {
+		notes = append(notes, dropNotes(g.Rolls, g.Group)...)
+	}
+	return notes
+}
+
+// dropNotes describes why a group's dice were dropped, if any were
+func dropNotes(rolls []Die, g *Group) []string {
+	rerolled, dropped, raised := false, false, false
+	for _, die := range rolls {
+		switch {
+		case die.Rerolled:
+			rerolled = true
+		case !die.Kept:
+			dropped = true
+		}
+		raised = raised || die.Original != 0
+	}
+
+	var notes []string
+	if rerolled {
+		notes = append(notes, formatRerollDescription(g.Reroll))
+	}
+	if raised {
+		notes = append(notes, fmt.Sprintf("raised to %d", g.Min))
+	}
+	if dropped {
+		if g.Advantage && g.Triple {
+			notes = append(notes, "triple advantage")
+		} else if g.Advantage {
+			notes = append(notes, "advantage")
+		} else if g.Disadvantage {
+			notes = append(notes, "disadvantage")
 		} else {
-			b.WriteString(fmt.Sprintf(" %d", r.Expression.Modifier))
+			for _, op := range g.Operations {
+				notes = append(notes, formatOperationDescription(op))
+			}
 		}
 	}
+	return notes
+}
 
-	// Show total
-	b.WriteString(fmt.Sprintf(" = %d", r.Total))
+// Breakdown itemizes a result for settling disputes: a line per dice group
+// following every die through its rerolls, minimum and drop, then the
+// modifier, any halving or doubling, and the total, e.g.
+// "2d6r1: 1 rerolled → 4, 6 = 10", "modifier +3", "total 13"
+func (r *Result) Breakdown() []string {
+	lines := []string{breakdownGroup(r.Rolls, r.Expression.firstGroup(), keptSum(r.Rolls))}
+	for _, g := range r.Groups {
+		lines = append(lines, breakdownGroup(g.Rolls, g.Group, g.KeptTotal))
+	}
+	if r.Expression.Modifier != 0 {
+		lines = append(lines, fmt.Sprintf("modifier %+d", r.Expression.Modifier))
+	}
+	if r.Expression.Halve {
+		lines = append(lines, r.ScaleNote()+", rounded down")
+	} else if r.Expression.Double {
+		lines = append(lines, r.ScaleNote())
+	}
+	return append(lines, fmt.Sprintf("total %d", r.Total))
+}
 
-	// Add description if there are dropped dice
-	if len(droppedDice) > 0 {
-		b.WriteString(" (")
-		if r.Expression.Advantage {
-			b.WriteString("advantage")
-		} else if r.Expression.Operation != nil {
-			b.WriteString(formatOperationDescription(r.Expression.Operation))
+// keptSum sums a group's kept dice
+func keptSum(rolls []Die) int {
+	kept := 0
+	for _, die := range rolls {
+		if die.Kept {
+			kept += die.Value
 		}
-		b.WriteString(")")
 	}
+	return kept
+}
 
-	return b.String()
+// breakdownGroup itemizes one group's dice, e.g. "-1d4: 3 = -3"
+func breakdownGroup(rolls []Die, g *Group, kept int) string {
+	var dice []string
+	chain := ""
+	for _, die := range rolls {
+		if die.Rerolled {
+			chain += fmt.Sprintf("%s rerolled → ", die.ValueString())
+			continue
+		}
+		value := die.ValueString()
+		if die.Original != 0 {
+			value = fmt.Sprintf("%d raised to %d", die.Original, die.Value)
+		}
+		if !die.Kept {
+			value += " (dropped)"
+		}
+		dice = append(dice, chain+value)
+		chain = ""
+	}
+	notation := formatGroup(g)
+	if g.Negative {
+		notation, kept = "-"+notation, -kept
+	}
+	return fmt.Sprintf("%s: %s = %d", notation, strings.Join(dice, ", "), kept)
+}
+
+// String reconstructs the canonical notation for the expression (e.g. "4d6kh3+2")
+func (e *Expression) String() string {
+	if e == nil {
+		return ""
+	}
+	if e.Custom != "" {
+		return fmt.Sprintf("%dd%s", e.Count, e.Custom)
+	}
+
+	notation := formatGroup(e.firstGroup())
+	for _, g := range e.Groups {
+		if g.Negative {
+			notation += "-"
+		} else {
+			notation += "+"
+		}
+		notation += formatGroup(g)
+	}
+	if e.Modifier != 0 {
+		if e.Modifier > 0 {
+			notation += fmt.Sprintf("+%d", e.Modifier)
+		} else {
+			notation += fmt.Sprintf("%d", e.Modifier)
+		}
+	}
+	if e.Halve {
+		notation = "half(" + notation + ")"
+	} else if e.Double {
+		notation = "double(" + notation + ")"
+	}
+	return notation
+}
+
+// formatGroup formats one dice group in notation (e.g. "4d6kh3")
+func formatGroup(g *Group) string {
+	notation := fmt.Sprintf("%dd%d", g.Count, g.Sides)
+	if g.Reroll != nil {
+		notation += formatReroll(g.Reroll)
+	}
+	if g.Min != 0 {
+		notation += fmt.Sprintf("min%d", g.Min)
+	}
+	if g.Advantage && g.Triple {
+		notation += "!!!"
+	} else if g.Advantage {
+		notation += "!"
+	}
+	if g.Disadvantage {
+		notation += "?"
+	}
+	for _, op := range g.Operations {
+		notation += formatOperation(op)
+	}
+	return notation
+}
+
+// formatReroll formats a reroll rule for display in notation (e.g. "ro<3")
+func formatReroll(r *Reroll) string {
+	notation := "r"
+	if r.Once {
+		notation += "o"
+	}
+	if r.Below {
+		notation += "<"
+	}
+	return notation + fmt.Sprintf("%d", r.Value)
+}
+
+// formatRerollDescription returns a human-readable description of a reroll rule
+func formatRerollDescription(r *Reroll) string {
+	description := fmt.Sprintf("rerolled %ds", r.Value)
+	if r.Below {
+		description = fmt.Sprintf("rerolled under %d", r.Value)
+	}
+	if r.Once {
+		description += " once"
+	}
+	return description
 }
 
 // formatOperation formats an operation for display in notation
@@ -0,0 +1,236 @@
+package dice
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// expressionJSON is an Expression as JSON: its canonical notation, which is
+// what's read back, with the parsed parts alongside for tools that don't want
+// to parse notation themselves
+type expressionJSON struct {
+	Notation     string          `json:"notation"`
+	Count        int             `json:"count"`
+	Sides        int             `json:"sides"`
+	Modifier     int             `json:"modifier"`
+	Operations   []operationJSON `json:"operations,omitempty"`
+	Advantage    bool            `json:"advantage,omitempty"`
+	Disadvantage bool            `json:"disadvantage,omitempty"`
+	Triple       bool            `json:"triple,omitempty"`
+	Reroll       *rerollJSON     `json:"reroll,omitempty"`
+	Min          int             `json:"min,omitempty"`
+	Groups       []groupJSON     `json:"groups,omitempty"`
+	Halve        bool            `json:"halve,omitempty"`
+	Double       bool            `json:"double,omitempty"`
+	Label        string          `json:"label,omitempty"`
+}
+
+// groupJSON is a further dice group as JSON
+type groupJSON struct {
+	Count        int             `json:"count"`
+	Sides        int             `json:"sides"`
+	Operations   []operationJSON `json:"operations,omitempty"`
+	Advantage    bool            `json:"advantage,omitempty"`
+	Disadvantage bool            `json:"disadvantage,omitempty"`
+	Triple       bool            `json:"triple,omitempty"`
+	Reroll       *rerollJSON     `json:"reroll,omitempty"`
+	Min          int             `json:"min,omitempty"`
+	Negative     bool            `json:"negative,omitempty"`
+}
+
+// operationJSON is a keep/drop operation as JSON, its type written as in
+// notation ("kh", "kl", "dh" or "dl")
+type operationJSON struct {
+	Type  string `json:"type"`
+	Count int    `json:"count"`
+}
+
+// rerollJSON is a reroll rule as JSON
+type rerollJSON struct {
+	Value int  `json:"value"`
+	Below bool `json:"below,omitempty"`
+	Once  bool `json:"once,omitempty"`
+}
+
+// resultJSON is a Result as JSON
+type resultJSON struct {
+	Expression *Expression       `json:"expression"`
+	Rolls      []dieJSON         `json:"rolls"`
+	Groups     []groupResultJSON `json:"groups,omitempty"`
+	KeptTotal  int               `json:"kept_total"`
+	Total      int               `json:"total"`
+	Crit       bool              `json:"crit,omitempty"`
+	Fumble     bool              `json:"fumble,omitempty"`
+	Label      string            `json:"label,omitempty"`
+	Bonus      int               `json:"bonus,omitempty"`
+	BonusNote  string            `json:"bonus_note,omitempty"`
+}
+
+// groupResultJSON is a further group's rolls as JSON; the group itself is in
+// the expression's groups, in the same order
+type groupResultJSON struct {
+	Rolls     []dieJSON `json:"rolls"`
+	KeptTotal int       `json:"kept_total"`
+}
+
+// dieJSON is a rolled die as JSON
+type dieJSON struct {
+	Value    int    `json:"value"`
+	Sides    int    `json:"sides"`
+	Kept     bool   `json:"kept"`
+	Rerolled bool   `json:"rerolled,omitempty"`
+	Original int    `json:"original,omitempty"`
+	Digits   []int  `json:"digits,omitempty"`
+	Face     string `json:"face,omitempty"`
+}
+
+// MarshalJSON writes the expression as its notation and parsed parts, e.g.
+// {"notation": "4d6kh3+2", "count": 4, "sides": 6, "modifier": 2, "operations": [{"type": "kh", "count": 3}]}
+func (e *Expression) MarshalJSON() ([]byte, error) {
+	return json.Marshal(expressionJSON{
+		Notation:     e.String(),
+		Count:        e.Count,
+		Sides:        e.Sides,
+		Modifier:     e.Modifier,
+		Operations:   operationsJSON(e.Operations),
+		Advantage:    e.Advantage,
+		Disadvantage: e.Disadvantage,
+		Triple:       e.Triple,
+		Reroll:       rerollToJSON(e.Reroll),
+		Min:          e.Min,
+		Groups:       groupsJSON(e.Groups),
+		Halve:        e.Halve,
+		Double:       e.Double,
+		Label:        e.Label,
+	})
+}
+
+// UnmarshalJSON reads an expression back from its notation (and label); the
+// parsed parts are only there for other tools, so they're not read
+func (e *Expression) UnmarshalJSON(data []byte) error {
+	var j expressionJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	if j.Notation == "" {
+		return fmt.Errorf("expression has no notation")
+	}
+	expr, err := Parse(j.Notation)
+	if err != nil {
+		return err
+	}
+	expr.Label = j.Label
+	*e = *expr
+	return nil
+}
+
+// MarshalJSON writes the result with its expression and every die rolled,
+// dropped and rerolled ones included
+func (r *Result) MarshalJSON() ([]byte, error) {
+	j := resultJSON{
+		Expression: r.Expression,
+		Rolls:      diceJSON(r.Rolls),
+		KeptTotal:  r.KeptTotal,
+		Total:      r.Total,
+		Crit:       r.Crit,
+		Fumble:     r.Fumble,
+		Label:      r.Label,
+		Bonus:      r.Bonus,
+		BonusNote:  r.BonusNote,
+	}
+	for _, g := range r.Groups {
+		j.Groups = append(j.Groups, groupResultJSON{Rolls: diceJSON(g.Rolls), KeptTotal: g.KeptTotal})
+	}
+	return json.Marshal(j)
+}
+
+// UnmarshalJSON reads a result back as it was written, linking each group's
+// rolls to the expression's group
+func (r *Result) UnmarshalJSON(data []byte) error {
+	var j resultJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	if j.Expression == nil {
+		return fmt.Errorf("result has no expression")
+	}
+	if len(j.Groups) != len(j.Expression.Groups) {
+		return fmt.Errorf("result has %d dice groups, but %s has %d", len(j.Groups)+1, j.Expression, len(j.Expression.Groups)+1)
+	}
+
+	*r = Result{
+		Expression: j.Expression,
+		Rolls:      diceFromJSON(j.Rolls),
+		KeptTotal:  j.KeptTotal,
+		Total:      j.Total,
+		Crit:       j.Crit,
+		Fumble:     j.Fumble,
+		Label:      j.Label,
+		Bonus:      j.Bonus,
+		BonusNote:  j.BonusNote,
+	}
+	r.Expression.Bonus, r.Expression.BonusNote = j.Bonus, j.BonusNote
+	for i, g := range j.Groups {
+		r.Groups = append(r.Groups, GroupResult{
+			Group:     j.Expression.Groups[i],
+			Rolls:     diceFromJSON(g.Rolls),
+			KeptTotal: g.KeptTotal,
+		})
+	}
+	return nil
+}
+
+// groupsJSON converts further dice groups for JSON
+func groupsJSON(groups []*Group) []groupJSON {
+	var j []groupJSON
+	for _, g := range groups {
+		j = append(j, groupJSON{
+			Count:        g.Count,
+			Sides:        g.Sides,
+			Operations:   operationsJSON(g.Operations),
+			Advantage:    g.Advantage,
+			Disadvantage: g.Disadvantage,
+			Triple:       g.Triple,
+			Reroll:       rerollToJSON(g.Reroll),
+			Min:          g.Min,
+			Negative:     g.Negative,
+		})
+	}
+	return j
+}
+
+// operationsJSON converts keep/drop operations for JSON
+func operationsJSON(ops []*Operation) []operationJSON {
+	var j []operationJSON
+	for _, op := range ops {
+		j = append(j, operationJSON{Type: strings.TrimRight(formatOperation(op), "0123456789"), Count: op.Count})
+	}
+	return j
+}
+
+// rerollToJSON converts a reroll rule for JSON (nil if there isn't one)
+func rerollToJSON(r *Reroll) *rerollJSON {
+	if r == nil {
+		return nil
+	}
+	return &rerollJSON{Value: r.Value, Below: r.Below, Once: r.Once}
+}
+
+// diceJSON converts rolled dice for JSON
+func diceJSON(rolls []Die) []dieJSON {
+	j := make([]dieJSON, len(rolls))
+	for i, d := range rolls {
+		j[i] = dieJSON{Value: d.Value, Sides: d.Sides, Kept: d.Kept, Rerolled: d.Rerolled, Original: d.Original, Digits: d.Digits, Face: d.Face}
+	}
+	return j
+}
+
+// diceFromJSON converts rolled dice back from JSON
+func diceFromJSON(j []dieJSON) []Die {
+	rolls := make([]Die, len(j))
+	for i, d := range j {
+		rolls[i] = Die{Value: d.Value, Sides: d.Sides, Kept: d.Kept, Rerolled: d.Rerolled, Original: d.Original, Digits: d.Digits, Face: d.Face}
+	}
+	return rolls
+}
@@ -0,0 +1,148 @@
+package dice
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// Limits caps how much work a roll can ask for, for notation from someone
+// who isn't at the table (a web request, piped input) where 1000d6+1000d6+...
+// shouldn't be able to tie up the host; 0 leaves a limit off
+type Limits struct {
+	Length      int // characters of notation
+	Groups      int // dice groups in one expression
+	Dice        int // dice rolled in all, counting (dis)advantage and the rerolls a rule is expected to need
+	Faces       int // faces on one die (a d66 has 36)
+	Repeat      int // times a roll is repeated (the 6 in 6x4d6kh3)
+	Simulations int // rolls in one simulation
+}
+
+// StrictLimits are the limits for untrusted notation, still well above any
+// roll made at a real table. Stats need no limit of their own: Analyze
+// simulates anything too big to work out exactly
+var StrictLimits = Limits{
+	Length:      200,
+	Groups:      20,
+	Dice:        500,
+	Faces:       1000,
+	Repeat:      20,
+	Simulations: 100000,
+}
+
+// LimitError is notation (or a repeat or simulation) going over a limit
+type LimitError struct {
+	Limit string // "length", "groups", "dice", "faces", "repeat" or "simulations"
+	Max   int
+	Got   int
+}
+
+func (e *LimitError) Error() string {
+	switch e.Limit {
+	case "length":
+		return fmt.Sprintf("notation is %d characters long (max %d)", e.Got, e.Max)
+	case "groups":
+		return fmt.Sprintf("too many dice groups: %d (max %d)", e.Got, e.Max)
+	case "dice":
+		return fmt.Sprintf("too many dice: %d to roll (max %d)", e.Got, e.Max)
+	case "faces":
+		return fmt.Sprintf("die has too many faces: %d (max %d)", e.Got, e.Max)
+	case "repeat":
+		return fmt.Sprintf("can't repeat a roll %d times (max %d)", e.Got, e.Max)
+	default:
+		return fmt.Sprintf("too many %s: %d (max %d)", e.Limit, e.Got, e.Max)
+	}
+}
+
+// over returns a LimitError if got is over a limit that's set
+func over(limit string, max, got int) error {
+	if max > 0 && got > max {
+		return &LimitError{Limit: limit, Max: max, Got: got}
+	}
+	return nil
+}
+
+// ParseLimited parses notation like Parse, refusing notation too long to
+// parse or an expression that would go over the limits to roll
+func ParseLimited(notation string, l Limits) (*Expression, error) {
+	if err := over("length", l.Length, utf8.RuneCountInString(notation)); err != nil {
+		return nil, err
+	}
+	expr, err := Parse(notation)
+	if err != nil {
+		return nil, err
+	}
+	if err := l.Check(expr); err != nil {
+		return nil, err
+	}
+	return expr, nil
+}
+
+// Check returns a LimitError if rolling expr would go over the limits
+func (l Limits) Check(expr *Expression) error {
+	groups := append([]*Group{expr.firstGroup()}, expr.Groups...)
+	if err := over("groups", l.Groups, len(groups)); err != nil {
+		return err
+	}
+
+	dice := 0
+	for _, g := range groups {
+		if err := over("faces", l.Faces, g.faceCount()); err != nil {
+			return err
+		}
+		dice += g.diceToRoll()
+		if err := over("dice", l.Dice, dice); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// diceToRoll returns how many dice rolling expr takes, counting (dis)advantage
+// and the rerolls a rule is expected to need
+func (expr *Expression) diceToRoll() int {
+	dice := 0
+	for _, g := range append([]*Group{expr.firstGroup()}, expr.Groups...) {
+		dice += g.diceToRoll()
+	}
+	return dice
+}
+
+// diceToRoll returns how many dice rolling the group takes
+func (g *Group) diceToRoll() int {
+	perDie := g.rollsPerDie()
+	if _, digits := digitDice(g.Sides); digits > 0 {
+		perDie *= digits
+	}
+	return g.Count * perDie * expectedRerolls(g.Reroll, g.faceCount())
+}
+
+// CheckRepeat returns a LimitError if a roll repeated n times is over the limits
+func (l Limits) CheckRepeat(n int) error {
+	return over("repeat", l.Repeat, n)
+}
+
+// CheckSimulations returns a LimitError if a simulation of n rolls is over the limits
+func (l Limits) CheckSimulations(n int) error {
+	return over("simulations", l.Simulations, n)
+}
+
+// expectedRerolls returns how many times a die is expected to be rolled
+// under a reroll rule, rounded up: twice at most for ro, but r<999 on a d1000
+// keeps going for about 500 rolls
+func expectedRerolls(r *Reroll, faces int) int {
+	if r == nil {
+		return 1
+	}
+	if r.Once {
+		return 2
+	}
+	rerolled := 1
+	if r.Below {
+		rerolled = r.Value - 1
+	}
+	if rerolled >= faces {
+		return faces
+	}
+	stay := faces - rerolled
+	return (faces + stay - 1) / stay
+}
@@ -0,0 +1,84 @@
+package dice
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseLimited(t *testing.T) {
+	tests := []struct {
+		notation string
+		limit    string // "" if it's within the limits
+	}{
+		{"d20+5", ""},
+		{"4d6kh3", ""},
+		{"2d20!+1d4", ""},
+		{"8d6min2ro<3", ""},
+		{"d66", ""},
+		{"100d1000", ""},
+		{strings.Repeat("d6+", 70) + "1", "length"},
+		{strings.Repeat("d6+", 20) + "d6", "groups"},
+		{"501d6", "dice"},
+		{"300d20!", "dice"},
+		{"400d6+200d6", "dice"},
+		{"200d1000r<999", "dice"},
+		{"d1001", "faces"},
+		{"d6666", "faces"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.notation, func(t *testing.T) {
+			_, err := ParseLimited(tt.notation, StrictLimits)
+			var limitErr *LimitError
+			switch {
+			case tt.limit == "" && err != nil:
+				t.Errorf("Expected no error, got %v", err)
+			case tt.limit != "" && !errors.As(err, &limitErr):
+				t.Errorf("Expected a %s limit error, got %v", tt.limit, err)
+			case tt.limit != "" && limitErr.Limit != tt.limit:
+				t.Errorf("Expected a %s limit error, got %s: %v", tt.limit, limitErr.Limit, err)
+			}
+		})
+	}
+}
+
+func TestParseLimitedErrors(t *testing.T) {
+	// Notation that doesn't parse gets the parse error, and a limit left at 0 is off
+	if _, err := ParseLimited("4d6k3", StrictLimits); !errors.As(err, new(*ParseError)) {
+		t.Errorf("Expected a parse error, got %v", err)
+	}
+	if _, err := ParseLimited("1000d6", Limits{}); err != nil {
+		t.Errorf("Expected no error with no limits, got %v", err)
+	}
+}
+
+func TestLimitsCheckCounts(t *testing.T) {
+	if err := StrictLimits.CheckRepeat(20); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if err := StrictLimits.CheckRepeat(21); err == nil {
+		t.Error("Expected an error repeating 21 times, got nil")
+	}
+	if err := StrictLimits.CheckSimulations(MaxSimulations); err == nil || err.Error() != "too many simulations: 1000000 (max 100000)" {
+		t.Errorf("Expected a simulations limit error, got %v", err)
+	}
+}
+
+func TestStrictStatsBounded(t *testing.T) {
+	// Twenty 2d1000s are within the strict limits, and took 18 seconds to
+	// work out exactly
+	notation := strings.TrimSuffix(strings.Repeat("2d1000+", 20), "+")
+	expr, err := ParseLimited(notation, StrictLimits)
+	if err != nil {
+		t.Fatalf("Expected %s within the strict limits, got %v", notation, err)
+	}
+	start := time.Now()
+	if _, err := Analyze(expr); err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected stats well under a second, took %s", elapsed)
+	}
+}
@@ -5,24 +5,237 @@ import (
 	"strconv"
 	"strings"
 	"unicode"
+	"unicode/utf8"
 )
 
+// typographyReplacer maps characters that sneak in when notation is pasted
+// from PDFs and notes (Unicode minus, en/em dashes, ×) to their plain forms
+var typographyReplacer = strings.NewReplacer(
+	"\u2212", "-", // minus sign
+	"\u2010", "-", // hyphen
+	"\u2011", "-", // non-breaking hyphen
+	"\u2012", "-", // figure dash
+	"\u2013", "-", // en dash
+	"\u2014", "-", // em dash
+	"\ufe63", "-", // small hyphen-minus
+	"\uff0d", "-", // fullwidth hyphen-minus
+	"\uff0b", "+", // fullwidth plus
+	"\u00d7", "x", // multiplication sign
+)
+
+// normalize strips whitespace (including non-breaking spaces) and replaces
+// typographic characters so pasted notation parses like typed notation
+func normalize(notation string) string {
+	notation = typographyReplacer.Replace(notation)
+	notation = strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, notation)
+	return strings.ToLower(notation) // Case-insensitive
+}
+
 // Parse parses a dice notation string into an Expression
-// Supports: XdY, XdY+Z, XdY!, XdYkhN, XdYdlN, etc.
+// Supports: XdY, Xd%, XdY+Z, XdY!, XdYkhN, XdYdlN, XdYminN, etc., and several dice groups
+// and constants added together, e.g. 2d6+1d8+3 or 1d20+1d4-2
+// A trailing "# comment" becomes the expression's Label
 func Parse(notation string) (*Expression, error) {
+	notation, label := SplitLabel(notation)
 	if notation == "" {
 		return nil, fmt.Errorf("empty dice notation")
 	}
 
-	// Remove all whitespace for easier parsing
-	notation = strings.ReplaceAll(notation, " ", "")
-	notation = strings.ToLower(notation) // Case-insensitive
+	// Remove all whitespace and typographic characters for easier parsing
+	normalized := normalize(notation)
+	notation, halve, double, err := splitScale(normalized)
+	if err != nil {
+		return nil, err
+	}
+	// Errors point into the whole notation, not just what half(...) wraps
+	offset := strings.Index(normalized, notation)
+	located := func(err error) error {
+		if parseErr, ok := err.(*ParseError); ok {
+			parseErr.Notation = normalized
+			parseErr.Pos += offset
+		}
+		return err
+	}
 
+	// The first term must be a dice group
+	first, i, err := parseGroup(notation, 0)
+	if err != nil {
+		return nil, located(err)
+	}
 	expr := &Expression{
-		Count: 1, // Default to 1 die
+		Count:        first.Count,
+		Sides:        first.Sides,
+		Operations:   first.Operations,
+		Advantage:    first.Advantage,
+		Disadvantage: first.Disadvantage,
+		Triple:       first.Triple,
+		Reroll:       first.Reroll,
+		Min:          first.Min,
+		Halve:        halve,
+		Double:       double,
+		Label:        label,
+	}
+
+	// Each further term is a signed dice group or constant
+	for i < len(notation) {
+		sign := notation[i]
+		if sign != '+' && sign != '-' {
+			return nil, located(errAt(i, "unexpected '%c' (expected + or - before the next term)", sign))
+		}
+		i++
+
+		if !isGroupStart(notation, i) {
+			// Constant modifier
+			if i >= len(notation) || !unicode.IsDigit(rune(notation[i])) {
+				return nil, located(errAt(i, "expected number after '%c'", sign))
+			}
+			start := i
+			for i < len(notation) && unicode.IsDigit(rune(notation[i])) {
+				i++
+			}
+			modifier, err := strconv.Atoi(notation[start:i])
+			if err != nil {
+				return nil, fmt.Errorf("invalid modifier")
+			}
+			if sign == '-' {
+				modifier = -modifier
+			}
+			expr.Modifier += modifier
+			continue
+		}
+
+		group, next, err := parseGroup(notation, i)
+		if err != nil {
+			return nil, located(err)
+		}
+		group.Negative = sign == '-'
+		expr.Groups = append(expr.Groups, group)
+		i = next
 	}
 
+	return expr, nil
+}
+
+// splitScale takes halving or doubling off normalized notation, written as a
+// wrapper, half(8d6) or double(2d6+3), or a postfix, 8d6/2 or 2d6+3*2
+func splitScale(notation string) (string, bool, bool, error) {
+	halve, double := false, false
+	for _, wrapper := range []string{"half(", "double("} {
+		if strings.HasPrefix(notation, wrapper) {
+			if !strings.HasSuffix(notation, ")") {
+				return "", false, false, fmt.Errorf("missing ')' to close %s", wrapper)
+			}
+			notation = notation[len(wrapper) : len(notation)-1]
+			halve, double = wrapper == "half(", wrapper == "double("
+			break
+		}
+	}
+	for _, postfix := range []string{"/2", "*2", "x2"} {
+		if !strings.HasSuffix(notation, postfix) {
+			continue
+		}
+		if halve || double {
+			return "", false, false, fmt.Errorf("can only halve or double a roll once")
+		}
+		notation = strings.TrimSuffix(notation, postfix)
+		halve, double = postfix == "/2", postfix != "/2"
+		break
+	}
+	if notation == "" {
+		return "", false, false, fmt.Errorf("empty dice notation")
+	}
+	return notation, halve, double, nil
+}
+
+// SplitLabel splits a trailing comment off a roll, so "2d6+3 # greatsword damage"
+// gives "2d6+3" and "greatsword damage"
+func SplitLabel(notation string) (string, string) {
+	notation, label, _ := strings.Cut(notation, "#")
+	return strings.TrimSpace(notation), strings.TrimSpace(label)
+}
+
+// MaxRepeat is the most times one command can repeat a roll
+const MaxRepeat = 100
+
+// SplitRepeat splits a repeated roll like "6x4d6kh3" or "3x d20+7" into the
+// number of times to roll and the notation to roll
+// Notation without an "Nx" prefix is returned as is, to be rolled once
+func SplitRepeat(notation string) (int, string, error) {
+	trimmed := typographyReplacer.Replace(strings.TrimSpace(notation))
 	i := 0
+	for i < len(trimmed) && unicode.IsDigit(rune(trimmed[i])) {
+		i++
+	}
+	if i == 0 || i == len(trimmed) || (trimmed[i] != 'x' && trimmed[i] != 'X') {
+		return 1, notation, nil
+	}
+
+	// "2x6" isn't a repeat: what follows must start with a die (or a
+	// letter, for a saved roll's name)
+	rest := strings.TrimSpace(trimmed[i+1:])
+	if rest == "" || !(isGroupStart(normalize(rest), 0) || unicode.IsLetter(rune(rest[0]))) {
+		return 1, notation, nil
+	}
+	count, err := strconv.Atoi(trimmed[:i])
+	if err != nil || count < 1 || count > MaxRepeat {
+		return 0, "", fmt.Errorf("can't repeat a roll %s times (expected 1-%d)", trimmed[:i], MaxRepeat)
+	}
+	return count, rest, nil
+}
+
+// checkReroll rejects reroll rules that could never trigger or never stop
+func checkReroll(r *Reroll, sides int) error {
+	if r.Below {
+		if r.Value < 2 || r.Value > sides {
+			return fmt.Errorf("reroll under %d doesn't fit a d%d", r.Value, sides)
+		}
+		return nil
+	}
+	if r.Value < 1 || r.Value > sides {
+		return fmt.Errorf("can't reroll %ds on a d%d", r.Value, sides)
+	}
+	return nil
+}
+
+// checkOperation rejects keep/drop operations that ask for more dice than the
+// group rolls, or that would drop every die
+func checkOperation(op *Operation, count int) error {
+	dice := "dice"
+	if count == 1 {
+		dice = "die"
+	}
+	switch op.Type {
+	case OpKeepHighest, OpKeepLowest:
+		if op.Count > count {
+			return fmt.Errorf("can't keep %d of %d %s", op.Count, count, dice)
+		}
+	case OpDropHighest, OpDropLowest:
+		if op.Count >= count {
+			return fmt.Errorf("can't drop %d of %d %s: nothing would be left", op.Count, count, dice)
+		}
+	}
+	return nil
+}
+
+// isGroupStart reports whether a dice group (rather than a constant) starts at i
+func isGroupStart(notation string, i int) bool {
+	for i < len(notation) && unicode.IsDigit(rune(notation[i])) {
+		i++
+	}
+	return i < len(notation) && notation[i] == 'd'
+}
+
+// parseGroup parses one dice group (XdY with optional advantage and keep/drop)
+// starting at i, returning the group and the position after it
+func parseGroup(notation string, i int) (*Group, int, error) {
+	group := &Group{
+		Count: 1, // Default to 1 die
+	}
 
 	// Step 1: Parse optional count (digits before 'd')
 	start := i
@@ -32,54 +245,136 @@ func Parse(notation string) (*Expression, error) {
 	if i > start {
 		count, err := strconv.Atoi(notation[start:i])
 		if err != nil {
-			return nil, fmt.Errorf("invalid die count")
+			return nil, i, fmt.Errorf("invalid die count")
 		}
 		if count < 1 {
-			return nil, fmt.Errorf("die count must be at least 1")
+			return nil, i, errAt(start, "die count must be at least 1")
 		}
 		if count > 1000 {
-			return nil, fmt.Errorf("die count too large (max 1000)")
+			return nil, i, errAt(start, "die count too large (max 1000)")
 		}
-		expr.Count = count
+		group.Count = count
 	}
 
 	// Step 2: Parse 'd'
 	if i >= len(notation) || notation[i] != 'd' {
-		return nil, fmt.Errorf("expected 'd' at position %d", i)
+		return nil, i, suggestAt(errAt(i, "expected 'd'"), notation, i)
 	}
 	i++
 
-	// Step 3: Parse sides (digits after 'd')
-	if i >= len(notation) || !unicode.IsDigit(rune(notation[i])) {
-		return nil, fmt.Errorf("expected number of sides after 'd'")
-	}
-	start = i
-	for i < len(notation) && unicode.IsDigit(rune(notation[i])) {
+	// Step 3: Parse sides (digits after 'd', or % for percentile dice)
+	if i < len(notation) && notation[i] == '%' {
+		group.Sides = 100
 		i++
+	} else {
+		if i >= len(notation) || !unicode.IsDigit(rune(notation[i])) {
+			return nil, i, errAt(i, "expected number of sides after 'd'")
+		}
+		start = i
+		for i < len(notation) && unicode.IsDigit(rune(notation[i])) {
+			i++
+		}
+		sides, err := strconv.Atoi(notation[start:i])
+		if err != nil {
+			return nil, i, fmt.Errorf("invalid die sides")
+		}
+		if sides < 2 {
+			return nil, i, errAt(start, "die must have at least 2 sides")
+		}
+		group.Sides = sides
 	}
-	sides, err := strconv.Atoi(notation[start:i])
-	if err != nil {
-		return nil, fmt.Errorf("invalid die sides")
-	}
-	if sides < 2 {
-		return nil, fmt.Errorf("die must have at least 2 sides")
-	}
-	expr.Sides = sides
 
-	// Step 4: Parse optional advantage (!), operation (kh/kl/dh/dl), and modifier (+/-)
-	for i < len(notation) {
+	// Step 4: Parse optional advantage (!, or !!! or adv3 for triple advantage),
+	// disadvantage (?), reroll (r1, ro<3), minimum (min2) and operation
+	// (kh/kl/dh/dl), stopping at the next term's sign
+	for i < len(notation) && notation[i] != '+' && notation[i] != '-' {
 		ch := notation[i]
 
 		switch ch {
 		case '!':
-			// Advantage
-			expr.Advantage = true
+			// Advantage, or triple advantage (!!!)
+			group.Advantage = true
+			switch {
+			case strings.HasPrefix(notation[i:], "!!!"):
+				group.Triple = true
+				i += 3
+			case strings.HasPrefix(notation[i:], "!!"):
+				return nil, i, &ParseError{Pos: i, Msg: "'!!' isn't advantage: use ! for advantage or !!! to roll three times", Suggestion: "!"}
+			default:
+				i++
+			}
+
+		case 'a':
+			// Triple advantage (adv3)
+			if !strings.HasPrefix(notation[i:], "adv3") {
+				return nil, i, suggestAt(errAt(i, "unexpected '%c'", ch), notation, i)
+			}
+			group.Advantage = true
+			group.Triple = true
+			i += 4
+
+		case '?':
+			// Disadvantage
+			group.Disadvantage = true
 			i++
 
+		case 'r':
+			// Reroll (r1, r<3, ro1, ro<3)
+			reroll := &Reroll{}
+			rerollAt := i
+			i++
+			if i < len(notation) && notation[i] == 'o' {
+				reroll.Once = true
+				i++
+			}
+			if i < len(notation) && notation[i] == '<' {
+				reroll.Below = true
+				i++
+			}
+			if i >= len(notation) || !unicode.IsDigit(rune(notation[i])) {
+				return nil, i, errAt(i, "expected number after reroll")
+			}
+			start = i
+			for i < len(notation) && unicode.IsDigit(rune(notation[i])) {
+				i++
+			}
+			value, err := strconv.Atoi(notation[start:i])
+			if err != nil {
+				return nil, i, fmt.Errorf("invalid reroll value")
+			}
+			reroll.Value = value
+			if err := checkReroll(reroll, group.Sides); err != nil {
+				return nil, i, errAt(rerollAt, "%s", err)
+			}
+			group.Reroll = reroll
+
+		case 'm':
+			// Minimum per die (min2)
+			if !strings.HasPrefix(notation[i:], "min") {
+				return nil, i, suggestAt(errAt(i, "unexpected '%c'", ch), notation, i)
+			}
+			minAt := i
+			i += 3
+			if i >= len(notation) || !unicode.IsDigit(rune(notation[i])) {
+				return nil, i, errAt(i, "expected number after min")
+			}
+			start = i
+			for i < len(notation) && unicode.IsDigit(rune(notation[i])) {
+				i++
+			}
+			value, err := strconv.Atoi(notation[start:i])
+			if err != nil {
+				return nil, i, fmt.Errorf("invalid minimum")
+			}
+			if value < 2 || value > group.Sides {
+				return nil, i, errAt(minAt, "min%d can't work on a d%d (expected min2 to min%d)", value, group.Sides, group.Sides)
+			}
+			group.Min = value
+
 		case 'k', 'd':
 			// Operation (kh, kl, dh, dl)
 			if i+1 >= len(notation) {
-				return nil, fmt.Errorf("incomplete operation at position %d", i)
+				return nil, i, suggestAt(errAt(i, "incomplete operation"), notation, i)
 			}
 			op := notation[i : i+2]
 			var opType OpType
@@ -93,13 +388,14 @@ func Parse(notation string) (*Expression, error) {
 			case "dl":
 				opType = OpDropLowest
 			default:
-				return nil, fmt.Errorf("unknown operation: %s (expected kh, kl, dh, or dl)", op)
+				return nil, i, suggestAt(errAt(i, "unknown operation: %s (expected kh, kl, dh, or dl)", op), notation, i)
 			}
+			opAt := i
 			i += 2
 
 			// Parse count after operation
 			if i >= len(notation) || !unicode.IsDigit(rune(notation[i])) {
-				return nil, fmt.Errorf("expected number after operation %s", op)
+				return nil, i, errAt(i, "expected number after operation %s", op)
 			}
 			start = i
 			for i < len(notation) && unicode.IsDigit(rune(notation[i])) {
@@ -107,42 +403,98 @@ func Parse(notation string) (*Expression, error) {
 			}
 			count, err := strconv.Atoi(notation[start:i])
 			if err != nil {
-				return nil, fmt.Errorf("invalid operation count")
+				return nil, i, fmt.Errorf("invalid operation count")
 			}
 			if count < 1 {
-				return nil, fmt.Errorf("operation count must be at least 1")
+				return nil, i, errAt(opAt, "operation count must be at least 1")
 			}
 
-			expr.Operation = &Operation{
+			group.Operations = append(group.Operations, &Operation{
 				Type:  opType,
 				Count: count,
-			}
-
-		case '+', '-':
-			// Modifier
-			sign := ch
-			i++
-			if i >= len(notation) || !unicode.IsDigit(rune(notation[i])) {
-				return nil, fmt.Errorf("expected number after '%c'", sign)
-			}
-			start = i
-			for i < len(notation) && unicode.IsDigit(rune(notation[i])) {
-				i++
-			}
-			modifier, err := strconv.Atoi(notation[start:i])
-			if err != nil {
-				return nil, fmt.Errorf("invalid modifier")
-			}
-			if sign == '-' {
-				modifier = -modifier
-			}
-			expr.Modifier = modifier
+			})
 
 		default:
-			return nil, fmt.Errorf("unexpected character '%c' at position %d", ch, i)
+			return nil, i, suggestAt(errAt(i, "unexpected '%c'", ch), notation, i)
 		}
 	}
 
-	return expr, nil
+	if group.Advantage && group.Disadvantage {
+		return nil, i, fmt.Errorf("can't roll with both advantage (!) and disadvantage (?)")
+	}
+	kept := group.Count
+	for _, op := range group.Operations {
+		if err := checkOperation(op, kept); err != nil {
+			return nil, i, err
+		}
+		kept = op.remaining(kept)
+	}
+	return group, i, nil
+}
+
+// ParseError is a dice notation error at a spot in the notation, with a
+// suggestion when it looks like a near miss (k3 for kh3)
+type ParseError struct {
+	Notation   string // the notation as read: lowercase, without spaces
+	Pos        int    // byte offset of the error in Notation
+	Msg        string
+	Suggestion string // what was probably meant, e.g. "kh3" ("" if nothing close)
+}
+
+// Error returns the message, with the suggestion if there is one
+func (e *ParseError) Error() string {
+	if e.Suggestion != "" {
+		return fmt.Sprintf("%s (did you mean %s?)", e.Msg, e.Suggestion)
+	}
+	return e.Msg
+}
+
+// Caret returns the notation and a line with a caret under the error, e.g.
+// "4d6k3" and "   ^"
+func (e *ParseError) Caret() []string {
+	column := utf8.RuneCountInString(e.Notation[:min(e.Pos, len(e.Notation))])
+	return []string{e.Notation, strings.Repeat(" ", column) + "^"}
+}
+
+// errAt returns a ParseError at position i of the notation being parsed
+func errAt(i int, format string, args ...any) *ParseError {
+	return &ParseError{Pos: i, Msg: fmt.Sprintf(format, args...)}
 }
 
+// nearMisses maps letters typed by mistake to the notation they were probably
+// meant as
+var nearMisses = map[string]string{
+	"k": "kh", "hk": "kh", "kk": "kh", "lk": "kl",
+	"d": "dl", "ld": "dl", "hd": "dh",
+	"mi": "min", "mn": "min", "mni": "min", "mim": "min",
+	"adv": "!", "advantage": "!",
+	"dis": "?", "disadv": "?", "disadvantage": "?",
+	"x": "d",
+}
+
+// suggestAt adds a suggestion to err if the letters at position i of the
+// notation are a near miss, e.g. "kh3" for "k3" or "hk3", or "!" for "adv"
+func suggestAt(err *ParseError, notation string, i int) *ParseError {
+	end := i
+	for end < len(notation) && notation[end] >= 'a' && notation[end] <= 'z' {
+		end++
+	}
+	fix, ok := nearMisses[notation[i:end]]
+	if !ok && end-i == 2 && notation[i] == 'k' {
+		fix, ok = "kh", true // kx3: keep, but neither highest nor lowest
+	}
+	if !ok {
+		return err
+	}
+
+	digits := end
+	for digits < len(notation) && unicode.IsDigit(rune(notation[digits])) {
+		digits++
+	}
+	if fix == "!" || fix == "?" {
+		err.Suggestion = fix
+	} else if digits > end {
+		err.Suggestion = fix + notation[end:digits]
+	}
+	return err
+}
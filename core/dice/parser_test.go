@@ -1,6 +1,8 @@
 package dice
 
 import (
+	"errors"
+	"strings"
 	"testing"
 )
 
@@ -90,6 +92,40 @@ func TestParse_Advantage(t *testing.T) {
 	}
 }
 
+func TestParse_TripleAdvantage(t *testing.T) {
+	tests := []struct {
+		notation string
+		canon    string
+	}{
+		{"d20!!!", "1d20!!!"},
+		{"d20!!!+7", "1d20!!!+7"},
+		{"d20adv3", "1d20!!!"},
+		{"D20 ADV3 + 7", "1d20!!!+7"},
+		{"2d20!!!kh1", "2d20!!!kh1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.notation, func(t *testing.T) {
+			got, err := Parse(tt.notation)
+			if err != nil {
+				t.Fatalf("Parse(%q) unexpected error: %v", tt.notation, err)
+			}
+			if !got.Advantage || !got.Triple {
+				t.Errorf("Parse(%q) = Adv:%v Triple:%v, want both", tt.notation, got.Advantage, got.Triple)
+			}
+			if got.String() != tt.canon {
+				t.Errorf("Parse(%q).String() = %q, want %q", tt.notation, got.String(), tt.canon)
+			}
+		})
+	}
+
+	for _, notation := range []string{"d20!!", "d20adv", "d20!!!?"} {
+		if _, err := Parse(notation); err == nil {
+			t.Errorf("Parse(%q) expected error", notation)
+		}
+	}
+}
+
 func TestParse_Operations(t *testing.T) {
 	tests := []struct {
 		notation string
@@ -110,18 +146,37 @@ func TestParse_Operations(t *testing.T) {
 				t.Errorf("Parse(%q) unexpected error: %v", tt.notation, err)
 				return
 			}
-			if got.Operation == nil {
-				t.Errorf("Parse(%q) Operation is nil", tt.notation)
+			if len(got.Operations) != 1 {
+				t.Errorf("Parse(%q) Operations = %d, want 1", tt.notation, len(got.Operations))
 				return
 			}
-			if got.Operation.Type != tt.wantOp || got.Operation.Count != tt.wantCnt {
+			if op := got.Operations[0]; op.Type != tt.wantOp || op.Count != tt.wantCnt {
 				t.Errorf("Parse(%q) Operation = Type:%v Count:%d, want Type:%v Count:%d",
-					tt.notation, got.Operation.Type, got.Operation.Count, tt.wantOp, tt.wantCnt)
+					tt.notation, op.Type, op.Count, tt.wantOp, tt.wantCnt)
 			}
 		})
 	}
 }
 
+func TestParse_ChainedOperations(t *testing.T) {
+	expr, err := Parse("6d6dl1kh4")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(expr.Operations) != 2 {
+		t.Fatalf("Expected 2 operations, got %d", len(expr.Operations))
+	}
+	if op := expr.Operations[0]; op.Type != OpDropLowest || op.Count != 1 {
+		t.Errorf("Expected dl1 first, got Type:%v Count:%d", op.Type, op.Count)
+	}
+	if op := expr.Operations[1]; op.Type != OpKeepHighest || op.Count != 4 {
+		t.Errorf("Expected kh4 second, got Type:%v Count:%d", op.Type, op.Count)
+	}
+	if expr.String() != "6d6dl1kh4" {
+		t.Errorf("Expected '6d6dl1kh4', got '%s'", expr.String())
+	}
+}
+
 func TestParse_Combined(t *testing.T) {
 	tests := []struct {
 		notation string
@@ -160,6 +215,17 @@ func TestParse_Errors(t *testing.T) {
 		{"2d6+", "modifier without value"},
 		{"2d6xyz", "invalid operation"},
 		{"2d6kh0", "operation count zero"},
+		{"2d6kh5", "keeping more dice than rolled"},
+		{"2d6kl3", "keeping more dice than rolled"},
+		{"d20dl20", "dropping every die"},
+		{"4d6dh4", "dropping every die"},
+		{"d20+2d6kh3", "keeping more dice than a later group rolls"},
+		{"8d6min1", "minimum that raises nothing"},
+		{"8d6min7", "minimum above the highest face"},
+		{"8d6mi2", "misspelled minimum"},
+		{"8d6min", "minimum without value"},
+		{"4d6dl1kh4", "keeping more dice than a drop leaves"},
+		{"4d6kh2dl2", "dropping every die a keep leaves"},
 	}
 
 	for _, tt := range tests {
@@ -199,3 +265,303 @@ func TestParse_Whitespace(t *testing.T) {
 	}
 }
 
+func TestParse_Typography(t *testing.T) {
+	tests := []struct {
+		notation string
+		want     int
+	}{
+		{"2d6 – 1", -1},   // en dash
+		{"2d6−1", -1},     // minus sign
+		{"1d8 — 2", -2},   // em dash
+		{"1d8 + 3", 3},    // non-breaking spaces
+		{"1d4＋2", 2},      // fullwidth plus
+		{"d20\t-\t1", -1}, // tabs
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.notation, func(t *testing.T) {
+			got, err := Parse(tt.notation)
+			if err != nil {
+				t.Errorf("Parse(%q) unexpected error: %v", tt.notation, err)
+				return
+			}
+			if got.Modifier != tt.want {
+				t.Errorf("Parse(%q) Modifier = %d, want %d", tt.notation, got.Modifier, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse_Groups(t *testing.T) {
+	tests := []struct {
+		notation string
+		groups   []Group
+		modifier int
+		canon    string
+	}{
+		{"2d6+1d8+3", []Group{{Count: 1, Sides: 8}}, 3, "2d6+1d8+3"},
+		{"1d20+1d4-2", []Group{{Count: 1, Sides: 4}}, -2, "1d20+1d4-2"},
+		{"d20-d4", []Group{{Count: 1, Sides: 4, Negative: true}}, 0, "1d20-1d4"},
+		{"2d6+3+1d8+1", []Group{{Count: 1, Sides: 8}}, 4, "2d6+1d8+4"},
+		{"4d6kh3+2d20!", []Group{{Count: 2, Sides: 20, Advantage: true}}, 0, "4d6kh3+2d20!"},
+		{"1d8+1d6+1d4", []Group{{Count: 1, Sides: 6}, {Count: 1, Sides: 4}}, 0, "1d8+1d6+1d4"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.notation, func(t *testing.T) {
+			got, err := Parse(tt.notation)
+			if err != nil {
+				t.Fatalf("Parse(%q) unexpected error: %v", tt.notation, err)
+			}
+			if len(got.Groups) != len(tt.groups) {
+				t.Fatalf("Parse(%q) = %d groups, want %d", tt.notation, len(got.Groups), len(tt.groups))
+			}
+			for i, g := range got.Groups {
+				want := tt.groups[i]
+				if g.Count != want.Count || g.Sides != want.Sides || g.Negative != want.Negative || g.Advantage != want.Advantage {
+					t.Errorf("Parse(%q) group %d = %+v, want %+v", tt.notation, i, *g, want)
+				}
+			}
+			if got.Modifier != tt.modifier {
+				t.Errorf("Parse(%q) Modifier = %d, want %d", tt.notation, got.Modifier, tt.modifier)
+			}
+			if got.String() != tt.canon {
+				t.Errorf("Parse(%q).String() = %q, want %q", tt.notation, got.String(), tt.canon)
+			}
+		})
+	}
+
+	for _, notation := range []string{"2d6+1d", "2d6+d1", "2d6+1x8", "2d6+-1d8"} {
+		if _, err := Parse(notation); err == nil {
+			t.Errorf("Parse(%q) expected error, got nil", notation)
+		}
+	}
+}
+
+func TestParse_Reroll(t *testing.T) {
+	tests := []struct {
+		notation string
+		want     Reroll
+		canon    string
+	}{
+		{"2d6r1", Reroll{Value: 1}, "2d6r1"},
+		{"2d6ro<3", Reroll{Value: 3, Below: true, Once: true}, "2d6ro<3"},
+		{"d20ro1+5", Reroll{Value: 1, Once: true}, "1d20ro1+5"},
+		{"2d6r<3", Reroll{Value: 3, Below: true}, "2d6r<3"},
+		{"d20!r1", Reroll{Value: 1}, "1d20r1!"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.notation, func(t *testing.T) {
+			got, err := Parse(tt.notation)
+			if err != nil {
+				t.Fatalf("Parse(%q) unexpected error: %v", tt.notation, err)
+			}
+			if got.Reroll == nil || *got.Reroll != tt.want {
+				t.Errorf("Parse(%q) Reroll = %v, want %+v", tt.notation, got.Reroll, tt.want)
+			}
+			if got.String() != tt.canon {
+				t.Errorf("Parse(%q).String() = %q, want %q", tt.notation, got.String(), tt.canon)
+			}
+		})
+	}
+
+	for _, notation := range []string{"2d6r", "2d6ro", "2d6r0", "2d6r7", "2d6r<1", "2d6r<7", "2d6rx"} {
+		if _, err := Parse(notation); err == nil {
+			t.Errorf("Parse(%q) expected error, got nil", notation)
+		}
+	}
+}
+
+func TestParse_Disadvantage(t *testing.T) {
+	tests := []struct {
+		notation string
+		canon    string
+	}{
+		{"d20?", "1d20?"},
+		{"d20?+5", "1d20?+5"},
+		{"2d6?kh1", "2d6?kh1"},
+		{"d20+d4?", "1d20+1d4?"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.notation, func(t *testing.T) {
+			got, err := Parse(tt.notation)
+			if err != nil {
+				t.Fatalf("Parse(%q) unexpected error: %v", tt.notation, err)
+			}
+			if got.String() != tt.canon {
+				t.Errorf("Parse(%q).String() = %q, want %q", tt.notation, got.String(), tt.canon)
+			}
+		})
+	}
+
+	if _, err := Parse("d20!?"); err == nil {
+		t.Error("Parse(\"d20!?\") expected error for advantage and disadvantage together")
+	}
+}
+
+func TestParse_Scale(t *testing.T) {
+	tests := []struct {
+		notation string
+		canon    string
+	}{
+		{"half(8d6)", "half(8d6)"},
+		{"Half( 8d6 )", "half(8d6)"},
+		{"8d6/2", "half(8d6)"},
+		{"double(2d6+3)", "double(2d6+3)"},
+		{"2d6+3*2", "double(2d6+3)"},
+		{"2d6+3 × 2", "double(2d6+3)"},
+		{"half(2d6+3) # fire", "half(2d6+3)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.notation, func(t *testing.T) {
+			got, err := Parse(tt.notation)
+			if err != nil {
+				t.Fatalf("Parse(%q) unexpected error: %v", tt.notation, err)
+			}
+			if got.String() != tt.canon {
+				t.Errorf("Parse(%q).String() = %q, want %q", tt.notation, got.String(), tt.canon)
+			}
+		})
+	}
+
+	for _, notation := range []string{"half(8d6", "half()", "/2", "half(8d6)/2", "double(8d6)*2"} {
+		if _, err := Parse(notation); err == nil {
+			t.Errorf("Parse(%q) expected error", notation)
+		}
+	}
+}
+
+func TestParse_Percentile(t *testing.T) {
+	tests := []struct {
+		notation string
+		canon    string
+	}{
+		{"d%", "1d100"},
+		{"D%", "1d100"},
+		{"2d%+5", "2d100+5"},
+		{"d100", "1d100"},
+		{"d20+d%", "1d20+1d100"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.notation, func(t *testing.T) {
+			got, err := Parse(tt.notation)
+			if err != nil {
+				t.Fatalf("Parse(%q) unexpected error: %v", tt.notation, err)
+			}
+			if got.String() != tt.canon {
+				t.Errorf("Parse(%q).String() = %q, want %q", tt.notation, got.String(), tt.canon)
+			}
+		})
+	}
+
+	if _, err := Parse("d%%"); err == nil {
+		t.Error("Parse(\"d%%\") expected error")
+	}
+}
+
+func TestSplitRepeat(t *testing.T) {
+	tests := []struct {
+		notation string
+		count    int
+		rest     string
+		wantErr  bool
+	}{
+		{"6x4d6kh3", 6, "4d6kh3", false},
+		{"3x d20+7", 3, "d20+7", false},
+		{"2X2d6", 2, "2d6", false},
+		{"2×d8", 2, "d8", false},
+		{"3x smite", 3, "smite", false},
+		{"d20+5", 1, "d20+5", false},
+		{"2x6", 1, "2x6", false},       // not a repeat; fails to parse as notation
+		{"2d6xyz", 1, "2d6xyz", false}, // not a repeat either
+		{"0xd20", 0, "", true},
+		{"101xd20", 0, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.notation, func(t *testing.T) {
+			count, rest, err := SplitRepeat(tt.notation)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SplitRepeat(%q) error = %v, wantErr %v", tt.notation, err, tt.wantErr)
+			}
+			if count != tt.count || rest != tt.rest {
+				t.Errorf("SplitRepeat(%q) = %d, %q, want %d, %q", tt.notation, count, rest, tt.count, tt.rest)
+			}
+		})
+	}
+}
+
+func TestParse_Label(t *testing.T) {
+	tests := []struct {
+		notation string
+		label    string
+		modifier int
+	}{
+		{"2d6+3 # greatsword damage", "greatsword damage", 3},
+		{"2d6+3#greatsword", "greatsword", 3},
+		{"d20 #", "", 0},
+		{"d20", "", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.notation, func(t *testing.T) {
+			got, err := Parse(tt.notation)
+			if err != nil {
+				t.Fatalf("Parse(%q) unexpected error: %v", tt.notation, err)
+			}
+			if got.Label != tt.label || got.Modifier != tt.modifier {
+				t.Errorf("Parse(%q) = Label:%q Modifier:%d, want Label:%q Modifier:%d",
+					tt.notation, got.Label, got.Modifier, tt.label, tt.modifier)
+			}
+		})
+	}
+
+	if _, err := Parse("# just a comment"); err == nil {
+		t.Error("Expected an error for a comment with no dice")
+	}
+}
+
+func TestParse_ErrorPosition(t *testing.T) {
+	tests := []struct {
+		notation   string
+		pos        int
+		suggestion string
+	}{
+		{"4d6k3", 3, "kh3"},
+		{"4d6hk3", 3, "kh3"},
+		{"4d6kx3", 3, "kh3"},
+		{"4d6d1", 3, "dl1"},
+		{"4d6kh", 5, ""},
+		{"8d6mn2", 3, "min2"},
+		{"d20adv", 3, "!"},
+		{"d20dis", 3, "?"},
+		{"d20!!", 3, "!"},
+		{"2x6", 1, "d6"},
+		{"2d6xyz", 3, ""},
+		{"d20+5adv", 5, ""},
+		{"2d6 + 1d8 k2", 7, "kh2"},
+		{"half(4d6k3)", 8, "kh3"},
+		{"2d6r7", 3, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.notation, func(t *testing.T) {
+			_, err := Parse(tt.notation)
+			var parseErr *ParseError
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("Parse(%q): expected a ParseError, got %v", tt.notation, err)
+			}
+			if parseErr.Pos != tt.pos || parseErr.Suggestion != tt.suggestion {
+				t.Errorf("Parse(%q): expected position %d suggesting %q, got %d suggesting %q", tt.notation, tt.pos, tt.suggestion, parseErr.Pos, parseErr.Suggestion)
+			}
+			caret := parseErr.Caret()
+			if caret[1] != strings.Repeat(" ", tt.pos)+"^" {
+				t.Errorf("Parse(%q): expected the caret under position %d, got %q", tt.notation, tt.pos, caret)
+			}
+		})
+	}
+}
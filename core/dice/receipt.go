@@ -0,0 +1,50 @@
+package dice
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// receiptHashLength is how many hex characters of the digest are shown
+const receiptHashLength = 16
+
+// Receipt returns a compact, pasteable text block describing the roll
+// The block ends with a short SHA-256 checksum of its contents, which catches
+// typos and mangled pastes. It isn't keyed, so it doesn't stop anyone who
+// edits a receipt on purpose: they can work out the new checksum themselves
+func (r *Result) Receipt(at time.Time) string {
+	if r == nil {
+		return "<nil result>"
+	}
+
+	body := receiptBody(r, at)
+	var b strings.Builder
+	b.WriteString("--- TavernShell roll receipt ---\n")
+	b.WriteString(body)
+	b.WriteString(fmt.Sprintf("Hash:  %s\n", receiptHash(body)))
+	b.WriteString("--------------------------------")
+	return b.String()
+}
+
+// receiptBody builds the hashed portion of a receipt
+func receiptBody(r *Result, at time.Time) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("Roll:  %s\n", r.Expression.String()))
+	b.WriteString(fmt.Sprintf("Dice:  %s\n", formatAllRolls(r)))
+	if bonus := r.BonusString(); bonus != "" {
+		b.WriteString(fmt.Sprintf("Bonus: %s\n", bonus))
+	}
+	b.WriteString(fmt.Sprintf("Total: %d\n", r.Total))
+	b.WriteString(fmt.Sprintf("Time:  %s\n", at.UTC().Format(time.RFC3339)))
+	return b.String()
+}
+
+// receiptHash returns the truncated hex digest of a receipt body, as a
+// checksum against accidental changes (not a signature)
+func receiptHash(body string) string {
+	sum := sha256.Sum256([]byte(body))
+	return hex.EncodeToString(sum[:])[:receiptHashLength]
+}
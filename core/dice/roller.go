@@ -1,8 +1,6 @@
 package dice
 
 import (
-	"crypto/rand"
-	"encoding/binary"
 	"fmt"
 	"sort"
 )
@@ -16,28 +14,20 @@ type Roll struct {
 	Total    int    // sum of all results
 }
 
-// rollDie rolls a single die with the specified number of sides
-// Uses crypto/rand for cryptographically secure random numbers
+// rollDie rolls a single die with the specified number of sides using the
+// current roller, or takes the next result queued by Force
 func rollDie(sides int) (int, error) {
-	if sides < 2 {
-		return 0, fmt.Errorf("die must have at least 2 sides")
+	if value, ok, err := nextForced(sides); ok {
+		return value, err
 	}
+	return CurrentRoller().RollDie(sides)
+}
 
-	// Generate a random number using crypto/rand
-	var randomBytes [8]byte
-	_, err := rand.Read(randomBytes[:])
-	if err != nil {
-		return 0, fmt.Errorf("failed to generate random number: %w", err)
-	}
-
-	// Convert bytes to uint64
-	randomNum := binary.BigEndian.Uint64(randomBytes[:])
-
-	// Use modulo to get a number in range [0, sides-1], then add 1
-	// This is slightly biased, but the bias is negligible for crypto/rand
-	result := int(randomNum%uint64(sides)) + 1
-
-	return result, nil
+// cryptoRollDie rolls a single die with the specified number of sides
+// Uses crypto/rand for cryptographically secure random numbers, drawn from a
+// shared pool so that big rolls (1000d6) don't make a system call per die
+func cryptoRollDie(sides int) (int, error) {
+	return entropyPool.RollDie(sides)
 }
 
 // RollExpression rolls dice according to an Expression and returns a Result
@@ -46,58 +36,186 @@ func RollExpression(expr *Expression) (*Result, error) {
 		return nil, fmt.Errorf("nil expression")
 	}
 
-	// Determine how many dice to actually roll
-	diceToRoll := expr.Count
-	if expr.Advantage {
-		diceToRoll *= 2 // Per-die advantage: roll each die twice
+	rolls, keptTotal, err := rollGroup(expr.firstGroup(), rollDie)
+	if err != nil {
+		return nil, err
 	}
 
-	// Roll all the dice
-	rolls := make([]Die, diceToRoll)
-	for i := 0; i < diceToRoll; i++ {
-		value, err := rollDie(expr.Sides)
+	// Roll each further group, adding or subtracting its kept dice
+	var groups []GroupResult
+	for _, g := range expr.Groups {
+		groupRolls, groupTotal, err := rollGroup(g, rollDie)
 		if err != nil {
 			return nil, err
 		}
-		rolls[i] = Die{
-			Value: value,
-			Sides: expr.Sides,
-			Kept:  true, // Initially all dice are kept
+		groups = append(groups, GroupResult{Group: g, Rolls: groupRolls, KeptTotal: groupTotal})
+		if g.Negative {
+			keptTotal -= groupTotal
+		} else {
+			keptTotal += groupTotal
+		}
+	}
+	total := expr.scale(keptTotal+expr.Modifier) + expr.Bonus
+
+	result := &Result{
+		Expression: expr,
+		Rolls:      rolls,
+		Groups:     groups,
+		KeptTotal:  keptTotal,
+		Total:      total,
+		Label:      expr.Label,
+		Bonus:      expr.Bonus,
+		BonusNote:  expr.BonusNote,
+	}
+	result.markNaturals()
+	return result, nil
+}
+
+// markNaturals sets Crit and Fumble from the faces the kept d20s landed on
+func (r *Result) markNaturals() {
+	check := func(rolls []Die) {
+		for _, die := range rolls {
+			if !die.Kept || die.Sides != 20 {
+				continue
+			}
+			switch die.Natural() {
+			case 20:
+				r.Crit = true
+			case 1:
+				r.Fumble = true
+			}
+		}
+	}
+	check(r.Rolls)
+	for _, g := range r.Groups {
+		check(g.Rolls)
+	}
+}
+
+// firstGroup returns the expression's own dice group as a Group
+func (e *Expression) firstGroup() *Group {
+	return &Group{
+		Count:        e.Count,
+		Sides:        e.Sides,
+		Operations:   e.Operations,
+		Advantage:    e.Advantage,
+		Disadvantage: e.Disadvantage,
+		Triple:       e.Triple,
+		Reroll:       e.Reroll,
+		Min:          e.Min,
+	}
+}
+
+// rollGroup rolls one group of dice, applying rerolls, minimums, (dis)advantage and keep/drop,
+// drawing each die's value from roll
+// Returns all dice rolled and the sum of the kept ones
+func rollGroup(g *Group, roll func(sides int) (int, error)) ([]Die, int, error) {
+	// Determine how many dice to actually roll
+	diceToRoll := g.Count * g.rollsPerDie() // Per-die (dis)advantage: roll each die two or three times
+
+	// Roll all the dice, keeping rerolled-away dice (dropped) ahead of their replacements
+	rolls := make([]Die, 0, diceToRoll)
+	for i := 0; i < diceToRoll; i++ {
+		rerolled := false
+		for {
+			value, digits, err := rollFace(g.Sides, roll)
+			if err != nil {
+				return nil, 0, err
+			}
+			die := Die{
+				Value:  value,
+				Sides:  g.Sides,
+				Kept:   true, // Initially all dice are kept
+				Digits: digits,
+			}
+			if g.Reroll != nil && g.Reroll.matches(value) && !(g.Reroll.Once && rerolled) {
+				die.Kept = false
+				die.Rerolled = true
+				rolls = append(rolls, die)
+				rerolled = true
+				continue
+			}
+			if value < g.Min {
+				die.Original = value
+				die.Value = g.Min
+			}
+			rolls = append(rolls, die)
+			break
+		}
+	}
+
+	return rolls, keepDice(rolls, g), nil
+}
+
+// rollFace rolls one die with roll, reading digit dice (d66) from a die per
+// digit; it returns the value and, for digit dice, the digits rolled
+func rollFace(sides int, roll func(sides int) (int, error)) (int, []int, error) {
+	faces, count := digitDice(sides)
+	if count == 0 {
+		value, err := roll(sides)
+		return value, nil, err
+	}
+	value := 0
+	digits := make([]int, count)
+	for i := range digits {
+		digit, err := roll(faces)
+		if err != nil {
+			return 0, nil, err
 		}
+		digits[i] = digit
+		value = value*10 + digit
 	}
+	return value, digits, nil
+}
 
-	// Apply per-die advantage if needed
-	if expr.Advantage {
-		// Pair consecutive dice and keep the highest from each pair
-		for i := 0; i < len(rolls); i += 2 {
-			if rolls[i].Value >= rolls[i+1].Value {
-				rolls[i+1].Kept = false // Drop the lower one
-			} else {
-				rolls[i].Kept = false // Drop the lower one
+// matches reports whether a rolled value should be rerolled
+func (r *Reroll) matches(value int) bool {
+	if r.Below {
+		return value < r.Value
+	}
+	return value == r.Value
+}
+
+// keepDice marks which of a group's rolled dice are kept, applying
+// (dis)advantage and keep/drop, and returns the sum of the kept dice
+func keepDice(rolls []Die, g *Group) int {
+	// Apply per-die advantage or disadvantage if needed
+	if per := g.rollsPerDie(); per > 1 {
+		// Group consecutive dice (skipping rerolled-away ones) in twos, or
+		// threes, and keep the highest (or lowest, for disadvantage) from each
+		live := []int{}
+		for i, die := range rolls {
+			if !die.Rerolled {
+				live = append(live, i)
+			}
+		}
+		for i := 0; i+per <= len(live); i += per {
+			// On a tie the first die is kept
+			keep := live[i]
+			for _, j := range live[i+1 : i+per] {
+				if (g.Disadvantage && rolls[j].Value < rolls[keep].Value) || (!g.Disadvantage && rolls[j].Value > rolls[keep].Value) {
+					keep = j
+				}
+			}
+			for _, j := range live[i : i+per] {
+				rolls[j].Kept = j == keep
 			}
 		}
 	}
 
-	// Apply keep/drop operation if specified
-	if expr.Operation != nil {
-		applyOperation(rolls, expr.Operation)
+	// Apply keep/drop operations in order, each to the dice still kept
+	for _, op := range g.Operations {
+		applyOperation(rolls, op)
 	}
 
-	// Calculate totals
+	// Calculate the kept total
 	keptTotal := 0
 	for _, die := range rolls {
 		if die.Kept {
 			keptTotal += die.Value
 		}
 	}
-	total := keptTotal + expr.Modifier
-
-	return &Result{
-		Expression: expr,
-		Rolls:      rolls,
-		KeptTotal:  keptTotal,
-		Total:      total,
-	}, nil
+	return keptTotal
 }
 
 // applyOperation applies a keep/drop operation to rolled dice
@@ -168,23 +286,28 @@ func RollDice(notation string) (*Roll, error) {
 		return nil, err
 	}
 
-	// Convert Result to legacy Roll format
+	// Convert Result to legacy Roll format (further groups' dice are listed
+	// after the first group's, and only counted in the total)
 	results := []int{}
-	total := 0
 	for _, die := range result.Rolls {
 		if die.Kept {
 			results = append(results, die.Value)
-			total += die.Value
 		}
 	}
-	total += expr.Modifier
+	for _, g := range result.Groups {
+		for _, die := range g.Rolls {
+			if die.Kept {
+				results = append(results, die.Value)
+			}
+		}
+	}
 
 	return &Roll{
 		Notation: notation,
 		Count:    expr.Count,
 		Sides:    expr.Sides,
 		Results:  results,
-		Total:    total,
+		Total:    result.Total,
 	}, nil
 }
 
@@ -1,7 +1,12 @@
 package dice
 
 import (
+	cryptorand "crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"strings"
 	"testing"
+	"time"
 )
 
 // TestParse tests the new Parse function that returns an Expression
@@ -137,3 +142,630 @@ func TestRollString(t *testing.T) {
 		})
 	}
 }
+
+func TestResultReceipt(t *testing.T) {
+	expr := &Expression{Count: 2, Sides: 6, Modifier: 3}
+	result := &Result{
+		Expression: expr,
+		Rolls:      []Die{{Value: 4, Sides: 6, Kept: true}, {Value: 2, Sides: 6, Kept: true}},
+		KeptTotal:  6,
+		Total:      9,
+	}
+	at := time.Date(2024, 5, 1, 20, 0, 0, 0, time.UTC)
+
+	receipt := result.Receipt(at)
+	for _, want := range []string{"Roll:  2d6+3", "Dice:  [4, 2]", "Total: 9", "Time:  2024-05-01T20:00:00Z", "Hash:  "} {
+		if !strings.Contains(receipt, want) {
+			t.Errorf("Receipt() missing %q in:\n%s", want, receipt)
+		}
+	}
+
+	// Same roll and time must produce the same receipt
+	if receipt != result.Receipt(at) {
+		t.Error("Receipt() is not deterministic")
+	}
+
+	// A different total must change the hash
+	tampered := *result
+	tampered.Total = 12
+	if receiptHash(receiptBody(&tampered, at)) == receiptHash(receiptBody(result, at)) {
+		t.Error("Expected hash to change when the total changes")
+	}
+}
+
+func TestSeededRollerDeterministic(t *testing.T) {
+	a := NewSeededRoller(42)
+	b := NewSeededRoller(42)
+	for i := 0; i < 50; i++ {
+		x, err := a.RollDie(20)
+		if err != nil {
+			t.Fatalf("RollDie failed: %v", err)
+		}
+		y, _ := b.RollDie(20)
+		if x != y {
+			t.Fatalf("Roll %d: seeded rollers diverged (%d != %d)", i, x, y)
+		}
+		if x < 1 || x > 20 {
+			t.Errorf("Roll %d out of range [1, 20]", x)
+		}
+	}
+}
+
+func TestSetRoller(t *testing.T) {
+	defer SetRoller(nil)
+
+	expr := &Expression{Count: 4, Sides: 6}
+	SetRoller(NewSeededRoller(7))
+	first, err := RollExpression(expr)
+	if err != nil {
+		t.Fatalf("RollExpression failed: %v", err)
+	}
+	SetRoller(NewSeededRoller(7))
+	second, _ := RollExpression(expr)
+
+	for i := range first.Rolls {
+		if first.Rolls[i].Value != second.Rolls[i].Value {
+			t.Fatalf("Expected identical rolls with the same seed, got %v and %v", first.Rolls, second.Rolls)
+		}
+	}
+
+	SetRoller(nil)
+	if _, ok := CurrentRoller().(CryptoRoller); !ok {
+		t.Error("Expected SetRoller(nil) to restore the crypto roller")
+	}
+}
+
+func TestSequenceRoller(t *testing.T) {
+	r := NewSequenceRoller(20, 1)
+	for i, want := range []int{20, 1, 20} {
+		got, err := r.RollDie(20)
+		if err != nil || got != want {
+			t.Errorf("Roll %d: expected %d, got %d (%v)", i, want, got, err)
+		}
+	}
+
+	// The next value is 1, then 20 again, which can't come up on a d6
+	r.RollDie(6)
+	if _, err := r.RollDie(6); err == nil {
+		t.Error("Expected error for a sequence value larger than the die")
+	}
+	if _, err := NewSequenceRoller().RollDie(6); err == nil {
+		t.Error("Expected error for an empty sequence")
+	}
+}
+
+func TestForce(t *testing.T) {
+	defer SetRoller(CurrentRoller())
+	defer ClearForced()
+
+	SetRoller(NewSequenceRoller(3))
+	if err := Force(20, 1); err != nil {
+		t.Fatalf("Force failed: %v", err)
+	}
+	if got := Forced(); len(got) != 2 || got[0] != 20 || got[1] != 1 {
+		t.Errorf("Expected [20 1] queued, got %v", got)
+	}
+
+	expr, _ := Parse("3d20")
+	result, err := RollExpression(expr)
+	if err != nil {
+		t.Fatalf("RollExpression failed: %v", err)
+	}
+	if got := formatRolls(result.Rolls); got != "[20, 1, 3]" {
+		t.Errorf("Expected forced dice then the roller's, got %s", got)
+	}
+	if !result.Crit || !result.Fumble {
+		t.Errorf("Expected a forced crit and fumble, got crit=%v fumble=%v", result.Crit, result.Fumble)
+	}
+
+	// A value too big for the die fails the roll and clears the queue
+	Force(7, 2)
+	expr, _ = Parse("d6")
+	if _, err := RollExpression(expr); err == nil {
+		t.Error("Expected an error forcing a 7 on a d6")
+	}
+	if got := Forced(); len(got) != 0 {
+		t.Errorf("Expected the queue cleared, got %v", got)
+	}
+
+	if err := Force(0); err == nil {
+		t.Error("Expected an error forcing a 0")
+	}
+}
+
+func TestParseRoller(t *testing.T) {
+	tests := []struct {
+		spec    string
+		wantErr bool
+	}{
+		{"crypto", false},
+		{"", false},
+		{"seeded:42", false},
+		{"seeded:abc", true},
+		{"sequence:20,1,7", false},
+		{"sequence:", true},
+		{"sequence:4,0", true},
+		{"camera", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			_, err := ParseRoller(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseRoller(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRollExpressionGroups(t *testing.T) {
+	defer SetRoller(CurrentRoller())
+	SetRoller(NewSeededRoller(7))
+
+	expr, err := Parse("2d6+1d8-1d4+3")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	for i := 0; i < 50; i++ {
+		result, err := RollExpression(expr)
+		if err != nil {
+			t.Fatalf("RollExpression failed: %v", err)
+		}
+		if len(result.Rolls) != 2 || len(result.Groups) != 2 {
+			t.Fatalf("Expected 2 first-group dice and 2 further groups, got %d and %d", len(result.Rolls), len(result.Groups))
+		}
+
+		want := result.Rolls[0].Value + result.Rolls[1].Value + result.Groups[0].KeptTotal - result.Groups[1].KeptTotal + 3
+		if result.Total != want {
+			t.Errorf("Expected total %d, got %d", want, result.Total)
+		}
+		if result.Total != result.KeptTotal+3 {
+			t.Errorf("Expected total to be kept total + 3, got %d and %d", result.Total, result.KeptTotal)
+		}
+		if d := result.Groups[1].Rolls[0]; d.Sides != 4 || d.Value < 1 || d.Value > 4 {
+			t.Errorf("Expected a d4 in the last group, got d%d rolling %d", d.Sides, d.Value)
+		}
+	}
+}
+
+func TestResultStringGroups(t *testing.T) {
+	expr, _ := Parse("2d6+1d8-1d4!+3")
+	result := &Result{
+		Expression: expr,
+		Rolls:      []Die{{Value: 4, Sides: 6, Kept: true}, {Value: 2, Sides: 6, Kept: true}},
+		Groups: []GroupResult{
+			{Group: expr.Groups[0], Rolls: []Die{{Value: 7, Sides: 8, Kept: true}}, KeptTotal: 7},
+			{Group: expr.Groups[1], Rolls: []Die{{Value: 1, Sides: 4, Kept: false}, {Value: 3, Sides: 4, Kept: true}}, KeptTotal: 3},
+		},
+		KeptTotal: 10,
+		Total:     13,
+	}
+
+	want := "2d6+1d8-1d4!+3: [4, 2] + [7] - [‹1›, 3] +3 = 13 (advantage)"
+	if got := result.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestShareCode(t *testing.T) {
+	defer SetRoller(CurrentRoller())
+	SetRoller(NewSeededRoller(3))
+
+	at := time.Date(2024, 5, 1, 20, 0, 0, 0, time.UTC)
+	for _, notation := range []string{"d20+5", "4d6kh3", "2d20!-1", "2d6+1d8-1d4+3"} {
+		expr, err := Parse(notation)
+		if err != nil {
+			t.Fatalf("Parse(%q) failed: %v", notation, err)
+		}
+		result, err := RollExpression(expr)
+		if err != nil {
+			t.Fatalf("RollExpression failed: %v", err)
+		}
+
+		code := result.ShareCode(at)
+		decoded, when, err := DecodeShare(code)
+		if err != nil {
+			t.Fatalf("DecodeShare(%q) failed: %v", code, err)
+		}
+		if decoded.String() != result.String() {
+			t.Errorf("Expected %q, got %q", result.String(), decoded.String())
+		}
+		if !when.Equal(at) {
+			t.Errorf("Expected time %v, got %v", at, when)
+		}
+	}
+}
+
+func TestBonus(t *testing.T) {
+	defer SetRoller(CurrentRoller())
+	SetRoller(NewSequenceRoller(4, 5))
+
+	// A bonus from outside the notation goes on after halving, and travels
+	// with the result without changing its notation
+	expr, err := Parse("half(2d6+3)")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	expr.Bonus, expr.BonusNote = 2, "Aria +2"
+	result, err := RollExpression(expr)
+	if err != nil {
+		t.Fatalf("RollExpression failed: %v", err)
+	}
+	expected := "half(2d6+3): [4, 5] +3 (12 halved) +2 (Aria +2) = 8"
+	if result.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, result.String())
+	}
+
+	decoded, _, err := DecodeShare(result.ShareCode(time.Unix(0, 0)))
+	if err != nil {
+		t.Fatalf("DecodeShare failed: %v", err)
+	}
+	if decoded.String() != expected {
+		t.Errorf("Expected %q from the share code, got %q", expected, decoded.String())
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	var fromJSON Result
+	if err := json.Unmarshal(data, &fromJSON); err != nil {
+		t.Fatalf("Unmarshal(%s) failed: %v", data, err)
+	}
+	if fromJSON.String() != expected {
+		t.Errorf("Expected %q from JSON, got %q", expected, fromJSON.String())
+	}
+}
+
+func TestDecodeShareErrors(t *testing.T) {
+	result := &Result{
+		Expression: &Expression{Count: 1, Sides: 20},
+		Rolls:      []Die{{Value: 12, Sides: 20, Kept: true}},
+		KeptTotal:  12,
+		Total:      12,
+	}
+	code := result.ShareCode(time.Unix(0, 0))
+
+	// Flip one character of the payload
+	tampered := []byte(code)
+	i := len(sharePrefix) + 2
+	if tampered[i] == 'A' {
+		tampered[i] = 'B'
+	} else {
+		tampered[i] = 'A'
+	}
+
+	for _, bad := range []string{"", "hello", "ts1.!!!", string(tampered), code[:len(code)-3]} {
+		if _, _, err := DecodeShare(bad); err == nil {
+			t.Errorf("DecodeShare(%q) expected error, got nil", bad)
+		}
+	}
+}
+
+func TestResultJSON(t *testing.T) {
+	defer SetRoller(CurrentRoller())
+	SetRoller(NewSeededRoller(3))
+
+	for _, notation := range []string{"d20+5", "4d6kh3", "2d20!-1", "2d6+1d8-1d4+3", "8d6min2ro<3", "half(2d66)"} {
+		expr, err := Parse(notation)
+		if err != nil {
+			t.Fatalf("Parse(%q) failed: %v", notation, err)
+		}
+		expr.Label = "fireball"
+		result, err := RollExpression(expr)
+		if err != nil {
+			t.Fatalf("RollExpression failed: %v", err)
+		}
+
+		data, err := json.Marshal(result)
+		if err != nil {
+			t.Fatalf("Marshal(%q) failed: %v", notation, err)
+		}
+		var decoded Result
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("Unmarshal(%s) failed: %v", data, err)
+		}
+		if decoded.String() != result.String() {
+			t.Errorf("Expected %q, got %q", result.String(), decoded.String())
+		}
+		if decoded.Expression.Label != "fireball" {
+			t.Errorf("Expected label 'fireball', got %q", decoded.Expression.Label)
+		}
+		for i, g := range decoded.Groups {
+			if g.Group != decoded.Expression.Groups[i] {
+				t.Errorf("%s: group %d isn't linked to the expression's group", notation, i+1)
+			}
+		}
+	}
+}
+
+func TestResultJSONFields(t *testing.T) {
+	expr, err := Parse("4d6kh3+2")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	result := &Result{
+		Expression: expr,
+		Rolls:      []Die{{Value: 1, Sides: 6}, {Value: 4, Sides: 6, Kept: true}, {Value: 5, Sides: 6, Kept: true}, {Value: 6, Sides: 6, Kept: true}},
+		KeptTotal:  15,
+		Total:      17,
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	expected := `{"expression":{"notation":"4d6kh3+2","count":4,"sides":6,"modifier":2,"operations":[{"type":"kh","count":3}]},` +
+		`"rolls":[{"value":1,"sides":6,"kept":false},{"value":4,"sides":6,"kept":true},{"value":5,"sides":6,"kept":true},{"value":6,"sides":6,"kept":true}],` +
+		`"kept_total":15,"total":17}`
+	if string(data) != expected {
+		t.Errorf("Expected %s, got %s", expected, data)
+	}
+}
+
+func TestResultJSONErrors(t *testing.T) {
+	for _, bad := range []string{
+		`{"rolls":[]}`,
+		`{"expression":{"count":1,"sides":20},"rolls":[]}`,
+		`{"expression":{"notation":"2d6k3"},"rolls":[]}`,
+		`{"expression":{"notation":"2d6+1d8"},"rolls":[]}`,
+	} {
+		var r Result
+		if err := json.Unmarshal([]byte(bad), &r); err == nil {
+			t.Errorf("Unmarshal(%s) expected error, got nil", bad)
+		}
+	}
+}
+
+// scriptedRoller returns fixed values in order
+type scriptedRoller struct {
+	values []int
+}
+
+func (s *scriptedRoller) RollDie(sides int) (int, error) {
+	v := s.values[0]
+	s.values = s.values[1:]
+	return v, nil
+}
+
+func TestRollExpressionScripted(t *testing.T) {
+	defer SetRoller(CurrentRoller())
+
+	tests := []struct {
+		notation string
+		values   []int
+		want     string
+	}{
+		{"2d6r1", []int{1, 1, 4, 6}, "2d6r1: [‹1›, ‹1›, 4, 6] = 10 (rerolled 1s)"},
+		{"2d6ro<3", []int{2, 1, 5}, "2d6ro<3: [‹2›, 1, 5] = 6 (rerolled under 3 once)"},
+		{"d20r1!", []int{1, 7, 12}, "1d20r1!: [‹1›, ‹7›, 12] = 12 (rerolled 1s; advantage)"},
+		{"4d6r1kh3", []int{1, 2, 3, 4, 5}, "4d6r1kh3: [‹1›, ‹2›, 3, 4, 5] = 12 (rerolled 1s; kept highest 3)"},
+		{"d20?", []int{15, 4}, "1d20?: [‹15›, 4] = 4 (disadvantage)"},
+		{"2d20?+1", []int{3, 9, 12, 12}, "2d20?+1: [3, ‹9›, 12, ‹12›] +1 = 16 (disadvantage)"},
+		{"d20!", []int{4, 15}, "1d20!: [‹4›, 15] = 15 (advantage)"},
+		{"d20!!!", []int{4, 15, 9}, "1d20!!!: [‹4›, 15, ‹9›] = 15 (triple advantage)"},
+		{"2d20!!!", []int{4, 15, 15, 2, 3, 1}, "2d20!!!: [‹4›, 15, ‹15›, ‹2›, 3, ‹1›] = 18 (triple advantage)"},
+		{"d20r1adv3", []int{1, 6, 12, 3}, "1d20r1!!!: [‹1›, ‹6›, 12, ‹3›] = 12 (rerolled 1s; triple advantage)"},
+		{"3d6min2", []int{1, 4, 1}, "3d6min2: [1→2, 4, 1→2] = 8 (raised to 2)"},
+		{"2d6r1min3", []int{1, 2, 5}, "2d6r1min3: [‹1›, 2→3, 5] = 8 (rerolled 1s; raised to 3)"},
+		{"3d4min2kh2", []int{1, 1, 3}, "3d4min2kh2: [‹1→2›, 1→2, 3] = 5 (raised to 2; kept highest 2)"},
+		{"6d6dl1kh4", []int{1, 6, 2, 5, 3, 4}, "6d6dl1kh4: [‹1›, 6, ‹2›, 5, 3, 4] = 18 (dropped lowest 1; kept highest 4)"},
+		{"5d6kh3dh1", []int{6, 5, 4, 2, 1}, "5d6kh3dh1: [‹6›, 5, 4, ‹2›, ‹1›] = 9 (kept highest 3; dropped highest 1)"},
+		{"half(2d6+3)", []int{4, 2}, "half(2d6+3): [4, 2] +3 (9 halved) = 4"},
+		{"2d6-5/2", []int{1, 1}, "half(2d6-5): [1, 1] -5 (-3 halved) = -2"},
+		{"double(2d6+3)", []int{4, 2}, "double(2d6+3): [4, 2] +3 (9 doubled) = 18"},
+		{"d66", []int{3, 5}, "1d66: [35 (3·5)] = 35"},
+		{"2d66+1", []int{6, 6, 2, 1}, "2d66+1: [66 (6·6), 21 (2·1)] +1 = 88"},
+		{"d666", []int{4, 3, 3}, "1d666: [433 (4·3·3)] = 433"},
+		{"d66r11", []int{1, 1, 6, 2}, "1d66r11: [‹11 (1·1)›, 62 (6·2)] = 62 (rerolled 11s)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.notation, func(t *testing.T) {
+			SetRoller(&scriptedRoller{values: tt.values})
+			expr, err := Parse(tt.notation)
+			if err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+			result, err := RollExpression(expr)
+			if err != nil {
+				t.Fatalf("RollExpression failed: %v", err)
+			}
+			if got := result.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+
+			// Rerolled dice replay the same way from a share code
+			decoded, _, err := DecodeShare(result.ShareCode(time.Unix(0, 0)))
+			if err != nil {
+				t.Fatalf("DecodeShare failed: %v", err)
+			}
+			if decoded.String() != tt.want {
+				t.Errorf("Decoded share = %q, want %q", decoded.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestResultBreakdown(t *testing.T) {
+	defer SetRoller(CurrentRoller())
+
+	tests := []struct {
+		notation string
+		values   []int
+		want     []string
+	}{
+		{"2d6r1+3", []int{1, 1, 4, 6}, []string{"2d6r1: 1 rerolled → 1 rerolled → 4, 6 = 10", "modifier +3", "total 13"}},
+		{"d20!-d4", []int{4, 15, 3}, []string{"1d20!: 4 (dropped), 15 = 15", "-1d4: 3 = -3", "total 12"}},
+		{"3d6min2/2", []int{1, 4, 5}, []string{"3d6min2: 1 raised to 2, 4, 5 = 11", "11 halved, rounded down", "total 5"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.notation, func(t *testing.T) {
+			SetRoller(&scriptedRoller{values: tt.values})
+			expr, err := Parse(tt.notation)
+			if err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+			result, err := RollExpression(expr)
+			if err != nil {
+				t.Fatalf("RollExpression failed: %v", err)
+			}
+			if got := strings.Join(result.Breakdown(), "; "); got != strings.Join(tt.want, "; ") {
+				t.Errorf("Breakdown() = %q, want %q", got, strings.Join(tt.want, "; "))
+			}
+		})
+	}
+}
+
+func TestPercentileDice(t *testing.T) {
+	tests := []struct {
+		value, tens, ones int
+	}{
+		{37, 30, 7},
+		{1, 0, 1},
+		{10, 10, 0},
+		{90, 90, 0},
+		{100, 0, 0},
+	}
+
+	for _, tt := range tests {
+		tens, ones := PercentileDice(tt.value)
+		if tens != tt.tens || ones != tt.ones {
+			t.Errorf("PercentileDice(%d) = %d, %d, want %d, %d", tt.value, tens, ones, tt.tens, tt.ones)
+		}
+	}
+}
+
+func TestDigitDice(t *testing.T) {
+	tests := []struct {
+		sides, faces, count int
+	}{
+		{66, 6, 2},
+		{88, 8, 2},
+		{666, 6, 3},
+		{22, 2, 2},
+		{6, 0, 0},   // one digit: an ordinary die
+		{11, 0, 0},  // no d1s
+		{100, 0, 0}, // percentile
+		{67, 0, 0},
+	}
+
+	for _, tt := range tests {
+		faces, count := digitDice(tt.sides)
+		if faces != tt.faces || count != tt.count {
+			t.Errorf("digitDice(%d) = %d, %d, want %d, %d", tt.sides, faces, count, tt.faces, tt.count)
+		}
+	}
+}
+
+func TestCritAndFumble(t *testing.T) {
+	defer SetRoller(CurrentRoller())
+
+	tests := []struct {
+		notation     string
+		values       []int
+		crit, fumble bool
+	}{
+		{"d20+5", []int{20}, true, false},
+		{"d20+5", []int{1}, false, true},
+		{"d20+5", []int{12}, false, false},
+		{"d20!", []int{1, 20}, true, false},  // the dropped 1 doesn't count
+		{"d20?", []int{1, 20}, false, true},  // nor does the dropped 20
+		{"2d10", []int{1, 10}, false, false}, // only d20s
+		{"d6+d20", []int{1, 20}, true, false},
+		{"d20min10", []int{1}, false, true}, // raised, but it landed on a 1
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.notation, func(t *testing.T) {
+			SetRoller(&scriptedRoller{values: tt.values})
+			expr, err := Parse(tt.notation)
+			if err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+			result, err := RollExpression(expr)
+			if err != nil {
+				t.Fatalf("RollExpression failed: %v", err)
+			}
+			if result.Crit != tt.crit || result.Fumble != tt.fumble {
+				t.Errorf("Expected crit=%v fumble=%v for %v, got crit=%v fumble=%v",
+					tt.crit, tt.fumble, tt.values, result.Crit, result.Fumble)
+			}
+		})
+	}
+}
+
+// unpooledRollDie rolls a die reading crypto/rand once per die, the way rolls
+// worked before the entropy pool, for comparison
+func unpooledRollDie(sides int) (int, error) {
+	var randomBytes [8]byte
+	if _, err := cryptorand.Read(randomBytes[:]); err != nil {
+		return 0, err
+	}
+	return int(binary.BigEndian.Uint64(randomBytes[:])%uint64(sides)) + 1, nil
+}
+
+func BenchmarkRollDie(b *testing.B) {
+	for _, roll := range []struct {
+		name string
+		die  func(sides int) (int, error)
+	}{{"pooled", cryptoRollDie}, {"unpooled", unpooledRollDie}} {
+		b.Run(roll.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := roll.die(6); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkRoll1000d6(b *testing.B) {
+	defer SetRoller(CurrentRoller())
+	SetRoller(nil)
+
+	expr, err := Parse("1000d6")
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := RollExpression(expr); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSimulate(b *testing.B) {
+	expr, err := Parse("d20+7")
+	if err != nil {
+		b.Fatal(err)
+	}
+	for i := 0; i < b.N; i++ {
+		if _, err := Simulate(expr, 10000, NewBatchRoller(), 15, true); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestCryptoRollDieRange(t *testing.T) {
+	// More dice than one batch holds, so the pool refills along the way
+	seen := make(map[int]bool)
+	for i := 0; i < 2*batchSize/8; i++ {
+		value, err := cryptoRollDie(6)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if value < 1 || value > 6 {
+			t.Fatalf("Expected 1-6, got %d", value)
+		}
+		seen[value] = true
+	}
+	if len(seen) != 6 {
+		t.Errorf("Expected every face of a d6 over %d rolls, got %v", 2*batchSize/8, seen)
+	}
+	if _, err := cryptoRollDie(1); err == nil {
+		t.Error("Expected an error for a one-sided die")
+	}
+}
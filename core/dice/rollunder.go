@@ -0,0 +1,105 @@
+package dice
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// RollUnder is a GURPS-style success roll: 3d6 against a skill, succeeding
+// on a roll at or under it
+type RollUnder struct {
+	Result *Result // The 3d6 roll
+	Skill  int     // Effective skill, after modifiers
+}
+
+// RollAgainst rolls 3d6 against an effective skill
+func RollAgainst(skill int) (*RollUnder, error) {
+	expr, err := Parse("3d6")
+	if err != nil {
+		return nil, err
+	}
+	result, err := RollExpression(expr)
+	if err != nil {
+		return nil, err
+	}
+	return &RollUnder{Result: result, Skill: skill}, nil
+}
+
+// ParseSkill parses an effective skill with optional modifiers, e.g. "14" or "14-2+1"
+func ParseSkill(s string) (int, error) {
+	s = normalize(s)
+	if s == "" {
+		return 0, fmt.Errorf("expected a skill level, e.g. 14 or 14-2")
+	}
+	skill, start := 0, 0
+	for i := 1; i <= len(s); i++ {
+		if i < len(s) && s[i] != '+' && s[i] != '-' {
+			continue
+		}
+		term, err := strconv.Atoi(s[start:i])
+		if err != nil {
+			return 0, fmt.Errorf("invalid skill '%s' (expected e.g. 14 or 14-2)", s)
+		}
+		skill += term
+		start = i
+	}
+	return skill, nil
+}
+
+// Margin is how far the roll came in under the skill (negative if over)
+func (r RollUnder) Margin() int {
+	return r.Skill - r.Result.Total
+}
+
+// Degree works out the result under the GURPS rules: 3 and 4 always
+// critically succeed, as do 5 at skill 15+ and 6 at skill 16+; 18 always
+// critically fails, as does 17 at skill 15 or less and any roll 10 or more
+// over the skill; otherwise 17 always fails
+func (r RollUnder) Degree() Degree {
+	roll := r.Result.Total
+	switch {
+	case roll <= 4, roll == 5 && r.Skill >= 15, roll == 6 && r.Skill >= 16:
+		return CriticalSuccess
+	case roll == 18, roll == 17 && r.Skill <= 15, roll >= r.Skill+10:
+		return CriticalFailure
+	case roll == 17, roll > r.Skill:
+		return Failure
+	default:
+		return Success
+	}
+}
+
+// String describes the outcome, e.g. "SUCCESS by 3 (rolled 11 vs skill 14)"
+func (r RollUnder) String() string {
+	margin := r.Margin()
+	by := fmt.Sprintf("by %d", margin)
+	switch success := r.Degree() >= Success; {
+	case success && margin < 0:
+		by = fmt.Sprintf("despite missing by %d", -margin)
+	case margin < 0:
+		by = fmt.Sprintf("by %d", -margin)
+	case !success:
+		by = fmt.Sprintf("despite making it by %d", margin)
+	}
+	return fmt.Sprintf("%s %s (rolled %d vs skill %d)", r.Degree(), by, r.Result.Total, r.Skill)
+}
+
+// RollUnderThresholds describes the critical thresholds at a skill, e.g.
+// "crit on 3-5, critfail on 17-18"
+func RollUnderThresholds(skill int) string {
+	crit := 4
+	if skill >= 16 {
+		crit = 6
+	} else if skill >= 15 {
+		crit = 5
+	}
+	critFail := 18
+	if skill <= 15 {
+		critFail = 17
+	}
+	critFail = max(min(critFail, skill+10), crit+1)
+	if critFail == 18 {
+		return fmt.Sprintf("crit on 3-%d, critfail on 18", crit)
+	}
+	return fmt.Sprintf("crit on 3-%d, critfail on %d-18", crit, critFail)
+}
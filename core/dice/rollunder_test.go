@@ -0,0 +1,108 @@
+package dice
+
+import "testing"
+
+func TestRollUnderDegree(t *testing.T) {
+	tests := []struct {
+		skill    int
+		roll     int
+		expected Degree
+	}{
+		{14, 3, CriticalSuccess},
+		{14, 4, CriticalSuccess},
+		{14, 5, Success},
+		{15, 5, CriticalSuccess},
+		{16, 6, CriticalSuccess},
+		{14, 14, Success},
+		{14, 15, Failure},
+		{14, 17, CriticalFailure},
+		{16, 17, Failure},
+		{20, 17, Failure},
+		{20, 18, CriticalFailure},
+		{6, 16, CriticalFailure},
+		{6, 15, Failure},
+		{2, 4, CriticalSuccess},
+	}
+	for _, tt := range tests {
+		r := RollUnder{Result: &Result{Total: tt.roll}, Skill: tt.skill}
+		if got := r.Degree(); got != tt.expected {
+			t.Errorf("Rolled %d vs skill %d: expected %s, got %s", tt.roll, tt.skill, tt.expected, got)
+		}
+	}
+}
+
+func TestRollUnderString(t *testing.T) {
+	tests := []struct {
+		skill    int
+		roll     int
+		expected string
+	}{
+		{14, 11, "SUCCESS by 3 (rolled 11 vs skill 14)"},
+		{14, 16, "FAILURE by 2 (rolled 16 vs skill 14)"},
+		{2, 4, "CRITICAL SUCCESS despite missing by 2 (rolled 4 vs skill 2)"},
+		{20, 17, "FAILURE despite making it by 3 (rolled 17 vs skill 20)"},
+	}
+	for _, tt := range tests {
+		r := RollUnder{Result: &Result{Total: tt.roll}, Skill: tt.skill}
+		if got := r.String(); got != tt.expected {
+			t.Errorf("Expected '%s', got '%s'", tt.expected, got)
+		}
+	}
+}
+
+func TestRollUnderThresholds(t *testing.T) {
+	tests := []struct {
+		skill    int
+		expected string
+	}{
+		{10, "crit on 3-4, critfail on 17-18"},
+		{15, "crit on 3-5, critfail on 17-18"},
+		{16, "crit on 3-6, critfail on 18"},
+		{5, "crit on 3-4, critfail on 15-18"},
+		{-8, "crit on 3-4, critfail on 5-18"},
+	}
+	for _, tt := range tests {
+		if got := RollUnderThresholds(tt.skill); got != tt.expected {
+			t.Errorf("Skill %d: expected '%s', got '%s'", tt.skill, tt.expected, got)
+		}
+	}
+}
+
+func TestParseSkill(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int
+		wantErr  bool
+	}{
+		{"14", 14, false},
+		{"14-2", 12, false},
+		{"12 + 2 - 1", 13, false},
+		{"-3", -3, false},
+		{"", 0, true},
+		{"fourteen", 0, true},
+		{"14--2", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseSkill(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseSkill(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if got != tt.expected {
+			t.Errorf("ParseSkill(%q) = %d, want %d", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestRollAgainst(t *testing.T) {
+	defer SetRoller(CurrentRoller())
+	SetRoller(NewSequenceRoller(4, 3, 4))
+
+	r, err := RollAgainst(14)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if r.Result.Total != 11 || r.Degree() != Success || r.Margin() != 3 {
+		t.Errorf("Expected 11 to succeed by 3, got %d (%s by %d)", r.Result.Total, r.Degree(), r.Margin())
+	}
+}
@@ -0,0 +1,86 @@
+package dice
+
+import (
+	"fmt"
+	"math"
+)
+
+// SelfTestSides are the die sizes a dice self-test checks
+var SelfTestSides = []int{4, 6, 8, 10, 12, 20, 100}
+
+// SelfTestRollsPerFace is how many times each face should come up, on
+// average, in a self-test sample (so bigger dice get bigger samples)
+const SelfTestRollsPerFace = 1000
+
+// suspiciousP is the p-value below which a die is reported as unfair
+// A fair die falls below it only once in a thousand checks
+const suspiciousP = 0.001
+
+// Uniformity is the result of a chi-squared check that every face of a die
+// comes up equally often
+type Uniformity struct {
+	Sides      int
+	Rolls      int
+	Counts     []int   // how often each face came up (Counts[0] is face 1)
+	ChiSquared float64 // chi-squared statistic against a uniform spread
+	PValue     float64 // chance of a spread at least this uneven from a fair die
+}
+
+// CheckUniformity rolls a die rolls times and tests whether its faces come up equally often
+func CheckUniformity(roller DieRoller, sides, rolls int) (*Uniformity, error) {
+	if sides < 2 {
+		return nil, fmt.Errorf("die must have at least 2 sides")
+	}
+	if rolls < sides {
+		return nil, fmt.Errorf("need at least %d rolls to test a d%d", sides, sides)
+	}
+
+	counts := make([]int, sides)
+	for i := 0; i < rolls; i++ {
+		v, err := roller.RollDie(sides)
+		if err != nil {
+			return nil, err
+		}
+		if v < 1 || v > sides {
+			return nil, fmt.Errorf("rolled %d on a d%d", v, sides)
+		}
+		counts[v-1]++
+	}
+
+	expected := float64(rolls) / float64(sides)
+	chi := 0.0
+	for _, n := range counts {
+		d := float64(n) - expected
+		chi += d * d / expected
+	}
+	return &Uniformity{
+		Sides:      sides,
+		Rolls:      rolls,
+		Counts:     counts,
+		ChiSquared: chi,
+		PValue:     chiSquaredP(chi, sides-1),
+	}, nil
+}
+
+// Fair returns false if the spread is too uneven to believe the die is fair
+func (u *Uniformity) Fair() bool {
+	return u.PValue >= suspiciousP
+}
+
+// Summary returns a one-line report, e.g. "d20: 20000 rolls, chi-squared 17.3 (19 df), p = 0.57"
+func (u *Uniformity) Summary() string {
+	return fmt.Sprintf("d%d: %d rolls, chi-squared %.1f (%d df), p = %.3f", u.Sides, u.Rolls, u.ChiSquared, u.Sides-1, u.PValue)
+}
+
+// chiSquaredP returns the chance of a chi-squared statistic of at least x
+// with df degrees of freedom, using the Wilson-Hilferty approximation
+// (accurate to a few parts in a thousand for the dice sizes we test)
+func chiSquaredP(x float64, df int) float64 {
+	if x <= 0 {
+		return 1
+	}
+	k := float64(df)
+	v := 2 / (9 * k)
+	z := (math.Cbrt(x/k) - (1 - v)) / math.Sqrt(v)
+	return 0.5 * math.Erfc(z/math.Sqrt2)
+}
@@ -0,0 +1,55 @@
+package dice
+
+import (
+	"math"
+	"testing"
+)
+
+func TestChiSquaredP(t *testing.T) {
+	// Critical values from a chi-squared table
+	tests := []struct {
+		x        float64
+		df       int
+		expected float64
+	}{
+		{9.342, 10, 0.5},
+		{18.307, 10, 0.05},
+		{30.144, 19, 0.05},
+		{36.191, 19, 0.01},
+		{148.23, 99, 0.001},
+	}
+	for _, tt := range tests {
+		if got := chiSquaredP(tt.x, tt.df); math.Abs(got-tt.expected) > tt.expected*0.1 {
+			t.Errorf("chiSquaredP(%.3f, %d): expected %.3f, got %.4f", tt.x, tt.df, tt.expected, got)
+		}
+	}
+}
+
+func TestCheckUniformity(t *testing.T) {
+	for _, sides := range SelfTestSides {
+		u, err := CheckUniformity(NewSeededRoller(7), sides, sides*SelfTestRollsPerFace)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !u.Fair() {
+			t.Errorf("Expected the seeded d%d to look fair, got %s", sides, u.Summary())
+		}
+	}
+
+	// A die that favors its first face
+	loaded := NewSequenceRoller(1, 1, 2, 3, 4, 5, 6)
+	u, err := CheckUniformity(loaded, 6, 6000)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if u.Fair() {
+		t.Errorf("Expected a loaded die to fail, got %s", u.Summary())
+	}
+
+	if _, err := CheckUniformity(NewSequenceRoller(7), 6, 60); err == nil {
+		t.Error("Expected error for a roll off the die")
+	}
+	if _, err := CheckUniformity(CryptoRoller{}, 20, 10); err == nil {
+		t.Error("Expected error for too few rolls")
+	}
+}
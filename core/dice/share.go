@@ -0,0 +1,145 @@
+package dice
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// sharePrefix marks a share code (and its format version)
+const sharePrefix = "ts1."
+
+// shareHashLength is how many hex characters of the digest a share code carries
+const shareHashLength = 8
+
+// ShareCode encodes the roll (notation, every die rolled, when, and any bonus
+// from outside the notation) as a compact string another TavernShell can
+// decode with DecodeShare
+// The code carries a short checksum, so a mistyped or mangled code is
+// rejected; it isn't a signature, so anyone can edit a code and fix it up
+func (r *Result) ShareCode(at time.Time) string {
+	groups := []string{joinValues(r.Rolls)}
+	for _, g := range r.Groups {
+		groups = append(groups, joinValues(g.Rolls))
+	}
+	payload := fmt.Sprintf("%s|%s|%d", r.Expression.String(), strings.Join(groups, ";"), at.Unix())
+	if r.Bonus != 0 {
+		payload += fmt.Sprintf("|%d %s", r.Bonus, strings.ReplaceAll(r.BonusNote, "|", "/"))
+	}
+	payload += "|" + receiptHash(payload)[:shareHashLength]
+	return sharePrefix + base64.RawURLEncoding.EncodeToString([]byte(payload))
+}
+
+// DecodeShare decodes a share code back into the roll it describes and when it was rolled
+// Kept and dropped dice are worked out again from the notation
+func DecodeShare(code string) (*Result, time.Time, error) {
+	invalid := fmt.Errorf("invalid share code")
+
+	code = strings.TrimSpace(code)
+	if !strings.HasPrefix(code, sharePrefix) {
+		return nil, time.Time{}, invalid
+	}
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(code, sharePrefix))
+	if err != nil {
+		return nil, time.Time{}, invalid
+	}
+	// A roll with a bonus has it as a fourth field, before the checksum
+	fields := strings.Split(string(data), "|")
+	if len(fields) != 4 && len(fields) != 5 {
+		return nil, time.Time{}, invalid
+	}
+	last := len(fields) - 1
+	payload := strings.Join(fields[:last], "|")
+	if receiptHash(payload)[:shareHashLength] != fields[last] {
+		return nil, time.Time{}, fmt.Errorf("share code doesn't match its checksum (mistyped or edited?)")
+	}
+
+	expr, err := Parse(fields[0])
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("invalid share code: %w", err)
+	}
+	if len(fields) == 5 {
+		amount, note, _ := strings.Cut(fields[3], " ")
+		if expr.Bonus, err = strconv.Atoi(amount); err != nil {
+			return nil, time.Time{}, invalid
+		}
+		expr.BonusNote = note
+	}
+	unix, err := strconv.ParseInt(fields[2], 10, 64)
+	if err != nil {
+		return nil, time.Time{}, invalid
+	}
+
+	groups := strings.Split(fields[1], ";")
+	if len(groups) != len(expr.Groups)+1 {
+		return nil, time.Time{}, invalid
+	}
+	rolls, keptTotal, err := restoreGroup(groups[0], expr.firstGroup())
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	result := &Result{Expression: expr, Rolls: rolls}
+	for i, g := range expr.Groups {
+		groupRolls, groupTotal, err := restoreGroup(groups[i+1], g)
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+		result.Groups = append(result.Groups, GroupResult{Group: g, Rolls: groupRolls, KeptTotal: groupTotal})
+		if g.Negative {
+			keptTotal -= groupTotal
+		} else {
+			keptTotal += groupTotal
+		}
+	}
+	result.KeptTotal = keptTotal
+	result.Total = expr.scale(keptTotal+expr.Modifier) + expr.Bonus
+	result.Bonus, result.BonusNote = expr.Bonus, expr.BonusNote
+	result.markNaturals()
+	return result, time.Unix(unix, 0), nil
+}
+
+// joinValues lists the faces dice landed on as "4,2,6", with each of a digit
+// die's digits (minimums are applied again when the code is decoded)
+func joinValues(rolls []Die) string {
+	var values []string
+	for _, die := range rolls {
+		if len(die.Digits) > 0 {
+			for _, digit := range die.Digits {
+				values = append(values, strconv.Itoa(digit))
+			}
+			continue
+		}
+		values = append(values, strconv.Itoa(die.Natural()))
+	}
+	return strings.Join(values, ",")
+}
+
+// restoreGroup rebuilds a group's dice from "4,2,6" by replaying the values
+// through the group's rules, checking they fit the notation exactly
+func restoreGroup(values string, g *Group) ([]Die, int, error) {
+	parts := strings.Split(values, ",")
+	next := 0
+	replay := func(sides int) (int, error) {
+		if next >= len(parts) {
+			return 0, fmt.Errorf("invalid share code: too few dice for %s", formatGroup(g))
+		}
+		part := parts[next]
+		next++
+		value, err := strconv.Atoi(part)
+		if err != nil || value < 1 || value > sides {
+			return 0, fmt.Errorf("invalid share code: '%s' can't be rolled on a d%d", part, sides)
+		}
+		return value, nil
+	}
+
+	rolls, keptTotal, err := rollGroup(g, replay)
+	if err != nil {
+		return nil, 0, err
+	}
+	if next != len(parts) {
+		return nil, 0, fmt.Errorf("invalid share code: too many dice for %s", formatGroup(g))
+	}
+	return rolls, keptTotal, nil
+}
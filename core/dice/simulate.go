@@ -0,0 +1,121 @@
+package dice
+
+import (
+	"fmt"
+)
+
+const (
+	// DefaultSimulations is how many rolls a simulation makes unless told otherwise
+	DefaultSimulations = 100000
+
+	// MaxSimulations caps the rolls in a simulation
+	MaxSimulations = 1000000
+
+	// MaxSimulatedDice caps the dice a simulation rolls in all (rolls times
+	// dice per roll), keeping it under a second: 100,000 rolls of 1000d6
+	// took six
+	MaxSimulatedDice = 10000000
+)
+
+// Simulation is the outcome of rolling an expression many times: the spread
+// of totals that came up, and how often they met a target
+type Simulation struct {
+	Stats          // Min, max, mean and the share of rolls landing on each total
+	Rolls     int  // How many times the expression was rolled
+	Check     bool // Whether the rolls were made against Target
+	Target    int
+	Successes int // Rolls that met the target
+}
+
+// Simulate rolls an expression n times with roller, counting how often the
+// total meets target if check is set
+func Simulate(expr *Expression, n int, roller DieRoller, target int, check bool) (*Simulation, error) {
+	if expr == nil {
+		return nil, fmt.Errorf("nil expression")
+	}
+	if n < 1 || n > MaxSimulations {
+		return nil, fmt.Errorf("can't simulate %s rolls (expected 1 to %s)", thousands(n), thousands(MaxSimulations))
+	}
+	if limit := MaxRolls(expr); n > limit {
+		return nil, fmt.Errorf("can't simulate %s rolls of %s dice (at most %s)", thousands(n), thousands(expr.diceToRoll()), thousands(limit))
+	}
+
+	groups := append([]*Group{expr.firstGroup()}, expr.Groups...)
+	counts := make(map[int]int)
+	sim := &Simulation{Rolls: n, Check: check, Target: target}
+	sum := 0
+	for i := 0; i < n; i++ {
+		total := expr.Modifier
+		for _, g := range groups {
+			_, kept, err := rollGroup(g, roller.RollDie)
+			if err != nil {
+				return nil, err
+			}
+			if g.Negative {
+				kept = -kept
+			}
+			total += kept
+		}
+		total = expr.scale(total)
+		counts[total]++
+		sum += total
+		if i == 0 || total < sim.Min {
+			sim.Min = total
+		}
+		if i == 0 || total > sim.Max {
+			sim.Max = total
+		}
+		if check && total >= target {
+			sim.Successes++
+		}
+	}
+
+	sim.Expression = expr
+	sim.Simulated = true
+	sim.Mean = float64(sum) / float64(n)
+	sim.Probabilities = make(map[int]float64, len(counts))
+	for v, c := range counts {
+		sim.Probabilities[v] = float64(c) / float64(n)
+	}
+	return sim, nil
+}
+
+// MaxRolls returns the most times a simulation may roll expr: MaxSimulations,
+// or fewer for a roll of many dice
+func MaxRolls(expr *Expression) int {
+	return min(MaxSimulations, MaxSimulatedDice/max(expr.diceToRoll(), 1))
+}
+
+// SuccessRate is the share of rolls that met the target
+func (s *Simulation) SuccessRate() float64 {
+	return float64(s.Successes) / float64(s.Rolls)
+}
+
+// Summary returns a one-line description, e.g.
+// "2d6+1d8 vs 15: 22.9% succeeded (22,871 of 100,000); min 3, max 20, mean 11.5"
+func (s *Simulation) Summary() string {
+	spread := fmt.Sprintf("min %d, max %d, mean %.2f", s.Min, s.Max, s.Mean)
+	if !s.Check {
+		return fmt.Sprintf("%s over %s rolls: %s", s.Expression.String(), thousands(s.Rolls), spread)
+	}
+	return fmt.Sprintf("%s vs %d: %.1f%% succeeded (%s of %s); %s",
+		s.Expression.String(), s.Target, s.SuccessRate()*100, thousands(s.Successes), thousands(s.Rolls), spread)
+}
+
+// thousands formats a count with comma separators, e.g. 100,000
+func thousands(n int) string {
+	s := fmt.Sprint(n)
+	for i := len(s) - 3; i > 0 && s[i-1] != '-'; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	return s
+}
+
+// SimulationRoller returns the roller a simulation should use: the current
+// roller, except that crypto/rand is read in batches
+func SimulationRoller() DieRoller {
+	if _, ok := CurrentRoller().(CryptoRoller); ok {
+		return NewBatchRoller()
+	}
+	return CurrentRoller()
+}
@@ -0,0 +1,134 @@
+package dice
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestSimulate(t *testing.T) {
+	expr, err := Parse("d6")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	sim, err := Simulate(expr, 6, NewSequenceRoller(1, 2, 3, 4, 5, 6), 4, true)
+	if err != nil {
+		t.Fatalf("Simulate failed: %v", err)
+	}
+	if sim.Min != 1 || sim.Max != 6 || sim.Mean != 3.5 || sim.Successes != 3 {
+		t.Errorf("Expected 1-6, mean 3.5, 3 successes, got %d-%d, mean %.2f, %d successes", sim.Min, sim.Max, sim.Mean, sim.Successes)
+	}
+	if p := sim.Probabilities[4]; math.Abs(p-1.0/6) > 1e-9 {
+		t.Errorf("Expected each face 1/6 of the time, got %.3f for 4", p)
+	}
+	expected := "1d6 vs 4: 50.0% succeeded (3 of 6); min 1, max 6, mean 3.50"
+	if sim.Summary() != expected {
+		t.Errorf("Expected '%s', got '%s'", expected, sim.Summary())
+	}
+
+	if _, err := Simulate(expr, 0, NewSeededRoller(1), 0, false); err == nil {
+		t.Error("Expected error for zero rolls")
+	}
+	if _, err := Simulate(expr, MaxSimulations+1, NewSeededRoller(1), 0, false); err == nil {
+		t.Error("Expected error for too many rolls")
+	}
+}
+
+func TestSimulateManyDice(t *testing.T) {
+	tests := []struct {
+		notation string
+		maxRolls int
+	}{
+		{"d20", MaxSimulations},
+		{"100d6", 100000},
+		{"1000d6", 10000},
+		{"500d6+500d6", 10000},
+		{"1000d20!", 5000},
+	}
+	for _, tt := range tests {
+		expr, err := Parse(tt.notation)
+		if err != nil {
+			t.Fatalf("Parse failed: %v", err)
+		}
+		if got := MaxRolls(expr); got != tt.maxRolls {
+			t.Errorf("%s: expected at most %d rolls, got %d", tt.notation, tt.maxRolls, got)
+		}
+	}
+
+	// 100,000 rolls of 1000d6 took six seconds
+	expr, err := Parse("1000d6")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	_, err = Simulate(expr, 100000, NewSeededRoller(1), 0, false)
+	if err == nil || err.Error() != "can't simulate 100,000 rolls of 1,000 dice (at most 10,000)" {
+		t.Errorf("Expected too many dice to be refused, got %v", err)
+	}
+}
+
+func TestSimulateGroups(t *testing.T) {
+	expr, err := Parse("2d6+1d8-1d4+2")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	sim, err := Simulate(expr, 50000, NewSeededRoller(7), 0, false)
+	if err != nil {
+		t.Fatalf("Simulate failed: %v", err)
+	}
+	// 7 + 4.5 - 2.5 + 2
+	if math.Abs(sim.Mean-11) > 0.1 {
+		t.Errorf("Expected a mean near 11, got %.2f", sim.Mean)
+	}
+	if sim.Min < 1 || sim.Max > 21 {
+		t.Errorf("Expected totals within 1-21, got %d-%d", sim.Min, sim.Max)
+	}
+}
+
+func TestSimulateSpeed(t *testing.T) {
+	expr, err := Parse("2d6+1d8")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	start := time.Now()
+	sim, err := Simulate(expr, DefaultSimulations, NewBatchRoller(), 15, true)
+	if err != nil {
+		t.Fatalf("Simulate failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected %d rolls in well under a second, took %s", DefaultSimulations, elapsed)
+	}
+	// 15+ on 2d6+1d8 comes up 56 times in 288
+	if rate := sim.SuccessRate(); math.Abs(rate-56.0/288) > 0.01 {
+		t.Errorf("Expected about %.3f to succeed, got %.3f", 56.0/288, rate)
+	}
+}
+
+func TestBatchRoller(t *testing.T) {
+	r := NewBatchRoller()
+	seen := make(map[int]bool)
+	for i := 0; i < 2000; i++ {
+		v, err := r.RollDie(6)
+		if err != nil {
+			t.Fatalf("RollDie failed: %v", err)
+		}
+		if v < 1 || v > 6 {
+			t.Fatalf("Expected 1-6, got %d", v)
+		}
+		seen[v] = true
+	}
+	if len(seen) != 6 {
+		t.Errorf("Expected every face to come up, got %d of them", len(seen))
+	}
+	if _, err := r.RollDie(1); err == nil {
+		t.Error("Expected error for a 1-sided die")
+	}
+}
+
+func TestThousands(t *testing.T) {
+	tests := map[int]string{0: "0", 999: "999", 1000: "1,000", 100000: "100,000", 1234567: "1,234,567", -4500: "-4,500"}
+	for n, expected := range tests {
+		if got := thousands(n); got != expected {
+			t.Errorf("thousands(%d) = %q, want %q", n, got, expected)
+		}
+	}
+}
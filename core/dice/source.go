@@ -0,0 +1,219 @@
+package dice
+
+import (
+	cryptorand "crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DieRoller is a source of die results
+// Implementations decide where the entropy comes from (crypto/rand, a seeded
+// PRNG for reproducible demos, or an external source such as a dice tower)
+type DieRoller interface {
+	// RollDie returns a value between 1 and sides (inclusive)
+	RollDie(sides int) (int, error)
+}
+
+// CryptoRoller rolls dice using crypto/rand (the default)
+type CryptoRoller struct{}
+
+// RollDie rolls a single die using crypto/rand
+func (CryptoRoller) RollDie(sides int) (int, error) {
+	return cryptoRollDie(sides)
+}
+
+// BatchRoller rolls dice using crypto/rand, reading the entropy in large
+// batches rather than once per die
+// CryptoRoller shares one; a simulation gets its own (see SimulationRoller)
+// so it doesn't hold up other rolls
+type BatchRoller struct {
+	buf  [batchSize]byte
+	next int // offset of the next unused bytes in buf
+	mu   sync.Mutex
+}
+
+// batchSize is how many bytes of entropy a BatchRoller reads at a time (512 dice)
+const batchSize = 4096
+
+// entropyPool is the batch CryptoRoller draws from
+var entropyPool = NewBatchRoller()
+
+// NewBatchRoller creates a batched crypto/rand roller
+func NewBatchRoller() *BatchRoller {
+	return &BatchRoller{next: batchSize}
+}
+
+// RollDie rolls a single die from the batch, reading another when it runs out
+func (b *BatchRoller) RollDie(sides int) (int, error) {
+	if sides < 2 {
+		return 0, fmt.Errorf("die must have at least 2 sides")
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.next+8 > batchSize {
+		if _, err := cryptorand.Read(b.buf[:]); err != nil {
+			return 0, fmt.Errorf("failed to generate random number: %w", err)
+		}
+		b.next = 0
+	}
+	randomNum := binary.BigEndian.Uint64(b.buf[b.next : b.next+8])
+	b.next += 8
+	// Use modulo to get a number in range [0, sides-1], then add 1
+	// This is slightly biased, but the bias is negligible for crypto/rand
+	return int(randomNum%uint64(sides)) + 1, nil
+}
+
+// SeededRoller rolls dice from a deterministic PRNG
+// The same seed always produces the same sequence of rolls
+type SeededRoller struct {
+	rng *rand.Rand
+	mu  sync.Mutex
+}
+
+// NewSeededRoller creates a roller seeded with the given value
+func NewSeededRoller(seed uint64) *SeededRoller {
+	return &SeededRoller{
+		rng: rand.New(rand.NewPCG(seed, seed)),
+	}
+}
+
+// RollDie rolls a single die from the seeded sequence
+func (s *SeededRoller) RollDie(sides int) (int, error) {
+	if sides < 2 {
+		return 0, fmt.Errorf("die must have at least 2 sides")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rng.IntN(sides) + 1, nil
+}
+
+// SequenceRoller returns fixed values in order, starting over when it runs out
+// Useful for tests and for replaying the exact rolls from a bug report
+type SequenceRoller struct {
+	values []int
+	next   int
+	mu     sync.Mutex
+}
+
+// NewSequenceRoller creates a roller that returns values in order
+func NewSequenceRoller(values ...int) *SequenceRoller {
+	return &SequenceRoller{values: values}
+}
+
+// RollDie returns the next value in the sequence
+func (s *SequenceRoller) RollDie(sides int) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.values) == 0 {
+		return 0, fmt.Errorf("empty roll sequence")
+	}
+	value := s.values[s.next]
+	s.next = (s.next + 1) % len(s.values)
+	if value < 1 || value > sides {
+		return 0, fmt.Errorf("sequence value %d doesn't fit a d%d", value, sides)
+	}
+	return value, nil
+}
+
+var (
+	currentRoller DieRoller = CryptoRoller{}
+	rollerMu      sync.RWMutex
+
+	forced   []int // die results queued by Force, used before the roller's
+	forcedMu sync.Mutex
+)
+
+// Force queues die results for the next dice rolled, whichever roller is in
+// use, so a demo, screenshot or test shows a particular roll (Force(20) for
+// a critical hit); each value is used once, in order
+func Force(values ...int) error {
+	for _, v := range values {
+		if v < 1 {
+			return fmt.Errorf("can't force a die to roll %d", v)
+		}
+	}
+	forcedMu.Lock()
+	defer forcedMu.Unlock()
+	forced = append(forced, values...)
+	return nil
+}
+
+// Forced returns the die results still queued by Force
+func Forced() []int {
+	forcedMu.Lock()
+	defer forcedMu.Unlock()
+	return append([]int(nil), forced...)
+}
+
+// ClearForced drops any die results still queued by Force
+func ClearForced() {
+	forcedMu.Lock()
+	defer forcedMu.Unlock()
+	forced = nil
+}
+
+// nextForced takes the next die result queued by Force, if there is one
+// A value too big for the die is an error, and drops the rest of the queue
+func nextForced(sides int) (int, bool, error) {
+	forcedMu.Lock()
+	defer forcedMu.Unlock()
+	if len(forced) == 0 {
+		return 0, false, nil
+	}
+	value := forced[0]
+	forced = forced[1:]
+	if value > sides {
+		forced = nil
+		return 0, true, fmt.Errorf("forced roll %d doesn't fit a d%d", value, sides)
+	}
+	return value, true, nil
+}
+
+// CurrentRoller returns the roller used by RollExpression
+func CurrentRoller() DieRoller {
+	rollerMu.RLock()
+	defer rollerMu.RUnlock()
+	return currentRoller
+}
+
+// SetRoller replaces the roller used by RollExpression (nil restores crypto/rand)
+func SetRoller(r DieRoller) {
+	rollerMu.Lock()
+	defer rollerMu.Unlock()
+	if r == nil {
+		r = CryptoRoller{}
+	}
+	currentRoller = r
+}
+
+// ParseRoller creates a roller from a spec string
+// Supported specs: "crypto", "seeded:<seed>" and "sequence:<n>,<n>,..."
+func ParseRoller(spec string) (DieRoller, error) {
+	name, arg, _ := strings.Cut(strings.ToLower(strings.TrimSpace(spec)), ":")
+	switch name {
+	case "", "crypto":
+		return CryptoRoller{}, nil
+	case "seeded", "seed":
+		seed, err := strconv.ParseUint(arg, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid seed '%s' (expected seeded:<number>)", arg)
+		}
+		return NewSeededRoller(seed), nil
+	case "sequence", "seq":
+		var values []int
+		for _, field := range strings.Split(arg, ",") {
+			value, err := strconv.Atoi(strings.TrimSpace(field))
+			if err != nil || value < 1 {
+				return nil, fmt.Errorf("invalid roll '%s' in sequence (expected sequence:<n>,<n>,...)", field)
+			}
+			values = append(values, value)
+		}
+		return NewSequenceRoller(values...), nil
+	default:
+		return nil, fmt.Errorf("unknown roller '%s' (expected crypto, seeded:<seed> or sequence:<n>,...)", name)
+	}
+}
@@ -0,0 +1,322 @@
+package dice
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+const (
+	// maxExactSupport bounds the totals a group may span to be worked out exactly
+	maxExactSupport = 2000
+
+	// maxExactWork bounds the products summed across all the convolutions of
+	// one expression (a fifth of a second or so); past it, the expression is
+	// simulated instead, so sixteen d2000s added up don't freeze the shell
+	maxExactWork = 5000000
+
+	// statsSamples is how many rolls a simulated group is estimated from
+	statsSamples = 20000
+
+	// statsSeed seeds simulations, so the same expression always gives the same chart
+	statsSeed = 20
+)
+
+// Stats describes the spread of totals an expression can roll
+type Stats struct {
+	Expression    *Expression
+	Min           int
+	Max           int
+	Mean          float64
+	Probabilities map[int]float64 // chance of each total
+	Simulated     bool            // true if any group was estimated by rolling rather than worked out
+}
+
+// Analyze works out the min, max, mean and distribution of an expression's total
+// Groups without keep/drop are worked out exactly; the rest are simulated, and
+// so is the whole expression if working it out would take too long
+func Analyze(expr *Expression) (*Stats, error) {
+	if expr == nil {
+		return nil, fmt.Errorf("nil expression")
+	}
+
+	stats := &Stats{
+		Expression:    expr,
+		Min:           expr.Modifier,
+		Max:           expr.Modifier,
+		Probabilities: map[int]float64{expr.Modifier: 1},
+	}
+
+	groups := append([]*Group{expr.firstGroup()}, expr.Groups...)
+	work := 0
+	for _, g := range groups {
+		low, high := groupRange(g)
+		dist, ok := exactGroup(g, &work)
+		if !ok {
+			var err error
+			if dist, err = simulateGroup(g); err != nil {
+				return nil, err
+			}
+			stats.Simulated = true
+		}
+		if g.Negative {
+			low, high = -high, -low
+			negated := make(map[int]float64, len(dist))
+			for v, p := range dist {
+				negated[-v] = p
+			}
+			dist = negated
+		}
+		stats.Min += low
+		stats.Max += high
+		if work += len(stats.Probabilities) * len(dist); work > maxExactWork {
+			return simulateStats(expr)
+		}
+		stats.Probabilities = convolve(stats.Probabilities, dist)
+	}
+
+	if expr.Halve || expr.Double {
+		scaled := make(map[int]float64, len(stats.Probabilities))
+		for v, p := range stats.Probabilities {
+			scaled[expr.scale(v)] += p
+		}
+		stats.Probabilities = scaled
+		stats.Min, stats.Max = expr.scale(stats.Min), expr.scale(stats.Max)
+	}
+
+	for v, p := range stats.Probabilities {
+		stats.Mean += float64(v) * p
+	}
+	return stats, nil
+}
+
+// groupRange returns the lowest and highest total a group can roll
+func groupRange(g *Group) (int, int) {
+	kept := g.Count
+	for _, op := range g.Operations {
+		kept = op.remaining(kept)
+	}
+
+	low, high := 0, g.faceCount()-1
+	if g.Reroll != nil && !g.Reroll.Once {
+		// Rerolling until it sticks means the rerolled faces never count
+		for low < high && g.Reroll.matches(g.face(low)) {
+			low++
+		}
+		for high > 0 && g.Reroll.matches(g.face(high)) {
+			high--
+		}
+	}
+	return kept * max(g.face(low), g.Min), kept * g.face(high)
+}
+
+// exactGroup works out a group's distribution of kept totals, if it has no
+// keep/drop and is small enough to work out, adding what it took to work
+func exactGroup(g *Group, work *int) (map[int]float64, bool) {
+	if len(g.Operations) > 0 || g.Count*g.Sides > maxExactSupport {
+		return nil, false
+	}
+
+	die := faceOdds(g)
+	dist := map[int]float64{0: 1}
+	for i := 0; i < g.Count; i++ {
+		if *work += len(dist) * len(die); *work > maxExactWork {
+			return nil, false
+		}
+		dist = convolve(dist, die)
+	}
+	return dist, true
+}
+
+// simulateStats estimates an expression's distribution by rolling it, keeping
+// the range it can actually roll
+func simulateStats(expr *Expression) (*Stats, error) {
+	sim, err := Simulate(expr, statsSamples, NewSeededRoller(statsSeed), 0, false)
+	if err != nil {
+		return nil, err
+	}
+	stats := sim.Stats
+	stats.Min, stats.Max = expr.Modifier, expr.Modifier
+	for _, g := range append([]*Group{expr.firstGroup()}, expr.Groups...) {
+		low, high := groupRange(g)
+		if g.Negative {
+			low, high = -high, -low
+		}
+		stats.Min += low
+		stats.Max += high
+	}
+	stats.Min, stats.Max = expr.scale(stats.Min), expr.scale(stats.Max)
+	return &stats, nil
+}
+
+// faceOdds returns the chance of each face on one die of the group, after
+// rerolls, minimums and (dis)advantage
+func faceOdds(g *Group) map[int]float64 {
+	n := g.faceCount()
+	s := float64(n)
+	odds := make(map[int]float64, n)
+
+	matched := 0
+	if g.Reroll != nil {
+		for i := 0; i < n; i++ {
+			if g.Reroll.matches(g.face(i)) {
+				matched++
+			}
+		}
+	}
+	for i := 0; i < n; i++ {
+		v := g.face(i)
+		switch {
+		case g.Reroll == nil:
+			odds[v] = 1 / s
+		case g.Reroll.Once:
+			// Either it stuck the first time, or it was rerolled into v
+			if !g.Reroll.matches(v) {
+				odds[v] = 1 / s
+			}
+			odds[v] += float64(matched) / s / s
+		case !g.Reroll.matches(v):
+			odds[v] = 1 / float64(n-matched)
+		}
+	}
+
+	// A minimum turns every lower face into the minimum
+	for v := 1; v < g.Min; v++ {
+		odds[g.Min] += odds[v]
+		delete(odds, v)
+	}
+
+	if !g.Advantage && !g.Disadvantage {
+		return odds
+	}
+
+	// Keep the better (or worse) of two (or three) such dice
+	per := float64(g.rollsPerDie())
+	paired := make(map[int]float64, g.Sides)
+	below := 0.0 // chance of rolling under v on one die
+	for v := 1; v <= g.Sides; v++ {
+		atMost := below + odds[v]
+		if g.Advantage {
+			paired[v] = math.Pow(atMost, per) - math.Pow(below, per)
+		} else {
+			paired[v] = math.Pow(1-below, per) - math.Pow(1-atMost, per)
+		}
+		below = atMost
+	}
+	return paired
+}
+
+// simulateGroup estimates a group's distribution of kept totals by rolling it
+func simulateGroup(g *Group) (map[int]float64, error) {
+	roller := NewSeededRoller(statsSeed)
+	counts := make(map[int]int)
+	for i := 0; i < statsSamples; i++ {
+		_, total, err := rollGroup(g, roller.RollDie)
+		if err != nil {
+			return nil, err
+		}
+		counts[total]++
+	}
+
+	dist := make(map[int]float64, len(counts))
+	for v, n := range counts {
+		dist[v] = float64(n) / statsSamples
+	}
+	return dist, nil
+}
+
+// convolve returns the distribution of the sum of two independent totals
+func convolve(a, b map[int]float64) map[int]float64 {
+	sum := make(map[int]float64, len(a)+len(b))
+	for va, pa := range a {
+		for vb, pb := range b {
+			sum[va+vb] += pa * pb
+		}
+	}
+	return sum
+}
+
+// Average is the mean total rounded down, the way 5e stat blocks give a
+// monster's damage (2d6+3 averages 10, 8d6 averages 28)
+func (s *Stats) Average() int {
+	// Allow for the mean landing a hair under a whole number
+	return int(math.Floor(s.Mean + 1e-9))
+}
+
+// Summary returns a one-line description, e.g. "2d6+3: min 5, max 15, mean 10.0"
+func (s *Stats) Summary() string {
+	summary := fmt.Sprintf("%s: min %d, max %d, mean %.1f", s.Expression.String(), s.Min, s.Max, s.Mean)
+	if s.Simulated {
+		summary += fmt.Sprintf(" (simulated over %d rolls)", statsSamples)
+	}
+	return summary
+}
+
+// Chart draws the distribution as horizontal bars, one line per total (or per
+// range of totals, so there are at most rows lines), the longest bar width wide
+func (s *Stats) Chart(rows, width int) []string {
+	if len(s.Probabilities) == 0 || rows < 1 || width < 1 {
+		return nil
+	}
+
+	// Only chart totals that can actually come up
+	totals := make([]int, 0, len(s.Probabilities))
+	for v, p := range s.Probabilities {
+		if p > 0 {
+			totals = append(totals, v)
+		}
+	}
+	sort.Ints(totals)
+	low, high := totals[0], totals[len(totals)-1]
+
+	// Step over the totals as they're spaced, so a doubled roll charts
+	// 2, 4, 6 rather than a 0% line for every odd total in between
+	step := 0
+	for i := 1; i < len(totals); i++ {
+		step = gcd(step, totals[i]-totals[i-1])
+	}
+	step = max(step, 1)
+	bucket := int(math.Ceil(float64((high-low)/step+1)/float64(rows))) * step
+
+	// Group totals into buckets of equal width
+	var odds []float64
+	for start := low; start <= high; start += bucket {
+		p := 0.0
+		for v := start; v < start+bucket; v += step {
+			p += s.Probabilities[v]
+		}
+		odds = append(odds, p)
+	}
+	most := 0.0
+	for _, p := range odds {
+		most = math.Max(most, p)
+	}
+
+	labelWidth := len(fmt.Sprint(high))
+	if n := len(fmt.Sprint(low)); n > labelWidth {
+		labelWidth = n
+	}
+	lines := make([]string, len(odds))
+	for i, p := range odds {
+		start, end := low+i*bucket, min(low+(i+1)*bucket-step, high)
+		label := fmt.Sprintf("%*d", labelWidth, start)
+		if bucket > step {
+			label += strings.Repeat(" ", labelWidth+1)
+			if end > start {
+				label = fmt.Sprintf("%*d-%-*d", labelWidth, start, labelWidth, end)
+			}
+		}
+		bar := strings.Repeat("█", int(math.Round(p/most*float64(width))))
+		lines[i] = strings.TrimRight(fmt.Sprintf("%s %5.1f%% %s", label, p*100, bar), " ")
+	}
+	return lines
+}
+
+// gcd returns the greatest common divisor of a and b (b if a is 0)
+func gcd(a, b int) int {
+	for a != 0 {
+		a, b = b%a, a
+	}
+	return b
+}
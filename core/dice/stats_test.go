@@ -0,0 +1,152 @@
+package dice
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAnalyze(t *testing.T) {
+	tests := []struct {
+		notation  string
+		min, max  int
+		mean      float64
+		simulated bool
+	}{
+		{"2d6+3", 5, 15, 10, false},
+		{"d20!", 1, 20, 13.825, false},
+		{"d20?", 1, 20, 7.175, false},
+		{"d20!!!", 1, 20, 15.4875, false},
+		{"d6r1", 2, 6, 4, false},
+		{"d6ro1", 1, 6, 3.5 + 2.5/6, false},
+		{"d20-d4", -3, 19, 8, false},
+		{"d6min2", 2, 6, 22.0 / 6, false},
+		{"d6min3!", 3, 6, (3*9 + 4*7 + 5*9 + 6*11) / 36.0, false},
+		{"4d6kh3", 3, 18, 12.24, true},
+		{"5d6kh3dh1", 2, 12, 8, true},
+		{"half(2d6+3)", 2, 7, 4.75, false},
+		{"2d6+3*2", 10, 30, 20, false},
+		{"d66", 11, 66, 38.5, false},
+		{"d66r11", 12, 66, 1375.0 / 35, false},
+		{"d666", 111, 666, 388.5, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.notation, func(t *testing.T) {
+			expr, err := Parse(tt.notation)
+			if err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+			stats, err := Analyze(expr)
+			if err != nil {
+				t.Fatalf("Analyze failed: %v", err)
+			}
+			if stats.Min != tt.min || stats.Max != tt.max {
+				t.Errorf("Expected range %d-%d, got %d-%d", tt.min, tt.max, stats.Min, stats.Max)
+			}
+			tolerance := 0.001
+			if tt.simulated {
+				tolerance = 0.1
+			}
+			if math.Abs(stats.Mean-tt.mean) > tolerance {
+				t.Errorf("Expected mean %.3f, got %.3f", tt.mean, stats.Mean)
+			}
+			if stats.Simulated != tt.simulated {
+				t.Errorf("Expected simulated=%v, got %v", tt.simulated, stats.Simulated)
+			}
+		})
+	}
+}
+
+func TestAverage(t *testing.T) {
+	tests := []struct {
+		notation string
+		expected int
+	}{
+		{"2d6+3", 10},
+		{"8d6", 28},
+		{"1d6+2", 5},
+		{"3d6", 10},
+		{"2d10+5", 16},
+		{"d4-3", -1},
+	}
+
+	for _, tt := range tests {
+		expr, err := Parse(tt.notation)
+		if err != nil {
+			t.Fatalf("Parse failed: %v", err)
+		}
+		stats, err := Analyze(expr)
+		if err != nil {
+			t.Fatalf("Analyze failed: %v", err)
+		}
+		if got := stats.Average(); got != tt.expected {
+			t.Errorf("%s: expected average %d, got %d", tt.notation, tt.expected, got)
+		}
+	}
+}
+
+func TestStatsChart(t *testing.T) {
+	expr, _ := Parse("2d6")
+	stats, err := Analyze(expr)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+
+	lines := stats.Chart(20, 10)
+	if len(lines) != 11 {
+		t.Fatalf("Expected one line per total (11), got %d", len(lines))
+	}
+	if lines[5] != " 7  16.7% ██████████" {
+		t.Errorf("Expected the 7 line to have the longest bar, got %q", lines[5])
+	}
+
+	if lines := stats.Chart(4, 10); len(lines) != 4 {
+		t.Errorf("Expected totals grouped into 4 lines, got %d", len(lines))
+	}
+}
+
+func TestStatsChartDoubled(t *testing.T) {
+	expr, _ := Parse("2d6x2")
+	stats, err := Analyze(expr)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+
+	// Only even totals can come up, so there's no line for the odd ones
+	lines := stats.Chart(20, 10)
+	if len(lines) != 11 {
+		t.Fatalf("Expected one line per even total (11), got %d: %q", len(lines), lines)
+	}
+	if lines[0] != " 4   2.8% ██" || lines[5] != "14  16.7% ██████████" {
+		t.Errorf("Expected lines for 4 and 14, got %q and %q", lines[0], lines[5])
+	}
+
+	lines = stats.Chart(4, 10)
+	if len(lines) != 4 || lines[0] != " 4-8   16.7% ████" {
+		t.Errorf("Expected 4 lines starting 4-8, got %q", lines)
+	}
+}
+
+func TestAnalyzeManyBigGroups(t *testing.T) {
+	// Worked out exactly, sixteen d2000s took around 20 seconds
+	expr, err := Parse(strings.TrimSuffix(strings.Repeat("d2000+", 16), "+"))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	start := time.Now()
+	stats, err := Analyze(expr)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected it well under a second, took %s", elapsed)
+	}
+	if !stats.Simulated || stats.Min != 16 || stats.Max != 32000 {
+		t.Errorf("Expected a simulated range of 16-32000, got %d-%d (simulated=%v)", stats.Min, stats.Max, stats.Simulated)
+	}
+	if math.Abs(stats.Mean-16*1000.5) > 200 {
+		t.Errorf("Expected a mean near %.1f, got %.1f", 16*1000.5, stats.Mean)
+	}
+}
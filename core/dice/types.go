@@ -1,12 +1,118 @@
 package dice
 
+import (
+	"strconv"
+	"strings"
+)
+
 // Expression represents a dice notation to be rolled (input)
 type Expression struct {
 	Count     int        // Number of dice to roll
 	Sides     int        // Sides per die
 	Modifier  int        // +/- modifier to add to total
-	Operation *Operation // Optional keep/drop operation
+	Operations []*Operation // Keep/drop operations, applied left to right (e.g. dl1 then kh4)
 	Advantage bool       // Per-die advantage (roll each die twice, keep highest)
+	Disadvantage bool    // Per-die disadvantage (roll each die twice, keep lowest)
+	Triple    bool       // With Advantage, roll each die three times instead (d20!!!, Elven Accuracy)
+	Reroll    *Reroll    // Optional reroll rule (e.g. r1, ro<3)
+	Min       int        // Optional minimum per die (e.g. min2); 0 if none
+	Groups    []*Group   // Further dice groups, e.g. the 1d8 in 2d6+1d8+3
+	Halve     bool       // Halve the total, rounding down (resistance): half(8d6) or 8d6/2
+	Double    bool       // Double the total (vulnerability): double(2d6+3) or 2d6+3*2
+	Label     string     // Optional comment, e.g. "greatsword damage" from "2d6+3 # greatsword damage"
+	Bonus     int        // Added to the total from outside the notation (e.g. buffs); String leaves it out
+	BonusNote string     // Where Bonus comes from, e.g. "Aria +2 attack"
+	Custom    string     // For custom dice (2dhitdir), the die's name; Sides is its number of faces
+}
+
+// scale halves or doubles a total as the expression asks, rounding a halved
+// total down
+func (e *Expression) scale(total int) int {
+	switch {
+	case e.Halve && total < 0:
+		return (total - 1) / 2
+	case e.Halve:
+		return total / 2
+	case e.Double:
+		return total * 2
+	default:
+		return total
+	}
+}
+
+// Group is an additional dice group in a multi-term expression
+type Group struct {
+	Count     int        // Number of dice to roll
+	Sides     int        // Sides per die
+	Operations []*Operation // Keep/drop operations, applied left to right
+	Advantage bool       // Per-die advantage
+	Disadvantage bool    // Per-die disadvantage
+	Triple    bool       // With Advantage, roll each die three times instead
+	Reroll    *Reroll    // Optional reroll rule
+	Min       int        // Optional minimum per die; 0 if none
+	Negative  bool       // Subtracted from the total (e.g. the 1d4 in 1d20-1d4)
+}
+
+// rollsPerDie is how many dice are rolled for each die the group keeps:
+// two with (dis)advantage, three with triple advantage
+func (g *Group) rollsPerDie() int {
+	switch {
+	case g.Advantage && g.Triple:
+		return 3
+	case g.Advantage || g.Disadvantage:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// digitDice reads sides written as one digit repeated (66, 88, 666) as digit
+// dice: one die with the digit's sides for each digit, read as tens, ones and
+// so on, the way Traveller rolls d66. It returns 0 dice for an ordinary die
+func digitDice(sides int) (faces, count int) {
+	s := strconv.Itoa(sides)
+	if len(s) < 2 || s[0] < '2' || strings.Count(s, s[:1]) != len(s) {
+		return 0, 0
+	}
+	return int(s[0] - '0'), len(s)
+}
+
+// faceCount returns how many faces the group's dice have: their sides, or
+// every combination of digits for digit dice
+func (g *Group) faceCount() int {
+	faces, count := digitDice(g.Sides)
+	if count == 0 {
+		return g.Sides
+	}
+	n := 1
+	for range count {
+		n *= faces
+	}
+	return n
+}
+
+// face returns the group's i'th lowest face, counting from 0 (d66 faces
+// run 11-16, 21-26 and on to 66)
+func (g *Group) face(i int) int {
+	faces, count := digitDice(g.Sides)
+	if count == 0 {
+		return i + 1
+	}
+	value, place := 0, 1
+	for range count {
+		value += (i%faces + 1) * place
+		i /= faces
+		place *= 10
+	}
+	return value
+}
+
+// Reroll is a reroll rule: r1 rerolls 1s until the die isn't a 1,
+// and ro<3 rerolls a die once if it's under 3
+type Reroll struct {
+	Value int  // Face to reroll (or the bound, with Below)
+	Below bool // Reroll faces under Value rather than equal to it
+	Once  bool // Reroll each die at most once
 }
 
 // Operation represents a keep/drop operation on rolled dice
@@ -15,6 +121,16 @@ type Operation struct {
 	Count int    // How many dice to keep/drop
 }
 
+// remaining returns how many of kept dice are still kept after the operation
+func (op *Operation) remaining(kept int) int {
+	switch op.Type {
+	case OpKeepHighest, OpKeepLowest:
+		return min(op.Count, kept)
+	default:
+		return max(kept-op.Count, 0)
+	}
+}
+
 // OpType represents the type of keep/drop operation
 type OpType int
 
@@ -27,16 +143,41 @@ const (
 
 // Result represents the outcome of rolling dice (output)
 type Result struct {
-	Expression *Expression // Original expression that created this result
-	Rolls      []Die       // All dice rolled (including dropped)
-	KeptTotal  int         // Sum of kept dice only
-	Total      int         // Final result (kept + modifier)
+	Expression *Expression   // Original expression that created this result
+	Rolls      []Die         // All dice rolled for the first group (including dropped)
+	Groups     []GroupResult // Rolls for each further group, in order
+	KeptTotal  int           // Sum of kept dice only, across all groups
+	Total      int           // Final result (kept + modifier)
+	Crit       bool          // A kept d20 rolled a natural 20
+	Fumble     bool          // A kept d20 rolled a natural 1
+	Label      string        // The expression's label, if any
+	Bonus      int           // The expression's Bonus, already in Total
+	BonusNote  string        // Where Bonus comes from
+}
+
+// GroupResult is the outcome of rolling one further dice group
+type GroupResult struct {
+	Group     *Group // Group that was rolled
+	Rolls     []Die  // All dice rolled (including dropped)
+	KeptTotal int    // Sum of kept dice (before the group's sign is applied)
 }
 
 // Die represents a single rolled die
 type Die struct {
-	Value int  // The rolled value
-	Sides int  // Number of sides on this die
-	Kept  bool // Whether this die counts toward the total
+	Value    int    // The rolled value (after any minimum)
+	Sides    int    // Number of sides on this die
+	Kept     bool   // Whether this die counts toward the total
+	Rerolled bool   // Whether this die was rerolled away (and so isn't kept)
+	Original int    // Value actually rolled, if a minimum raised it (0 if not raised)
+	Digits   []int  // For digit dice (d66), the dice read as its tens, ones and so on
+	Face     string // For custom dice, the label on the face rolled
+}
+
+// Natural returns the face the die actually landed on, before any minimum
+func (d Die) Natural() int {
+	if d.Original != 0 {
+		return d.Original
+	}
+	return d.Value
 }
 
@@ -0,0 +1,244 @@
+package environment
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/angusmclean/tavernshell/core/dice"
+)
+
+// Effect is an environmental condition in play (heavy rain, thick fog) and
+// what it does to checks
+type Effect struct {
+	Name  string
+	Rules []Rule
+}
+
+// Rule is one part of an effect: a bonus or penalty and/or (dis)advantage on
+// checks labelled with any of its tags
+type Rule struct {
+	Amount       int
+	Advantage    bool
+	Disadvantage bool
+	Tags         []string // e.g. "perception" or "ranged" (none applies to every check)
+}
+
+// ParseEffect parses the words after an effect's name; each bonus, penalty or
+// (dis)advantage applies to the tags that follow it, e.g.
+// "-2 perception disadvantage ranged" is -2 on perception and disadvantage on ranged
+func ParseEffect(name string, words []string) (*Effect, error) {
+	if name == "" {
+		return nil, fmt.Errorf("an effect needs a name")
+	}
+	effect := &Effect{Name: name}
+	var rule *Rule
+	for _, word := range words {
+		lower := strings.ToLower(word)
+		amount, err := strconv.Atoi(word)
+		isAmount := err == nil
+		isAdvantage := lower == "advantage" || lower == "adv"
+		isDisadvantage := lower == "disadvantage" || lower == "dis"
+
+		if !isAmount && !isAdvantage && !isDisadvantage {
+			if rule == nil {
+				return nil, fmt.Errorf("expected a bonus, penalty or (dis)advantage before '%s' (e.g., -2 perception)", word)
+			}
+			rule.Tags = append(rule.Tags, lower)
+			continue
+		}
+
+		// Start a new rule once the current one has its tags
+		if rule == nil || len(rule.Tags) > 0 {
+			effect.Rules = append(effect.Rules, Rule{})
+			rule = &effect.Rules[len(effect.Rules)-1]
+		}
+		switch {
+		case isAmount && rule.Amount != 0:
+			return nil, fmt.Errorf("two amounts in a row ('%+d' and '%s')", rule.Amount, word)
+		case isAmount:
+			rule.Amount = amount
+		case isAdvantage:
+			rule.Advantage = true
+		default:
+			rule.Disadvantage = true
+		}
+		if rule.Advantage && rule.Disadvantage {
+			return nil, fmt.Errorf("advantage and disadvantage cancel out; give them different tags")
+		}
+	}
+	if len(effect.Rules) == 0 {
+		return nil, fmt.Errorf("expected a bonus, penalty or (dis)advantage (e.g., -2 perception)")
+	}
+	return effect, nil
+}
+
+// AppliesTo returns true if the rule applies to a check with the given
+// label: it has no tags, or the label has one of them as a word
+func (r Rule) AppliesTo(label string) bool {
+	if len(r.Tags) == 0 {
+		return true
+	}
+	for _, word := range strings.Fields(strings.ToLower(label)) {
+		for _, tag := range r.Tags {
+			if word == tag {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// String returns a string representation of the rule, e.g. "-2 perception"
+func (r Rule) String() string {
+	var parts []string
+	if r.Amount != 0 {
+		parts = append(parts, fmt.Sprintf("%+d", r.Amount))
+	}
+	if r.Advantage {
+		parts = append(parts, "advantage")
+	}
+	if r.Disadvantage {
+		parts = append(parts, "disadvantage")
+	}
+	if len(r.Tags) == 0 {
+		parts = append(parts, "on every check")
+	}
+	return strings.Join(append(parts, r.Tags...), " ")
+}
+
+// String returns a string representation of the effect,
+// e.g. "heavy rain: -2 perception, disadvantage ranged"
+func (e *Effect) String() string {
+	rules := make([]string, len(e.Rules))
+	for i, r := range e.Rules {
+		rules[i] = r.String()
+	}
+	return fmt.Sprintf("%s: %s", e.Name, strings.Join(rules, ", "))
+}
+
+// Adjustment is what the active effects do to one check
+type Adjustment struct {
+	Amount       int
+	Advantage    bool
+	Disadvantage bool
+	Sources      []string // names of the effects that apply
+}
+
+// IsZero returns true if no effect applies
+func (a Adjustment) IsZero() bool {
+	return len(a.Sources) == 0
+}
+
+// Apply adjusts a roll's expression: the amount goes on the modifier, and
+// (dis)advantage on the first dice group, cancelling out against the other
+func (a Adjustment) Apply(expr *dice.Expression) {
+	expr.Modifier += a.Amount
+	switch {
+	case a.Advantage == a.Disadvantage:
+	case a.Advantage && expr.Disadvantage:
+		expr.Disadvantage = false
+	case a.Advantage:
+		expr.Advantage = true
+	case expr.Advantage:
+		expr.Advantage = false
+	default:
+		expr.Disadvantage = true
+	}
+}
+
+// String describes the adjustment, e.g. "-2, disadvantage (heavy rain)"
+func (a Adjustment) String() string {
+	var parts []string
+	if a.Amount != 0 {
+		parts = append(parts, fmt.Sprintf("%+d", a.Amount))
+	}
+	switch {
+	case a.Advantage && a.Disadvantage:
+		parts = append(parts, "advantage and disadvantage cancel")
+	case a.Advantage:
+		parts = append(parts, "advantage")
+	case a.Disadvantage:
+		parts = append(parts, "disadvantage")
+	}
+	if len(parts) == 0 {
+		parts = append(parts, "no change")
+	}
+	return fmt.Sprintf("%s (%s)", strings.Join(parts, ", "), strings.Join(a.Sources, ", "))
+}
+
+// Manager manages the active environmental effects
+type Manager struct {
+	effects []*Effect // in the order they were added
+	mu      sync.RWMutex
+}
+
+// NewManager creates a new environment manager
+func NewManager() *Manager {
+	return &Manager{}
+}
+
+// Add adds an effect, replacing any with the same name (case-insensitive)
+func (m *Manager) Add(effect *Effect) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, e := range m.effects {
+		if strings.EqualFold(e.Name, effect.Name) {
+			m.effects[i] = effect
+			return
+		}
+	}
+	m.effects = append(m.effects, effect)
+}
+
+// Remove removes the effect with the given name (case-insensitive)
+func (m *Manager) Remove(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, e := range m.effects {
+		if strings.EqualFold(e.Name, name) {
+			m.effects = append(m.effects[:i], m.effects[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("no effect '%s'", name)
+}
+
+// Clear removes all effects
+func (m *Manager) Clear() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.effects = nil
+}
+
+// List returns the active effects in the order they were added
+func (m *Manager) List() []*Effect {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]*Effect(nil), m.effects...)
+}
+
+// For returns what the active effects do to a check with the given label
+func (m *Manager) For(label string) Adjustment {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var adj Adjustment
+	for _, e := range m.effects {
+		applied := false
+		for _, r := range e.Rules {
+			if !r.AppliesTo(label) {
+				continue
+			}
+			adj.Amount += r.Amount
+			adj.Advantage = adj.Advantage || r.Advantage
+			adj.Disadvantage = adj.Disadvantage || r.Disadvantage
+			applied = true
+		}
+		if applied {
+			adj.Sources = append(adj.Sources, e.Name)
+		}
+	}
+	return adj
+}
@@ -0,0 +1,96 @@
+package environment
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/angusmclean/tavernshell/core/dice"
+)
+
+func TestParseEffect(t *testing.T) {
+	tests := []struct {
+		words    string
+		expected string
+		wantErr  bool
+	}{
+		{"-2 perception disadvantage ranged", "heavy rain: -2 perception, disadvantage ranged", false},
+		{"-2 disadvantage perception", "heavy rain: -2 disadvantage perception", false},
+		{"-1", "heavy rain: -1 on every check", false},
+		{"+1 Stealth survival", "heavy rain: +1 stealth survival", false},
+		{"perception -2", "", true},
+		{"-2 +1 ranged", "", true},
+		{"advantage disadvantage ranged", "", true},
+		{"", "", true},
+	}
+	for _, tt := range tests {
+		effect, err := ParseEffect("heavy rain", strings.Fields(tt.words))
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseEffect(%q) error = %v, wantErr %v", tt.words, err, tt.wantErr)
+			continue
+		}
+		if err == nil && effect.String() != tt.expected {
+			t.Errorf("Expected '%s', got '%s'", tt.expected, effect.String())
+		}
+	}
+}
+
+func TestFor(t *testing.T) {
+	m := NewManager()
+	rain, _ := ParseEffect("heavy rain", strings.Fields("-2 perception disadvantage ranged"))
+	fog, _ := ParseEffect("fog", strings.Fields("-1 perception"))
+	m.Add(rain)
+	m.Add(fog)
+
+	adj := m.For("Perception check")
+	if adj.Amount != -3 || adj.Disadvantage || len(adj.Sources) != 2 {
+		t.Errorf("Expected -3 from both effects, got %+v", adj)
+	}
+	if adj := m.For("longbow ranged attack"); adj.Amount != 0 || !adj.Disadvantage || adj.String() != "disadvantage (heavy rain)" {
+		t.Errorf("Expected disadvantage from the rain, got %+v", adj)
+	}
+	if adj := m.For("athletics"); !adj.IsZero() {
+		t.Errorf("Expected nothing to apply, got %+v", adj)
+	}
+
+	// Re-adding an effect replaces it
+	rain, _ = ParseEffect("Heavy Rain", strings.Fields("-5 perception"))
+	m.Add(rain)
+	if len(m.List()) != 2 || m.For("perception").Amount != -6 {
+		t.Errorf("Expected the rain replaced, got %+v", m.For("perception"))
+	}
+
+	if err := m.Remove("fog"); err != nil {
+		t.Fatalf("Remove failed: %v", err)
+	}
+	if err := m.Remove("fog"); err == nil {
+		t.Error("Expected error removing an effect twice")
+	}
+	m.Clear()
+	if len(m.List()) != 0 {
+		t.Errorf("Expected no effects after Clear, got %d", len(m.List()))
+	}
+}
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		notation string
+		adj      Adjustment
+		expected string
+	}{
+		{"d20+5", Adjustment{Amount: -2}, "1d20+3"},
+		{"d20+5", Adjustment{Disadvantage: true}, "1d20?+5"},
+		{"d20!+5", Adjustment{Disadvantage: true}, "1d20+5"},
+		{"d20?", Adjustment{Advantage: true}, "1d20"},
+		{"d20", Adjustment{Advantage: true, Disadvantage: true}, "1d20"},
+	}
+	for _, tt := range tests {
+		expr, err := dice.Parse(tt.notation)
+		if err != nil {
+			t.Fatalf("Parse failed: %v", err)
+		}
+		tt.adj.Apply(expr)
+		if expr.String() != tt.expected {
+			t.Errorf("%s with %+v: expected '%s', got '%s'", tt.notation, tt.adj, tt.expected, expr.String())
+		}
+	}
+}
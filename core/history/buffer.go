@@ -0,0 +1,269 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Entry is one line of output history
+type Entry struct {
+	Time     time.Time `json:"time"`
+	Category string    `json:"category"`
+	Text     string    `json:"text"`
+	Private  bool      `json:"private,omitempty"` // DM-only (whispered) output
+}
+
+// Buffer holds the most recent history entries in a fixed-size ring
+// Entries pushed out of the ring are dropped, or appended to a spill file
+// (one JSON entry per line) so they can still be read back later
+type Buffer struct {
+	entries []Entry // ring storage, len == capacity
+	start   int     // index of the oldest entry in the ring
+	count   int     // number of entries in the ring
+
+	spill      *os.File // spill file (nil if spilling is off)
+	spillPath  string   // where the spill file lives, for rotation
+	spillLimit int      // entries spilled before the file is rotated (0 = never)
+	offsets    []int64  // byte offset of each spilled entry
+	end        int64    // byte offset of the end of the spill file
+
+	mu sync.RWMutex
+}
+
+// NewBuffer creates a buffer that keeps up to capacity entries in memory
+func NewBuffer(capacity int) *Buffer {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &Buffer{
+		entries: make([]Entry, capacity),
+	}
+}
+
+// SpillTo starts writing entries that fall out of the ring to a file
+// The file is truncated; it only holds this session's overflow
+func (b *Buffer) SpillTo(path string) error {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open history log: %w", err)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closeSpill()
+	b.spill = f
+	b.spillPath = path
+	return nil
+}
+
+// SetSpillLimit rotates the spill file once it holds limit entries (0 = never):
+// it is moved aside to <path>.1, replacing any earlier one, and a fresh file
+// is started, so scrollback only reaches back as far as the current file
+func (b *Buffer) SetSpillLimit(limit int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.spillLimit = limit
+}
+
+// Resize changes how many entries are kept in memory
+// When shrinking, the oldest entries are spilled (or dropped) as if evicted
+func (b *Buffer) Resize(capacity int) error {
+	if capacity < 1 {
+		capacity = 1
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var err error
+	for b.count > capacity {
+		if spillErr := b.spillEntry(b.entries[b.start]); spillErr != nil {
+			err = spillErr
+		}
+		b.start = (b.start + 1) % len(b.entries)
+		b.count--
+	}
+	entries := make([]Entry, capacity)
+	for i := 0; i < b.count; i++ {
+		entries[i] = b.entries[(b.start+i)%len(b.entries)]
+	}
+	b.entries = entries
+	b.start = 0
+	return err
+}
+
+// Add appends an entry, evicting the oldest entry if the ring is full
+// If writing an evicted entry to the spill file fails, spilling is turned off
+// and the error is returned; the new entry is still added
+func (b *Buffer) Add(e Entry) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var err error
+	if b.count == len(b.entries) {
+		err = b.spillEntry(b.entries[b.start])
+		b.entries[b.start] = e
+		b.start = (b.start + 1) % len(b.entries)
+		return err
+	}
+	b.entries[(b.start+b.count)%len(b.entries)] = e
+	b.count++
+	return nil
+}
+
+// spillEntry writes an evicted entry to the spill file (caller holds the lock)
+func (b *Buffer) spillEntry(e Entry) error {
+	if b.spill == nil {
+		return nil
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	if _, err := b.spill.WriteAt(data, b.end); err != nil {
+		b.closeSpill()
+		return fmt.Errorf("history log disabled: %w", err)
+	}
+	b.offsets = append(b.offsets, b.end)
+	b.end += int64(len(data))
+	if b.spillLimit > 0 && len(b.offsets) >= b.spillLimit {
+		return b.rotateSpill()
+	}
+	return nil
+}
+
+// rotateSpill moves the full spill file aside and starts an empty one (caller holds the lock)
+func (b *Buffer) rotateSpill() error {
+	err := b.spill.Close()
+	b.spill = nil
+	b.offsets = nil
+	b.end = 0
+	if err != nil {
+		return fmt.Errorf("history log disabled: %w", err)
+	}
+	if err := os.Rename(b.spillPath, b.spillPath+".1"); err != nil {
+		return fmt.Errorf("history log disabled: %w", err)
+	}
+	f, err := os.OpenFile(b.spillPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return fmt.Errorf("history log disabled: %w", err)
+	}
+	b.spill = f
+	return nil
+}
+
+// Len returns the number of entries held in memory
+func (b *Buffer) Len() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.count
+}
+
+// Total returns the number of entries available, including spilled ones
+func (b *Buffer) Total() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.offsets) + b.count
+}
+
+// Range returns entries [start, end) counting from the oldest available entry
+// Spilled entries are read back from disk as needed
+func (b *Buffer) Range(start, end int) ([]Entry, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	spilled := len(b.offsets)
+	if start < 0 {
+		start = 0
+	}
+	if end > spilled+b.count {
+		end = spilled + b.count
+	}
+	if start >= end {
+		return nil, nil
+	}
+
+	result := make([]Entry, 0, end-start)
+	for i := start; i < end && i < spilled; i++ {
+		e, err := b.readSpilled(i)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, e)
+	}
+	for i := max(start, spilled); i < end; i++ {
+		result = append(result, b.entries[(b.start+i-spilled)%len(b.entries)])
+	}
+	return result, nil
+}
+
+// readSpilled reads the i-th spilled entry back from disk (caller holds the lock)
+func (b *Buffer) readSpilled(i int) (Entry, error) {
+	next := b.end
+	if i+1 < len(b.offsets) {
+		next = b.offsets[i+1]
+	}
+	data := make([]byte, next-b.offsets[i])
+	if _, err := b.spill.ReadAt(data, b.offsets[i]); err != nil {
+		return Entry{}, fmt.Errorf("failed to read history log: %w", err)
+	}
+
+	var e Entry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return Entry{}, fmt.Errorf("corrupt history log entry: %w", err)
+	}
+	return e, nil
+}
+
+// Recent returns the newest n entries held in memory, oldest first
+func (b *Buffer) Recent(n int) []Entry {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if n > b.count {
+		n = b.count
+	}
+	result := make([]Entry, n)
+	for i := 0; i < n; i++ {
+		result[i] = b.entries[(b.start+b.count-n+i)%len(b.entries)]
+	}
+	return result
+}
+
+// Reset removes all entries, including any spilled to disk
+func (b *Buffer) Reset() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.start = 0
+	b.count = 0
+	b.offsets = nil
+	b.end = 0
+	if b.spill != nil {
+		return b.spill.Truncate(0)
+	}
+	return nil
+}
+
+// Close closes the spill file, if any
+func (b *Buffer) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.closeSpill()
+}
+
+// closeSpill closes the spill file and forgets spilled entries (caller holds the lock)
+func (b *Buffer) closeSpill() error {
+	if b.spill == nil {
+		return nil
+	}
+	err := b.spill.Close()
+	b.spill = nil
+	b.offsets = nil
+	b.end = 0
+	return err
+}
@@ -0,0 +1,122 @@
+package history
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func entry(i int) Entry {
+	return Entry{Category: "roll", Text: fmt.Sprintf("line %d", i)}
+}
+
+func TestBufferRing(t *testing.T) {
+	b := NewBuffer(3)
+	for i := 1; i <= 5; i++ {
+		b.Add(entry(i))
+	}
+
+	if b.Len() != 3 {
+		t.Errorf("Expected 3 entries in memory, got %d", b.Len())
+	}
+	if b.Total() != 3 {
+		t.Errorf("Expected 3 entries total without spilling, got %d", b.Total())
+	}
+
+	recent := b.Recent(2)
+	if len(recent) != 2 || recent[0].Text != "line 4" || recent[1].Text != "line 5" {
+		t.Errorf("Expected [line 4, line 5], got %v", recent)
+	}
+
+	all, _ := b.Range(0, b.Total())
+	if len(all) != 3 || all[0].Text != "line 3" {
+		t.Errorf("Expected oldest in-memory entry to be line 3, got %v", all)
+	}
+}
+
+func TestBufferSpill(t *testing.T) {
+	b := NewBuffer(3)
+	if err := b.SpillTo(filepath.Join(t.TempDir(), "history.log")); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer b.Close()
+
+	for i := 1; i <= 10; i++ {
+		if err := b.Add(entry(i)); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	if b.Total() != 10 {
+		t.Fatalf("Expected 10 entries total, got %d", b.Total())
+	}
+
+	// A range spanning the spill file and the ring
+	entries, err := b.Range(5, 9)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for i, e := range entries {
+		want := fmt.Sprintf("line %d", i+6)
+		if e.Text != want || e.Category != "roll" {
+			t.Errorf("Entry %d: expected %s, got %s (%s)", i, want, e.Text, e.Category)
+		}
+	}
+
+	b.Reset()
+	if b.Total() != 0 {
+		t.Errorf("Expected empty buffer after reset, got %d", b.Total())
+	}
+	b.Add(entry(1))
+	if entries, _ := b.Range(0, 1); len(entries) != 1 || entries[0].Text != "line 1" {
+		t.Errorf("Expected buffer to be usable after reset, got %v", entries)
+	}
+}
+
+func TestBufferResize(t *testing.T) {
+	b := NewBuffer(3)
+	for i := 1; i <= 3; i++ {
+		b.Add(entry(i))
+	}
+
+	b.Resize(5)
+	b.Add(entry(4))
+	if all, _ := b.Range(0, b.Total()); len(all) != 4 || all[0].Text != "line 1" {
+		t.Errorf("Expected growing to keep every entry, got %v", all)
+	}
+
+	b.Resize(2)
+	recent := b.Recent(5)
+	if len(recent) != 2 || recent[0].Text != "line 3" || recent[1].Text != "line 4" {
+		t.Errorf("Expected shrinking to keep the newest 2, got %v", recent)
+	}
+}
+
+func TestBufferSpillRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.log")
+	b := NewBuffer(2)
+	if err := b.SpillTo(path); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer b.Close()
+	b.SetSpillLimit(3)
+
+	for i := 1; i <= 7; i++ {
+		if err := b.Add(entry(i)); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	// Lines 1-3 filled the first file and were rotated away; 4-5 are in the new one
+	if b.Total() != 4 {
+		t.Fatalf("Expected 4 entries reachable after rotation, got %d", b.Total())
+	}
+	entries, err := b.Range(0, 1)
+	if err != nil || len(entries) != 1 || entries[0].Text != "line 4" {
+		t.Errorf("Expected oldest reachable entry to be line 4, got %v (%v)", entries, err)
+	}
+	if _, err := os.Stat(path + ".1"); err != nil {
+		t.Errorf("Expected the rotated file at %s.1: %v", path, err)
+	}
+}
@@ -0,0 +1,293 @@
+package macro
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/angusmclean/tavernshell/core/dice"
+)
+
+// Macro is a named list of commands replayed in order, or a named roll
+type Macro struct {
+	Name     string   `json:"name"`
+	Commands []string `json:"commands,omitempty"`
+	Roll     string   `json:"roll,omitempty"` // dice notation, for a named roll (with no commands)
+}
+
+// Steps returns the commands the macro runs (a named roll runs "r <notation>")
+func (mc *Macro) Steps() []string {
+	if mc.Roll != "" {
+		return []string{"r " + mc.Roll}
+	}
+	return mc.Commands
+}
+
+// Expand fills in the macro's command templates with vars
+func (mc *Macro) Expand(vars map[string]string) ([]string, error) {
+	steps := mc.Steps()
+	commands := make([]string, len(steps))
+	for i, template := range steps {
+		command, err := Expand(template, vars)
+		if err != nil {
+			return nil, err
+		}
+		commands[i] = command
+	}
+	return commands, nil
+}
+
+// Variables returns the names of the variables the macro refers to
+func (mc *Macro) Variables() []string {
+	return Variables(strings.Join(mc.Steps(), "\n"))
+}
+
+// Manager records and stores macros
+type Manager struct {
+	macros    map[string]*Macro // keyed by lowercase name
+	recording []string          // commands captured so far (nil when not recording)
+	path      string            // file macros are saved to ("" keeps them in memory only)
+	modTime   time.Time         // modification time of the file when we last read or wrote it
+	mu        sync.RWMutex
+}
+
+// NewManager creates a new macro manager
+func NewManager() *Manager {
+	return &Manager{
+		macros: make(map[string]*Macro),
+	}
+}
+
+// Open loads macros from path (if it exists) and saves changes back to it
+func (m *Manager) Open(path string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	macros, err := readFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	for _, macro := range macros {
+		m.macros[strings.ToLower(macro.Name)] = macro
+	}
+	m.path = path
+	m.modTime = modTime(path)
+	return nil
+}
+
+// Reload reads the macro file again if it has changed on disk since it was
+// last read or saved, replacing the macros in memory
+// Returns true if the macros were reloaded
+func (m *Manager) Reload() (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.path == "" {
+		return false, nil
+	}
+	changed := modTime(m.path)
+	if !changed.After(m.modTime) {
+		return false, nil
+	}
+	m.modTime = changed // don't retry a broken file until it changes again
+
+	macros, err := readFile(m.path)
+	if err != nil {
+		return false, err
+	}
+	m.macros = make(map[string]*Macro, len(macros))
+	for _, macro := range macros {
+		m.macros[strings.ToLower(macro.Name)] = macro
+	}
+	return true, nil
+}
+
+// readFile reads macros from a JSON file
+func readFile(path string) ([]*Macro, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var macros []*Macro
+	if err := json.Unmarshal(data, &macros); err != nil {
+		return nil, fmt.Errorf("invalid macro file %s: %w", path, err)
+	}
+	return macros, nil
+}
+
+// modTime returns a file's modification time (zero if it can't be read)
+func modTime(path string) time.Time {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
+
+// StartRecording begins capturing commands
+func (m *Manager) StartRecording() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.recording != nil {
+		return fmt.Errorf("already recording")
+	}
+	m.recording = []string{}
+	return nil
+}
+
+// Recording returns true while commands are being captured
+func (m *Manager) Recording() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.recording != nil
+}
+
+// Record captures a command if recording
+func (m *Manager) Record(command string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.recording != nil {
+		m.recording = append(m.recording, command)
+	}
+}
+
+// StopRecording saves the captured commands as a macro (replacing any with the same name)
+func (m *Manager) StopRecording(name string) (*Macro, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.recording == nil {
+		return nil, fmt.Errorf("not recording")
+	}
+	if len(m.recording) == 0 {
+		return nil, fmt.Errorf("no commands recorded")
+	}
+
+	macro := &Macro{Name: name, Commands: m.recording}
+	m.macros[strings.ToLower(name)] = macro
+	m.recording = nil
+	return macro, m.save()
+}
+
+// Define saves a macro written out by hand (replacing any with the same name)
+// Commands may use templates (see Expand)
+func (m *Manager) Define(name string, commands []string) (*Macro, error) {
+	if len(commands) == 0 {
+		return nil, fmt.Errorf("no commands given")
+	}
+	for _, command := range commands {
+		if err := Check(command); err != nil {
+			return nil, fmt.Errorf("'%s': %w", command, err)
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	macro := &Macro{Name: name, Commands: commands}
+	m.macros[strings.ToLower(name)] = macro
+	return macro, m.save()
+}
+
+// AddRoll saves a named roll, e.g. "smite" for 2d8+1d6+4 (replacing any
+// roll with the same name, but not a macro of commands)
+func (m *Manager) AddRoll(name, notation string) (*Macro, error) {
+	if _, err := dice.Parse(notation); err != nil {
+		return nil, err
+	}
+	if _, err := dice.Parse(name); err == nil {
+		return nil, fmt.Errorf("'%s' is already dice notation", name)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if old, ok := m.macros[strings.ToLower(name)]; ok && old.Roll == "" {
+		return nil, fmt.Errorf("'%s' is already a macro of commands (delete it first to reuse the name)", old.Name)
+	}
+	macro := &Macro{Name: name, Roll: notation}
+	m.macros[strings.ToLower(name)] = macro
+	return macro, m.save()
+}
+
+// Complete returns the names of macros starting with prefix (case-insensitive),
+// sorted; with rollsOnly, only named rolls
+func (m *Manager) Complete(prefix string, rollsOnly bool) []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	prefix = strings.ToLower(prefix)
+	var names []string
+	for _, macro := range m.sorted() {
+		if rollsOnly && macro.Roll == "" {
+			continue
+		}
+		if strings.HasPrefix(strings.ToLower(macro.Name), prefix) {
+			names = append(names, macro.Name)
+		}
+	}
+	return names
+}
+
+// CancelRecording stops recording without saving
+func (m *Manager) CancelRecording() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.recording = nil
+}
+
+// Get retrieves a macro by name (case-insensitive)
+func (m *Manager) Get(name string) *Macro {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.macros[strings.ToLower(name)]
+}
+
+// Delete removes a macro
+func (m *Manager) Delete(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := strings.ToLower(name)
+	if _, ok := m.macros[key]; !ok {
+		return fmt.Errorf("macro '%s' not found", name)
+	}
+	delete(m.macros, key)
+	return m.save()
+}
+
+// List returns all macros sorted by name
+func (m *Manager) List() []*Macro {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.sorted()
+}
+
+// sorted returns all macros sorted by name (caller holds the lock)
+func (m *Manager) sorted() []*Macro {
+	macros := make([]*Macro, 0, len(m.macros))
+	for _, macro := range m.macros {
+		macros = append(macros, macro)
+	}
+	sort.Slice(macros, func(i, j int) bool {
+		return strings.ToLower(macros[i].Name) < strings.ToLower(macros[j].Name)
+	})
+	return macros
+}
+
+// save writes all macros to disk, if a file is open (caller holds the lock)
+func (m *Manager) save() error {
+	if m.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(m.sorted(), "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(m.path, data, 0644); err != nil {
+		return err
+	}
+	m.modTime = modTime(m.path)
+	return nil
+}
@@ -0,0 +1,224 @@
+package macro
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecording(t *testing.T) {
+	m := NewManager()
+
+	if _, err := m.StopRecording("regen"); err == nil {
+		t.Error("Expected error stopping when not recording")
+	}
+
+	m.Record("ignored")
+	if err := m.StartRecording(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := m.StartRecording(); err == nil {
+		t.Error("Expected error starting twice")
+	}
+	if !m.Recording() {
+		t.Error("Expected to be recording")
+	}
+	m.Record("t adj Troll 10")
+	m.Record("i n")
+
+	macro, err := m.StopRecording("Regen")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(macro.Commands) != 2 || macro.Commands[0] != "t adj Troll 10" {
+		t.Errorf("Expected 2 recorded commands, got %v", macro.Commands)
+	}
+	if m.Recording() {
+		t.Error("Expected recording to stop")
+	}
+	if m.Get("regen") != macro {
+		t.Error("Expected case-insensitive lookup")
+	}
+}
+
+func TestEmptyRecording(t *testing.T) {
+	m := NewManager()
+	m.StartRecording()
+	if _, err := m.StopRecording("nothing"); err == nil {
+		t.Error("Expected error saving an empty macro")
+	}
+	if !m.Recording() {
+		t.Error("Expected to keep recording after a failed stop")
+	}
+	m.CancelRecording()
+	if m.Recording() {
+		t.Error("Expected cancel to stop recording")
+	}
+}
+
+func TestOpenAndSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "macros.json")
+
+	m := NewManager()
+	if err := m.Open(path); err != nil {
+		t.Fatalf("Unexpected error opening a new file: %v", err)
+	}
+	m.StartRecording()
+	m.Record("a 1m lair")
+	if _, err := m.StopRecording("lair"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	reopened := NewManager()
+	if err := reopened.Open(path); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if macro := reopened.Get("lair"); macro == nil || macro.Commands[0] != "a 1m lair" {
+		t.Errorf("Expected saved macro, got %v", macro)
+	}
+
+	if err := reopened.Delete("lair"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := reopened.Delete("lair"); err == nil {
+		t.Error("Expected error deleting a missing macro")
+	}
+	if len(reopened.List()) != 0 {
+		t.Errorf("Expected no macros, got %d", len(reopened.List()))
+	}
+}
+
+func TestReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "macros.json")
+	m := NewManager()
+	m.Open(path)
+	if _, err := m.Define("lair", []string{"a 1m lair"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if reloaded, err := m.Reload(); reloaded || err != nil {
+		t.Errorf("Expected our own save not to trigger a reload, got %v (%v)", reloaded, err)
+	}
+
+	// Edited by hand (a moment later, so the change shows in the modification time)
+	later := time.Now().Add(time.Minute)
+	os.WriteFile(path, []byte(`[{"name": "regen", "commands": ["t adj Troll 10"]}]`), 0644)
+	os.Chtimes(path, later, later)
+	if reloaded, err := m.Reload(); !reloaded || err != nil {
+		t.Fatalf("Expected a reload after an edit, got %v (%v)", reloaded, err)
+	}
+	if m.Get("regen") == nil || m.Get("lair") != nil {
+		t.Errorf("Expected the file's macros to replace the old ones, got %v", m.List())
+	}
+
+	later = later.Add(time.Minute)
+	os.WriteFile(path, []byte(`[{"name": `), 0644)
+	os.Chtimes(path, later, later)
+	if _, err := m.Reload(); err == nil {
+		t.Error("Expected error reloading a broken file")
+	}
+	if m.Get("regen") == nil {
+		t.Error("Expected macros kept after a failed reload")
+	}
+	if reloaded, err := m.Reload(); reloaded || err != nil {
+		t.Errorf("Expected a broken file not to be retried until it changes, got %v (%v)", reloaded, err)
+	}
+}
+
+func TestDefineAndExpand(t *testing.T) {
+	m := NewManager()
+	if _, err := m.Define("empty", nil); err == nil {
+		t.Error("Expected error defining an empty macro")
+	}
+	if _, err := m.Define("broken", []string{"r {if crit}4d6"}); err == nil {
+		t.Error("Expected error defining a macro with a broken template")
+	}
+
+	macro, err := m.Define("attack", []string{"r d20+5", "r {if crit}4d6{else}2d6{end}+3", "t adj $target -7"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if vars := macro.Variables(); len(vars) != 2 || vars[0] != "crit" || vars[1] != "target" {
+		t.Errorf("Expected variables [crit target], got %v", vars)
+	}
+
+	commands, err := macro.Expand(map[string]string{"crit": "true", "target": "Goblin"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if commands[1] != "r 4d6+3" || commands[2] != "t adj Goblin -7" {
+		t.Errorf("Unexpected expansion: %v", commands)
+	}
+
+	if _, err := macro.Expand(nil); err == nil {
+		t.Error("Expected error for missing $target")
+	}
+}
+
+func TestAddRoll(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "macros.json")
+	m := NewManager()
+	if err := m.Open(path); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if _, err := m.AddRoll("smite", "2d8+"); err == nil {
+		t.Error("Expected error for invalid notation")
+	}
+	if _, err := m.AddRoll("d20", "1d20+5"); err == nil {
+		t.Error("Expected error naming a roll after dice notation")
+	}
+	if _, err := m.AddRoll("Smite", "2d8"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := m.AddRoll("Smite", "2d8+1d6+4"); err != nil {
+		t.Fatalf("Expected a roll to replace a roll, got %v", err)
+	}
+	if _, err := m.Define("attack", []string{"r d20+5"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := m.AddRoll("Attack", "1d20+5"); err == nil {
+		t.Error("Expected error replacing a macro of commands with a roll")
+	}
+	if macro := m.Get("attack"); macro == nil || macro.Roll != "" {
+		t.Errorf("Expected the attack macro to be kept, got %v", macro)
+	}
+
+	reopened := NewManager()
+	if err := reopened.Open(path); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	macro := reopened.Get("smite")
+	if macro == nil || macro.Roll != "2d8+1d6+4" {
+		t.Fatalf("Expected saved roll, got %v", macro)
+	}
+	commands, err := macro.Expand(nil)
+	if err != nil || len(commands) != 1 || commands[0] != "r 2d8+1d6+4" {
+		t.Errorf("Expected [r 2d8+1d6+4], got %v (%v)", commands, err)
+	}
+}
+
+func TestComplete(t *testing.T) {
+	m := NewManager()
+	m.AddRoll("smite", "2d8")
+	m.AddRoll("Sneak", "3d6")
+	m.Define("spells", []string{"a 1m spell"})
+
+	tests := []struct {
+		prefix    string
+		rollsOnly bool
+		expected  []string
+	}{
+		{"s", true, []string{"smite", "Sneak"}},
+		{"s", false, []string{"smite", "Sneak", "spells"}},
+		{"SN", true, []string{"Sneak"}},
+		{"sp", true, nil},
+	}
+	for _, tt := range tests {
+		got := m.Complete(tt.prefix, tt.rollsOnly)
+		if strings.Join(got, ",") != strings.Join(tt.expected, ",") {
+			t.Errorf("Complete(%q, %v): expected %v, got %v", tt.prefix, tt.rollsOnly, tt.expected, got)
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package macro
+
+import (
+	"fmt"
+	"strings"
+)
+
+// frame is one open {if} block while expanding a template
+type frame struct {
+	active bool // the branch being read is kept
+	inElse bool // past the block's {else}
+	outer  bool // the enclosing text is kept
+}
+
+// Expand fills in a command template with the given variables:
+// $name (or ${name}) is replaced by the variable's value, and
+// {if name}...{else}...{end} keeps the first branch if the variable is set
+// (and not "false", "no", "off" or "0") and the second otherwise.
+// {if !name} tests the opposite. A variable used in a dropped branch
+// doesn't need a value
+func Expand(template string, vars map[string]string) (string, error) {
+	var b strings.Builder
+	var stack []frame
+	emitting := func() bool {
+		return len(stack) == 0 || (stack[len(stack)-1].active && stack[len(stack)-1].outer)
+	}
+
+	for i := 0; i < len(template); {
+		switch template[i] {
+		case '$':
+			name, n := readVariable(template[i:])
+			if n == 0 {
+				break
+			}
+			if emitting() {
+				value, ok := vars[name]
+				if !ok {
+					return "", fmt.Errorf("missing value for $%s", name)
+				}
+				b.WriteString(value)
+			}
+			i += n
+			continue
+
+		case '{':
+			tag, arg, n := readTag(template[i:])
+			if n == 0 {
+				break
+			}
+			switch tag {
+			case "if":
+				negate := strings.HasPrefix(arg, "!")
+				set := isSet(vars[strings.TrimPrefix(arg, "!")])
+				stack = append(stack, frame{active: set != negate, outer: emitting()})
+			case "else":
+				if len(stack) == 0 || stack[len(stack)-1].inElse {
+					return "", fmt.Errorf("{else} without {if}")
+				}
+				top := &stack[len(stack)-1]
+				top.active = !top.active
+				top.inElse = true
+			case "end":
+				if len(stack) == 0 {
+					return "", fmt.Errorf("{end} without {if}")
+				}
+				stack = stack[:len(stack)-1]
+			}
+			i += n
+			continue
+		}
+
+		if emitting() {
+			b.WriteByte(template[i])
+		}
+		i++
+	}
+
+	if len(stack) > 0 {
+		return "", fmt.Errorf("{if} without {end}")
+	}
+	return b.String(), nil
+}
+
+// Substitute replaces $name (or ${name}) with the variable's value for each
+// variable that has one, leaving anything else (other variables, {if} tags)
+// as it is, e.g. for values stored during the session
+func Substitute(text string, vars map[string]string) string {
+	var b strings.Builder
+	for i := 0; i < len(text); {
+		if text[i] == '$' {
+			name, n := readVariable(text[i:])
+			if value, ok := vars[name]; ok && n > 0 {
+				b.WriteString(value)
+				i += n
+				continue
+			}
+		}
+		b.WriteByte(text[i])
+		i++
+	}
+	return b.String()
+}
+
+// IsName returns true if s can name a variable: letters, digits and _
+func IsName(s string) bool {
+	return isName(s)
+}
+
+// Check reports mismatched {if}, {else} and {end} tags in a template
+func Check(template string) error {
+	vars := make(map[string]string)
+	for _, name := range Variables(template) {
+		vars[name] = "true"
+	}
+	_, err := Expand(template, vars)
+	return err
+}
+
+// Variables returns the names of the variables a template refers to, in order of first use
+func Variables(template string) []string {
+	var names []string
+	seen := make(map[string]bool)
+	add := func(name string) {
+		if name != "" && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	for i := 0; i < len(template); i++ {
+		switch template[i] {
+		case '$':
+			name, n := readVariable(template[i:])
+			add(name)
+			i += max(n-1, 0)
+		case '{':
+			tag, arg, n := readTag(template[i:])
+			if tag == "if" {
+				add(strings.TrimPrefix(arg, "!"))
+			}
+			i += max(n-1, 0)
+		}
+	}
+	return names
+}
+
+// readVariable reads "$name" or "${name}" from the start of s
+// Returns the name and its length in s (0 if s doesn't start with a variable)
+func readVariable(s string) (string, int) {
+	if strings.HasPrefix(s, "${") {
+		end := strings.IndexByte(s, '}')
+		if end < 0 || !isName(s[2:end]) {
+			return "", 0
+		}
+		return s[2:end], end + 1
+	}
+	n := 1
+	for n < len(s) && isNameByte(s[n]) {
+		n++
+	}
+	if n == 1 {
+		return "", 0
+	}
+	return s[1:n], n
+}
+
+// readTag reads "{if name}", "{else}" or "{end}" from the start of s
+// Returns the tag, its argument, and its length in s (0 if s doesn't start with a tag)
+func readTag(s string) (string, string, int) {
+	end := strings.IndexByte(s, '}')
+	if end < 0 {
+		return "", "", 0
+	}
+	fields := strings.Fields(s[1:end])
+	switch {
+	case len(fields) == 1 && (fields[0] == "else" || fields[0] == "end"):
+		return fields[0], "", end + 1
+	case len(fields) == 2 && fields[0] == "if" && isName(strings.TrimPrefix(fields[1], "!")):
+		return "if", fields[1], end + 1
+	}
+	return "", "", 0
+}
+
+// isName returns true if s is a valid variable name
+func isName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if !isNameByte(s[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// isNameByte returns true for characters allowed in variable names
+func isNameByte(c byte) bool {
+	return c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9')
+}
+
+// isSet returns true if a variable's value counts as set for {if}
+func isSet(value string) bool {
+	switch strings.ToLower(value) {
+	case "", "false", "no", "off", "0":
+		return false
+	}
+	return true
+}
@@ -0,0 +1,90 @@
+package macro
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExpand(t *testing.T) {
+	tests := []struct {
+		template string
+		vars     map[string]string
+		expected string
+	}{
+		{"r d20+5", nil, "r d20+5"},
+		{"t adj $target -5", map[string]string{"target": "Goblin"}, "t adj Goblin -5"},
+		{"t adj ${target}HP -5", map[string]string{"target": "Ogre"}, "t adj OgreHP -5"},
+		{"r {if crit}4d6{else}2d6{end}+3", map[string]string{"crit": "true"}, "r 4d6+3"},
+		{"r {if crit}4d6{else}2d6{end}+3", nil, "r 2d6+3"},
+		{"r {if crit}4d6{else}2d6{end}+3", map[string]string{"crit": "no"}, "r 2d6+3"},
+		{"r d20{if !adv}{else}!{end}", map[string]string{"adv": "yes"}, "r d20!"},
+		{"{if hit}t adj $target -7{end}", nil, ""}, // $target not needed in a dropped branch
+		{"{if a}{if b}ab{else}a{end}{else}none{end}", map[string]string{"a": "1"}, "a"},
+		{"costs $ and {braces}", nil, "costs $ and {braces}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			result, err := Expand(tt.template, tt.vars)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Expected '%s', got '%s'", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestSubstitute(t *testing.T) {
+	vars := map[string]string{"damage": "14", "dc": "15"}
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{"t adj HP -$damage", "t adj HP -14"},
+		{"r d20+5 vs ${dc}", "r d20+5 vs 15"},
+		{"t adj $target -$damage", "t adj $target -14"}, // $target has no value, so it's left for later
+		{"$damages", "$damages"},
+		{"{if crit}$damage{end}", "{if crit}14{end}"},
+	}
+
+	for _, tt := range tests {
+		if got := Substitute(tt.text, vars); got != tt.expected {
+			t.Errorf("Substitute(%q): expected '%s', got '%s'", tt.text, tt.expected, got)
+		}
+	}
+}
+
+func TestExpandErrors(t *testing.T) {
+	tests := []string{
+		"t adj $target -5",
+		"{if crit}4d6",
+		"2d6{end}",
+		"{else}",
+		"{if a}x{else}y{else}z{end}",
+	}
+
+	for _, template := range tests {
+		if _, err := Expand(template, nil); err == nil {
+			t.Errorf("Expected error for '%s'", template)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	if err := Check("r {if crit}4d6{else}2d6{end}; t adj $target -7"); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := Check("r {if crit}4d6"); err == nil {
+		t.Error("Expected error for a missing {end}")
+	}
+}
+
+func TestVariables(t *testing.T) {
+	names := Variables("r {if crit}4d6{else}2d6{end}; t adj $target -${dmg}; {if !crit}a 1m $target{end}")
+	expected := []string{"crit", "target", "dmg"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("Expected %v, got %v", expected, names)
+	}
+}
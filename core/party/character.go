@@ -0,0 +1,230 @@
+package party
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// abilities are the six ability abbreviations, used as keys for saves
+var abilities = []string{"str", "dex", "con", "int", "wis", "cha"}
+
+// Character is a player character's numbers, as imported from a character sheet
+type Character struct {
+	Name       string         `json:"name"`
+	AC         int            `json:"ac"`
+	HP         int            `json:"hp"`
+	Initiative int            `json:"initiative"`          // initiative bonus
+	Saves      map[string]int `json:"saves"`               // saving throw bonuses, keyed by ability ("dex")
+	Skills     map[string]int `json:"skills"`              // skill bonuses, keyed by lowercase skill name
+	Color      string         `json:"color"`               // palette name or color code (see Colors); assigned by the roster if empty
+	Abilities  map[string]int `json:"abilities,omitempty"` // ability scores, keyed by ability ("dex")
+}
+
+// sheet is the character JSON format, accepting common alternative field names
+type sheet struct {
+	Name       string         `json:"name"`
+	AC         *int           `json:"ac"`
+	ArmorClass *int           `json:"armor_class"`
+	HP         *int           `json:"hp"`
+	MaxHP      *int           `json:"max_hp"`
+	HitPoints  *int           `json:"hit_points"`
+	Initiative *int           `json:"initiative"`
+	Saves      map[string]int `json:"saves"`
+	Saving     map[string]int `json:"saving_throws"`
+	Skills     map[string]int `json:"skills"`
+	Color      string         `json:"color"`
+	Abilities  map[string]int `json:"abilities"`
+}
+
+// ParseCharacters reads one character or an array of characters from JSON in
+// TavernShell's own flat format (no character builder exports it; the
+// numbers are copied over from a sheet):
+//
+//	{"name": "Aria", "ac": 15, "hp": 30, "initiative": 3,
+//	 "saves": {"dex": 5, "wis": 1}, "skills": {"perception": 4, "stealth": 7},
+//	 "abilities": {"str": 8, "dex": 16}}
+//
+// "armor_class", "max_hp"/"hit_points" and "saving_throws" are accepted too,
+// and saves and abilities may be keyed by full ability name ("dexterity")
+func ParseCharacters(r io.Reader) ([]*Character, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var sheets []sheet
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		err = json.Unmarshal(data, &sheets)
+	} else {
+		sheets = make([]sheet, 1)
+		err = json.Unmarshal(data, &sheets[0])
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid character sheet: %w", err)
+	}
+	if len(sheets) == 0 {
+		return nil, fmt.Errorf("no characters found")
+	}
+
+	characters := make([]*Character, len(sheets))
+	for i, s := range sheets {
+		c, err := s.character()
+		if err != nil {
+			return nil, fmt.Errorf("character %d: %w", i+1, err)
+		}
+		characters[i] = c
+	}
+	return characters, nil
+}
+
+// LoadCharacters reads characters from a JSON file
+func LoadCharacters(path string) ([]*Character, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParseCharacters(f)
+}
+
+// character maps a sheet onto a Character
+func (s sheet) character() (*Character, error) {
+	if strings.TrimSpace(s.Name) == "" {
+		return nil, fmt.Errorf("missing name")
+	}
+	c := &Character{
+		Name:       strings.TrimSpace(s.Name),
+		AC:         first(s.AC, s.ArmorClass),
+		HP:         first(s.HP, s.MaxHP, s.HitPoints),
+		Initiative: first(s.Initiative),
+		Color:      strings.TrimSpace(s.Color),
+		Saves:      make(map[string]int),
+		Skills:     make(map[string]int),
+	}
+	if c.AC < 0 || c.HP < 0 {
+		return nil, fmt.Errorf("%s: ac and hp can't be negative", c.Name)
+	}
+	if c.Color != "" {
+		if _, err := ColorCode(c.Color); err != nil {
+			return nil, fmt.Errorf("%s: %w", c.Name, err)
+		}
+	}
+
+	saves := s.Saves
+	if saves == nil {
+		saves = s.Saving
+	}
+	for name, bonus := range saves {
+		ability, ok := abilityKey(name)
+		if !ok {
+			return nil, fmt.Errorf("%s: unknown saving throw '%s'", c.Name, name)
+		}
+		c.Saves[ability] = bonus
+	}
+	for name, score := range s.Abilities {
+		ability, ok := abilityKey(name)
+		if !ok {
+			return nil, fmt.Errorf("%s: unknown ability '%s'", c.Name, name)
+		}
+		if c.Abilities == nil {
+			c.Abilities = make(map[string]int)
+		}
+		c.Abilities[ability] = score
+	}
+	for name, bonus := range s.Skills {
+		c.Skills[strings.ToLower(name)] = bonus
+	}
+	return c, nil
+}
+
+// Save returns the character's bonus for a saving throw (by ability name or abbreviation)
+func (c *Character) Save(ability string) (int, bool) {
+	key, ok := abilityKey(ability)
+	if !ok {
+		return 0, false
+	}
+	bonus, ok := c.Saves[key]
+	return bonus, ok
+}
+
+// Skill returns the character's bonus for a skill, matching a prefix of its
+// name ("perc") if only one of their skills starts that way
+func (c *Character) Skill(skill string) (string, int, bool) {
+	skill = strings.ToLower(skill)
+	if bonus, ok := c.Skills[skill]; ok {
+		return skill, bonus, true
+	}
+	var match string
+	for name := range c.Skills {
+		if strings.HasPrefix(name, skill) {
+			if match != "" {
+				return "", 0, false
+			}
+			match = name
+		}
+	}
+	if match == "" {
+		return "", 0, false
+	}
+	return match, c.Skills[match], true
+}
+
+// SkillName works out the skill a check for the whole party means: one
+// someone has by that name, or else the one skill anyone has starting that
+// way ("perc"). It returns "" if no one has a skill like it, and an error if
+// several start that way
+func SkillName(characters []*Character, skill string) (string, error) {
+	skill = strings.ToLower(skill)
+	matches := make(map[string]bool)
+	for _, c := range characters {
+		if _, ok := c.Skills[skill]; ok {
+			return skill, nil
+		}
+		for name := range c.Skills {
+			if strings.HasPrefix(name, skill) {
+				matches[name] = true
+			}
+		}
+	}
+	names := make([]string, 0, len(matches))
+	for name := range matches {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	switch len(names) {
+	case 0:
+		return "", nil
+	case 1:
+		return names[0], nil
+	}
+	return "", fmt.Errorf("'%s' could be %s", skill, strings.Join(names, ", "))
+}
+
+// abilityKey maps "dex" or "Dexterity" to "dex"
+func abilityKey(name string) (string, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if len(name) < 3 {
+		return "", false
+	}
+	for _, ability := range abilities {
+		if strings.HasPrefix(name, ability) {
+			return ability, true
+		}
+	}
+	return "", false
+}
+
+// first returns the first value that is set, or 0
+func first(values ...*int) int {
+	for _, v := range values {
+		if v != nil {
+			return *v
+		}
+	}
+	return 0
+}
@@ -0,0 +1,174 @@
+package party
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Method is a way of generating a new character's ability scores
+type Method struct {
+	Name        string
+	Description string
+	Notation    string // dice rolled for each score ("" for a fixed array)
+	Array       []int  // scores to assign instead of rolling
+	InOrder     bool   // scores go to STR, DEX, CON, INT, WIS, CHA as rolled
+}
+
+// Methods are the ways chargen can generate ability scores
+var Methods = []Method{
+	{Name: "4d6", Description: "roll 4d6 and drop the lowest, then assign them", Notation: "4d6kh3"},
+	{Name: "3d6", Description: "roll 3d6 straight down the line, in order", Notation: "3d6", InOrder: true},
+	{Name: "heroic", Description: "roll 2d6+6, then assign them", Notation: "2d6+6"},
+	{Name: "array", Description: "assign the standard array 15, 14, 13, 12, 10, 8", Array: []int{15, 14, 13, 12, 10, 8}},
+}
+
+// ParseMethod finds a method by name (prefixes allowed) or its number in Methods
+func ParseMethod(s string) (Method, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if n, err := strconv.Atoi(s); err == nil && n >= 1 && n <= len(Methods) {
+		return Methods[n-1], nil
+	}
+	if s != "" {
+		for _, method := range Methods {
+			if strings.HasPrefix(method.Name, s) {
+				return method, nil
+			}
+		}
+	}
+	names := make([]string, len(Methods))
+	for i, method := range Methods {
+		names[i] = method.Name
+	}
+	return Method{}, fmt.Errorf("unknown method '%s' (expected %s)", s, strings.Join(names, ", "))
+}
+
+// AbilityModifier returns the modifier for an ability score (10 and 11 give +0)
+func AbilityModifier(score int) int {
+	if score < 10 {
+		return (score - 11) / 2
+	}
+	return (score - 10) / 2
+}
+
+// Build is a character being created: ability scores waiting to be assigned
+type Build struct {
+	Name      string
+	Method    Method
+	Scores    []int          // scores still to assign, highest first
+	Abilities map[string]int // scores assigned so far, keyed by ability ("dex")
+}
+
+// NewBuild starts a character with the scores a method generated; scores
+// generated in order are assigned straight away
+func NewBuild(name string, method Method, scores []int) *Build {
+	b := &Build{
+		Name:      name,
+		Method:    method,
+		Scores:    append([]int(nil), scores...),
+		Abilities: make(map[string]int),
+	}
+	if method.InOrder {
+		for i, score := range b.Scores {
+			if i < len(abilities) {
+				b.Abilities[abilities[i]] = score
+			}
+		}
+		b.Scores = nil
+		return b
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(b.Scores)))
+	return b
+}
+
+// Next returns the next score to assign, false when they're all assigned
+func (b *Build) Next() (int, bool) {
+	if len(b.Scores) == 0 {
+		return 0, false
+	}
+	return b.Scores[0], true
+}
+
+// Unassigned returns the abilities still without a score, in sheet order
+func (b *Build) Unassigned() []string {
+	var open []string
+	for _, ability := range abilities {
+		if _, ok := b.Abilities[ability]; !ok {
+			open = append(open, ability)
+		}
+	}
+	return open
+}
+
+// Assign gives the next score to an ability ("dex" or "Dexterity"); the last
+// score goes to the last ability left on its own
+func (b *Build) Assign(ability string) error {
+	score, ok := b.Next()
+	if !ok {
+		return fmt.Errorf("every score is already assigned")
+	}
+	key, ok := abilityKey(ability)
+	if !ok {
+		return fmt.Errorf("unknown ability '%s'", ability)
+	}
+	if _, taken := b.Abilities[key]; taken {
+		return fmt.Errorf("%s already has a score", strings.ToUpper(key))
+	}
+	b.Abilities[key] = score
+	b.Scores = b.Scores[1:]
+
+	if open := b.Unassigned(); len(open) == 1 && len(b.Scores) == 1 {
+		b.Abilities[open[0]] = b.Scores[0]
+		b.Scores = nil
+	}
+	return nil
+}
+
+// Finished returns true once every score is assigned
+func (b *Build) Finished() bool {
+	return len(b.Scores) == 0
+}
+
+// Summary describes the finished character in a line, e.g.
+// "Aria (4d6): STR 15 (+2), DEX 14 (+2), CON 13 (+1), ..."
+func (b *Build) Summary() string {
+	parts := make([]string, 0, len(abilities))
+	for _, ability := range abilities {
+		score, ok := b.Abilities[ability]
+		if !ok {
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%s %d (%+d)", strings.ToUpper(ability), score, AbilityModifier(score)))
+	}
+	return fmt.Sprintf("%s (%s): %s", b.Name, b.Method.Name, strings.Join(parts, ", "))
+}
+
+// Character returns the character as a roster entry: their ability scores,
+// with initiative and saves from the ability modifiers (AC and HP left at 0)
+func (b *Build) Character() *Character {
+	c := &Character{
+		Name:      b.Name,
+		Saves:     make(map[string]int),
+		Skills:    make(map[string]int),
+		Abilities: make(map[string]int),
+	}
+	for ability, score := range b.Abilities {
+		c.Abilities[ability] = score
+		c.Saves[ability] = AbilityModifier(score)
+	}
+	c.Initiative = c.Saves["dex"]
+	return c
+}
+
+// WriteSheet saves the character as a JSON character sheet, which
+// LoadCharacters (and 'party import') reads back
+func (c *Character) WriteSheet(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
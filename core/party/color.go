@@ -0,0 +1,56 @@
+package party
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Color is a named terminal color a character can be shown in
+type Color struct {
+	Name string
+	Code string // 256-color code, as lipgloss takes it
+}
+
+// Colors are handed out to characters in order as they join the roster, so
+// each player keeps one color wherever their name appears; chosen to stand
+// apart from each other and from the crit, fumble and current-turn colors
+var Colors = []Color{
+	{"blue", "39"},
+	{"orange", "208"},
+	{"purple", "135"},
+	{"teal", "37"},
+	{"pink", "213"},
+	{"gold", "178"},
+	{"lime", "112"},
+	{"salmon", "210"},
+}
+
+// ColorNames returns the names of the palette colors
+func ColorNames() []string {
+	names := make([]string, len(Colors))
+	for i, c := range Colors {
+		names[i] = c.Name
+	}
+	return names
+}
+
+// ColorCode returns the terminal color for a palette name (case-insensitive),
+// a 256-color code ("39") or a hex color ("#ff8800")
+func ColorCode(color string) (string, error) {
+	color = strings.TrimSpace(color)
+	for _, c := range Colors {
+		if strings.EqualFold(c.Name, color) {
+			return c.Code, nil
+		}
+	}
+	if n, err := strconv.Atoi(color); err == nil && n >= 0 && n <= 255 {
+		return color, nil
+	}
+	if len(color) == 7 && color[0] == '#' {
+		if _, err := strconv.ParseUint(color[1:], 16, 32); err == nil {
+			return strings.ToLower(color), nil
+		}
+	}
+	return "", fmt.Errorf("unknown color '%s' (expected %s, a number from 0 to 255, or #rrggbb)", color, strings.Join(ColorNames(), ", "))
+}
@@ -0,0 +1,289 @@
+package party
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseCharacters(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		count   int
+		wantErr bool
+	}{
+		{"single character", `{"name": "Aria", "ac": 15, "hp": 30}`, 1, false},
+		{"array", `[{"name": "Aria"}, {"name": "Borin"}]`, 2, false},
+		{"missing name", `{"ac": 15}`, 0, true},
+		{"empty array", `[]`, 0, true},
+		{"unknown save", `{"name": "Aria", "saves": {"luck": 2}}`, 0, true},
+		{"unknown ability", `{"name": "Aria", "abilities": {"luck": 12}}`, 0, true},
+		{"negative hp", `{"name": "Aria", "hp": -3}`, 0, true},
+		{"invalid json", `{"name": `, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			characters, err := ParseCharacters(strings.NewReader(tt.input))
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected error for %s", tt.input)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(characters) != tt.count {
+				t.Errorf("Expected %d characters, got %d", tt.count, len(characters))
+			}
+		})
+	}
+}
+
+func TestCharacterFields(t *testing.T) {
+	input := `{"name": "Aria", "armor_class": 16, "max_hp": 31, "initiative": 3,
+		"saving_throws": {"Dexterity": 5, "wis": 1},
+		"skills": {"Perception": 4, "stealth": 7}}`
+	characters, err := ParseCharacters(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	c := characters[0]
+
+	if c.AC != 16 || c.HP != 31 || c.Initiative != 3 {
+		t.Errorf("Expected AC 16, HP 31, init +3, got AC %d, HP %d, init %+d", c.AC, c.HP, c.Initiative)
+	}
+	if bonus, ok := c.Save("dex"); !ok || bonus != 5 {
+		t.Errorf("Expected dex save +5, got %+d (found %v)", bonus, ok)
+	}
+	if bonus, ok := c.Save("Wisdom"); !ok || bonus != 1 {
+		t.Errorf("Expected wisdom save +1, got %+d (found %v)", bonus, ok)
+	}
+	if _, ok := c.Save("str"); ok {
+		t.Errorf("Expected no strength save")
+	}
+	if name, bonus, ok := c.Skill("perc"); !ok || name != "perception" || bonus != 4 {
+		t.Errorf("Expected perception +4, got %s %+d (found %v)", name, bonus, ok)
+	}
+}
+
+func TestSkillName(t *testing.T) {
+	characters := []*Character{
+		{Name: "Aria", Skills: map[string]int{"acrobatics": 5, "perception": 4}},
+		{Name: "Borin", Skills: map[string]int{"athletics": 6, "arcana": 1}},
+	}
+	tests := []struct {
+		skill    string
+		expected string
+		wantErr  bool
+	}{
+		{"Perception", "perception", false},
+		{"perc", "perception", false},
+		{"ath", "athletics", false},
+		{"a", "", true}, // acrobatics, arcana or athletics
+		{"stealth", "", false},
+	}
+	for _, tt := range tests {
+		got, err := SkillName(characters, tt.skill)
+		if (err != nil) != tt.wantErr || got != tt.expected {
+			t.Errorf("SkillName(%q): expected %q (error %v), got %q (%v)", tt.skill, tt.expected, tt.wantErr, got, err)
+		}
+	}
+	if _, _, ok := characters[1].Skill("a"); ok {
+		t.Error("Expected an ambiguous prefix to match no skill")
+	}
+}
+
+func TestRoster(t *testing.T) {
+	r := NewRoster()
+	r.Add(&Character{Name: "Borin"})
+	r.Add(&Character{Name: "Aria"})
+	r.Add(&Character{Name: "aria", AC: 17})
+
+	list := r.List()
+	if len(list) != 2 || list[0].Name != "aria" || list[1].Name != "Borin" {
+		t.Errorf("Expected [aria Borin], got %d characters", len(list))
+	}
+	if c := r.Get("ARIA"); c == nil || c.AC != 17 {
+		t.Errorf("Expected replaced Aria with AC 17")
+	}
+	if !r.Remove("borin") || r.Remove("borin") {
+		t.Errorf("Expected Borin to be removed exactly once")
+	}
+}
+
+func TestRosterImport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "party.json")
+	write := func(data string) {
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r := NewRoster()
+	write(`[{"name": "Aria", "ac": 15}, {"name": "Borin"}, {"name": "Cai"}]`)
+	if _, err := r.Import(path); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	color := r.Get("Aria").Color
+	r.Add(&Character{Name: "Cai", AC: 12}) // Cai is replaced from elsewhere
+
+	// Borin and Cai are taken out of the file; only Borin was the file's
+	write(`[{"name": "Aria", "ac": 16}]`)
+	if _, err := r.Import(path); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if c := r.Get("Aria"); c == nil || c.AC != 16 || c.Color != color {
+		t.Errorf("Expected Aria updated to AC 16 keeping %s, got %+v", color, c)
+	}
+	if r.Get("Borin") != nil {
+		t.Errorf("Expected Borin to leave the party with the file")
+	}
+	if c := r.Get("Cai"); c == nil || c.AC != 12 {
+		t.Errorf("Expected the Cai added since to stay, got %+v", c)
+	}
+}
+
+func TestRosterColors(t *testing.T) {
+	r := NewRoster()
+	r.Add(&Character{Name: "Aria"})
+	r.Add(&Character{Name: "Borin", Color: "blue"})
+	r.Add(&Character{Name: "Cass"})
+
+	if c := r.Get("Aria"); c.Color != "blue" {
+		t.Errorf("Expected Aria to get the first color, got %q", c.Color)
+	}
+	if c := r.Get("Cass"); c.Color != "orange" {
+		t.Errorf("Expected Cass to get the first unused color, got %q", c.Color)
+	}
+
+	// Reloading a character keeps their color
+	r.Add(&Character{Name: "cass", AC: 14})
+	if c := r.Get("Cass"); c.Color != "orange" {
+		t.Errorf("Expected Cass to keep orange, got %q", c.Color)
+	}
+
+	if !r.SetColor("aria", "teal") || r.Get("Aria").Color != "teal" {
+		t.Errorf("Expected Aria's color changed to teal")
+	}
+	if r.SetColor("Dain", "teal") {
+		t.Error("Expected no color set for someone not in the roster")
+	}
+}
+
+func TestColorCode(t *testing.T) {
+	tests := []struct {
+		color   string
+		code    string
+		wantErr bool
+	}{
+		{"Blue", "39", false},
+		{"202", "202", false},
+		{"#FF8800", "#ff8800", false},
+		{"256", "", true},
+		{"#ff88", "", true},
+		{"chartreuse", "", true},
+	}
+	for _, tt := range tests {
+		code, err := ColorCode(tt.color)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ColorCode(%q) error = %v, wantErr %v", tt.color, err, tt.wantErr)
+			continue
+		}
+		if code != tt.code {
+			t.Errorf("ColorCode(%q) = %q, want %q", tt.color, code, tt.code)
+		}
+	}
+
+	if _, err := ParseCharacters(strings.NewReader(`{"name": "Aria", "color": "plaid"}`)); err == nil {
+		t.Error("Expected error for a sheet with an unknown color")
+	}
+}
+
+func TestAbilityModifier(t *testing.T) {
+	tests := []struct {
+		score, expected int
+	}{
+		{10, 0}, {11, 0}, {12, 1}, {15, 2}, {18, 4}, {9, -1}, {8, -1}, {7, -2}, {3, -4}, {1, -5},
+	}
+	for _, tt := range tests {
+		if got := AbilityModifier(tt.score); got != tt.expected {
+			t.Errorf("AbilityModifier(%d): expected %+d, got %+d", tt.score, tt.expected, got)
+		}
+	}
+}
+
+func TestParseMethod(t *testing.T) {
+	for input, expected := range map[string]string{"4d6": "4d6", "2": "3d6", "her": "heroic", "ARRAY": "array"} {
+		method, err := ParseMethod(input)
+		if err != nil || method.Name != expected {
+			t.Errorf("ParseMethod(%q): expected %s, got %s (%v)", input, expected, method.Name, err)
+		}
+	}
+	for _, input := range []string{"", "5", "point-buy"} {
+		if _, err := ParseMethod(input); err == nil {
+			t.Errorf("ParseMethod(%q): expected error", input)
+		}
+	}
+}
+
+func TestBuild(t *testing.T) {
+	array, _ := ParseMethod("array")
+	b := NewBuild("Aria", array, array.Array)
+
+	for _, ability := range []string{"dex", "Constitution", "wis", "cha"} {
+		if err := b.Assign(ability); err != nil {
+			t.Fatalf("Unexpected error assigning %s: %v", ability, err)
+		}
+	}
+	if err := b.Assign("dex"); err == nil {
+		t.Error("Expected error assigning DEX twice")
+	}
+	if err := b.Assign("luck"); err == nil {
+		t.Error("Expected error assigning an unknown ability")
+	}
+	if err := b.Assign("int"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// The last score goes to the last ability left
+	if !b.Finished() {
+		t.Fatalf("Expected the build to be finished, %v left", b.Scores)
+	}
+	want := "Aria (array): STR 8 (-1), DEX 15 (+2), CON 14 (+2), INT 10 (+0), WIS 13 (+1), CHA 12 (+1)"
+	if got := b.Summary(); got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+
+	c := b.Character()
+	if c.Initiative != 2 || c.Saves["str"] != -1 || c.Abilities["wis"] != 13 {
+		t.Errorf("Expected init +2, STR save -1 and WIS 13, got %+d, %+d, %d", c.Initiative, c.Saves["str"], c.Abilities["wis"])
+	}
+
+	// The saved sheet imports back
+	path := filepath.Join(t.TempDir(), "aria.json")
+	if err := c.WriteSheet(path); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	loaded, err := LoadCharacters(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(loaded) != 1 || loaded[0].Abilities["dex"] != 15 || loaded[0].Initiative != 2 {
+		t.Errorf("Expected Aria back with DEX 15 and init +2, got %+v", loaded)
+	}
+}
+
+func TestBuildInOrder(t *testing.T) {
+	method, _ := ParseMethod("3d6")
+	b := NewBuild("Cai", method, []int{9, 12, 7, 8, 14, 10})
+	if !b.Finished() {
+		t.Fatal("Expected scores rolled in order to be assigned straight away")
+	}
+	if b.Abilities["str"] != 9 || b.Abilities["wis"] != 14 || b.Abilities["cha"] != 10 {
+		t.Errorf("Expected STR 9, WIS 14, CHA 10, got %v", b.Abilities)
+	}
+}
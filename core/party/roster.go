@@ -0,0 +1,134 @@
+package party
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Roster holds the party's characters
+type Roster struct {
+	characters map[string]*Character   // keyed by lowercase name
+	files      map[string][]*Character // the characters each file imported, keyed by path
+	mu         sync.RWMutex
+}
+
+// NewRoster creates an empty roster
+func NewRoster() *Roster {
+	return &Roster{
+		characters: make(map[string]*Character),
+		files:      make(map[string][]*Character),
+	}
+}
+
+// Add adds (or replaces) a character; a character without a color keeps the
+// one they had, or gets the first palette color nobody else has
+func (r *Roster) Add(c *Character) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.add(c)
+}
+
+// add adds a character; the caller holds the lock
+func (r *Roster) add(c *Character) {
+	key := strings.ToLower(c.Name)
+	if c.Color == "" {
+		if old, ok := r.characters[key]; ok {
+			c.Color = old.Color
+		} else {
+			c.Color = r.freeColor()
+		}
+	}
+	r.characters[key] = c
+}
+
+// Import adds the characters in a character sheet file (see LoadCharacters).
+// Importing a file again replaces what it imported before, so a character
+// taken out of the file leaves the party; one since replaced from elsewhere stays
+func (r *Roster) Import(path string) ([]*Character, error) {
+	characters, err := LoadCharacters(path)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	// Characters still in the file keep their colors
+	for _, c := range characters {
+		if old, ok := r.characters[strings.ToLower(c.Name)]; ok && c.Color == "" {
+			c.Color = old.Color
+		}
+	}
+	for _, old := range r.files[path] {
+		key := strings.ToLower(old.Name)
+		if r.characters[key] == old {
+			delete(r.characters, key)
+		}
+	}
+	for _, c := range characters {
+		r.add(c)
+	}
+	r.files[path] = characters
+	return characters, nil
+}
+
+// SetColor changes a character's color, returning false if they aren't in the roster
+func (r *Roster) SetColor(name, color string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c, ok := r.characters[strings.ToLower(name)]
+	if !ok {
+		return false
+	}
+	c.Color = color
+	return true
+}
+
+// freeColor returns the first palette color no character has, cycling
+// through the palette again once every color is taken
+func (r *Roster) freeColor() string {
+	used := make(map[string]bool)
+	for _, c := range r.characters {
+		used[strings.ToLower(c.Color)] = true
+	}
+	for _, c := range Colors {
+		if !used[c.Name] {
+			return c.Name
+		}
+	}
+	return Colors[len(r.characters)%len(Colors)].Name
+}
+
+// Get retrieves a character by name (case-insensitive)
+func (r *Roster) Get(name string) *Character {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.characters[strings.ToLower(name)]
+}
+
+// Remove removes a character, returning false if they weren't in the roster
+func (r *Roster) Remove(name string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := strings.ToLower(name)
+	if _, ok := r.characters[key]; !ok {
+		return false
+	}
+	delete(r.characters, key)
+	return true
+}
+
+// List returns all characters sorted by name
+func (r *Roster) List() []*Character {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	characters := make([]*Character, 0, len(r.characters))
+	for _, c := range r.characters {
+		characters = append(characters, c)
+	}
+	sort.Slice(characters, func(i, j int) bool {
+		return strings.ToLower(characters[i].Name) < strings.ToLower(characters[j].Name)
+	})
+	return characters
+}
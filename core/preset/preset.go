@@ -0,0 +1,299 @@
+package preset
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/angusmclean/tavernshell/core/dice"
+)
+
+// MaxLevel is the highest level a spell can be cast at
+const MaxLevel = 9
+
+// Preset is a named roll for a spell or ability, e.g. fireball's 8d6, which
+// can grow with the level it's cast at
+type Preset struct {
+	Name        string `json:"-"`
+	Roll        string `json:"roll"`                  // dice notation; $name and @name are filled in when it's cast
+	Level       int    `json:"level,omitempty"`       // lowest level it's cast at (0 for cantrips and abilities)
+	Upcast      string `json:"upcast,omitempty"`      // dice added for each level above Level, e.g. "1d6"
+	Description string `json:"description,omitempty"` // shown by 'preset list'
+}
+
+// UnmarshalJSON accepts a preset as just its roll ("1d6") or as
+// {"roll": ..., "level": ..., "upcast": ...}
+func (p *Preset) UnmarshalJSON(data []byte) error {
+	var roll string
+	if err := json.Unmarshal(data, &roll); err == nil {
+		p.Roll = roll
+		return nil
+	}
+	type plain Preset
+	var pl plain
+	if err := json.Unmarshal(data, &pl); err != nil {
+		return err
+	}
+	*p = Preset(pl)
+	return nil
+}
+
+// builtins are the presets available without a file: damage and healing
+// from the 5e SRD, with $mod for the caster's spellcasting modifier
+var builtins = []*Preset{
+	{Name: "fireball", Roll: "8d6", Level: 3, Upcast: "1d6", Description: "fire, 20 ft radius, Dex save for half"},
+	{Name: "lightning bolt", Roll: "8d6", Level: 3, Upcast: "1d6", Description: "lightning, 100 ft line, Dex save for half"},
+	{Name: "burning hands", Roll: "3d6", Level: 1, Upcast: "1d6", Description: "fire, 15 ft cone, Dex save for half"},
+	{Name: "thunderwave", Roll: "2d8", Level: 1, Upcast: "1d8", Description: "thunder, 15 ft cube, Con save for half"},
+	{Name: "magic missile", Roll: "3d4+3", Level: 1, Upcast: "1d4+1", Description: "force, one dart per 1d4+1"},
+	{Name: "guiding bolt", Roll: "4d6", Level: 1, Upcast: "1d6", Description: "radiant, spell attack"},
+	{Name: "inflict wounds", Roll: "3d10", Level: 1, Upcast: "1d10", Description: "necrotic, melee spell attack"},
+	{Name: "shatter", Roll: "3d8", Level: 2, Upcast: "1d8", Description: "thunder, 10 ft sphere, Con save for half"},
+	{Name: "cure wounds", Roll: "1d8+$mod", Level: 1, Upcast: "1d8", Description: "healing by touch"},
+	{Name: "healing word", Roll: "1d4+$mod", Level: 1, Upcast: "1d4", Description: "healing at range, as a bonus action"},
+	{Name: "divine smite", Roll: "2d8", Level: 1, Upcast: "1d8", Description: "radiant, on a melee hit (+1d8 vs undead or fiends)"},
+	{Name: "fire bolt", Roll: "1d10", Description: "fire cantrip, spell attack"},
+	{Name: "eldritch blast", Roll: "1d10", Description: "force cantrip, spell attack per beam"},
+	{Name: "sacred flame", Roll: "1d8", Description: "radiant cantrip, Dex save"},
+	{Name: "hex", Roll: "1d6", Description: "necrotic, on each hit against the target"},
+	{Name: "hunter's mark", Roll: "1d6", Description: "on each weapon hit against the target"},
+}
+
+// Manager holds the available presets
+type Manager struct {
+	presets map[string]*Preset   // keyed by folded name (see fold)
+	files   map[string][]*Preset // the presets each file loaded, keyed by path
+	mu      sync.RWMutex
+}
+
+// NewManager creates a manager holding the built-in presets
+func NewManager() *Manager {
+	m := &Manager{presets: make(map[string]*Preset), files: make(map[string][]*Preset)}
+	for _, p := range builtins {
+		m.presets[fold(p.Name)] = p
+	}
+	return m
+}
+
+// builtin returns the built-in preset with a folded name, or nil
+func builtin(key string) *Preset {
+	for _, p := range builtins {
+		if fold(p.Name) == key {
+			return p
+		}
+	}
+	return nil
+}
+
+// fold reduces a name to lowercase letters and digits, so "Hunter's Mark"
+// and "huntersmark" are the same preset
+func fold(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, name)
+}
+
+// Load reads presets from a JSON file mapping names to rolls:
+//
+//	{"hex": "1d6", "fireball": {"roll": "8d6", "level": 3, "upcast": "1d6"}}
+//
+// Presets in the file add to the built-in ones, replacing any with the same
+// name. Loading a file again replaces what it loaded before, so a preset taken
+// out of the file is gone (or back to the built-in one)
+func (m *Manager) Load(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var presets map[string]*Preset
+	if err := json.Unmarshal(data, &presets); err != nil {
+		return fmt.Errorf("invalid preset file %s: %w", path, err)
+	}
+
+	for name, p := range presets {
+		p.Name = name
+		if err := p.check(); err != nil {
+			return err
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, old := range m.files[path] {
+		key := fold(old.Name)
+		if m.presets[key] != old {
+			continue // since replaced by another file
+		}
+		delete(m.presets, key)
+		if b := builtin(key); b != nil {
+			m.presets[key] = b
+		}
+	}
+	loaded := make([]*Preset, 0, len(presets))
+	for _, p := range presets {
+		m.presets[fold(p.Name)] = p
+		loaded = append(loaded, p)
+	}
+	m.files[path] = loaded
+	return nil
+}
+
+// check rejects a preset that could never be cast
+func (p *Preset) check() error {
+	if fold(p.Name) == "" {
+		return fmt.Errorf("preset '%s' needs a name with letters or digits", p.Name)
+	}
+	if strings.TrimSpace(p.Roll) == "" {
+		return fmt.Errorf("preset '%s' has no roll", p.Name)
+	}
+	if p.Level < 0 || p.Level > MaxLevel {
+		return fmt.Errorf("preset '%s' has level %d (expected 0-%d)", p.Name, p.Level, MaxLevel)
+	}
+	if p.Upcast != "" {
+		if _, _, _, err := parseUpcast(p.Upcast); err != nil {
+			return fmt.Errorf("preset '%s' upcast: %w", p.Name, err)
+		}
+	}
+	return nil
+}
+
+// Find looks a preset up by name, or by the start of its name if only one
+// preset starts that way ("cure" for cure wounds)
+func (m *Manager) Find(name string) (*Preset, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	key := fold(name)
+	if p, ok := m.presets[key]; ok {
+		return p, nil
+	}
+	var matches []*Preset
+	if key != "" {
+		for k, p := range m.presets {
+			if strings.HasPrefix(k, key) {
+				matches = append(matches, p)
+			}
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no preset named '%s' (see 'preset list')", name)
+	case 1:
+		return matches[0], nil
+	}
+	names := make([]string, len(matches))
+	for i, p := range matches {
+		names[i] = p.Name
+	}
+	sort.Strings(names)
+	return nil, fmt.Errorf("'%s' could be %s", name, strings.Join(names, ", "))
+}
+
+// List returns all presets sorted by level, then name
+func (m *Manager) List() []*Preset {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	presets := make([]*Preset, 0, len(m.presets))
+	for _, p := range m.presets {
+		presets = append(presets, p)
+	}
+	sort.Slice(presets, func(i, j int) bool {
+		if presets[i].Level != presets[j].Level {
+			return presets[i].Level < presets[j].Level
+		}
+		return presets[i].Name < presets[j].Name
+	})
+	return presets
+}
+
+// Notation returns the notation to roll for the preset cast at a level (0
+// for its lowest), adding the upcast dice for each level above its own:
+// fireball at 5 is 10d6, and cure wounds at 3 is 3d8+$mod
+func (p *Preset) Notation(level int) (string, error) {
+	if level == 0 {
+		level = p.Level
+	}
+	if level > MaxLevel {
+		return "", fmt.Errorf("spells go up to level %d", MaxLevel)
+	}
+	if level < p.Level {
+		return "", fmt.Errorf("%s is cast at level %d or higher", p.Name, p.Level)
+	}
+	extra := level - p.Level
+	if extra == 0 || p.Upcast == "" {
+		return p.Roll, nil
+	}
+
+	count, sides, modifier, err := parseUpcast(p.Upcast)
+	if err != nil {
+		return "", err
+	}
+	count *= extra
+	modifier *= extra
+
+	// Fold the extra dice into the first group when it's the same die
+	// (10d6 rather than 8d6+2d6), otherwise add them as a group of their own
+	notation := p.Roll
+	if n, s, rest, ok := leadingDice(p.Roll); ok && s == sides {
+		notation = fmt.Sprintf("%dd%d%s", n+count, sides, rest)
+	} else {
+		notation += fmt.Sprintf("+%dd%d", count, sides)
+	}
+	if modifier != 0 {
+		notation += fmt.Sprintf("%+d", modifier)
+	}
+	return notation, nil
+}
+
+// parseUpcast reads upcast dice, a single group with an optional modifier
+// ("1d6" or "1d4+1")
+func parseUpcast(notation string) (count, sides, modifier int, err error) {
+	expr, err := dice.Parse(notation)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	if len(expr.Groups) > 0 || len(expr.Operations) > 0 || expr.Advantage || expr.Disadvantage ||
+		expr.Reroll != nil || expr.Min != 0 || expr.Halve || expr.Double {
+		return 0, 0, 0, fmt.Errorf("'%s' should be plain dice, like 1d6 or 1d4+1", notation)
+	}
+	return expr.Count, expr.Sides, expr.Modifier, nil
+}
+
+// leadingDice reads plain dice at the start of notation ("8d6" of "8d6+$mod"),
+// returning the rest; ok is false if the first group has anything more to it
+func leadingDice(notation string) (count, sides int, rest string, ok bool) {
+	d := strings.IndexByte(notation, 'd')
+	if d < 0 {
+		return 0, 0, "", false
+	}
+	count = 1
+	if d > 0 {
+		n, err := strconv.Atoi(notation[:d])
+		if err != nil {
+			return 0, 0, "", false
+		}
+		count = n
+	}
+	end := d + 1
+	for end < len(notation) && notation[end] >= '0' && notation[end] <= '9' {
+		end++
+	}
+	sides, err := strconv.Atoi(notation[d+1 : end])
+	if err != nil {
+		return 0, 0, "", false
+	}
+	rest = notation[end:]
+	if rest != "" && rest[0] != '+' && rest[0] != '-' {
+		return 0, 0, "", false
+	}
+	return count, sides, rest, true
+}
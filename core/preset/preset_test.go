@@ -0,0 +1,141 @@
+package preset
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNotation(t *testing.T) {
+	m := NewManager()
+	tests := []struct {
+		name     string
+		level    int
+		expected string
+	}{
+		{"fireball", 0, "8d6"},
+		{"fireball", 3, "8d6"},
+		{"fireball", 5, "10d6"},
+		{"cure wounds", 3, "3d8+$mod"},
+		{"magic missile", 3, "5d4+3+2"},
+		{"fire bolt", 0, "1d10"},
+		{"hex", 4, "1d6"},
+	}
+
+	for _, tt := range tests {
+		p, err := m.Find(tt.name)
+		if err != nil {
+			t.Fatalf("Find(%q) failed: %v", tt.name, err)
+		}
+		notation, err := p.Notation(tt.level)
+		if err != nil {
+			t.Errorf("%s at %d: unexpected error: %v", tt.name, tt.level, err)
+			continue
+		}
+		if notation != tt.expected {
+			t.Errorf("%s at %d: expected %q, got %q", tt.name, tt.level, tt.expected, notation)
+		}
+	}
+
+	fireball, _ := m.Find("fireball")
+	for _, level := range []int{2, 10} {
+		if _, err := fireball.Notation(level); err == nil {
+			t.Errorf("Expected error casting fireball at level %d", level)
+		}
+	}
+}
+
+func TestNotationSeparateGroup(t *testing.T) {
+	p := &Preset{Name: "chromatic orb", Roll: "3d8", Level: 1, Upcast: "1d6"}
+	notation, err := p.Notation(2)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if notation != "3d8+1d6" {
+		t.Errorf("Expected 3d8+1d6, got %q", notation)
+	}
+}
+
+func TestFind(t *testing.T) {
+	m := NewManager()
+	tests := []struct {
+		name     string
+		expected string // "" for an error
+	}{
+		{"Fireball", "fireball"},
+		{"cure", "cure wounds"},
+		{"Hunters Mark", "hunter's mark"},
+		{"magic missile", "magic missile"},
+		{"h", ""},
+		{"wish", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		p, err := m.Find(tt.name)
+		switch {
+		case tt.expected == "" && err == nil:
+			t.Errorf("Find(%q): expected error, got %s", tt.name, p.Name)
+		case tt.expected != "" && err != nil:
+			t.Errorf("Find(%q): unexpected error: %v", tt.name, err)
+		case tt.expected != "" && p.Name != tt.expected:
+			t.Errorf("Find(%q): expected %s, got %s", tt.name, tt.expected, p.Name)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "presets.json")
+	data := `{
+		"sneak attack": "3d6",
+		"Fireball": {"roll": "8d6+$mod", "level": 3, "upcast": "1d6"}
+	}`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewManager()
+	if err := m.Load(path); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if p, err := m.Find("sneak"); err != nil || p.Roll != "3d6" {
+		t.Errorf("Expected sneak attack 3d6, got %v (%v)", p, err)
+	}
+	if p, _ := m.Find("fireball"); p.Roll != "8d6+$mod" {
+		t.Errorf("Expected the file's fireball to replace the built-in one, got %q", p.Roll)
+	}
+	if _, err := m.Find("cure wounds"); err != nil {
+		t.Errorf("Expected built-in presets to stay, got %v", err)
+	}
+
+	// Loading the file again replaces what it loaded before
+	if err := os.WriteFile(path, []byte(`{"hex": "1d8"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Load(path); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if _, err := m.Find("sneak attack"); err == nil {
+		t.Errorf("Expected sneak attack to be gone once taken out of the file")
+	}
+	if p, _ := m.Find("fireball"); p.Roll != "8d6" {
+		t.Errorf("Expected the built-in fireball back, got %q", p.Roll)
+	}
+	if p, _ := m.Find("hex"); p.Roll != "1d8" {
+		t.Errorf("Expected the file's hex, got %q", p.Roll)
+	}
+
+	for _, bad := range []string{
+		`{"nothing": ""}`,
+		`{"wish": {"roll": "1d6", "level": 10}}`,
+		`{"fireball": {"roll": "8d6", "level": 3, "upcast": "2d6kh1"}}`,
+		`not json`,
+	} {
+		if err := os.WriteFile(path, []byte(bad), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := m.Load(path); err == nil {
+			t.Errorf("Load(%s): expected error, got nil", bad)
+		}
+	}
+}
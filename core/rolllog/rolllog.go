@@ -0,0 +1,162 @@
+package rolllog
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/angusmclean/tavernshell/core/dice"
+)
+
+// Entry is one roll in the log
+type Entry struct {
+	Time     time.Time `json:"time"`
+	Notation string    `json:"notation"`
+	Dice     string    `json:"dice"` // every die rolled, dropped ones in ‹angle brackets›
+	Total    int       `json:"total"`
+	Note     string    `json:"note,omitempty"` // the roll's label, or added afterwards with Annotate
+}
+
+// Log keeps every roll made in a session, oldest first
+type Log struct {
+	entries []Entry
+	limit   int
+
+	mu sync.RWMutex
+}
+
+// NewLog creates a log that keeps up to limit rolls
+func NewLog(limit int) *Log {
+	if limit < 1 {
+		limit = 1
+	}
+	return &Log{limit: limit}
+}
+
+// SetLimit changes how many rolls the log keeps, dropping the oldest if it's over
+func (l *Log) SetLimit(limit int) {
+	if limit < 1 {
+		limit = 1
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.limit = limit
+	if len(l.entries) > l.limit {
+		l.entries = l.entries[len(l.entries)-l.limit:]
+	}
+}
+
+// Add records a roll made at the given time, dropping the oldest roll if the log is full
+func (l *Log) Add(r *dice.Result, at time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.entries = append(l.entries, Entry{
+		Time:     at,
+		Notation: r.Expression.String(),
+		Dice:     r.DiceString(),
+		Total:    r.Total,
+		Note:     r.Label,
+	})
+	if len(l.entries) > l.limit {
+		l.entries = l.entries[len(l.entries)-l.limit:]
+	}
+}
+
+// AddEntry records an entry as given, for numbers that weren't rolled but
+// belong in the log all the same (a character's standard array, say)
+func (l *Log) AddEntry(e Entry) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.entries = append(l.entries, e)
+	if len(l.entries) > l.limit {
+		l.entries = l.entries[len(l.entries)-l.limit:]
+	}
+}
+
+// Annotate attaches a note to the most recent roll, replacing any earlier note
+func (l *Log) Annotate(note string) (Entry, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(l.entries) == 0 {
+		return Entry{}, fmt.Errorf("no rolls to annotate")
+	}
+	last := &l.entries[len(l.entries)-1]
+	last.Note = note
+	return *last, nil
+}
+
+// Entries returns a copy of the logged rolls, oldest first
+func (l *Log) Entries() []Entry {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return append([]Entry(nil), l.entries...)
+}
+
+// Len returns how many rolls are logged
+func (l *Log) Len() int {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return len(l.entries)
+}
+
+// Clear removes every logged roll
+func (l *Log) Clear() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = nil
+}
+
+// Export writes the log to a file, as JSON for a .json path and CSV for a .csv path
+func (l *Log) Export(path string) error {
+	var write func(io.Writer) error
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		write = l.WriteJSON
+	case ".csv":
+		write = l.WriteCSV
+	default:
+		return fmt.Errorf("unknown export format for %s (use a .csv or .json file)", path)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// WriteJSON writes the log as a JSON array
+func (l *Log) WriteJSON(w io.Writer) error {
+	entries := l.Entries()
+	if entries == nil {
+		entries = []Entry{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(entries)
+}
+
+// WriteCSV writes the log as CSV with a header row
+func (l *Log) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"time", "notation", "dice", "total", "note"})
+	for _, e := range l.Entries() {
+		cw.Write([]string{e.Time.Format(time.RFC3339), e.Notation, e.Dice, strconv.Itoa(e.Total), e.Note})
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,151 @@
+package rolllog
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/angusmclean/tavernshell/core/dice"
+)
+
+// result builds a rolled result without touching the dice roller
+func result(t *testing.T, notation string, values ...int) *dice.Result {
+	t.Helper()
+	expr, err := dice.Parse(notation)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	r := &dice.Result{Expression: expr, Label: expr.Label}
+	for _, v := range values {
+		r.Rolls = append(r.Rolls, dice.Die{Value: v, Sides: expr.Sides, Kept: true})
+		r.Total += v
+	}
+	r.Total += expr.Modifier
+	return r
+}
+
+func TestLogLimit(t *testing.T) {
+	log := NewLog(2)
+	at := time.Date(2024, 5, 1, 20, 0, 0, 0, time.UTC)
+	log.Add(result(t, "d20", 4), at)
+	log.Add(result(t, "d20", 11), at)
+	log.Add(result(t, "2d6+3", 2, 5), at)
+
+	entries := log.Entries()
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].Total != 11 || entries[1].Total != 10 {
+		t.Errorf("Expected the oldest roll dropped, got totals %d and %d", entries[0].Total, entries[1].Total)
+	}
+	if entries[1].Notation != "2d6+3" || entries[1].Dice != "[2, 5]" {
+		t.Errorf("Expected '2d6+3' with dice '[2, 5]', got %q with %q", entries[1].Notation, entries[1].Dice)
+	}
+
+	log.SetLimit(1)
+	if entries := log.Entries(); len(entries) != 1 || entries[0].Total != 10 {
+		t.Errorf("Expected only the newest roll kept after lowering the limit, got %+v", entries)
+	}
+
+	log.Clear()
+	if log.Len() != 0 {
+		t.Errorf("Expected an empty log after Clear, got %d", log.Len())
+	}
+}
+
+func TestAnnotate(t *testing.T) {
+	log := NewLog(100)
+	if _, err := log.Annotate("too early"); err == nil {
+		t.Error("Expected error annotating an empty log")
+	}
+
+	log.Add(result(t, "d20", 3), time.Now())
+	log.Add(result(t, "2d6", 6, 6), time.Now())
+	entry, err := log.Annotate("the killing blow")
+	if err != nil {
+		t.Fatalf("Annotate failed: %v", err)
+	}
+	if entry.Total != 12 {
+		t.Errorf("Expected the most recent roll annotated, got total %d", entry.Total)
+	}
+	entries := log.Entries()
+	if entries[0].Note != "" || entries[1].Note != "the killing blow" {
+		t.Errorf("Expected only the last roll noted, got %q and %q", entries[0].Note, entries[1].Note)
+	}
+}
+
+func TestAddEntry(t *testing.T) {
+	log := NewLog(2)
+	log.Add(result(t, "d20", 3), time.Now())
+	log.Add(result(t, "d20", 8), time.Now())
+	log.AddEntry(Entry{Time: time.Now(), Notation: "array", Dice: "[15, 14, 13, 12, 10, 8]", Total: 72, Note: "Aria (array)"})
+
+	entries := log.Entries()
+	if len(entries) != 2 {
+		t.Fatalf("Expected the oldest roll dropped for 2 entries, got %d", len(entries))
+	}
+	if entries[0].Total != 8 || entries[1].Notation != "array" || entries[1].Note != "Aria (array)" {
+		t.Errorf("Expected the d20 then the array, got %+v", entries)
+	}
+}
+
+func TestLabelNote(t *testing.T) {
+	log := NewLog(100)
+	log.Add(result(t, "2d6+3 # greatsword damage", 4, 5), time.Now())
+	if note := log.Entries()[0].Note; note != "greatsword damage" {
+		t.Errorf("Expected the label as the note, got %q", note)
+	}
+	if _, err := log.Annotate("dropped the ogre"); err != nil {
+		t.Fatalf("Annotate failed: %v", err)
+	}
+	if note := log.Entries()[0].Note; note != "dropped the ogre" {
+		t.Errorf("Expected the annotation to replace the label, got %q", note)
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	log := NewLog(100)
+	log.Add(result(t, "2d6", 3, 4), time.Date(2024, 5, 1, 20, 0, 0, 0, time.UTC))
+	if _, err := log.Annotate("that was the killing blow"); err != nil {
+		t.Fatalf("Annotate failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := log.WriteCSV(&buf); err != nil {
+		t.Fatalf("WriteCSV failed: %v", err)
+	}
+	expected := "time,notation,dice,total,note\n2024-05-01T20:00:00Z,2d6,\"[3, 4]\",7,that was the killing blow\n"
+	if buf.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestExport(t *testing.T) {
+	log := NewLog(100)
+	log.Add(result(t, "d20+5", 15), time.Now())
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "rolls.json")
+	if err := log.Export(path); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var entries []Entry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		t.Fatalf("Expected valid JSON, got %v", err)
+	}
+	if len(entries) != 1 || entries[0].Total != 20 {
+		t.Errorf("Expected one roll totalling 20, got %+v", entries)
+	}
+
+	if err := log.Export(filepath.Join(dir, "rolls.txt")); err == nil || !strings.Contains(err.Error(), "format") {
+		t.Errorf("Expected an unknown format error, got %v", err)
+	}
+}
@@ -0,0 +1,103 @@
+package ruleset
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/angusmclean/tavernshell/core/tracker/rotation"
+)
+
+// Ruleset is the game system a campaign plays, consulted by the helpers that
+// depend on the rules (crits, advantage, initiative, what happens at 0 HP)
+// rather than each of them assuming 5e
+type Ruleset interface {
+	// Name is the short name used to select the ruleset (e.g. "5e")
+	Name() string
+	// Description names the system in a few words
+	Description() string
+	// InitiativeMode is the turn order 'i start' uses when no mode is given
+	InitiativeMode() rotation.Mode
+	// Advantage reports whether rolls may use advantage and disadvantage (! and ?)
+	Advantage() bool
+	// Naturals reports whether natural 20s and 1s on a d20 are called out as crits and fumbles
+	Naturals() bool
+	// DegreesOfSuccess reports whether rolls against a DC have critical
+	// successes and failures (beating it by 10, natural 20s and 1s; see dice.Check)
+	DegreesOfSuccess() bool
+	// MassiveDamage reports whether damage past 0 HP can kill outright (see campaign.IsMassiveDamage)
+	MassiveDamage() bool
+	// AtZeroHP describes what happens to a creature dropped to 0 HP, and
+	// whether it dies there and then
+	AtZeroHP() (note string, dies bool)
+}
+
+// Default is the ruleset used unless a campaign picks another
+const Default = "5e"
+
+// fifthEdition is D&D 5th edition
+type fifthEdition struct{}
+
+func (fifthEdition) Name() string                  { return "5e" }
+func (fifthEdition) Description() string           { return "D&D 5th edition" }
+func (fifthEdition) InitiativeMode() rotation.Mode { return rotation.ModeStandard }
+func (fifthEdition) Advantage() bool               { return true }
+func (fifthEdition) Naturals() bool                { return true }
+func (fifthEdition) DegreesOfSuccess() bool        { return false }
+func (fifthEdition) MassiveDamage() bool           { return true }
+func (fifthEdition) AtZeroHP() (string, bool) {
+	return "falls unconscious and makes death saving throws", false
+}
+
+// osr is old-school play in the style of B/X and its retroclones
+type osr struct{}
+
+func (osr) Name() string                  { return "osr" }
+func (osr) Description() string           { return "Old-school B/X and retroclones" }
+func (osr) InitiativeMode() rotation.Mode { return rotation.ModeSide }
+func (osr) Advantage() bool               { return false }
+func (osr) Naturals() bool                { return false }
+func (osr) DegreesOfSuccess() bool        { return false }
+func (osr) MassiveDamage() bool           { return false }
+func (osr) AtZeroHP() (string, bool) {
+	return "is dead", true
+}
+
+// pf2eLite is a light take on Pathfinder 2nd edition
+type pf2eLite struct{}
+
+func (pf2eLite) Name() string                  { return "pf2e" }
+func (pf2eLite) Description() string           { return "Pathfinder 2nd edition, lite" }
+func (pf2eLite) InitiativeMode() rotation.Mode { return rotation.ModeStandard }
+func (pf2eLite) Advantage() bool               { return true } // fortune and misfortune effects
+func (pf2eLite) Naturals() bool                { return true }
+func (pf2eLite) DegreesOfSuccess() bool        { return true }
+func (pf2eLite) MassiveDamage() bool           { return false }
+func (pf2eLite) AtZeroHP() (string, bool) {
+	return "is knocked out and dying 1 (dying 2 from a critical hit)", false
+}
+
+// rulesets are the rulesets that can be selected, by name
+var rulesets = map[string]Ruleset{
+	"5e":   fifthEdition{},
+	"osr":  osr{},
+	"pf2e": pf2eLite{},
+}
+
+// Get returns the ruleset with the given name (case-insensitive)
+func Get(name string) (Ruleset, error) {
+	if r, ok := rulesets[strings.ToLower(name)]; ok {
+		return r, nil
+	}
+	return nil, fmt.Errorf("unknown ruleset '%s' (expected %s)", name, strings.Join(Names(), ", "))
+}
+
+// Names returns the names of every ruleset, sorted
+func Names() []string {
+	names := make([]string, 0, len(rulesets))
+	for name := range rulesets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,39 @@
+package ruleset
+
+import (
+	"testing"
+
+	"github.com/angusmclean/tavernshell/core/tracker/rotation"
+)
+
+func TestGet(t *testing.T) {
+	tests := []struct {
+		name      string
+		mode      rotation.Mode
+		advantage bool
+		massive   bool
+		dies      bool
+		degrees   bool
+	}{
+		{"5e", rotation.ModeStandard, true, true, false, false},
+		{"OSR", rotation.ModeSide, false, false, true, false},
+		{"pf2e", rotation.ModeStandard, true, false, false, true},
+	}
+	for _, tt := range tests {
+		r, err := Get(tt.name)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		_, dies := r.AtZeroHP()
+		if r.InitiativeMode() != tt.mode || r.Advantage() != tt.advantage || r.MassiveDamage() != tt.massive || dies != tt.dies || r.DegreesOfSuccess() != tt.degrees {
+			t.Errorf("%s: unexpected rules %s, advantage=%v, massive=%v, dies=%v, degrees=%v", tt.name, r.InitiativeMode(), r.Advantage(), r.MassiveDamage(), dies, r.DegreesOfSuccess())
+		}
+	}
+
+	if _, err := Get("gurps"); err == nil {
+		t.Error("Expected error for an unknown ruleset")
+	}
+	if _, err := Get(Default); err != nil {
+		t.Errorf("Expected the default ruleset to exist, got %v", err)
+	}
+}
@@ -0,0 +1,79 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/angusmclean/tavernshell/core/tracker/number"
+	"github.com/angusmclean/tavernshell/core/tracker/rotation"
+)
+
+// State is the part of a session worth picking up again after a restart:
+// the trackers and the initiative order
+type State struct {
+	Saved      time.Time         `json:"saved"`
+	Clean      bool              `json:"clean"` // false while running; true once TavernShell quit normally
+	Trackers   []*number.Tracker `json:"trackers,omitempty"`
+	Initiative *rotation.Tracker `json:"initiative,omitempty"` // nil if initiative wasn't running
+}
+
+// Empty returns true if there is nothing in the state to resume
+func (s *State) Empty() bool {
+	return len(s.Trackers) == 0 && (s.Initiative == nil || !s.Initiative.HasParticipants())
+}
+
+// Interrupted returns true if the session ended without quitting normally
+// (a crash, a closed terminal, a killed process)
+func (s *State) Interrupted() bool {
+	return !s.Clean
+}
+
+// Summary describes the state in a few words, e.g. "3 trackers, initiative round 2"
+func (s *State) Summary() string {
+	var parts []string
+	switch len(s.Trackers) {
+	case 0:
+	case 1:
+		parts = append(parts, "1 tracker")
+	default:
+		parts = append(parts, fmt.Sprintf("%d trackers", len(s.Trackers)))
+	}
+	if s.Initiative != nil && s.Initiative.HasParticipants() {
+		parts = append(parts, fmt.Sprintf("initiative round %d", s.Initiative.Round))
+	}
+	if len(parts) == 0 {
+		return "nothing to resume"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Save writes the state to path
+// It is written to a temporary file and renamed into place, so a crash
+// mid-save leaves the previous state rather than half a file
+func Save(path string, s *State) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Load reads a state saved by Save
+func Load(path string) (*State, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var s State
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("invalid session file %s: %w", path, err)
+	}
+	return &s, nil
+}
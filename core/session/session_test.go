@@ -0,0 +1,74 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/angusmclean/tavernshell/core/tracker/number"
+	"github.com/angusmclean/tavernshell/core/tracker/rotation"
+)
+
+func TestSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.json")
+
+	initiative := rotation.NewTracker()
+	initiative.Add("Aria", 17)
+	initiative.Add("Ogre", 12)
+	initiative.Round = 3
+	ogre := number.NewTracker("Ogre", 40, 59)
+
+	if err := Save(path, &State{Saved: time.Now(), Trackers: []*number.Tracker{ogre}, Initiative: initiative}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Error("Expected the temporary file to be renamed into place")
+	}
+
+	s, err := Load(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !s.Interrupted() {
+		t.Error("Expected a state saved without Clean to look interrupted")
+	}
+	if len(s.Trackers) != 1 || s.Trackers[0].Current != 40 || s.Trackers[0].ID != ogre.ID {
+		t.Errorf("Expected the Ogre tracker at 40, got %v", s.Trackers)
+	}
+	if s.Initiative == nil || s.Initiative.Round != 3 || s.Initiative.Participants[0].Name != "Aria" {
+		t.Errorf("Expected initiative in round 3 led by Aria, got %+v", s.Initiative)
+	}
+	if got := s.Summary(); got != "1 tracker, initiative round 3" {
+		t.Errorf("Expected '1 tracker, initiative round 3', got '%s'", got)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := Load(filepath.Join(dir, "missing.json")); !os.IsNotExist(err) {
+		t.Errorf("Expected a not-exist error, got %v", err)
+	}
+
+	path := filepath.Join(dir, "broken.json")
+	os.WriteFile(path, []byte("{"), 0644)
+	if _, err := Load(path); err == nil {
+		t.Error("Expected error for invalid JSON")
+	}
+}
+
+func TestEmpty(t *testing.T) {
+	tests := []struct {
+		state    State
+		expected bool
+	}{
+		{State{}, true},
+		{State{Initiative: rotation.NewTracker()}, true},
+		{State{Trackers: []*number.Tracker{number.NewTracker("HP", 5, 10)}}, false},
+	}
+	for i, tt := range tests {
+		if got := tt.state.Empty(); got != tt.expected {
+			t.Errorf("Case %d: expected %v, got %v", i, tt.expected, got)
+		}
+	}
+}
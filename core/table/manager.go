@@ -0,0 +1,78 @@
+package table
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Manager manages loaded random tables
+type Manager struct {
+	tables map[string]*Table // keyed by lowercase name
+	mu     sync.RWMutex
+}
+
+// NewManager creates a new table manager
+func NewManager() *Manager {
+	return &Manager{
+		tables: make(map[string]*Table),
+	}
+}
+
+// Add adds (or replaces) a table
+func (m *Manager) Add(t *Table) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.tables[strings.ToLower(t.Name)] = t
+}
+
+// LoadFile loads a table from a file, naming it after the file (without extension)
+func (m *Manager) LoadFile(path string) (*Table, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	t, err := Parse(name, f)
+	if err != nil {
+		return nil, err
+	}
+	m.Add(t)
+	return t, nil
+}
+
+// Get retrieves a table by name (case-insensitive)
+func (m *Manager) Get(name string) *Table {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.tables[strings.ToLower(name)]
+}
+
+// Roll rolls on a named table with a modifier
+func (m *Manager) Roll(name string, modifier int) (*Result, error) {
+	t := m.Get(name)
+	if t == nil {
+		return nil, fmt.Errorf("table '%s' not found", name)
+	}
+	return t.Roll(modifier)
+}
+
+// List returns all tables sorted by name
+func (m *Manager) List() []*Table {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	tables := make([]*Table, 0, len(m.tables))
+	for _, t := range m.tables {
+		tables = append(tables, t)
+	}
+	sort.Slice(tables, func(i, j int) bool {
+		return tables[i].Name < tables[j].Name
+	})
+	return tables
+}
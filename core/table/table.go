@@ -0,0 +1,201 @@
+package table
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/angusmclean/tavernshell/core/dice"
+)
+
+// Entry represents one row of a random table, covering an inclusive range of rolls
+type Entry struct {
+	Min  int
+	Max  int
+	Text string
+}
+
+// quietTexts are entries that mean nothing happens, e.g. on an encounter check
+var quietTexts = []string{"-", "—", "nothing", "none", "quiet", "all quiet", "no encounter"}
+
+// IsQuiet returns true if the entry means nothing happens ("-", "nothing",
+// "no encounter"), as on most encounter-check tables
+func (e *Entry) IsQuiet() bool {
+	text := strings.ToLower(strings.TrimRight(strings.TrimSpace(e.Text), ".!"))
+	for _, quiet := range quietTexts {
+		if text == quiet {
+			return true
+		}
+	}
+	return false
+}
+
+// Table represents a random table (e.g. a d100 treasure table)
+type Table struct {
+	Name    string
+	Entries []Entry
+}
+
+// Result represents the outcome of rolling on a table
+type Result struct {
+	Table    *Table
+	Roll     int    // The natural die roll
+	Modifier int    // Modifier applied before lookup
+	Adjusted int    // Roll + modifier, clamped to the table's range
+	Clamped  bool   // Whether the adjusted roll was clamped
+	Entry    *Entry // Matching entry (nil if the table has a gap there)
+}
+
+// Parse reads a table from text, one entry per line in the form "<range> <text>"
+// Ranges look like "01-20", "7", or "96-00" ("00" means 100)
+// Blank lines and lines starting with '#' are ignored
+func Parse(name string, r io.Reader) (*Table, error) {
+	t := &Table{Name: name}
+
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		rangeStr, text, found := strings.Cut(line, " ")
+		if !found {
+			rangeStr, text, found = strings.Cut(line, "\t")
+		}
+		if !found {
+			return nil, fmt.Errorf("line %d: expected '<range> <text>'", lineNum)
+		}
+		text = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(text), ":|"))
+		if text == "" {
+			return nil, fmt.Errorf("line %d: missing entry text", lineNum)
+		}
+
+		min, max, err := parseRange(strings.TrimRight(rangeStr, ":|"))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNum, err)
+		}
+		t.Entries = append(t.Entries, Entry{Min: min, Max: max, Text: text})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(t.Entries) == 0 {
+		return nil, fmt.Errorf("table '%s' has no entries", name)
+	}
+	return t, nil
+}
+
+// parseRange parses "01-20", "7", or "96-00"
+func parseRange(s string) (int, int, error) {
+	lo, hi, isRange := strings.Cut(s, "-")
+	min, err := parseRangeValue(lo)
+	if err != nil {
+		return 0, 0, err
+	}
+	if !isRange {
+		return min, min, nil
+	}
+	max, err := parseRangeValue(hi)
+	if err != nil {
+		return 0, 0, err
+	}
+	if max < min {
+		return 0, 0, fmt.Errorf("range %s is backwards", s)
+	}
+	return min, max, nil
+}
+
+// parseRangeValue parses a single range bound, reading "00" as 100
+func parseRangeValue(s string) (int, error) {
+	if s == "00" {
+		return 100, nil
+	}
+	value, err := strconv.Atoi(s)
+	if err != nil || value < 1 {
+		return 0, fmt.Errorf("invalid range value '%s'", s)
+	}
+	return value, nil
+}
+
+// Sides returns the die size used to roll on the table (its highest entry)
+func (t *Table) Sides() int {
+	sides := 0
+	for _, e := range t.Entries {
+		if e.Max > sides {
+			sides = e.Max
+		}
+	}
+	return sides
+}
+
+// Lookup returns the entry covering a roll (nil if none does)
+func (t *Table) Lookup(roll int) *Entry {
+	for i := range t.Entries {
+		if roll >= t.Entries[i].Min && roll <= t.Entries[i].Max {
+			return &t.Entries[i]
+		}
+	}
+	return nil
+}
+
+// Roll rolls on the table, shifting the roll by modifier before lookup
+// The adjusted roll is clamped to the table's range, as most published tables expect
+func (t *Table) Roll(modifier int) (*Result, error) {
+	sides := t.Sides()
+	if sides < 2 {
+		return nil, fmt.Errorf("table '%s' needs entries above 1 to roll on", t.Name)
+	}
+
+	rolled, err := dice.RollExpression(&dice.Expression{Count: 1, Sides: sides})
+	if err != nil {
+		return nil, err
+	}
+	return t.Resolve(rolled.Total, modifier), nil
+}
+
+// Resolve looks up a natural roll shifted by modifier, clamping to the table's range
+func (t *Table) Resolve(roll, modifier int) *Result {
+	adjusted := roll + modifier
+	clamped := false
+	if adjusted < 1 {
+		adjusted = 1
+		clamped = true
+	}
+	if sides := t.Sides(); adjusted > sides {
+		adjusted = sides
+		clamped = true
+	}
+
+	return &Result{
+		Table:    t,
+		Roll:     roll,
+		Modifier: modifier,
+		Adjusted: adjusted,
+		Clamped:  clamped,
+		Entry:    t.Lookup(adjusted),
+	}
+}
+
+// String returns a formatted string representation of the result
+func (r *Result) String() string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("%s (d%d): %d", r.Table.Name, r.Table.Sides(), r.Roll))
+	if r.Modifier != 0 {
+		b.WriteString(fmt.Sprintf(" %+d = %d", r.Modifier, r.Adjusted))
+		if r.Clamped {
+			b.WriteString(" (clamped)")
+		}
+	}
+	if r.Entry != nil {
+		b.WriteString(" → " + r.Entry.Text)
+	} else {
+		b.WriteString(" → (no entry)")
+	}
+	return b.String()
+}
@@ -0,0 +1,128 @@
+package table
+
+import (
+	"strings"
+	"testing"
+)
+
+const treasure = `# Treasure (d100)
+01-20 Copper coins
+21-60 Silver coins
+61-95: Gems
+96-00 Magic item
+`
+
+func TestParse(t *testing.T) {
+	tbl, err := Parse("treasure", strings.NewReader(treasure))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(tbl.Entries) != 4 {
+		t.Fatalf("Expected 4 entries, got %d", len(tbl.Entries))
+	}
+	if tbl.Sides() != 100 {
+		t.Errorf("Expected d100 table, got d%d", tbl.Sides())
+	}
+	if tbl.Entries[2].Text != "Gems" {
+		t.Errorf("Expected 'Gems', got '%s'", tbl.Entries[2].Text)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		desc  string
+	}{
+		{"", "no entries"},
+		{"01-20", "missing text"},
+		{"20-01 Backwards", "backwards range"},
+		{"abc Nonsense", "invalid range"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if _, err := Parse("bad", strings.NewReader(tt.input)); err == nil {
+				t.Errorf("Parse(%q) expected error for %s", tt.input, tt.desc)
+			}
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	tbl, _ := Parse("treasure", strings.NewReader(treasure))
+
+	tests := []struct {
+		roll, modifier int
+		wantAdjusted   int
+		wantClamped    bool
+		wantText       string
+	}{
+		{10, 0, 10, false, "Copper coins"},
+		{50, 25, 75, false, "Gems"},
+		{90, 25, 100, true, "Magic item"},
+		{5, -10, 1, true, "Copper coins"},
+	}
+
+	for _, tt := range tests {
+		result := tbl.Resolve(tt.roll, tt.modifier)
+		if result.Adjusted != tt.wantAdjusted || result.Clamped != tt.wantClamped {
+			t.Errorf("Resolve(%d, %d) = adjusted %d clamped %v, want %d %v",
+				tt.roll, tt.modifier, result.Adjusted, result.Clamped, tt.wantAdjusted, tt.wantClamped)
+		}
+		if result.Entry == nil || result.Entry.Text != tt.wantText {
+			t.Errorf("Resolve(%d, %d) entry = %v, want %s", tt.roll, tt.modifier, result.Entry, tt.wantText)
+		}
+	}
+}
+
+func TestRoll(t *testing.T) {
+	tbl, _ := Parse("treasure", strings.NewReader(treasure))
+	for i := 0; i < 50; i++ {
+		result, err := tbl.Roll(0)
+		if err != nil {
+			t.Fatalf("Roll failed: %v", err)
+		}
+		if result.Roll < 1 || result.Roll > 100 {
+			t.Errorf("Roll %d out of range [1, 100]", result.Roll)
+		}
+		if result.Entry == nil {
+			t.Errorf("Roll %d found no entry", result.Roll)
+		}
+	}
+}
+
+func TestManager(t *testing.T) {
+	manager := NewManager()
+	tbl, _ := Parse("Treasure", strings.NewReader(treasure))
+	manager.Add(tbl)
+
+	if manager.Get("treasure") == nil {
+		t.Error("Expected case-insensitive lookup to find the table")
+	}
+	if _, err := manager.Roll("missing", 0); err == nil {
+		t.Error("Expected error rolling on a missing table")
+	}
+	if len(manager.List()) != 1 {
+		t.Errorf("Expected 1 table, got %d", len(manager.List()))
+	}
+}
+
+func TestIsQuiet(t *testing.T) {
+	tests := []struct {
+		text  string
+		quiet bool
+	}{
+		{"-", true},
+		{"Nothing", true},
+		{"No encounter.", true},
+		{"all quiet", true},
+		{"2d4 wolves", false},
+		{"Nothing stirs, but tracks lead north", false},
+	}
+	for _, tt := range tests {
+		entry := Entry{Min: 1, Max: 1, Text: tt.text}
+		if entry.IsQuiet() != tt.quiet {
+			t.Errorf("IsQuiet(%q): expected %v", tt.text, tt.quiet)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package modifier
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Manager manages active temporary modifiers
+type Manager struct {
+	modifiers map[string]*Modifier // keyed by ID
+	mu        sync.RWMutex
+}
+
+// NewManager creates a new modifier manager
+func NewManager() *Manager {
+	return &Manager{
+		modifiers: make(map[string]*Modifier),
+	}
+}
+
+// Add adds a modifier
+func (m *Manager) Add(mod *Modifier) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.modifiers[mod.ID] = mod
+}
+
+// Remove removes all modifiers on a target (case-insensitive), optionally only those with a tag
+// Returns the number of modifiers removed
+func (m *Manager) Remove(target, tag string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	count := 0
+	for id, mod := range m.modifiers {
+		if !strings.EqualFold(mod.Target, target) {
+			continue
+		}
+		if tag != "" && !strings.EqualFold(mod.Tag, tag) {
+			continue
+		}
+		delete(m.modifiers, id)
+		count++
+	}
+	return count
+}
+
+// Clear removes all modifiers
+func (m *Manager) Clear() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.modifiers = make(map[string]*Modifier)
+}
+
+// List returns all modifiers sorted by target, then tag
+func (m *Manager) List() []*Modifier {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	mods := make([]*Modifier, 0, len(m.modifiers))
+	for _, mod := range m.modifiers {
+		mods = append(mods, mod)
+	}
+	sortModifiers(mods)
+	return mods
+}
+
+// For returns the modifiers that apply to a roll by target with the given tag
+func (m *Manager) For(target, tag string) []*Modifier {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var mods []*Modifier
+	for _, mod := range m.modifiers {
+		if mod.AppliesTo(target, tag) && !mod.IsExpired() {
+			mods = append(mods, mod)
+		}
+	}
+	sortModifiers(mods)
+	return mods
+}
+
+// Total returns the combined bonus for a roll by target with the given tag
+func (m *Manager) Total(target, tag string) int {
+	total := 0
+	for _, mod := range m.For(target, tag) {
+		total += mod.Amount
+	}
+	return total
+}
+
+// AdvanceRound counts down round-limited modifiers, removing and returning any that run out
+func (m *Manager) AdvanceRound() []*Modifier {
+	return m.AdvanceRounds(1)
+}
+
+// AdvanceRounds counts down round-limited modifiers by several rounds at once
+// (e.g. the 100 rounds of a dungeon turn), removing and returning any that run out
+func (m *Manager) AdvanceRounds(rounds int) []*Modifier {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var expired []*Modifier
+	for id, mod := range m.modifiers {
+		if mod.Rounds == 0 {
+			continue
+		}
+		mod.Rounds = max(mod.Rounds-rounds, 0)
+		if mod.Rounds == 0 {
+			expired = append(expired, mod)
+			delete(m.modifiers, id)
+		}
+	}
+	sortModifiers(expired)
+	return expired
+}
+
+// GetExpired returns all time-limited modifiers that have expired and removes them
+func (m *Manager) GetExpired() []*Modifier {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var expired []*Modifier
+	for id, mod := range m.modifiers {
+		if mod.IsExpired() {
+			expired = append(expired, mod)
+			delete(m.modifiers, id)
+		}
+	}
+	sortModifiers(expired)
+	return expired
+}
+
+// Count returns the number of active modifiers
+func (m *Manager) Count() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.modifiers)
+}
+
+// sortModifiers sorts modifiers by target, then tag
+func sortModifiers(mods []*Modifier) {
+	sort.Slice(mods, func(i, j int) bool {
+		if mods[i].Target == mods[j].Target {
+			return mods[i].Tag < mods[j].Tag
+		}
+		return mods[i].Target < mods[j].Target
+	})
+}
@@ -0,0 +1,86 @@
+package modifier
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Modifier represents a temporary bonus or penalty (e.g. Bless, a -2 from poison)
+type Modifier struct {
+	ID      string
+	Target  string    // who it applies to ("" applies to everyone)
+	Amount  int       // bonus (positive) or penalty (negative)
+	Tag     string    // what kind of roll it applies to, e.g. "attack" ("" applies to all rolls)
+	Rounds  int       // rounds remaining (0 means not limited by rounds)
+	Expires time.Time // when it expires (zero means not limited by time)
+}
+
+// NewModifier creates a new modifier that lasts until removed
+func NewModifier(target string, amount int, tag string) *Modifier {
+	return &Modifier{
+		ID:     generateID(),
+		Target: target,
+		Amount: amount,
+		Tag:    tag,
+	}
+}
+
+// ForRounds limits the modifier to a number of rounds
+func (m *Modifier) ForRounds(rounds int) *Modifier {
+	m.Rounds = rounds
+	return m
+}
+
+// ForDuration limits the modifier to a duration starting now
+func (m *Modifier) ForDuration(d time.Duration) *Modifier {
+	m.Expires = time.Now().Add(d)
+	return m
+}
+
+// AppliesTo returns true if the modifier applies to a roll by target with the given tag
+func (m *Modifier) AppliesTo(target, tag string) bool {
+	if m.Target != "" && !strings.EqualFold(m.Target, target) {
+		return false
+	}
+	return m.Tag == "" || strings.EqualFold(m.Tag, tag)
+}
+
+// IsExpired returns true if a time-limited modifier has run out
+func (m *Modifier) IsExpired() bool {
+	return !m.Expires.IsZero() && !time.Now().Before(m.Expires)
+}
+
+// String returns a string representation of the modifier
+func (m *Modifier) String() string {
+	var b strings.Builder
+	if m.Target != "" {
+		b.WriteString(m.Target + " ")
+	} else {
+		b.WriteString("everyone ")
+	}
+	b.WriteString(fmt.Sprintf("%+d", m.Amount))
+	if m.Tag != "" {
+		b.WriteString(" " + m.Tag)
+	}
+	switch {
+	case m.Rounds > 0:
+		b.WriteString(fmt.Sprintf(" (%dr left)", m.Rounds))
+	case !m.Expires.IsZero():
+		remaining := time.Until(m.Expires).Round(time.Second)
+		if remaining < 0 {
+			remaining = 0
+		}
+		b.WriteString(fmt.Sprintf(" (%s left)", remaining))
+	}
+	return b.String()
+}
+
+var idCounter uint64
+
+// generateID generates a simple unique ID
+func generateID() string {
+	id := atomic.AddUint64(&idCounter, 1)
+	return fmt.Sprintf("%d-%d", time.Now().UnixNano(), id)
+}
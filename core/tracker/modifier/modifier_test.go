@@ -0,0 +1,104 @@
+package modifier
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAppliesTo(t *testing.T) {
+	bless := NewModifier("Aria", 2, "attack")
+
+	if !bless.AppliesTo("aria", "Attack") {
+		t.Error("Expected modifier to apply case-insensitively")
+	}
+	if bless.AppliesTo("Bram", "attack") {
+		t.Error("Expected modifier not to apply to another target")
+	}
+	if bless.AppliesTo("Aria", "save") {
+		t.Error("Expected modifier not to apply to another tag")
+	}
+
+	everyone := NewModifier("", -1, "")
+	if !everyone.AppliesTo("Bram", "save") {
+		t.Error("Expected untargeted, untagged modifier to apply to everything")
+	}
+}
+
+func TestString(t *testing.T) {
+	mod := NewModifier("Aria", 2, "attack").ForRounds(10)
+	expected := "Aria +2 attack (10r left)"
+	if mod.String() != expected {
+		t.Errorf("Expected '%s', got '%s'", expected, mod.String())
+	}
+}
+
+func TestManagerTotal(t *testing.T) {
+	manager := NewManager()
+	manager.Add(NewModifier("Aria", 2, "attack"))
+	manager.Add(NewModifier("Aria", 1, ""))
+	manager.Add(NewModifier("Bram", 5, "attack"))
+
+	if total := manager.Total("Aria", "attack"); total != 3 {
+		t.Errorf("Expected total 3, got %d", total)
+	}
+	if total := manager.Total("Aria", "save"); total != 1 {
+		t.Errorf("Expected total 1, got %d", total)
+	}
+}
+
+func TestManagerAdvanceRound(t *testing.T) {
+	manager := NewManager()
+	manager.Add(NewModifier("Aria", 2, "attack").ForRounds(2))
+	manager.Add(NewModifier("Bram", 1, "").ForRounds(1))
+	manager.Add(NewModifier("Cleo", 1, ""))
+
+	expired := manager.AdvanceRound()
+	if len(expired) != 1 || expired[0].Target != "Bram" {
+		t.Fatalf("Expected Bram's modifier to expire after 1 round, got %v", expired)
+	}
+
+	expired = manager.AdvanceRound()
+	if len(expired) != 1 || expired[0].Target != "Aria" {
+		t.Fatalf("Expected Aria's modifier to expire after 2 rounds, got %v", expired)
+	}
+
+	if manager.Count() != 1 {
+		t.Errorf("Expected the unlimited modifier to remain, got count %d", manager.Count())
+	}
+}
+
+func TestManagerGetExpired(t *testing.T) {
+	manager := NewManager()
+	manager.Add(NewModifier("Aria", 2, "attack").ForDuration(50 * time.Millisecond))
+	manager.Add(NewModifier("Bram", 1, "").ForDuration(time.Hour))
+
+	if len(manager.GetExpired()) != 0 {
+		t.Error("Expected no expired modifiers immediately")
+	}
+
+	time.Sleep(100 * time.Millisecond)
+
+	expired := manager.GetExpired()
+	if len(expired) != 1 || expired[0].Target != "Aria" {
+		t.Fatalf("Expected Aria's modifier to expire, got %v", expired)
+	}
+	if manager.Count() != 1 {
+		t.Errorf("Expected 1 modifier remaining, got %d", manager.Count())
+	}
+}
+
+func TestManagerRemove(t *testing.T) {
+	manager := NewManager()
+	manager.Add(NewModifier("Aria", 2, "attack"))
+	manager.Add(NewModifier("Aria", 1, "save"))
+
+	if removed := manager.Remove("aria", "save"); removed != 1 {
+		t.Errorf("Expected 1 removed, got %d", removed)
+	}
+	if removed := manager.Remove("Aria", ""); removed != 1 {
+		t.Errorf("Expected 1 removed, got %d", removed)
+	}
+	if manager.Count() != 0 {
+		t.Errorf("Expected 0 modifiers, got %d", manager.Count())
+	}
+}
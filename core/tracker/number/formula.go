@@ -0,0 +1,351 @@
+package number
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Formula works a tracker's value out from other trackers, e.g. "STRx15",
+// "HP.max/2" or "[Aria HP]+[Aria Temp]"
+// Tracker names are single words or in brackets; a name means its current
+// value, and a name followed by ".max" its maximum
+type Formula struct {
+	Text string // as written, with any name-x-number word spelled out ("STR x 15")
+	root formulaNode
+}
+
+// formulaNode is one part of a parsed formula
+type formulaNode struct {
+	op          byte // '+', '-', '*', '/'; 0 for a number or a tracker
+	left, right *formulaNode
+	value       int    // for a number
+	name        string // for a tracker
+	max         bool   // the tracker's maximum rather than its current value
+}
+
+// ParseFormula parses a derived tracker's formula. A word like "STRx15" is a
+// tracker of that name if known says there is one, and otherwise STR times 15
+// if there's a STR, so a tracker named "Hex5" stays whole (with a nil known,
+// such words are always names)
+func ParseFormula(text string, known func(name string) bool) (*Formula, error) {
+	text = strings.TrimSpace(text)
+	tokens, text, err := tokenizeFormula(text, known)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("expected a formula, e.g. STRx15 or HP.max/2")
+	}
+	p := &formulaParser{tokens: tokens}
+	root, err := p.sum()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected '%s' in formula '%s'", p.tokens[p.pos].text, text)
+	}
+	return &Formula{Text: text, root: *root}, nil
+}
+
+// Sources lists the trackers the formula reads, in order of appearance
+func (f *Formula) Sources() []string {
+	var names []string
+	seen := make(map[string]bool)
+	var walk func(n *formulaNode)
+	walk = func(n *formulaNode) {
+		if n == nil {
+			return
+		}
+		if n.op == 0 && n.name != "" && !seen[strings.ToLower(n.name)] {
+			seen[strings.ToLower(n.name)] = true
+			names = append(names, n.name)
+		}
+		walk(n.left)
+		walk(n.right)
+	}
+	walk(&f.root)
+	return names
+}
+
+// Eval works the formula out, looking trackers up by name; division rounds down
+func (f *Formula) Eval(lookup func(name string) *Tracker) (int, error) {
+	return f.root.eval(lookup)
+}
+
+// HasMax returns true if the formula only ever adds its sources up, scaled
+// by numbers that aren't negative (STRx15, HP/2, [Aria HP]+[Aria Temp]), so
+// working it out from their maximums gives its maximum. A formula that
+// subtracts a source (HP.max - HP) or multiplies two together has none
+func (f *Formula) HasMax() bool {
+	return f.root.additive()
+}
+
+// additive returns true if the node only grows as its trackers do
+func (n *formulaNode) additive() bool {
+	switch n.op {
+	case 0:
+		return true
+	case '+':
+		return n.left.additive() && n.right.additive()
+	case '-':
+		return n.left.additive() && !n.right.hasTracker()
+	case '*':
+		if !n.left.hasTracker() {
+			return n.left.constant() >= 0 && n.right.additive()
+		}
+		return !n.right.hasTracker() && n.right.constant() >= 0 && n.left.additive()
+	default:
+		return n.left.additive() && !n.right.hasTracker() && n.right.constant() > 0
+	}
+}
+
+// hasTracker returns true if any part of the node reads a tracker
+func (n *formulaNode) hasTracker() bool {
+	if n.op == 0 {
+		return n.name != ""
+	}
+	return n.left.hasTracker() || n.right.hasTracker()
+}
+
+// constant works out a node that reads no trackers (-1 if it can't be)
+func (n *formulaNode) constant() int {
+	value, err := n.eval(func(string) *Tracker { return nil })
+	if err != nil {
+		return -1
+	}
+	return value
+}
+
+// eval works out the node's value
+func (n *formulaNode) eval(lookup func(name string) *Tracker) (int, error) {
+	if n.op == 0 {
+		if n.name == "" {
+			return n.value, nil
+		}
+		t := lookup(n.name)
+		if t == nil {
+			return 0, fmt.Errorf("tracker '%s' not found", n.name)
+		}
+		if n.max {
+			return t.Max, nil
+		}
+		return t.Current, nil
+	}
+
+	left, err := n.left.eval(lookup)
+	if err != nil {
+		return 0, err
+	}
+	right, err := n.right.eval(lookup)
+	if err != nil {
+		return 0, err
+	}
+	switch n.op {
+	case '+':
+		return left + right, nil
+	case '-':
+		return left - right, nil
+	case '*':
+		return left * right, nil
+	default:
+		if right == 0 {
+			return 0, fmt.Errorf("division by zero")
+		}
+		quotient := left / right
+		if (left%right != 0) && ((left < 0) != (right < 0)) {
+			quotient-- // round down, not toward zero
+		}
+		return quotient, nil
+	}
+}
+
+// formulaToken is a number, a tracker name, an operator or a parenthesis
+type formulaToken struct {
+	kind byte // 'n' number, 't' tracker, or the operator/parenthesis itself
+	text string
+	max  bool
+}
+
+// tokenizeFormula splits a formula into tokens, returning the text with any
+// word read as a multiplication spelled out
+// "x" and "×" multiply as well as "*", so "STRx15" reads as STR times 15
+// (unless known has a tracker named STRx15, or none named STR)
+func tokenizeFormula(text string, known func(name string) bool) ([]formulaToken, string, error) {
+	var tokens []formulaToken
+	var spelled strings.Builder
+	runes := []rune(text)
+	copied := 0 // runes of text already in spelled
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case strings.ContainsRune("+-*/()", r):
+			tokens = append(tokens, formulaToken{kind: byte(r), text: string(r)})
+			i++
+		case r == '×':
+			tokens = append(tokens, formulaToken{kind: '*', text: "×"})
+			i++
+		case unicode.IsDigit(r):
+			start := i
+			for i < len(runes) && unicode.IsDigit(runes[i]) {
+				i++
+			}
+			tokens = append(tokens, formulaToken{kind: 'n', text: string(runes[start:i])})
+		case r == '[':
+			end := i + 1
+			for end < len(runes) && runes[end] != ']' {
+				end++
+			}
+			if end == len(runes) {
+				return nil, "", fmt.Errorf("missing ']' in formula '%s'", text)
+			}
+			name := strings.TrimSpace(string(runes[i+1 : end]))
+			i = end + 1
+			if name == "" {
+				return nil, "", fmt.Errorf("empty tracker name in formula '%s'", text)
+			}
+			token := formulaToken{kind: 't', text: name}
+			token.max, i = maxSuffix(runes, i)
+			tokens = append(tokens, token)
+		case isNameRune(r):
+			start := i
+			for i < len(runes) && isNameRune(runes[i]) {
+				i++
+			}
+			word := string(runes[start:i])
+			if strings.EqualFold(word, "x") {
+				tokens = append(tokens, formulaToken{kind: '*', text: word})
+				continue
+			}
+			// "STRx15": a name running into x and a number is a multiplication
+			if cut := strings.LastIndexAny(word, "xX"); cut > 0 && cut < len(word)-1 && isDigits(word[cut+1:]) &&
+				known != nil && !known(word) && known(word[:cut]) {
+				tokens = append(tokens,
+					formulaToken{kind: 't', text: word[:cut]},
+					formulaToken{kind: '*', text: word[cut : cut+1]},
+					formulaToken{kind: 'n', text: word[cut+1:]})
+				spelled.WriteString(string(runes[copied:start]))
+				spelled.WriteString(word[:cut] + " " + word[cut:cut+1] + " " + word[cut+1:])
+				copied = i
+				continue
+			}
+			token := formulaToken{kind: 't', text: word}
+			token.max, i = maxSuffix(runes, i)
+			tokens = append(tokens, token)
+		default:
+			return nil, "", fmt.Errorf("unexpected '%c' in formula '%s'", r, text)
+		}
+	}
+	spelled.WriteString(string(runes[copied:]))
+	return tokens, spelled.String(), nil
+}
+
+// maxSuffix reports whether a tracker name at runes[:i] is followed by ".max",
+// and where the formula carries on
+func maxSuffix(runes []rune, i int) (bool, int) {
+	if rest := string(runes[i:]); len(rest) >= 4 && strings.EqualFold(rest[:4], ".max") {
+		if len(runes) == i+4 || !isNameRune(runes[i+4]) {
+			return true, i + 4
+		}
+	}
+	return false, i
+}
+
+// isNameRune returns true for the runes a bare tracker name may contain
+func isNameRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '\''
+}
+
+// isDigits returns true if s is a run of ASCII digits
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// formulaParser is a recursive-descent parser over formula tokens:
+// sum = product { (+|-) product }, product = factor { (*|/) factor },
+// factor = number | tracker | -factor | ( sum )
+type formulaParser struct {
+	tokens []formulaToken
+	pos    int
+}
+
+// sum parses additions and subtractions
+func (p *formulaParser) sum() (*formulaNode, error) {
+	left, err := p.product()
+	if err != nil {
+		return nil, err
+	}
+	for p.pos < len(p.tokens) && (p.tokens[p.pos].kind == '+' || p.tokens[p.pos].kind == '-') {
+		op := p.tokens[p.pos].kind
+		p.pos++
+		right, err := p.product()
+		if err != nil {
+			return nil, err
+		}
+		left = &formulaNode{op: op, left: left, right: right}
+	}
+	return left, nil
+}
+
+// product parses multiplications and divisions
+func (p *formulaParser) product() (*formulaNode, error) {
+	left, err := p.factor()
+	if err != nil {
+		return nil, err
+	}
+	for p.pos < len(p.tokens) && (p.tokens[p.pos].kind == '*' || p.tokens[p.pos].kind == '/') {
+		op := p.tokens[p.pos].kind
+		p.pos++
+		right, err := p.factor()
+		if err != nil {
+			return nil, err
+		}
+		left = &formulaNode{op: op, left: left, right: right}
+	}
+	return left, nil
+}
+
+// factor parses a number, a tracker, a negation or a parenthesized sum
+func (p *formulaParser) factor() (*formulaNode, error) {
+	if p.pos >= len(p.tokens) {
+		return nil, fmt.Errorf("formula ends early (expected a number or tracker)")
+	}
+	token := p.tokens[p.pos]
+	p.pos++
+	switch token.kind {
+	case 'n':
+		value, err := strconv.Atoi(token.text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number '%s'", token.text)
+		}
+		return &formulaNode{value: value}, nil
+	case 't':
+		return &formulaNode{name: token.text, max: token.max}, nil
+	case '-':
+		operand, err := p.factor()
+		if err != nil {
+			return nil, err
+		}
+		return &formulaNode{op: '-', left: &formulaNode{}, right: operand}, nil
+	case '(':
+		inner, err := p.sum()
+		if err != nil {
+			return nil, err
+		}
+		if p.pos >= len(p.tokens) || p.tokens[p.pos].kind != ')' {
+			return nil, fmt.Errorf("missing ')' in formula")
+		}
+		p.pos++
+		return inner, nil
+	default:
+		return nil, fmt.Errorf("unexpected '%s' in formula", token.text)
+	}
+}
@@ -0,0 +1,215 @@
+package number
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFormulaEval(t *testing.T) {
+	trackers := map[string]*Tracker{
+		"str":     NewTracker("STR", 16, 18),
+		"hp":      NewTracker("HP", 23, 50),
+		"aria hp": NewTracker("Aria HP", 12, 30),
+		"temp":    NewTracker("Temp", -7, 10),
+		"hex5":    NewTracker("Hex5", 3, 6),
+		"hex":     NewTracker("Hex", 7, 7),
+	}
+	lookup := func(name string) *Tracker {
+		return trackers[strings.ToLower(name)]
+	}
+	known := func(name string) bool {
+		return lookup(name) != nil
+	}
+
+	tests := []struct {
+		formula  string
+		expected int
+	}{
+		{"STRx15", 240},
+		{"STR x 15", 240},
+		{"STR*15", 240},
+		{"STR×15", 240},
+		{"HP/2", 11},
+		{"HP.max/2", 25},
+		{"[Aria HP]+5", 17},
+		{"[Aria HP].max - [Aria HP]", 18},
+		{"(HP+STR)/3", 13},
+		{"2+3*4", 14},
+		{"-STR+20", 4},
+		{"Temp/2", -4}, // rounds down
+		{"Hex5+1", 4},  // a tracker's name, not Hex times 5
+		{"HEXx2", 14},
+	}
+	for _, tt := range tests {
+		f, err := ParseFormula(tt.formula, known)
+		if err != nil {
+			t.Errorf("ParseFormula(%q): unexpected error: %v", tt.formula, err)
+			continue
+		}
+		got, err := f.Eval(lookup)
+		if err != nil {
+			t.Errorf("Eval(%q): unexpected error: %v", tt.formula, err)
+			continue
+		}
+		if got != tt.expected {
+			t.Errorf("Eval(%q): expected %d, got %d", tt.formula, tt.expected, got)
+		}
+	}
+}
+
+func TestFormulaSources(t *testing.T) {
+	known := func(name string) bool {
+		return strings.EqualFold(name, "STR")
+	}
+	f, err := ParseFormula("[Aria HP]/2 + STRx3 + HP.max - [aria hp]", known)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []string{"Aria HP", "STR", "HP"}
+	if got := f.Sources(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected sources %v, got %v", expected, got)
+	}
+	if f.Text != "[Aria HP]/2 + STR x 3 + HP.max - [aria hp]" {
+		t.Errorf("Expected the multiplication spelled out, got %q", f.Text)
+	}
+
+	// Without STR, STRx3 is a name of its own
+	f, err = ParseFormula("STRx3", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := f.Sources(); !reflect.DeepEqual(got, []string{"STRx3"}) {
+		t.Errorf("Expected sources [STRx3], got %v", got)
+	}
+}
+
+func TestFormulaHasMax(t *testing.T) {
+	tests := []struct {
+		formula  string
+		expected bool
+	}{
+		{"STR x 15", true},
+		{"HP/2", true},
+		{"[Aria HP]+[Aria Temp]", true},
+		{"(HP+STR)/3 - 2", true},
+		{"2*HP", true},
+		{"[Aria HP].max - [Aria HP]", false},
+		{"-STR+20", false},
+		{"HP*STR", false},
+		{"20/HP", false},
+		{"(0-2)*HP", false},
+	}
+	for _, tt := range tests {
+		f, err := ParseFormula(tt.formula, nil)
+		if err != nil {
+			t.Errorf("ParseFormula(%q): unexpected error: %v", tt.formula, err)
+			continue
+		}
+		if got := f.HasMax(); got != tt.expected {
+			t.Errorf("HasMax(%q): expected %v, got %v", tt.formula, tt.expected, got)
+		}
+	}
+}
+
+func TestFormulaErrors(t *testing.T) {
+	for _, formula := range []string{"", "HP/", "(HP", "HP)", "[HP", "[]", "HP % 2", "HP STR"} {
+		if _, err := ParseFormula(formula, nil); err == nil {
+			t.Errorf("ParseFormula(%q): expected an error", formula)
+		}
+	}
+}
+
+func TestManagerDerive(t *testing.T) {
+	m := NewManager()
+	hp := m.Add("HP", 40, 50)
+	m.Add("STR", 16, 18)
+
+	bloodied, err := m.Derive("Bloodied", "HP.max/2")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if bloodied.Current != 25 || !bloodied.IsDerived() {
+		t.Errorf("Expected derived tracker at 25, got %s", bloodied)
+	}
+	carry, err := m.Derive("Carry", "STRx15")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if carry.Formula != "STR x 15" {
+		t.Errorf("Expected the formula kept as STR x 15, got %q", carry.Formula)
+	}
+	if carry.Current != 240 || carry.Max != 270 {
+		t.Errorf("Expected 240/270 (current and max from STR's), got %d/%d", carry.Current, carry.Max)
+	}
+	left, err := m.Derive("Left", "HP.max - HP")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !left.NoMax || left.Max != 0 || left.String() != "[Left] 10 (= HP.max - HP)" {
+		t.Errorf("Expected Left at 10 with no maximum, got %s", left)
+	}
+
+	// Chains update sources first, whenever the trackers are read
+	if _, err := m.Derive("Half", "HP/2"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := m.Derive("Quarter", "Half/2"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	hp.Adjust(-20)
+	if quarter := m.Get("quarter"); quarter.Current != 5 {
+		t.Errorf("Expected Quarter to follow HP to 5, got %d", quarter.Current)
+	}
+
+	// Deriving again replaces the formula
+	if half, err := m.Derive("half", "HP/4"); err != nil || half.Current != 5 || half.Formula != "HP/4" {
+		t.Errorf("Expected Half redefined as HP/4 = 5, got %v (%v)", half, err)
+	}
+}
+
+func TestManagerDeriveErrors(t *testing.T) {
+	m := NewManager()
+	m.Add("HP", 40, 50)
+	if _, err := m.Derive("Half", "HP/2"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name, formula string
+	}{
+		{"Carry", "STRx15"}, // no such source
+		{"HP", "Half*2"},    // HP isn't derived
+		{"Loop", "Loop+1"},  // itself
+		{"Half", "Quarter"}, // no such source
+		{"Zero", "HP/0"},    // can't be worked out
+		{"Bad", "HP +"},     // doesn't parse
+	}
+	for _, tt := range tests {
+		if _, err := m.Derive(tt.name, tt.formula); err == nil {
+			t.Errorf("Derive(%q, %q): expected an error", tt.name, tt.formula)
+		}
+	}
+
+	// A cycle through another derived tracker
+	if _, err := m.Derive("Quarter", "Half/2"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := m.Derive("Half", "Quarter*2"); err == nil {
+		t.Error("Expected an error for a cycle")
+	}
+
+	// Sources can't be deleted out from under a derived tracker
+	if err := m.Delete("HP"); err == nil {
+		t.Error("Expected an error deleting a source")
+	}
+	if err := m.Delete("Quarter"); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := m.Delete("Half"); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := m.Delete("HP"); err != nil {
+		t.Errorf("Expected HP deletable once nothing reads it, got %v", err)
+	}
+}
@@ -2,6 +2,7 @@ package number
 
 import (
 	"fmt"
+	"path"
 	"sort"
 	"strings"
 	"sync"
@@ -9,8 +10,9 @@ import (
 
 // Manager manages multiple number trackers
 type Manager struct {
-	trackers map[string]*Tracker // keyed by ID
-	mu       sync.RWMutex
+	trackers   map[string]*Tracker // keyed by ID
+	baselining bool                // true during an encounter; new trackers get a baseline
+	mu         sync.RWMutex
 }
 
 // NewManager creates a new tracker manager
@@ -26,15 +28,87 @@ func (m *Manager) Add(name string, current, max int) *Tracker {
 	defer m.mu.Unlock()
 
 	tracker := NewTracker(name, current, max)
+	if m.baselining {
+		tracker.SetBaseline()
+	}
 	m.trackers[tracker.ID] = tracker
 	return tracker
 }
 
+// Derive adds a tracker worked out from others by a formula (see ParseFormula),
+// e.g. "Carry" from "STRx15"; its current value comes from the sources'
+// current values and its maximum, if the formula has one (see HasMax), from
+// their maximums
+// Deriving an existing derived tracker again replaces its formula
+func (m *Manager) Derive(name, formula string) (*Tracker, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	f, err := m.parse(formula)
+	if err != nil {
+		return nil, err
+	}
+	for _, source := range f.Sources() {
+		if strings.EqualFold(source, name) {
+			return nil, fmt.Errorf("tracker '%s' can't be derived from itself", name)
+		}
+		if m.find(source) == nil {
+			return nil, fmt.Errorf("tracker '%s' not found", source)
+		}
+		if m.dependsOn(source, name) {
+			return nil, fmt.Errorf("'%s' is already worked out from '%s', so '%s' can't use it", source, name, name)
+		}
+	}
+	current, maximum, err := m.eval(f)
+	if err != nil {
+		return nil, err
+	}
+
+	tracker := m.find(name)
+	switch {
+	case tracker == nil:
+		tracker = NewTracker(name, current, maximum)
+		if m.baselining {
+			tracker.SetBaseline()
+		}
+		m.trackers[tracker.ID] = tracker
+	case !tracker.IsDerived():
+		return nil, fmt.Errorf("tracker '%s' already exists", tracker.Name)
+	default:
+		tracker.Set(current)
+		tracker.Max = maximum
+	}
+	tracker.Formula = f.Text
+	tracker.NoMax = !f.HasMax()
+	return tracker, nil
+}
+
+// eval works a formula out from the trackers' current values, and from their
+// maximums if it has a maximum (0 if not); the caller holds the lock
+func (m *Manager) eval(f *Formula) (current, maximum int, err error) {
+	if current, err = f.Eval(m.find); err != nil {
+		return 0, 0, err
+	}
+	if !f.HasMax() {
+		return current, 0, nil
+	}
+	if maximum, err = f.Eval(m.findMax); err != nil {
+		return 0, 0, err
+	}
+	return current, maximum, nil
+}
+
 // Get retrieves a tracker by name (case-insensitive)
 func (m *Manager) Get(name string) *Tracker {
-	m.mu.RLock()
-	defer m.mu.RUnlock()
+	m.mu.Lock()
+	defer m.mu.Unlock()
 
+	m.recalculate()
+	return m.find(name)
+}
+
+// find looks a tracker up by name (case-insensitive); the caller holds the lock
+func (m *Manager) find(name string) *Tracker {
 	nameLower := strings.ToLower(name)
 	for _, t := range m.trackers {
 		if strings.ToLower(t.Name) == nameLower {
@@ -44,7 +118,85 @@ func (m *Manager) Get(name string) *Tracker {
 	return nil
 }
 
+// parse parses a formula against the trackers there are (see ParseFormula);
+// the caller holds the lock
+func (m *Manager) parse(formula string) (*Formula, error) {
+	return ParseFormula(formula, func(name string) bool { return m.find(name) != nil })
+}
+
+// findMax looks a tracker up for working out a derived tracker's maximum:
+// the same tracker, but read at its maximum
+func (m *Manager) findMax(name string) *Tracker {
+	t := m.find(name)
+	if t == nil {
+		return nil
+	}
+	return &Tracker{Name: t.Name, Current: t.Max, Max: t.Max}
+}
+
+// dependsOn returns true if the named tracker is worked out, directly or
+// through other derived trackers, from source
+func (m *Manager) dependsOn(name, source string) bool {
+	seen := make(map[string]bool)
+	var walk func(name string) bool
+	walk = func(name string) bool {
+		if strings.EqualFold(name, source) {
+			return true
+		}
+		t := m.find(name)
+		if t == nil || !t.IsDerived() || seen[t.ID] {
+			return false
+		}
+		seen[t.ID] = true
+		f, err := m.parse(t.Formula)
+		if err != nil {
+			return false
+		}
+		for _, s := range f.Sources() {
+			if walk(s) {
+				return true
+			}
+		}
+		return false
+	}
+	return walk(name)
+}
+
+// recalculate brings every derived tracker up to date with its sources,
+// sources first; the caller holds the write lock
+// A tracker whose formula can't be worked out (e.g. a division by zero)
+// keeps its last value
+func (m *Manager) recalculate() {
+	done := make(map[string]bool)
+	var update func(t *Tracker)
+	update = func(t *Tracker) {
+		if done[t.ID] || !t.IsDerived() {
+			return
+		}
+		done[t.ID] = true // before the sources, so a cycle can't recurse forever
+		f, err := m.parse(t.Formula)
+		if err != nil {
+			return
+		}
+		for _, name := range f.Sources() {
+			if source := m.find(name); source != nil {
+				update(source)
+			}
+		}
+		current, maximum, err := m.eval(f)
+		if err != nil {
+			return
+		}
+		t.Set(current)
+		t.Max = maximum
+	}
+	for _, t := range m.trackers {
+		update(t)
+	}
+}
+
 // Delete removes a tracker by name (case-insensitive)
+// A tracker other trackers are derived from can't be deleted until they are
 func (m *Manager) Delete(name string) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -52,6 +204,9 @@ func (m *Manager) Delete(name string) error {
 	nameLower := strings.ToLower(name)
 	for id, t := range m.trackers {
 		if strings.ToLower(t.Name) == nameLower {
+			if users := m.derivedFrom(t.Name); len(users) > 0 {
+				return fmt.Errorf("can't delete '%s' while '%s' is worked out from it", t.Name, strings.Join(users, "', '"))
+			}
 			delete(m.trackers, id)
 			return nil
 		}
@@ -59,6 +214,28 @@ func (m *Manager) Delete(name string) error {
 	return fmt.Errorf("tracker '%s' not found", name)
 }
 
+// derivedFrom lists the derived trackers that read the named one directly
+func (m *Manager) derivedFrom(name string) []string {
+	var users []string
+	for _, t := range m.trackers {
+		if !t.IsDerived() || strings.EqualFold(t.Name, name) {
+			continue
+		}
+		f, err := m.parse(t.Formula)
+		if err != nil {
+			continue
+		}
+		for _, source := range f.Sources() {
+			if strings.EqualFold(source, name) {
+				users = append(users, t.Name)
+				break
+			}
+		}
+	}
+	sort.Strings(users)
+	return users
+}
+
 // DeleteAll removes all trackers
 func (m *Manager) DeleteAll() {
 	m.mu.Lock()
@@ -68,8 +245,10 @@ func (m *Manager) DeleteAll() {
 
 // List returns all trackers sorted by name
 func (m *Manager) List() []*Tracker {
-	m.mu.RLock()
-	defer m.mu.RUnlock()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.recalculate()
 
 	trackers := make([]*Tracker, 0, len(m.trackers))
 	for _, t := range m.trackers {
@@ -86,8 +265,10 @@ func (m *Manager) List() []*Tracker {
 
 // GetPinned returns all pinned trackers sorted by name
 func (m *Manager) GetPinned() []*Tracker {
-	m.mu.RLock()
-	defer m.mu.RUnlock()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.recalculate()
 
 	var pinned []*Tracker
 	for _, t := range m.trackers {
@@ -106,8 +287,10 @@ func (m *Manager) GetPinned() []*Tracker {
 
 // Search finds trackers whose names contain the search pattern (case-insensitive)
 func (m *Manager) Search(pattern string) []*Tracker {
-	m.mu.RLock()
-	defer m.mu.RUnlock()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.recalculate()
 
 	patternLower := strings.ToLower(pattern)
 	var results []*Tracker
@@ -126,6 +309,32 @@ func (m *Manager) Search(pattern string) []*Tracker {
 	return results
 }
 
+// Match finds trackers whose names match a glob pattern like "Goblin*" or
+// "Wolf ?" (case-insensitive), sorted by name
+func (m *Manager) Match(pattern string) ([]*Tracker, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.recalculate()
+	patternLower := strings.ToLower(pattern)
+	if _, err := path.Match(patternLower, ""); err != nil {
+		return nil, fmt.Errorf("invalid pattern '%s'", pattern)
+	}
+	var results []*Tracker
+	for _, t := range m.trackers {
+		if ok, _ := path.Match(patternLower, strings.ToLower(t.Name)); ok {
+			results = append(results, t)
+		}
+	}
+
+	// Sort by name
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Name < results[j].Name
+	})
+
+	return results, nil
+}
+
 // PinAll pins all unpinned trackers
 func (m *Manager) PinAll() int {
 	m.mu.Lock()
@@ -141,6 +350,43 @@ func (m *Manager) PinAll() int {
 	return count
 }
 
+// StartBaselines records every tracker's current value as its encounter baseline
+// Trackers added before EndBaselines is called start with a baseline too
+func (m *Manager) StartBaselines() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.baselining = true
+	for _, t := range m.trackers {
+		t.SetBaseline()
+	}
+}
+
+// EndBaselines clears every tracker's encounter baseline
+func (m *Manager) EndBaselines() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.baselining = false
+	for _, t := range m.trackers {
+		t.ClearBaseline()
+	}
+}
+
+// Restore replaces every tracker with saved ones (e.g. from a previous session)
+// If any has an encounter baseline, the encounter carries on baselining
+func (m *Manager) Restore(trackers []*Tracker) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.trackers = make(map[string]*Tracker, len(trackers))
+	m.baselining = false
+	for _, t := range trackers {
+		m.trackers[t.ID] = t
+		m.baselining = m.baselining || t.HasBaseline
+	}
+}
+
 // Count returns the total number of trackers
 func (m *Manager) Count() int {
 	m.mu.RLock()
@@ -6,6 +6,9 @@ import (
 	"time"
 )
 
+// HistorySize is how many recent values a tracker keeps for its sparkline
+const HistorySize = 8
+
 // Tracker represents a number tracker (e.g., HP, AC, etc.)
 type Tracker struct {
 	ID      string
@@ -13,6 +16,16 @@ type Tracker struct {
 	Current int
 	Max     int
 	Pinned  bool
+
+	Obscured bool // players see only a qualitative condition, not the numbers
+
+	Baseline    int  // value at the start of the current encounter
+	HasBaseline bool // true while an encounter baseline is set
+
+	History []int // recent values, oldest first (at most HistorySize)
+
+	Formula string // for a derived tracker, what it's worked out from (e.g. "STR x 15"); empty otherwise
+	NoMax   bool   // for a derived tracker, its formula has no maximum (e.g. HP.max - HP), and Max is 0
 }
 
 // NewTracker creates a new number tracker
@@ -23,17 +36,64 @@ func NewTracker(name string, current, max int) *Tracker {
 		Current: current,
 		Max:     max,
 		Pinned:  true,
+		History: []int{current},
 	}
 }
 
 // Set sets the current value
 func (t *Tracker) Set(value int) {
 	t.Current = value
+	t.record()
 }
 
 // Adjust adjusts the current value by a delta (can be positive or negative)
 func (t *Tracker) Adjust(delta int) {
 	t.Current += delta
+	t.record()
+}
+
+// record appends the current value to the history if it changed,
+// dropping the oldest values past HistorySize
+func (t *Tracker) record() {
+	if n := len(t.History); n > 0 && t.History[n-1] == t.Current {
+		return
+	}
+	t.History = append(t.History, t.Current)
+	if len(t.History) > HistorySize {
+		t.History = t.History[len(t.History)-HistorySize:]
+	}
+}
+
+// PercentOfMax returns a percentage of the tracker's maximum, rounded toward
+// zero, e.g. 50 for half (for "regain half your hit points")
+func (t *Tracker) PercentOfMax(percent int) int {
+	return t.Max * percent / 100
+}
+
+// SetBaseline records the current value as the start-of-encounter baseline
+func (t *Tracker) SetBaseline() {
+	t.Baseline = t.Current
+	t.HasBaseline = true
+}
+
+// ClearBaseline forgets the encounter baseline
+func (t *Tracker) ClearBaseline() {
+	t.Baseline = 0
+	t.HasBaseline = false
+}
+
+// Delta returns the change since the encounter baseline (false if there is none)
+func (t *Tracker) Delta() (int, bool) {
+	if !t.HasBaseline {
+		return 0, false
+	}
+	return t.Current - t.Baseline, true
+}
+
+// IsDerived returns true if the tracker is worked out from others and so
+// can't be changed directly
+func (t *Tracker) IsDerived() bool {
+	return t.Formula != ""
 }
 
 // Pin pins the tracker to display
@@ -46,9 +106,53 @@ func (t *Tracker) Unpin() {
 	t.Pinned = false
 }
 
+// Obscure hides the tracker's numbers from player-facing output
+func (t *Tracker) Obscure() {
+	t.Obscured = true
+}
+
+// Reveal shows the tracker's numbers in player-facing output again
+func (t *Tracker) Reveal() {
+	t.Obscured = false
+}
+
+// Condition describes the current value qualitatively:
+// Healthy above half, Bloodied at half or below, Critical at a quarter or below, Down at zero
+func (t *Tracker) Condition() string {
+	switch {
+	case t.Current <= 0:
+		return "Down"
+	case t.Current*4 <= t.Max:
+		return "Critical"
+	case t.Current*2 <= t.Max:
+		return "Bloodied"
+	default:
+		return "Healthy"
+	}
+}
+
+// PublicString returns the tracker as players may see it (the condition if obscured)
+func (t *Tracker) PublicString() string {
+	if t.Obscured {
+		return fmt.Sprintf("[%s] %s", t.Name, t.Condition())
+	}
+	return t.String()
+}
+
+// Value formats the tracker's value, e.g. "12/30" (just "12" if it has no maximum)
+func (t *Tracker) Value() string {
+	if t.NoMax {
+		return fmt.Sprintf("%d", t.Current)
+	}
+	return fmt.Sprintf("%d/%d", t.Current, t.Max)
+}
+
 // String returns a string representation of the tracker
 func (t *Tracker) String() string {
-	return fmt.Sprintf("[%s] %d/%d", t.Name, t.Current, t.Max)
+	if t.IsDerived() {
+		return fmt.Sprintf("[%s] %s (= %s)", t.Name, t.Value(), t.Formula)
+	}
+	return fmt.Sprintf("[%s] %s", t.Name, t.Value())
 }
 
 var idCounter uint64
@@ -1,6 +1,9 @@
 package number
 
-import "testing"
+import (
+	"strings"
+	"testing"
+)
 
 func TestNewTracker(t *testing.T) {
 	tracker := NewTracker("HP", 45, 50)
@@ -47,6 +50,35 @@ func TestAdjust(t *testing.T) {
 	}
 }
 
+func TestHistory(t *testing.T) {
+	tracker := NewTracker("HP", 45, 50)
+	tracker.Adjust(-10)
+	tracker.Adjust(0)
+	tracker.Set(35)
+	tracker.Set(50)
+
+	expected := []int{45, 35, 50}
+	if len(tracker.History) != len(expected) {
+		t.Fatalf("Expected history %v, got %v", expected, tracker.History)
+	}
+	for i, v := range expected {
+		if tracker.History[i] != v {
+			t.Errorf("Expected history %v, got %v", expected, tracker.History)
+			break
+		}
+	}
+
+	for i := 0; i < HistorySize*2; i++ {
+		tracker.Adjust(-1)
+	}
+	if len(tracker.History) != HistorySize {
+		t.Errorf("Expected history capped at %d, got %d", HistorySize, len(tracker.History))
+	}
+	if last := tracker.History[len(tracker.History)-1]; last != tracker.Current {
+		t.Errorf("Expected newest history value %d, got %d", tracker.Current, last)
+	}
+}
+
 func TestPin(t *testing.T) {
 	tracker := NewTracker("HP", 45, 50)
 	
@@ -231,3 +263,136 @@ func TestManagerPinAll(t *testing.T) {
 	}
 }
 
+
+func TestManagerBaselines(t *testing.T) {
+	manager := NewManager()
+	hp := manager.Add("HP", 45, 45)
+
+	if _, ok := hp.Delta(); ok {
+		t.Error("Expected no delta before an encounter starts")
+	}
+
+	manager.StartBaselines()
+	hp.Adjust(-12)
+	if delta, ok := hp.Delta(); !ok || delta != -12 {
+		t.Errorf("Expected delta -12, got %d (%v)", delta, ok)
+	}
+
+	// Trackers added mid-encounter start from their initial value
+	slots := manager.Add("Slots", 4, 4)
+	slots.Adjust(-1)
+	if delta, _ := slots.Delta(); delta != -1 {
+		t.Errorf("Expected delta -1 for tracker added mid-encounter, got %d", delta)
+	}
+
+	manager.EndBaselines()
+	if _, ok := hp.Delta(); ok {
+		t.Error("Expected no delta after the encounter ends")
+	}
+}
+
+func TestCondition(t *testing.T) {
+	tests := []struct {
+		current, max int
+		expected     string
+	}{
+		{45, 45, "Healthy"},
+		{23, 45, "Healthy"},
+		{22, 45, "Bloodied"},
+		{11, 45, "Critical"},
+		{0, 45, "Down"},
+		{-5, 45, "Down"},
+	}
+
+	for _, tt := range tests {
+		tracker := NewTracker("Goblin", tt.current, tt.max)
+		if result := tracker.Condition(); result != tt.expected {
+			t.Errorf("Condition() at %d/%d = %s, expected %s", tt.current, tt.max, result, tt.expected)
+		}
+	}
+}
+
+func TestPublicString(t *testing.T) {
+	tracker := NewTracker("Ogre", 20, 59)
+	if tracker.PublicString() != "[Ogre] 20/59" {
+		t.Errorf("Expected exact values before obscuring, got %s", tracker.PublicString())
+	}
+	tracker.Obscure()
+	if tracker.PublicString() != "[Ogre] Bloodied" {
+		t.Errorf("Expected '[Ogre] Bloodied', got %s", tracker.PublicString())
+	}
+	tracker.Reveal()
+	if tracker.Obscured {
+		t.Error("Expected tracker to be revealed")
+	}
+}
+
+func TestRestore(t *testing.T) {
+	m := NewManager()
+	m.Add("Old", 1, 1)
+
+	saved := NewTracker("Ogre", 40, 59)
+	saved.SetBaseline()
+	m.Restore([]*Tracker{saved})
+
+	if m.Count() != 1 || m.Get("old") != nil || m.Get("ogre") != saved {
+		t.Errorf("Expected only the restored tracker, got %v", m.List())
+	}
+	if added := m.Add("Goblin", 7, 7); !added.HasBaseline {
+		t.Error("Expected trackers added mid-encounter to get a baseline")
+	}
+}
+
+func TestPercentOfMax(t *testing.T) {
+	tracker := NewTracker("HP", 10, 45)
+	tests := []struct {
+		percent  int
+		expected int
+	}{
+		{50, 22},
+		{25, 11},
+		{100, 45},
+		{-10, -4},
+		{0, 0},
+	}
+	for _, tt := range tests {
+		if got := tracker.PercentOfMax(tt.percent); got != tt.expected {
+			t.Errorf("PercentOfMax(%d): expected %d, got %d", tt.percent, tt.expected, got)
+		}
+	}
+}
+
+func TestManagerMatch(t *testing.T) {
+	m := NewManager()
+	m.Add("Goblin 1", 7, 7)
+	m.Add("Goblin 2", 7, 7)
+	m.Add("Goblin Boss", 21, 21)
+	m.Add("Ogre", 59, 59)
+
+	tests := []struct {
+		pattern  string
+		expected []string
+	}{
+		{"goblin*", []string{"Goblin 1", "Goblin 2", "Goblin Boss"}},
+		{"Goblin ?", []string{"Goblin 1", "Goblin 2"}},
+		{"*", []string{"Goblin 1", "Goblin 2", "Goblin Boss", "Ogre"}},
+		{"Troll*", nil},
+	}
+	for _, tt := range tests {
+		results, err := m.Match(tt.pattern)
+		if err != nil {
+			t.Errorf("Match(%q): unexpected error: %v", tt.pattern, err)
+			continue
+		}
+		var names []string
+		for _, r := range results {
+			names = append(names, r.Name)
+		}
+		if strings.Join(names, ",") != strings.Join(tt.expected, ",") {
+			t.Errorf("Match(%q): expected %v, got %v", tt.pattern, tt.expected, names)
+		}
+	}
+	if _, err := m.Match("Goblin["); err == nil {
+		t.Error("Expected an error for a malformed pattern")
+	}
+}
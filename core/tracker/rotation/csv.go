@@ -0,0 +1,127 @@
+package rotation
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// csvColumns are the columns of an initiative CSV, in order when there is no header row
+var csvColumns = []string{"name", "initiative", "hp", "ac"}
+
+// ParseCSV reads an encounter from CSV rows of name,initiative,hp,ac
+// Only the name is required; a blank initiative is rolled as d20+bonus.
+// An optional header row may reorder the columns or add "bonus" and "side",
+// e.g. "name,ac,hp,bonus". Errors name the offending row
+func ParseCSV(r io.Reader) (*Encounter, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+
+	columns := csvColumns
+	var e Encounter
+	for first := true; ; first = false {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return nil, fmt.Errorf("row %d: %w", parseErr.StartLine, parseErr.Err)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		// Rows are counted as lines in the file, blank and comment lines
+		// included, so the number is the one an editor shows
+		row, _ := reader.FieldPos(0)
+		if first && strings.EqualFold(strings.TrimSpace(record[0]), "name") {
+			if columns, err = csvHeader(record); err != nil {
+				return nil, fmt.Errorf("row %d: %w", row, err)
+			}
+			continue
+		}
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue // blank line
+		}
+
+		entry, err := csvEntry(columns, record)
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", row, err)
+		}
+		e.Participants = append(e.Participants, entry)
+	}
+
+	if len(e.Participants) == 0 {
+		return nil, fmt.Errorf("no participants found")
+	}
+	return &e, nil
+}
+
+// csvHeader maps a header row to column names
+func csvHeader(record []string) ([]string, error) {
+	columns := make([]string, len(record))
+	for i, field := range record {
+		column := strings.ToLower(strings.TrimSpace(field))
+		switch column {
+		case "init":
+			column = "initiative"
+		case "name", "initiative", "hp", "ac", "bonus", "side":
+		default:
+			return nil, fmt.Errorf("unknown column '%s' (expected name, initiative, hp, ac, bonus, side)", field)
+		}
+		columns[i] = column
+	}
+	return columns, nil
+}
+
+// csvEntry builds an encounter entry from one row
+func csvEntry(columns []string, record []string) (Entry, error) {
+	if len(record) > len(columns) {
+		return Entry{}, fmt.Errorf("too many fields (expected at most %d: %s)", len(columns), strings.Join(columns, ","))
+	}
+
+	var entry Entry
+	for i, field := range record {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		if columns[i] == "name" {
+			entry.Name = field
+			continue
+		}
+		if columns[i] == "side" {
+			entry.Side = field
+			continue
+		}
+
+		value, err := strconv.Atoi(field)
+		if err != nil {
+			return Entry{}, fmt.Errorf("%s '%s' is not a number", columns[i], field)
+		}
+		switch columns[i] {
+		case "initiative":
+			entry.Initiative = &value
+		case "hp":
+			entry.HP = value
+		case "ac":
+			entry.AC = value
+		case "bonus":
+			entry.Bonus = value
+		}
+	}
+
+	if entry.Name == "" {
+		return Entry{}, fmt.Errorf("missing name")
+	}
+	if entry.HP < 0 || entry.AC < 0 {
+		return Entry{}, fmt.Errorf("hp and ac can't be negative")
+	}
+	return entry, nil
+}
@@ -0,0 +1,69 @@
+package rotation
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseCSV(t *testing.T) {
+	input := `Aria,17,30,15
+Goblin,,7,13
+# reinforcements
+Ogre, 8, 59`
+
+	e, err := ParseCSV(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(e.Participants) != 3 {
+		t.Fatalf("Expected 3 participants, got %d", len(e.Participants))
+	}
+
+	aria := e.Participants[0]
+	if aria.Name != "Aria" || *aria.Initiative != 17 || aria.HP != 30 || aria.AC != 15 {
+		t.Errorf("Unexpected entry for Aria: %+v", aria)
+	}
+	if e.Participants[1].Initiative != nil {
+		t.Error("Expected Goblin's blank initiative to be left for rolling")
+	}
+	if ogre := e.Participants[2]; *ogre.Initiative != 8 || ogre.HP != 59 || ogre.AC != 0 {
+		t.Errorf("Unexpected entry for Ogre: %+v", ogre)
+	}
+}
+
+func TestParseCSVHeader(t *testing.T) {
+	input := "Name,AC,HP,Bonus\nGoblin,13,7,2\n"
+
+	e, err := ParseCSV(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	goblin := e.Participants[0]
+	if goblin.AC != 13 || goblin.HP != 7 || goblin.Bonus != 2 || goblin.Initiative != nil {
+		t.Errorf("Unexpected entry: %+v", goblin)
+	}
+}
+
+func TestParseCSVErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"Aria,17\nGoblin,fast,7", "row 2: initiative 'fast' is not a number"},
+		{"Aria,17,30,15,extra", "row 1: too many fields"},
+		{"Aria,17\n,12", "row 2: missing name"},
+		{"name,speed\nAria,30", "row 1: unknown column 'speed'"},
+		{"Goblin,12,-7", "row 1: hp and ac can't be negative"},
+		{"# nobody here", "no participants found"},
+		{"# party\n\nAria,17\n\nGoblin,fast", "row 5: initiative 'fast' is not a number"},
+		{"name,init\n# monsters\n,12", "row 3: missing name"},
+		{"Aria,\"17", "row 1: extraneous or missing \" in quoted-field"},
+	}
+
+	for _, tt := range tests {
+		_, err := ParseCSV(strings.NewReader(tt.input))
+		if err == nil || !strings.HasPrefix(err.Error(), tt.expected) {
+			t.Errorf("Expected error starting '%s', got %v", tt.expected, err)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package rotation
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Entry describes one participant in an encounter file
+type Entry struct {
+	Name       string `json:"name"`
+	Initiative *int   `json:"initiative,omitempty"` // rolled as d20+bonus if omitted
+	Bonus      int    `json:"bonus,omitempty"`
+	HP         int    `json:"hp,omitempty"`
+	AC         int    `json:"ac,omitempty"`
+	Side       string `json:"side,omitempty"`
+	Summoner   string `json:"summoner,omitempty"`
+	Script     string `json:"script,omitempty"` // commands run when their turn starts
+}
+
+// Encounter is a prepared initiative setup, loaded from JSON:
+//
+//	{"mode": "side", "difficulty": "hard", "participants": [{"name": "Goblin", "bonus": 2, "hp": 7, "side": "monsters"}]}
+//
+// A participant's "script" is run when their turn starts (see Participant.Script)
+type Encounter struct {
+	Mode         string  `json:"mode,omitempty"`
+	Difficulty   string  `json:"difficulty,omitempty"` // intended difficulty (easy, medium, hard, deadly)
+	Participants []Entry `json:"participants"`
+}
+
+// ParseEncounter reads an encounter from JSON
+func ParseEncounter(r io.Reader) (*Encounter, error) {
+	var e Encounter
+	if err := json.NewDecoder(r).Decode(&e); err != nil {
+		return nil, fmt.Errorf("invalid encounter: %w", err)
+	}
+	if len(e.Participants) == 0 {
+		return nil, fmt.Errorf("encounter has no participants")
+	}
+	for i, p := range e.Participants {
+		if p.Name == "" {
+			return nil, fmt.Errorf("participant %d has no name", i+1)
+		}
+	}
+	return &e, nil
+}
+
+// LoadEncounter reads an encounter from a JSON file
+func LoadEncounter(path string) (*Encounter, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParseEncounter(f)
+}
+
+// Tracker builds an initiative tracker for the encounter, rolling any missing initiatives
+func (e *Encounter) Tracker() (*Tracker, error) {
+	mode := ModeStandard
+	if e.Mode != "" {
+		var err error
+		if mode, err = ParseMode(e.Mode); err != nil {
+			return nil, err
+		}
+	}
+
+	t := NewTrackerWithMode(mode)
+	var summons []Entry
+	for _, entry := range e.Participants {
+		if entry.Summoner != "" {
+			summons = append(summons, entry)
+			continue
+		}
+		if mode == ModeSide && entry.Side == "" {
+			return nil, fmt.Errorf("%s has no side (required in side mode)", entry.Name)
+		}
+
+		initiative := 0
+		if entry.Initiative != nil && mode != ModeCyclic {
+			initiative = *entry.Initiative
+		} else {
+			var err error
+			if initiative, err = rollInitiative(entry.Bonus); err != nil {
+				return nil, err
+			}
+		}
+		t.Participants = append(t.Participants, &Participant{
+			Name:       entry.Name,
+			Initiative: initiative,
+			IsActive:   true,
+			Side:       entry.Side,
+			Bonus:      entry.Bonus,
+			HP:         entry.HP,
+			AC:         entry.AC,
+			Script:     entry.Script,
+		})
+	}
+	t.sort()
+
+	// Summons go in once their summoners are placed
+	for _, entry := range summons {
+		if err := t.Summon(entry.Summoner, entry.Name); err != nil {
+			return nil, err
+		}
+		t.find(entry.Name).HP = entry.HP
+		t.find(entry.Name).AC = entry.AC
+		t.find(entry.Name).Script = entry.Script
+	}
+	return t, nil
+}
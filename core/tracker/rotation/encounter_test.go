@@ -0,0 +1,70 @@
+package rotation
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEncounter(t *testing.T) {
+	input := `{
+		"mode": "side",
+		"participants": [
+			{"name": "Aria", "initiative": 17, "side": "party"},
+			{"name": "Goblin", "initiative": 12, "hp": 7, "side": "monsters", "script": "r d6"},
+			{"name": "Wolf", "summoner": "Aria"},
+			{"name": "Ogre", "bonus": -1, "side": "monsters"}
+		]
+	}`
+	encounter, err := ParseEncounter(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	tracker, err := encounter.Tracker()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if tracker.Mode != ModeSide {
+		t.Errorf("Expected side mode, got %s", tracker.Mode)
+	}
+	if len(tracker.Participants) != 4 {
+		t.Fatalf("Expected 4 participants, got %d", len(tracker.Participants))
+	}
+	if tracker.Participants[0].Name != "Aria" || tracker.Participants[1].Name != "Wolf" {
+		t.Errorf("Expected Aria followed by her summon, got %s, %s",
+			tracker.Participants[0].Name, tracker.Participants[1].Name)
+	}
+	for _, p := range tracker.Participants {
+		if p.Name == "Goblin" && (p.HP != 7 || p.Script != "r d6") {
+			t.Errorf("Expected Goblin to have 7 HP and a script, got %d and '%s'", p.HP, p.Script)
+		}
+		if p.Name == "Ogre" && (p.Initiative < 0 || p.Initiative > 19) {
+			t.Errorf("Ogre rolled initiative %d outside d20-1", p.Initiative)
+		}
+	}
+}
+
+func TestEncounterErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		desc  string
+	}{
+		{`{"participants": []}`, "no participants"},
+		{`{"participants": [{"initiative": 3}]}`, "missing name"},
+		{`{"mode": "chaos", "participants": [{"name": "A"}]}`, "unknown mode"},
+		{`{"mode": "side", "participants": [{"name": "A"}]}`, "missing side"},
+		{`not json`, "invalid json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			encounter, err := ParseEncounter(strings.NewReader(tt.input))
+			if err == nil {
+				_, err = encounter.Tracker()
+			}
+			if err == nil {
+				t.Errorf("Expected error for %s", tt.desc)
+			}
+		})
+	}
+}
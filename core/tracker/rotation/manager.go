@@ -1,11 +1,18 @@
 package rotation
 
-import "sync"
+import (
+	"fmt"
+	"sync"
+)
+
+// maxRecent is how many ended trackers are kept for resuming
+const maxRecent = 5
 
 // Manager manages the initiative tracker state
 type Manager struct {
 	tracker *Tracker
 	active  bool
+	recent  []*Tracker // ended or replaced trackers, most recent first
 	mu      sync.RWMutex
 }
 
@@ -19,20 +26,91 @@ func NewManager() *Manager {
 
 // Start starts a new initiative session
 func (m *Manager) Start() {
+	m.StartWithMode(ModeStandard)
+}
+
+// StartWithMode starts a new initiative session using the given turn-order mode
+func (m *Manager) StartWithMode(mode Mode) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	m.tracker = NewTracker()
+	m.shelve()
+	m.tracker = NewTrackerWithMode(mode)
 	m.active = true
 }
 
+// StartEncounter starts a new initiative session from a prepared encounter
+func (m *Manager) StartEncounter(e *Encounter) error {
+	tracker, err := e.Tracker()
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.shelve()
+	m.tracker = tracker
+	m.active = true
+	return nil
+}
+
 // End ends the current initiative session
 func (m *Manager) End() {
 	m.mu.Lock()
 	defer m.mu.Unlock()
+	m.shelve()
 	m.tracker = nil
 	m.active = false
 }
 
+// shelve keeps the current tracker (if it has anyone in it) in the recent list (caller holds the lock)
+func (m *Manager) shelve() {
+	if m.tracker == nil || !m.tracker.HasParticipants() {
+		return
+	}
+	m.recent = append([]*Tracker{m.tracker}, m.recent...)
+	if len(m.recent) > maxRecent {
+		m.recent = m.recent[:maxRecent]
+	}
+}
+
+// Recent returns recently ended trackers, most recent first
+func (m *Manager) Recent() []*Tracker {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]*Tracker(nil), m.recent...)
+}
+
+// Resume brings back the n-th most recently ended tracker (1 = most recent)
+// Any active tracker is shelved in its place, so encounters can be swapped back and forth
+func (m *Manager) Resume(n int) (*Tracker, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.recent) == 0 {
+		return nil, fmt.Errorf("no recent initiative to resume")
+	}
+	if n < 1 || n > len(m.recent) {
+		return nil, fmt.Errorf("no recent initiative #%d (expected 1-%d)", n, len(m.recent))
+	}
+
+	tracker := m.recent[n-1]
+	m.recent = append(m.recent[:n-1], m.recent[n:]...)
+	m.shelve()
+	m.tracker = tracker
+	m.active = true
+	return tracker, nil
+}
+
+// Restore makes a saved tracker (e.g. from a previous session) the active one,
+// shelving any active tracker
+func (m *Manager) Restore(t *Tracker) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.shelve()
+	m.tracker = t
+	m.active = true
+}
+
 // IsActive returns true if initiative is currently active
 func (m *Manager) IsActive() bool {
 	m.mu.RLock()
@@ -56,6 +134,65 @@ func (m *Manager) Add(name string, initiative int) {
 	}
 }
 
+// AddToSide adds a participant to a side of the current initiative
+func (m *Manager) AddToSide(name string, initiative int, side string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.tracker != nil {
+		m.tracker.AddToSide(name, initiative, side)
+	}
+}
+
+// AddWithBonus adds a participant who rolls d20+bonus for initiative (cyclic mode)
+func (m *Manager) AddWithBonus(name string, bonus int) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.tracker == nil {
+		return 0, fmt.Errorf("no active initiative")
+	}
+	return m.tracker.AddWithBonus(name, bonus)
+}
+
+// SetHP records a participant's maximum hit points
+func (m *Manager) SetHP(name string, hp int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.tracker == nil {
+		return fmt.Errorf("no active initiative")
+	}
+	return m.tracker.SetHP(name, hp)
+}
+
+// SetAC records a participant's armor class
+func (m *Manager) SetAC(name string, ac int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.tracker == nil {
+		return fmt.Errorf("no active initiative")
+	}
+	return m.tracker.SetAC(name, ac)
+}
+
+// Link attaches a number tracker (by name) to a participant
+func (m *Manager) Link(name, tracker string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.tracker == nil {
+		return fmt.Errorf("no active initiative")
+	}
+	return m.tracker.Link(name, tracker)
+}
+
+// SetScript sets the commands run when a participant's turn starts ("" for none)
+func (m *Manager) SetScript(name, script string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.tracker == nil {
+		return fmt.Errorf("no active initiative")
+	}
+	return m.tracker.SetScript(name, script)
+}
+
 // Next advances to the next turn
 func (m *Manager) Next() {
 	m.mu.Lock()
@@ -65,6 +202,46 @@ func (m *Manager) Next() {
 	}
 }
 
+// NextTo hands the turn to a specific participant (popcorn mode)
+func (m *Manager) NextTo(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.tracker != nil {
+		return m.tracker.NextTo(name)
+	}
+	return nil
+}
+
+// Use marks an action as spent by a participant until their next turn
+func (m *Manager) Use(name string, a Action) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.tracker == nil {
+		return fmt.Errorf("no active initiative")
+	}
+	return m.tracker.Use(name, a)
+}
+
+// Summon adds a participant linked to a summoner
+func (m *Manager) Summon(summoner, name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.tracker == nil {
+		return fmt.Errorf("no active initiative")
+	}
+	return m.tracker.Summon(summoner, name)
+}
+
+// Dismiss removes everything summoned by a participant
+func (m *Manager) Dismiss(summoner string) []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.tracker != nil {
+		return m.tracker.Dismiss(summoner)
+	}
+	return nil
+}
+
 // MarkOut marks a participant as out
 func (m *Manager) MarkOut(name string) error {
 	m.mu.Lock()
@@ -3,13 +3,113 @@ package rotation
 import (
 	"fmt"
 	"sort"
+	"strings"
+
+	"github.com/angusmclean/tavernshell/core/dice"
+)
+
+// Mode selects how turn order is determined
+type Mode int
+
+const (
+	ModeStandard Mode = iota // Individual initiative, highest first
+	ModeSide                 // Side initiative: everyone on a side acts together
+	ModePopcorn              // Popcorn: the current actor picks who goes next
+	ModeCyclic               // Cyclic: everyone re-rolls initiative at the top of each round
+)
+
+// String returns the name of the mode
+func (m Mode) String() string {
+	switch m {
+	case ModeSide:
+		return "side"
+	case ModePopcorn:
+		return "popcorn"
+	case ModeCyclic:
+		return "cyclic"
+	default:
+		return "standard"
+	}
+}
+
+// ParseMode parses a mode name (case-insensitive)
+func ParseMode(name string) (Mode, error) {
+	switch strings.ToLower(name) {
+	case "standard", "std", "individual":
+		return ModeStandard, nil
+	case "side", "sides":
+		return ModeSide, nil
+	case "popcorn", "pop", "balsera":
+		return ModePopcorn, nil
+	case "cyclic", "reroll":
+		return ModeCyclic, nil
+	default:
+		return ModeStandard, fmt.Errorf("unknown initiative mode '%s' (expected standard, side, popcorn, or cyclic)", name)
+	}
+}
+
+// Action is one part of a participant's action economy; values combine as a bit set
+type Action int
+
+const (
+	ActionMain     Action = 1 << iota // Action
+	ActionBonus                       // Bonus action
+	ActionReaction                    // Reaction
 )
 
+// String returns the name of the action
+func (a Action) String() string {
+	switch a {
+	case ActionMain:
+		return "action"
+	case ActionBonus:
+		return "bonus action"
+	case ActionReaction:
+		return "reaction"
+	default:
+		return "unknown action"
+	}
+}
+
+// ParseAction parses an action name like "action", "bonus", or "reaction" (case-insensitive)
+func ParseAction(name string) (Action, error) {
+	switch strings.ToLower(name) {
+	case "action", "act", "a":
+		return ActionMain, nil
+	case "bonus", "ba", "b", "bonusaction":
+		return ActionBonus, nil
+	case "reaction", "react", "r":
+		return ActionReaction, nil
+	default:
+		return 0, fmt.Errorf("unknown action '%s' (expected action, bonus, or reaction)", name)
+	}
+}
+
 // Participant represents a participant in initiative
 type Participant struct {
 	Name       string
 	Initiative int
-	IsActive   bool // false if dead/out of combat
+	IsActive   bool   // false if dead/out of combat
+	Side       string // side this participant belongs to (side mode only)
+	Acted      bool   // true once they have acted this round (popcorn mode only)
+	Bonus      int    // initiative bonus used for re-rolls (cyclic mode only)
+	Summoner   string // name of the participant who summoned this one ("" if none)
+	HP         int    // maximum hit points (0 if unknown)
+	AC         int    // armor class (0 if unknown)
+	Tracker    string // name of the linked number tracker ("" if none)
+	Used       Action // actions spent since the start of their last turn
+	Script     string // commands run when their turn starts, e.g. "t adj Troll +10; r d6" ("" if none)
+}
+
+// HasUsed returns true if the participant has spent the given action
+func (p *Participant) HasUsed(a Action) bool {
+	return p.Used&a != 0
+}
+
+// takesTurns returns true if the participant gets turns of their own
+// Summons act on their summoner's turn, so they never do
+func (p *Participant) takesTurns() bool {
+	return p.IsActive && p.Summoner == ""
 }
 
 // Tracker manages initiative order and turn tracking
@@ -17,14 +117,21 @@ type Tracker struct {
 	Participants []*Participant
 	CurrentTurn  int // index into Participants
 	Round        int
+	Mode         Mode
 }
 
 // NewTracker creates a new initiative tracker
 func NewTracker() *Tracker {
+	return NewTrackerWithMode(ModeStandard)
+}
+
+// NewTrackerWithMode creates a new initiative tracker using the given turn-order mode
+func NewTrackerWithMode(mode Mode) *Tracker {
 	return &Tracker{
 		Participants: []*Participant{},
 		CurrentTurn:  0,
 		Round:        1,
+		Mode:         mode,
 	}
 }
 
@@ -39,22 +146,187 @@ func (t *Tracker) Add(name string, initiative int) {
 	t.sort()
 }
 
+// AddToSide adds a participant to a side (side mode)
+// A side acts on the best initiative rolled by any of its members
+func (t *Tracker) AddToSide(name string, initiative int, side string) {
+	p := &Participant{
+		Name:       name,
+		Initiative: initiative,
+		IsActive:   true,
+		Side:       side,
+	}
+	t.Participants = append(t.Participants, p)
+	t.sort()
+}
+
+// AddWithBonus adds a participant who rolls d20+bonus for initiative (cyclic mode)
+// The bonus is kept so initiative can be re-rolled every round
+func (t *Tracker) AddWithBonus(name string, bonus int) (int, error) {
+	initiative, err := rollInitiative(bonus)
+	if err != nil {
+		return 0, err
+	}
+	p := &Participant{
+		Name:       name,
+		Initiative: initiative,
+		IsActive:   true,
+		Bonus:      bonus,
+	}
+	t.Participants = append(t.Participants, p)
+	t.sort()
+	return initiative, nil
+}
+
+// Reroll re-rolls everyone's initiative from their bonus, re-sorts, and
+// hands the turn to the new first active participant
+func (t *Tracker) Reroll() error {
+	rolled := make(map[string]int)
+	for _, p := range t.Participants {
+		if p.Summoner != "" {
+			continue
+		}
+		initiative, err := rollInitiative(p.Bonus)
+		if err != nil {
+			return err
+		}
+		p.Initiative = initiative
+		rolled[p.Name] = initiative
+	}
+	// Summons keep acting on their summoner's initiative
+	for _, p := range t.Participants {
+		if initiative, ok := rolled[p.Summoner]; ok && p.Summoner != "" {
+			p.Initiative = initiative
+		}
+	}
+	t.sort()
+
+	t.CurrentTurn = 0
+	for i, p := range t.Participants {
+		if p.takesTurns() {
+			t.CurrentTurn = i
+			break
+		}
+	}
+	return nil
+}
+
+// rollInitiative rolls d20+bonus
+func rollInitiative(bonus int) (int, error) {
+	result, err := dice.RollExpression(&dice.Expression{Count: 1, Sides: 20, Modifier: bonus})
+	if err != nil {
+		return 0, err
+	}
+	return result.Total, nil
+}
+
 // sort sorts participants by initiative (descending), then alphabetically by name
+// In side mode, participants are grouped by side and sides are ordered by their best initiative
+// Summons sort directly after their summoner
 func (t *Tracker) sort() {
+	sideInitiative := t.sideInitiatives()
+	byName := make(map[string]*Participant, len(t.Participants))
+	for _, p := range t.Participants {
+		byName[p.Name] = p
+	}
+	anchor := func(p *Participant) *Participant {
+		if owner, ok := byName[p.Summoner]; ok && p.Summoner != "" {
+			return owner
+		}
+		return p
+	}
+
 	sort.Slice(t.Participants, func(i, j int) bool {
-		if t.Participants[i].Initiative == t.Participants[j].Initiative {
-			return t.Participants[i].Name < t.Participants[j].Name
+		a, b := anchor(t.Participants[i]), anchor(t.Participants[j])
+		if a == b {
+			// Same summoner group: summoner first, then summons by name
+			x, y := t.Participants[i], t.Participants[j]
+			if (x.Summoner == "") != (y.Summoner == "") {
+				return x.Summoner == ""
+			}
+			return x.Name < y.Name
 		}
-		return t.Participants[i].Initiative > t.Participants[j].Initiative
+		if t.Mode == ModeSide && a.Side != b.Side {
+			if sideInitiative[a.Side] == sideInitiative[b.Side] {
+				return a.Side < b.Side
+			}
+			return sideInitiative[a.Side] > sideInitiative[b.Side]
+		}
+		if a.Initiative == b.Initiative {
+			return a.Name < b.Name
+		}
+		return a.Initiative > b.Initiative
 	})
 }
 
+// sideInitiatives returns the best initiative of each side
+func (t *Tracker) sideInitiatives() map[string]int {
+	best := make(map[string]int)
+	for _, p := range t.Participants {
+		if current, ok := best[p.Side]; !ok || p.Initiative > current {
+			best[p.Side] = p.Initiative
+		}
+	}
+	return best
+}
+
 // Next advances to the next active participant's turn
+// In side mode this advances to the next side; in popcorn mode it picks the
+// next participant in initiative order who has not yet acted this round
 func (t *Tracker) Next() {
 	if len(t.Participants) == 0 {
 		return
 	}
 
+	switch t.Mode {
+	case ModeSide:
+		t.nextSide()
+	case ModePopcorn:
+		t.nextPopcorn()
+	case ModeCyclic:
+		round := t.Round
+		t.nextStandard()
+		if t.Round != round {
+			// Keep the old order if the dice fail; there is nothing better to fall back to
+			_ = t.Reroll()
+		}
+	default:
+		t.nextStandard()
+	}
+	t.startTurn()
+}
+
+// startTurn refreshes the action economy of whoever's turn it now is
+// In side mode the whole side refreshes; summons refresh with their summoner
+func (t *Tracker) startTurn() {
+	current := t.GetCurrent()
+	if current == nil {
+		return
+	}
+	for _, p := range t.Participants {
+		if p == current || p.Summoner == current.Name || (t.Mode == ModeSide && p.Side == current.Side) {
+			p.Used = 0
+		}
+	}
+}
+
+// Use marks an action as spent by a participant until their next turn
+func (t *Tracker) Use(name string, a Action) error {
+	p := t.find(name)
+	if p == nil {
+		return fmt.Errorf("participant '%s' not found", name)
+	}
+	if !p.IsActive {
+		return fmt.Errorf("%s is out of combat", p.Name)
+	}
+	if p.HasUsed(a) {
+		return fmt.Errorf("%s has already used their %s", p.Name, a)
+	}
+	p.Used |= a
+	return nil
+}
+
+// nextStandard advances to the next active participant in initiative order
+func (t *Tracker) nextStandard() {
 	// Find next active participant
 	startIndex := t.CurrentTurn
 	for {
@@ -72,17 +344,307 @@ func (t *Tracker) Next() {
 		}
 
 		// Found an active participant
-		if t.Participants[t.CurrentTurn].IsActive {
+		if t.Participants[t.CurrentTurn].takesTurns() {
 			break
 		}
 	}
 }
 
+// nextSide advances to the first active participant of the next side
+func (t *Tracker) nextSide() {
+	startSide := t.Participants[t.CurrentTurn].Side
+	for i := 0; i < len(t.Participants); i++ {
+		t.CurrentTurn++
+		if t.CurrentTurn >= len(t.Participants) {
+			t.CurrentTurn = 0
+			t.Round++
+		}
+		p := t.Participants[t.CurrentTurn]
+		if p.takesTurns() && p.Side != startSide {
+			return
+		}
+	}
+
+	// Only one side is still standing, so it goes again
+	for i, p := range t.Participants {
+		if p.takesTurns() && p.Side == startSide {
+			t.CurrentTurn = i
+			return
+		}
+	}
+}
+
+// nextPopcorn advances to the first active participant who has not acted this round
+func (t *Tracker) nextPopcorn() {
+	t.Participants[t.CurrentTurn].Acted = true
+	for i, p := range t.Participants {
+		if p.takesTurns() && !p.Acted {
+			t.CurrentTurn = i
+			return
+		}
+	}
+
+	// Everyone has acted: start a new round
+	t.newPopcornRound()
+	for i, p := range t.Participants {
+		if p.takesTurns() {
+			t.CurrentTurn = i
+			return
+		}
+	}
+}
+
+// NextTo hands the turn to a specific participant (popcorn mode)
+// Once every active participant has acted, the last actor may pick anyone
+// (including themselves) to start the next round
+func (t *Tracker) NextTo(name string) error {
+	if t.Mode != ModePopcorn {
+		return fmt.Errorf("choosing who goes next only works in popcorn initiative")
+	}
+
+	index := -1
+	for i, p := range t.Participants {
+		if p.Name == name {
+			index = i
+			break
+		}
+	}
+	if index == -1 {
+		return fmt.Errorf("participant '%s' not found", name)
+	}
+
+	target := t.Participants[index]
+	if !target.IsActive {
+		return fmt.Errorf("%s is out of combat", name)
+	}
+	if target.Summoner != "" {
+		return fmt.Errorf("%s acts on %s's turn", name, target.Summoner)
+	}
+
+	// Count who else still has to act once the current participant is done
+	current := t.GetCurrent()
+	waiting := 0
+	for _, p := range t.Participants {
+		if p.takesTurns() && !p.Acted && p != current {
+			waiting++
+		}
+	}
+
+	if target.Acted || target == current {
+		if waiting > 0 {
+			return fmt.Errorf("%s has already acted this round", name)
+		}
+		t.newPopcornRound()
+	} else if current != nil {
+		current.Acted = true
+	}
+
+	t.CurrentTurn = index
+	t.startTurn()
+	return nil
+}
+
+// newPopcornRound starts a new popcorn round, clearing everyone's acted flag
+func (t *Tracker) newPopcornRound() {
+	t.Round++
+	for _, p := range t.Participants {
+		p.Acted = false
+	}
+}
+
+// Upcoming returns up to n active participants due to act after the current one
+// In side mode it returns the first active member of each upcoming side; in
+// popcorn mode it returns those still waiting to act this round, in initiative order
+func (t *Tracker) Upcoming(n int) []*Participant {
+	current := t.GetCurrent()
+	if current == nil || n <= 0 {
+		return nil
+	}
+
+	var upcoming []*Participant
+	if t.Mode == ModePopcorn {
+		for _, p := range t.Participants {
+			if len(upcoming) == n {
+				break
+			}
+			if p.takesTurns() && !p.Acted && p != current {
+				upcoming = append(upcoming, p)
+			}
+		}
+		return upcoming
+	}
+
+	lastSide := current.Side
+	for i := 1; i < len(t.Participants) && len(upcoming) < n; i++ {
+		p := t.Participants[(t.CurrentTurn+i)%len(t.Participants)]
+		if !p.takesTurns() {
+			continue
+		}
+		if t.Mode == ModeSide {
+			if p.Side == lastSide {
+				continue
+			}
+			lastSide = p.Side
+		}
+		upcoming = append(upcoming, p)
+	}
+	return upcoming
+}
+
+// CurrentSide returns the side whose turn it is (side mode), or "" otherwise
+func (t *Tracker) CurrentSide() string {
+	if t.Mode != ModeSide {
+		return ""
+	}
+	current := t.GetCurrent()
+	if current == nil {
+		return ""
+	}
+	return current.Side
+}
+
+// find returns the participant with the given name (case-insensitive), or nil
+func (t *Tracker) find(name string) *Participant {
+	for _, p := range t.Participants {
+		if strings.EqualFold(p.Name, name) {
+			return p
+		}
+	}
+	return nil
+}
+
+// SetHP records a participant's maximum hit points
+func (t *Tracker) SetHP(name string, hp int) error {
+	p := t.find(name)
+	if p == nil {
+		return fmt.Errorf("participant '%s' not found", name)
+	}
+	if hp < 0 {
+		return fmt.Errorf("hit points cannot be negative")
+	}
+	p.HP = hp
+	return nil
+}
+
+// SetAC records a participant's armor class
+func (t *Tracker) SetAC(name string, ac int) error {
+	p := t.find(name)
+	if p == nil {
+		return fmt.Errorf("participant '%s' not found", name)
+	}
+	if ac < 0 {
+		return fmt.Errorf("armor class cannot be negative")
+	}
+	p.AC = ac
+	return nil
+}
+
+// Link attaches a number tracker (by name) to a participant
+func (t *Tracker) Link(name, tracker string) error {
+	p := t.find(name)
+	if p == nil {
+		return fmt.Errorf("participant '%s' not found", name)
+	}
+	p.Tracker = tracker
+	return nil
+}
+
+// SetScript sets the commands run when a participant's turn starts ("" for none)
+func (t *Tracker) SetScript(name, script string) error {
+	p := t.find(name)
+	if p == nil {
+		return fmt.Errorf("participant '%s' not found", name)
+	}
+	p.Script = strings.TrimSpace(script)
+	return nil
+}
+
+// Summon adds a participant linked to a summoner (a companion, mount, or spell effect)
+// Summons act on their summoner's turn and are listed directly beneath them
+func (t *Tracker) Summon(summoner, name string) error {
+	var owner *Participant
+	for _, p := range t.Participants {
+		if p.Name == name {
+			return fmt.Errorf("participant '%s' already exists", name)
+		}
+		if p.Name == summoner {
+			owner = p
+		}
+	}
+	if owner == nil {
+		return fmt.Errorf("participant '%s' not found", summoner)
+	}
+	if owner.Summoner != "" {
+		return fmt.Errorf("%s is itself a summon", summoner)
+	}
+	if !owner.IsActive {
+		return fmt.Errorf("%s is out of combat", summoner)
+	}
+
+	current := t.GetCurrent()
+	t.Participants = append(t.Participants, &Participant{
+		Name:       name,
+		Initiative: owner.Initiative,
+		IsActive:   true,
+		Side:       owner.Side,
+		Summoner:   owner.Name,
+	})
+	t.sort()
+	t.restoreCurrent(current)
+	return nil
+}
+
+// SummonsOf returns the names of everything summoned by a participant
+func (t *Tracker) SummonsOf(summoner string) []string {
+	var names []string
+	for _, p := range t.Participants {
+		if p.Summoner == summoner {
+			names = append(names, p.Name)
+		}
+	}
+	return names
+}
+
+// Dismiss removes everything summoned by a participant (e.g. they dropped concentration)
+// Returns the names of the removed summons
+func (t *Tracker) Dismiss(summoner string) []string {
+	removed := t.SummonsOf(summoner)
+	if len(removed) == 0 {
+		return nil
+	}
+
+	current := t.GetCurrent()
+	kept := t.Participants[:0]
+	for _, p := range t.Participants {
+		if p.Summoner != summoner {
+			kept = append(kept, p)
+		}
+	}
+	t.Participants = kept
+	t.restoreCurrent(current)
+	return removed
+}
+
+// restoreCurrent points CurrentTurn back at a participant after the list changed
+func (t *Tracker) restoreCurrent(current *Participant) {
+	for i, p := range t.Participants {
+		if p == current {
+			t.CurrentTurn = i
+			return
+		}
+	}
+	if t.CurrentTurn >= len(t.Participants) {
+		t.CurrentTurn = 0
+	}
+}
+
 // MarkOut marks a participant as out (dead/incapacitated)
+// Anything they summoned is dismissed
 func (t *Tracker) MarkOut(name string) error {
 	for _, p := range t.Participants {
 		if p.Name == name {
 			p.IsActive = false
+			t.Dismiss(name)
 			return nil
 		}
 	}
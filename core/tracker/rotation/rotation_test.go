@@ -191,3 +191,346 @@ func TestActiveCount(t *testing.T) {
 	}
 }
 
+
+func TestSideModeOrdering(t *testing.T) {
+	tracker := NewTrackerWithMode(ModeSide)
+	tracker.AddToSide("Goblin", 12, "monsters")
+	tracker.AddToSide("Bram", 8, "party")
+	tracker.AddToSide("Aria", 15, "party")
+	tracker.AddToSide("Orc", 5, "monsters")
+
+	// Party acts first on Aria's 15, then monsters on the Goblin's 12
+	expected := []string{"Aria", "Bram", "Goblin", "Orc"}
+	for i, name := range expected {
+		if tracker.Participants[i].Name != name {
+			t.Errorf("Position %d: expected %s, got %s", i, name, tracker.Participants[i].Name)
+		}
+	}
+
+	if tracker.CurrentSide() != "party" {
+		t.Errorf("Expected party to go first, got %s", tracker.CurrentSide())
+	}
+
+	tracker.Next()
+	if tracker.CurrentSide() != "monsters" {
+		t.Errorf("Expected monsters after party, got %s", tracker.CurrentSide())
+	}
+	if tracker.Round != 1 {
+		t.Errorf("Expected round 1, got %d", tracker.Round)
+	}
+
+	tracker.Next()
+	if tracker.CurrentSide() != "party" {
+		t.Errorf("Expected party again, got %s", tracker.CurrentSide())
+	}
+	if tracker.Round != 2 {
+		t.Errorf("Expected round 2 after both sides acted, got %d", tracker.Round)
+	}
+}
+
+func TestSideModeOneSideLeft(t *testing.T) {
+	tracker := NewTrackerWithMode(ModeSide)
+	tracker.AddToSide("Aria", 15, "party")
+	tracker.AddToSide("Goblin", 12, "monsters")
+	tracker.MarkOut("Goblin")
+
+	tracker.Next()
+	if tracker.GetCurrent().Name != "Aria" {
+		t.Errorf("Expected Aria to go again, got %s", tracker.GetCurrent().Name)
+	}
+	if tracker.Round != 2 {
+		t.Errorf("Expected round 2, got %d", tracker.Round)
+	}
+}
+
+func TestPopcornNextTo(t *testing.T) {
+	tracker := NewTrackerWithMode(ModePopcorn)
+	tracker.Add("Fighter", 18)
+	tracker.Add("Wizard", 15)
+	tracker.Add("Goblin", 12)
+
+	// Fighter hands off to the Goblin
+	if err := tracker.NextTo("Goblin"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if tracker.GetCurrent().Name != "Goblin" {
+		t.Errorf("Expected Goblin, got %s", tracker.GetCurrent().Name)
+	}
+
+	// Fighter already acted this round
+	if err := tracker.NextTo("Fighter"); err == nil {
+		t.Error("Expected error when picking someone who already acted")
+	}
+
+	// Plain Next picks the only one left
+	tracker.Next()
+	if tracker.GetCurrent().Name != "Wizard" {
+		t.Errorf("Expected Wizard, got %s", tracker.GetCurrent().Name)
+	}
+	if tracker.Round != 1 {
+		t.Errorf("Expected round 1, got %d", tracker.Round)
+	}
+
+	// Last actor may pick anyone, including themselves, to start round 2
+	if err := tracker.NextTo("Wizard"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if tracker.Round != 2 {
+		t.Errorf("Expected round 2, got %d", tracker.Round)
+	}
+	if tracker.GetCurrent().Name != "Wizard" {
+		t.Errorf("Expected Wizard, got %s", tracker.GetCurrent().Name)
+	}
+}
+
+func TestNextToRequiresPopcorn(t *testing.T) {
+	tracker := NewTracker()
+	tracker.Add("Fighter", 18)
+	tracker.Add("Goblin", 12)
+
+	if err := tracker.NextTo("Goblin"); err == nil {
+		t.Error("Expected error when using NextTo outside popcorn mode")
+	}
+}
+
+func TestUpcoming(t *testing.T) {
+	tracker := NewTracker()
+	tracker.Add("Fighter", 18)
+	tracker.Add("Wizard", 15)
+	tracker.Add("Goblin", 12)
+	tracker.Add("Ogre", 8)
+	tracker.MarkOut("Goblin")
+	tracker.Next() // Wizard
+
+	upcoming := tracker.Upcoming(3)
+	expected := []string{"Ogre", "Fighter"}
+	if len(upcoming) != len(expected) {
+		t.Fatalf("Expected %d upcoming, got %d", len(expected), len(upcoming))
+	}
+	for i, name := range expected {
+		if upcoming[i].Name != name {
+			t.Errorf("Position %d: expected %s, got %s", i, name, upcoming[i].Name)
+		}
+	}
+
+	if len(tracker.Upcoming(1)) != 1 {
+		t.Error("Expected Upcoming to respect the limit")
+	}
+}
+
+func TestCyclicRerollsEachRound(t *testing.T) {
+	tracker := NewTrackerWithMode(ModeCyclic)
+	for _, name := range []string{"Fighter", "Wizard", "Goblin"} {
+		initiative, err := tracker.AddWithBonus(name, 3)
+		if err != nil {
+			t.Fatalf("AddWithBonus failed: %v", err)
+		}
+		if initiative < 4 || initiative > 23 {
+			t.Errorf("Initiative %d out of range for d20+3", initiative)
+		}
+	}
+	tracker.MarkOut("Goblin")
+
+	// Finish round 1
+	for tracker.Round == 1 {
+		tracker.Next()
+	}
+
+	if tracker.Round != 2 {
+		t.Fatalf("Expected round 2, got %d", tracker.Round)
+	}
+	for i := 1; i < len(tracker.Participants); i++ {
+		if tracker.Participants[i-1].Initiative < tracker.Participants[i].Initiative {
+			t.Error("Expected participants re-sorted by initiative after re-roll")
+		}
+	}
+	if !tracker.GetCurrent().IsActive {
+		t.Error("Expected the first active participant to start the new round")
+	}
+	for _, p := range tracker.Participants {
+		if p.Name == "Goblin" && p.IsActive {
+			t.Error("Expected re-roll to preserve out-of-combat status")
+		}
+	}
+}
+
+func TestSummon(t *testing.T) {
+	tracker := NewTracker()
+	tracker.Add("Fighter", 18)
+	tracker.Add("Wizard", 15)
+	tracker.Add("Goblin", 12)
+	tracker.Next() // Wizard's turn
+
+	if err := tracker.Summon("Wizard", "Spirit Guardians"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Summon is listed directly after its summoner and the turn stays with the Wizard
+	expected := []string{"Fighter", "Wizard", "Spirit Guardians", "Goblin"}
+	for i, name := range expected {
+		if tracker.Participants[i].Name != name {
+			t.Errorf("Position %d: expected %s, got %s", i, name, tracker.Participants[i].Name)
+		}
+	}
+	if tracker.GetCurrent().Name != "Wizard" {
+		t.Errorf("Expected Wizard's turn, got %s", tracker.GetCurrent().Name)
+	}
+
+	// Summons don't take their own turns
+	tracker.Next()
+	if tracker.GetCurrent().Name != "Goblin" {
+		t.Errorf("Expected Goblin after Wizard, got %s", tracker.GetCurrent().Name)
+	}
+
+	if err := tracker.Summon("Dragon", "Kobold"); err == nil {
+		t.Error("Expected error summoning for a missing participant")
+	}
+}
+
+func TestDismissOnMarkOut(t *testing.T) {
+	tracker := NewTracker()
+	tracker.Add("Fighter", 18)
+	tracker.Add("Wizard", 15)
+	tracker.Add("Goblin", 12)
+	tracker.Summon("Wizard", "Spirit Guardians")
+	tracker.Next()
+	tracker.Next() // Goblin's turn
+
+	tracker.MarkOut("Wizard")
+
+	if len(tracker.Participants) != 3 {
+		t.Fatalf("Expected summon to be removed, got %d participants", len(tracker.Participants))
+	}
+	if tracker.GetCurrent().Name != "Goblin" {
+		t.Errorf("Expected Goblin's turn to be preserved, got %s", tracker.GetCurrent().Name)
+	}
+}
+
+func TestLink(t *testing.T) {
+	tracker := NewTracker()
+	tracker.Add("Goblin", 12)
+
+	if err := tracker.SetHP("goblin", 7); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := tracker.Link("Goblin", "Goblin HP"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	p := tracker.Participants[0]
+	if p.HP != 7 || p.Tracker != "Goblin HP" {
+		t.Errorf("Expected HP 7 linked to 'Goblin HP', got %d linked to '%s'", p.HP, p.Tracker)
+	}
+	if err := tracker.Link("Ogre", "Ogre"); err == nil {
+		t.Error("Expected error linking a missing participant")
+	}
+	if err := tracker.SetHP("Goblin", -1); err == nil {
+		t.Error("Expected error for negative HP")
+	}
+}
+
+func TestSetScript(t *testing.T) {
+	tracker := NewTracker()
+	tracker.Add("Troll", 12)
+
+	if err := tracker.SetScript("troll", " t adj Troll +10; r d6 "); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := tracker.Participants[0].Script; got != "t adj Troll +10; r d6" {
+		t.Errorf("Expected the script trimmed, got '%s'", got)
+	}
+	if err := tracker.SetScript("Ogre", "r d6"); err == nil {
+		t.Error("Expected error scripting a missing participant")
+	}
+}
+
+func TestActionEconomy(t *testing.T) {
+	tracker := NewTracker()
+	tracker.Add("Fighter", 18)
+	tracker.Add("Goblin", 12)
+
+	// Goblin spends their reaction on the Fighter's turn
+	if err := tracker.Use("Goblin", ActionReaction); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := tracker.Use("Goblin", ActionReaction); err == nil {
+		t.Error("Expected error using a reaction twice")
+	}
+	tracker.Use("Fighter", ActionMain)
+
+	// Goblin's reaction comes back at the start of their turn; the Fighter's action does not
+	tracker.Next()
+	goblin, fighter := tracker.Participants[1], tracker.Participants[0]
+	if goblin.Used != 0 {
+		t.Errorf("Expected Goblin's actions to reset on their turn, got %v", goblin.Used)
+	}
+	if !fighter.HasUsed(ActionMain) {
+		t.Error("Expected Fighter's action to stay spent until their turn")
+	}
+
+	tracker.Next()
+	if fighter.Used != 0 {
+		t.Errorf("Expected Fighter's actions to reset on their turn, got %v", fighter.Used)
+	}
+}
+
+func TestParseAction(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Action
+	}{
+		{"action", ActionMain},
+		{"bonus", ActionBonus},
+		{"BA", ActionBonus},
+		{"reaction", ActionReaction},
+		{"r", ActionReaction},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseAction(tt.input)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseAction(%q) = %v, %v; want %v", tt.input, got, err, tt.want)
+		}
+	}
+	if _, err := ParseAction("legendary"); err == nil {
+		t.Error("Expected error for unknown action")
+	}
+}
+
+func TestManagerResume(t *testing.T) {
+	manager := NewManager()
+	if _, err := manager.Resume(1); err == nil {
+		t.Error("Expected error resuming with nothing ended")
+	}
+
+	manager.Start()
+	manager.Add("Fighter", 18)
+	manager.Add("Goblin", 12)
+	manager.Next()
+	manager.End()
+
+	if manager.IsActive() {
+		t.Fatal("Expected initiative to be inactive after End")
+	}
+
+	// Start a flashback scene, then swap back to the fight
+	manager.Start()
+	manager.Add("Young Aria", 10)
+
+	tracker, err := manager.Resume(1)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !manager.IsActive() || tracker.GetCurrent().Name != "Goblin" {
+		t.Errorf("Expected the fight to resume on Goblin's turn")
+	}
+
+	// The flashback was shelved in its place
+	recent := manager.Recent()
+	if len(recent) != 1 || recent[0].Participants[0].Name != "Young Aria" {
+		t.Errorf("Expected the flashback to be shelved, got %d recent", len(recent))
+	}
+	if _, err := manager.Resume(2); err == nil {
+		t.Error("Expected error resuming an out-of-range entry")
+	}
+}
@@ -0,0 +1,144 @@
+package timer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// durationUnits maps spoken unit names to durations
+var durationUnits = map[string]time.Duration{
+	"s": time.Second, "sec": time.Second, "secs": time.Second, "second": time.Second, "seconds": time.Second,
+	"m": time.Minute, "min": time.Minute, "mins": time.Minute, "minute": time.Minute, "minutes": time.Minute,
+	"h": time.Hour, "hr": time.Hour, "hrs": time.Hour, "hour": time.Hour, "hours": time.Hour,
+	"day": 24 * time.Hour, "days": 24 * time.Hour,
+}
+
+// numberWords maps spoken quantities to numbers
+var numberWords = map[string]float64{
+	"a": 1, "an": 1, "one": 1, "two": 2, "three": 3, "four": 4, "five": 5, "six": 6,
+	"seven": 7, "eight": 8, "nine": 9, "ten": 10, "eleven": 11, "twelve": 12,
+	"fifteen": 15, "twenty": 20, "thirty": 30, "forty": 40, "forty-five": 45, "sixty": 60, "ninety": 90,
+	"half": 0.5, "quarter": 0.25,
+}
+
+// ParseDuration parses a duration from the start of words, accepting Go syntax
+// ("1h30m") or phrases like "1 hour", "90 minutes", "half an hour", and
+// "an hour and a half". It returns the duration and how many words it used,
+// so the caller can treat the rest as a label
+func ParseDuration(words []string) (time.Duration, int, error) {
+	if len(words) == 0 {
+		return 0, 0, fmt.Errorf("missing duration")
+	}
+
+	// Plain Go syntax
+	if d, err := time.ParseDuration(words[0]); err == nil {
+		return d, 1, nil
+	}
+
+	var total time.Duration
+	used := 0
+	lastUnit := time.Duration(0)
+	for used < len(words) {
+		i := used
+		if i > 0 && strings.ToLower(words[i]) == "and" {
+			i++
+		}
+
+		// "... and a half" adds half of the previous unit
+		if lastUnit > 0 && i > used && i+1 < len(words) &&
+			isArticle(words[i]) && strings.ToLower(words[i+1]) == "half" {
+			total += lastUnit / 2
+			used = i + 2
+			lastUnit = 0
+			continue
+		}
+
+		d, unit, n := parseDurationTerm(words[i:])
+		if n == 0 {
+			break
+		}
+		total += d
+		lastUnit = unit
+		used = i + n
+	}
+
+	if used == 0 {
+		return 0, 0, fmt.Errorf("invalid duration '%s' (use format like: 1h, 5m, 30s, 1h30m, '90 minutes', 'half an hour')", words[0])
+	}
+	return total, used, nil
+}
+
+// parseDurationTerm parses one "<quantity> <unit>" term, e.g. "90 minutes",
+// "90min", "half an hour", or "an hour"
+// Returns the duration, the unit, and the number of words used (0 if none matched)
+func parseDurationTerm(words []string) (time.Duration, time.Duration, int) {
+	if len(words) == 0 {
+		return 0, 0, 0
+	}
+	first := strings.ToLower(words[0])
+
+	// Number glued to its unit, e.g. "90min" or "1.5hours"
+	if split := strings.IndexFunc(first, unicode.IsLetter); split > 0 {
+		if quantity, err := strconv.ParseFloat(first[:split], 64); err == nil {
+			if unit, ok := durationUnits[first[split:]]; ok {
+				return scale(unit, quantity), unit, 1
+			}
+		}
+		return 0, 0, 0
+	}
+
+	quantity, ok := parseQuantity(first)
+	if !ok {
+		return 0, 0, 0
+	}
+	n := 1
+
+	// "half an hour", "a quarter of an hour"
+	if n < len(words) && strings.ToLower(words[n]) == "of" {
+		n++
+	}
+	if n < len(words) && quantity < 1 && isArticle(words[n]) {
+		n++
+	} else if n < len(words) && isArticle(words[0]) {
+		// "a half hour"
+		if q, ok := numberWords[strings.ToLower(words[n])]; ok && q < 1 {
+			quantity = q
+			n++
+		}
+	}
+
+	if n >= len(words) {
+		return 0, 0, 0
+	}
+	unit, ok := durationUnits[strings.ToLower(words[n])]
+	if !ok {
+		return 0, 0, 0
+	}
+	return scale(unit, quantity), unit, n + 1
+}
+
+// parseQuantity parses a number given as digits ("90", "1.5") or a word ("an", "half")
+func parseQuantity(word string) (float64, bool) {
+	if q, ok := numberWords[word]; ok {
+		return q, true
+	}
+	q, err := strconv.ParseFloat(word, 64)
+	if err != nil || q <= 0 {
+		return 0, false
+	}
+	return q, true
+}
+
+// isArticle returns true for "a" or "an"
+func isArticle(word string) bool {
+	word = strings.ToLower(word)
+	return word == "a" || word == "an"
+}
+
+// scale multiplies a unit by a (possibly fractional) quantity
+func scale(unit time.Duration, quantity float64) time.Duration {
+	return time.Duration(float64(unit) * quantity).Round(time.Second)
+}
@@ -0,0 +1,49 @@
+package timer
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected time.Duration
+		used     int
+	}{
+		{"5m", 5 * time.Minute, 1},
+		{"1h30m concentration", 90 * time.Minute, 1},
+		{"1 hour", time.Hour, 2},
+		{"90 minutes", 90 * time.Minute, 2},
+		{"90min torch", 90 * time.Minute, 1},
+		{"half an hour torch", 30 * time.Minute, 3},
+		{"an hour and a half", 90 * time.Minute, 5},
+		{"1 hour 30 minutes", 90 * time.Minute, 4},
+		{"2 hours and 15 minutes rest", 135 * time.Minute, 5},
+		{"ten minutes", 10 * time.Minute, 2},
+		{"a minute a torch", time.Minute, 2},
+		{"1.5 hours", 90 * time.Minute, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			d, used, err := ParseDuration(strings.Fields(tt.input))
+			if err != nil {
+				t.Fatalf("ParseDuration(%q) unexpected error: %v", tt.input, err)
+			}
+			if d != tt.expected || used != tt.used {
+				t.Errorf("ParseDuration(%q) = %v using %d words, expected %v using %d",
+					tt.input, d, used, tt.expected, tt.used)
+			}
+		})
+	}
+}
+
+func TestParseDurationErrors(t *testing.T) {
+	for _, input := range []string{"", "soon", "an torch", "5 parsecs"} {
+		if _, _, err := ParseDuration(strings.Fields(input)); err == nil {
+			t.Errorf("ParseDuration(%q) expected error", input)
+		}
+	}
+}
@@ -1,7 +1,9 @@
 package timer
 
 import (
+	"fmt"
 	"sort"
+	"strings"
 	"sync"
 )
 
@@ -44,15 +46,55 @@ func (m *Manager) GetActive() []*Timer {
 		}
 	}
 
-	// Sort by remaining time (shortest first)
+	// Sort the pinned timer first, then by remaining time (shortest first)
 	sort.Slice(active, func(i, j int) bool {
+		if active[i].Pinned != active[j].Pinned {
+			return active[i].Pinned
+		}
 		return active[i].Remaining() < active[j].Remaining()
 	})
 
 	return active
 }
 
+// Pin pins the active timer with the given label (case-insensitive) to the first slot
+// Only one timer can be pinned; pinning another unpins the previous one
+func (m *Manager) Pin(label string) (*Timer, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var found *Timer
+	for _, timer := range m.timers {
+		if !timer.IsExpired() && strings.EqualFold(timer.Label, label) {
+			found = timer
+			break
+		}
+	}
+	if found == nil {
+		return nil, fmt.Errorf("alarm '%s' not found", label)
+	}
+	for _, timer := range m.timers {
+		timer.Pinned = timer == found
+	}
+	return found, nil
+}
+
+// Unpin unpins the pinned timer, returning it (nil if none was pinned)
+func (m *Manager) Unpin() *Timer {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, timer := range m.timers {
+		if timer.Pinned {
+			timer.Pinned = false
+			return timer
+		}
+	}
+	return nil
+}
+
 // GetExpired returns all expired timers and removes them from the manager
+// Repeating timers are returned too, but restarted rather than removed
 func (m *Manager) GetExpired() []*Timer {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -61,13 +103,47 @@ func (m *Manager) GetExpired() []*Timer {
 	for id, timer := range m.timers {
 		if timer.IsExpired() {
 			expired = append(expired, timer)
-			delete(m.timers, id)
+			if timer.Repeat {
+				timer.Restart()
+			} else {
+				delete(m.timers, id)
+			}
 		}
 	}
 
 	return expired
 }
 
+// AdvanceRound counts down round-based timers, removing and returning any that
+// run out (repeating ones are restarted instead)
+func (m *Manager) AdvanceRound() []*Timer {
+	return m.AdvanceRounds(1)
+}
+
+// AdvanceRounds counts down round-based timers by several rounds at once
+// (e.g. the 100 rounds of a dungeon turn); a repeating timer goes off once
+func (m *Manager) AdvanceRounds(rounds int) []*Timer {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var expired []*Timer
+	for id, timer := range m.timers {
+		if !timer.IsRoundBased() {
+			continue
+		}
+		timer.Rounds -= rounds
+		if timer.IsExpired() {
+			expired = append(expired, timer)
+			if timer.Repeat {
+				timer.Restart()
+			} else {
+				delete(m.timers, id)
+			}
+		}
+	}
+	return expired
+}
+
 // Count returns the total number of timers (including expired)
 func (m *Manager) Count() int {
 	m.mu.RLock()
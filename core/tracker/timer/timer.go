@@ -5,12 +5,21 @@ import (
 	"time"
 )
 
+// RoundDuration is how long one combat round lasts in game time
+const RoundDuration = 6 * time.Second
+
 // Timer represents a countdown timer
+// Round-based timers count initiative rounds instead of wall-clock time
 type Timer struct {
-	ID        string
-	StartTime time.Time
-	Duration  time.Duration
-	Label     string // optional label for the timer
+	ID          string
+	StartTime   time.Time
+	Duration    time.Duration
+	Label       string // optional label for the timer
+	Pinned      bool   // pinned timers always take the first slot of the timer bar
+	Rounds      int    // rounds remaining on a round-based timer
+	TotalRounds int    // rounds a round-based timer started with (0 for timed ones)
+	Then        string // command(s) to run when the timer finishes ("" if none)
+	Repeat      bool   // starts over each time it finishes (e.g. exploration checks)
 }
 
 // NewTimer creates a new timer with the specified duration
@@ -23,8 +32,54 @@ func NewTimer(duration time.Duration, label string) *Timer {
 	}
 }
 
+// NewRoundTimer creates a timer that runs for a number of initiative rounds
+func NewRoundTimer(rounds int, label string) *Timer {
+	return &Timer{
+		ID:          generateID(),
+		StartTime:   time.Now(),
+		Duration:    time.Duration(rounds) * RoundDuration,
+		Label:       label,
+		Rounds:      rounds,
+		TotalRounds: rounds,
+	}
+}
+
+// Restart starts a repeating timer's next run where the last one finished,
+// or from now if it has fallen more than a whole run behind (e.g. after the
+// laptop slept)
+func (t *Timer) Restart() {
+	if t.IsRoundBased() {
+		t.Rounds = t.TotalRounds
+		return
+	}
+	t.StartTime = t.StartTime.Add(t.Duration)
+	if time.Since(t.StartTime) >= t.Duration {
+		t.StartTime = time.Now()
+	}
+}
+
+// IsRoundBased returns true if the timer counts rounds rather than time
+func (t *Timer) IsRoundBased() bool {
+	return t.TotalRounds > 0
+}
+
+// Length describes how long the timer runs, e.g. "5m0s" or "10 rounds"
+func (t *Timer) Length() string {
+	switch {
+	case t.TotalRounds == 1:
+		return "1 round"
+	case t.IsRoundBased():
+		return fmt.Sprintf("%d rounds", t.TotalRounds)
+	}
+	return FormatDuration(t.Duration)
+}
+
 // Remaining returns the time remaining on the timer
+// (for round-based timers, the game time left in the remaining rounds)
 func (t *Timer) Remaining() time.Duration {
+	if t.IsRoundBased() {
+		return time.Duration(t.Rounds) * RoundDuration
+	}
 	elapsed := time.Since(t.StartTime)
 	remaining := t.Duration - elapsed
 	if remaining < 0 {
@@ -35,6 +90,9 @@ func (t *Timer) Remaining() time.Duration {
 
 // Elapsed returns the time elapsed since the timer started
 func (t *Timer) Elapsed() time.Duration {
+	if t.IsRoundBased() {
+		return t.Duration - t.Remaining()
+	}
 	elapsed := time.Since(t.StartTime)
 	if elapsed > t.Duration {
 		return t.Duration
@@ -47,6 +105,9 @@ func (t *Timer) PercentComplete() float64 {
 	if t.Duration == 0 {
 		return 100.0
 	}
+	if t.IsRoundBased() {
+		return float64(t.TotalRounds-t.Rounds) / float64(t.TotalRounds) * 100.0
+	}
 	elapsed := time.Since(t.StartTime)
 	percent := (float64(elapsed) / float64(t.Duration)) * 100.0
 	if percent > 100.0 {
@@ -57,6 +118,9 @@ func (t *Timer) PercentComplete() float64 {
 
 // IsExpired returns true if the timer has expired
 func (t *Timer) IsExpired() bool {
+	if t.IsRoundBased() {
+		return t.Rounds <= 0
+	}
 	return time.Since(t.StartTime) >= t.Duration
 }
 
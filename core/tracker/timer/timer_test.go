@@ -123,3 +123,100 @@ func TestFormatDurationShort(t *testing.T) {
 	}
 }
 
+
+func TestManagerPin(t *testing.T) {
+	manager := NewManager()
+	short := NewTimer(1*time.Minute, "bless")
+	short.ID = "1"
+	long := NewTimer(10*time.Minute, "concentration")
+	long.ID = "2"
+	manager.Add(short)
+	manager.Add(long)
+
+	if active := manager.GetActive(); active[0] != short {
+		t.Errorf("Expected shortest timer first, got %s", active[0].Label)
+	}
+
+	if _, err := manager.Pin("Concentration"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if active := manager.GetActive(); active[0] != long {
+		t.Errorf("Expected pinned timer first, got %s", active[0].Label)
+	}
+
+	// Pinning another timer moves the pin
+	manager.Pin("bless")
+	if long.Pinned || !short.Pinned {
+		t.Error("Expected only the most recently pinned timer to be pinned")
+	}
+
+	if unpinned := manager.Unpin(); unpinned != short {
+		t.Errorf("Expected Unpin to return the pinned timer")
+	}
+	if _, err := manager.Pin("missing"); err == nil {
+		t.Error("Expected error pinning a missing timer")
+	}
+}
+
+func TestRoundTimer(t *testing.T) {
+	manager := NewManager()
+	haste := NewRoundTimer(2, "haste")
+	haste.ID = "1"
+	timed := NewTimer(1*time.Minute, "torch")
+	timed.ID = "2"
+	manager.Add(haste)
+	manager.Add(timed)
+
+	if haste.Length() != "2 rounds" || haste.Remaining() != 12*time.Second {
+		t.Errorf("Expected 2 rounds (12s) left, got %s (%v)", haste.Length(), haste.Remaining())
+	}
+
+	if expired := manager.AdvanceRound(); len(expired) != 0 {
+		t.Errorf("Expected nothing to expire after 1 round, got %d", len(expired))
+	}
+	if haste.PercentComplete() != 50 {
+		t.Errorf("Expected 50%% complete, got %.0f", haste.PercentComplete())
+	}
+
+	expired := manager.AdvanceRound()
+	if len(expired) != 1 || expired[0] != haste {
+		t.Fatalf("Expected haste to expire after 2 rounds, got %d expired", len(expired))
+	}
+	if manager.Count() != 1 {
+		t.Errorf("Expected only the timed timer left, got %d", manager.Count())
+	}
+}
+
+func TestRepeatingTimer(t *testing.T) {
+	manager := NewManager()
+	check := NewTimer(10*time.Minute, "explore")
+	check.Repeat = true
+	check.StartTime = time.Now().Add(-11 * time.Minute)
+	manager.Add(check)
+
+	expired := manager.GetExpired()
+	if len(expired) != 1 || expired[0] != check {
+		t.Fatalf("Expected the repeating timer to go off, got %d expired", len(expired))
+	}
+	if manager.Count() != 1 {
+		t.Errorf("Expected the repeating timer to stay, got %d timers", manager.Count())
+	}
+	if remaining := check.Remaining(); remaining > 9*time.Minute || remaining < 8*time.Minute {
+		t.Errorf("Expected the next run to start where the last one finished (~9m left), got %v", remaining)
+	}
+
+	// Far behind, it starts over from now rather than going off again at once
+	check.StartTime = time.Now().Add(-time.Hour)
+	manager.GetExpired()
+	if check.IsExpired() || check.Remaining() < 9*time.Minute {
+		t.Errorf("Expected a fresh 10m run, got %v left", check.Remaining())
+	}
+
+	rounds := NewRoundTimer(2, "dungeon turn")
+	rounds.Repeat = true
+	manager.Add(rounds)
+	manager.AdvanceRound()
+	if expired := manager.AdvanceRound(); len(expired) != 1 || rounds.Rounds != 2 {
+		t.Errorf("Expected the round timer to go off and restart at 2 rounds, got %d expired, %d rounds", len(expired), rounds.Rounds)
+	}
+}
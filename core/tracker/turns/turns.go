@@ -0,0 +1,93 @@
+package turns
+
+import (
+	"strings"
+	"time"
+
+	"github.com/angusmclean/tavernshell/core/tracker/timer"
+)
+
+// TurnLength is how long one dungeon turn lasts in game time
+const TurnLength = 10 * time.Minute
+
+// RoundsPerTurn is how many combat rounds pass in a dungeon turn
+const RoundsPerTurn = int(TurnLength / timer.RoundDuration)
+
+// DefaultCheckEvery is how many turns pass between wandering-monster checks
+// unless told otherwise (every other turn, as in B/X)
+const DefaultCheckEvery = 2
+
+// lightDurations are how many turns common light sources burn for
+var lightDurations = []struct {
+	kind  string
+	turns int
+}{
+	{"torch", 6},    // an hour
+	{"lantern", 24}, // four hours on a flask of oil
+	{"candle", 6},
+}
+
+// LightDuration returns how many turns a light source burns for, going by
+// its name (e.g. "Aria Torch" is a torch), or 0 if it isn't a kind we know
+func LightDuration(name string) int {
+	lower := strings.ToLower(name)
+	for _, light := range lightDurations {
+		if strings.Contains(lower, light.kind) {
+			return light.turns
+		}
+	}
+	return 0
+}
+
+// Delve counts dungeon turns: the light sources burning down a turn at a
+// time, and when a wandering-monster check is due
+type Delve struct {
+	Turn       int      // turns passed since the delve started
+	CheckEvery int      // turns between wandering-monster checks (0 for none)
+	Table      string   // table to roll wandering monsters on ("" for a 1-in-6 check)
+	Lights     []string // trackers for the light sources burning
+}
+
+// NewDelve starts a delve at turn 0
+func NewDelve(checkEvery int, table string) *Delve {
+	return &Delve{CheckEvery: checkEvery, Table: table}
+}
+
+// Advance moves on a turn, returning true if a wandering-monster check is due
+func (d *Delve) Advance() bool {
+	d.Turn++
+	return d.CheckEvery > 0 && d.Turn%d.CheckEvery == 0
+}
+
+// Elapsed is the game time the delve has taken so far
+func (d *Delve) Elapsed() time.Duration {
+	return time.Duration(d.Turn) * TurnLength
+}
+
+// AddLight starts a light source's tracker burning down with the turns
+func (d *Delve) AddLight(name string) {
+	if !d.HasLight(name) {
+		d.Lights = append(d.Lights, name)
+	}
+}
+
+// RemoveLight stops a light source burning down, returning false if it wasn't
+func (d *Delve) RemoveLight(name string) bool {
+	for i, light := range d.Lights {
+		if strings.EqualFold(light, name) {
+			d.Lights = append(d.Lights[:i], d.Lights[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// HasLight returns true if the named tracker is a light source burning down
+func (d *Delve) HasLight(name string) bool {
+	for _, light := range d.Lights {
+		if strings.EqualFold(light, name) {
+			return true
+		}
+	}
+	return false
+}
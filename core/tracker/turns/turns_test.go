@@ -0,0 +1,69 @@
+package turns
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAdvance(t *testing.T) {
+	d := NewDelve(DefaultCheckEvery, "")
+	var due []int
+	for i := 0; i < 6; i++ {
+		if d.Advance() {
+			due = append(due, d.Turn)
+		}
+	}
+	if len(due) != 3 || due[0] != 2 || due[1] != 4 || due[2] != 6 {
+		t.Errorf("Expected checks on turns 2, 4 and 6, got %v", due)
+	}
+	if d.Elapsed() != time.Hour {
+		t.Errorf("Expected an hour after 6 turns, got %v", d.Elapsed())
+	}
+
+	never := NewDelve(0, "")
+	for i := 0; i < 4; i++ {
+		if never.Advance() {
+			t.Errorf("Expected no checks with CheckEvery 0, got one on turn %d", never.Turn)
+		}
+	}
+}
+
+func TestRoundsPerTurn(t *testing.T) {
+	if RoundsPerTurn != 100 {
+		t.Errorf("Expected 100 rounds in a turn, got %d", RoundsPerTurn)
+	}
+}
+
+func TestLightDuration(t *testing.T) {
+	tests := []struct {
+		name  string
+		turns int
+	}{
+		{"Torch", 6},
+		{"Aria torch", 6},
+		{"Lantern", 24},
+		{"Candle", 6},
+		{"Light spell", 0},
+	}
+	for _, tt := range tests {
+		if got := LightDuration(tt.name); got != tt.turns {
+			t.Errorf("LightDuration(%q): expected %d, got %d", tt.name, tt.turns, got)
+		}
+	}
+}
+
+func TestLights(t *testing.T) {
+	d := NewDelve(2, "")
+	d.AddLight("Torch")
+	d.AddLight("torch")
+	d.AddLight("Lantern")
+	if len(d.Lights) != 2 || !d.HasLight("TORCH") {
+		t.Errorf("Expected Torch and Lantern, got %v", d.Lights)
+	}
+	if !d.RemoveLight("torch") || d.HasLight("Torch") {
+		t.Errorf("Expected Torch removed, got %v", d.Lights)
+	}
+	if d.RemoveLight("Torch") {
+		t.Error("Expected removing a missing light to return false")
+	}
+}
@@ -0,0 +1,98 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/angusmclean/tavernshell/core/tracker/number"
+)
+
+// changeTrackers sets or adjusts the trackers a name refers to (see
+// trackerTargets) by an amount, which may be a percentage of each tracker's
+// max: "t adjust party +50%" has everyone regain half their hit points
+func (m *Model) changeTrackers(name, amount string, adjust bool) {
+	value, err := strconv.Atoi(strings.TrimSuffix(amount, "%"))
+	if err != nil {
+		if adjust {
+			m.addHistory("Error: delta must be a number (e.g., +5, -10 or +25%)")
+		} else {
+			m.addHistory("Error: value must be a number (e.g., 30 or 50%)")
+		}
+		return
+	}
+	percent := strings.HasSuffix(amount, "%")
+
+	trackers, err := m.trackerTargets(name)
+	if err != nil {
+		m.addHistory(fmt.Sprintf("Error: %s", err))
+		return
+	}
+	if len(trackers) == 0 {
+		m.addHistory(fmt.Sprintf("Tracker '%s' not found", name))
+		return
+	}
+
+	many := len(trackers) > 1
+	for _, tracker := range trackers {
+		if err := checkWritable(tracker); err != nil {
+			if !many {
+				m.addHistory(fmt.Sprintf("Error: %s", err))
+			}
+			continue // derived trackers follow their sources
+		}
+		change := value
+		if percent {
+			change = tracker.PercentOfMax(value)
+		}
+		before := tracker.Current
+		if adjust {
+			tracker.Adjust(change)
+		} else {
+			tracker.Set(change)
+		}
+		line := fmt.Sprintf("[%s] %d/%d", tracker.Name, tracker.Current, tracker.Max)
+		if percent || many {
+			line += fmt.Sprintf(" (%+d)", tracker.Current-before)
+		}
+		m.addTrackerHistory(tracker, line)
+		if adjust {
+			m.checkDamage(tracker, before)
+		}
+	}
+}
+
+// trackerTargets finds the trackers a name refers to: the tracker with that
+// name, every tracker matching a pattern like "Goblin*", or with "party" (or
+// "party*"), the tracker of everyone in the imported party
+// A plain name that isn't a tracker gives none
+func (m *Model) trackerTargets(name string) ([]*number.Tracker, error) {
+	lower := strings.ToLower(name)
+	if (lower == "party" || lower == "party*") && len(m.roster.List()) > 0 {
+		var trackers []*number.Tracker
+		for _, c := range m.roster.List() {
+			if t, err := m.routeTracker(c.Name); err == nil {
+				trackers = append(trackers, t)
+			}
+		}
+		if len(trackers) == 0 {
+			return nil, fmt.Errorf("no one in the party has a tracker (use 'party init' and 'sync', or 't add')")
+		}
+		return trackers, nil
+	}
+
+	if !strings.ContainsAny(name, "*?[") {
+		if t := m.numberTrackerManager.Get(name); t != nil {
+			return []*number.Tracker{t}, nil
+		}
+		return nil, nil
+	}
+	trackers, err := m.numberTrackerManager.Match(name)
+	if err != nil {
+		return nil, err
+	}
+	if len(trackers) == 0 {
+		return nil, fmt.Errorf("no trackers match '%s'", name)
+	}
+	return trackers, nil
+}
@@ -0,0 +1,28 @@
+package tui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestAlarmFollowUpCommandReturned(t *testing.T) {
+	tests := []struct {
+		setup   []string
+		advance string
+	}{
+		{[]string{"i s", "Aria 15", "Goblin 10", "done", `a 1r Haste --then "quit"`, "i n"}, "i n"},
+		{[]string{"turns start", `a 1r Haste --then "quit"`}, "turns next"},
+	}
+	for _, tt := range tests {
+		m := runCommands(tt.setup...)
+		cmd := m.handleBatch(tt.advance)
+		if cmd == nil {
+			t.Errorf("%s: expected the alarm's quit to be returned, got no command", tt.advance)
+			continue
+		}
+		if _, ok := cmd().(tea.QuitMsg); !ok {
+			t.Errorf("%s: expected a quit, got %T", tt.advance, cmd())
+		}
+	}
+}
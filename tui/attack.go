@@ -0,0 +1,65 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/angusmclean/tavernshell/core/dice"
+)
+
+// handleAttack rolls to hit and, on a hit, the damage, doubling the damage
+// dice on a critical hit
+// Usage: atk <to-hit> vs <AC> dmg <damage> [# label]
+func (m *Model) handleAttack(args []string) {
+	if len(args) == 0 {
+		m.addHistory("Usage: atk <to-hit> vs <AC> dmg <damage> (e.g., 'atk d20+7 vs 15 dmg 1d8+4 # longsword')")
+		return
+	}
+
+	input, label := dice.SplitLabel(strings.Join(args, " "))
+	toHitNotation, ac, damageNotation, err := dice.SplitAttack(input)
+	if err != nil {
+		m.addHistory(fmt.Sprintf("Error: %s", err))
+		return
+	}
+	if label != "" {
+		toHitNotation += " # " + label
+	}
+	toHit, name, err := m.parseRoll(toHitNotation)
+	if err != nil {
+		m.addRollError(err)
+		return
+	}
+	damage, damageName, err := m.parseRoll(damageNotation)
+	if err != nil {
+		m.addRollError(fmt.Errorf("damage: %w", err))
+		return
+	}
+
+	environ := m.applyEnvironment(toHit, name)
+	m.applyBuffs(toHit, name, "attack")
+	m.applyBuffs(damage, toHit.Label+" "+name, "damage")
+	rules := m.rules()
+	attack, err := dice.RollAttack(toHit, damage, ac, rules.DegreesOfSuccess(), rules.Naturals())
+	if err != nil {
+		m.addHistory(fmt.Sprintf("Error: %s", err))
+		return
+	}
+
+	m.recordRoll(attack.Check.Result)
+	m.addHistory(fmt.Sprintf("⚔ %s%s → %s", rollLabel(name), m.formatDiceResult(attack.Check.Result), formatDegree(attack.Degree(), attack.String())))
+	m.addRollDetail(attack.Check.Result)
+	if environ != "" {
+		m.addHistory("  " + environ)
+	}
+	if attack.Damage == nil {
+		return
+	}
+	m.recordRoll(attack.Damage)
+	line := fmt.Sprintf("  Damage: %s%s", rollLabel(damageName), m.formatDiceResult(attack.Damage))
+	if attack.CriticalHit() {
+		line += " (dice doubled)"
+	}
+	m.addHistory(line)
+	m.addRollDetail(attack.Damage)
+}
@@ -0,0 +1,81 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/angusmclean/tavernshell/core/campaign"
+	"github.com/angusmclean/tavernshell/core/dice"
+	"github.com/angusmclean/tavernshell/core/tracker/timer"
+)
+
+// Banter thresholds: a roll is suspicious once a participant has minBanterRolls
+// on record and beats their average by at least banterMargin
+const (
+	minBanterRolls = 3
+	banterMargin   = 5
+)
+
+// OpenCampaign keeps the campaign record (initiative history, encounters,
+// sessions and natural 20s) in a file, so it carries over between sessions
+func (m *Model) OpenCampaign(path string) error {
+	log, err := campaign.Open(path)
+	if err != nil {
+		return err
+	}
+	m.campaign = log
+	return log.StartSession(time.Now())
+}
+
+// recordInitiative adds an initiative to the campaign record, teasing the
+// participant if it is suspiciously far above their average
+func (m *Model) recordInitiative(name string, initiative int) {
+	avg, count := m.campaign.InitiativeAverage(name)
+	if err := m.campaign.RecordInitiative(name, initiative); err != nil {
+		m.addHistory(fmt.Sprintf("Warning: failed to save campaign: %s", err))
+	}
+	if count >= minBanterRolls && float64(initiative) >= avg+banterMargin {
+		m.addHistory(fmt.Sprintf("🤨 %s rolled %d... their average is %.1f over %d encounters", name, initiative, avg, count))
+	}
+}
+
+// countRoll counts a roll in the campaign, with its natural 20 or 1 going to
+// whoever's turn it is in initiative, or else the roll's label
+func (m *Model) countRoll(result *dice.Result) {
+	name := result.Label
+	if tracker := m.initiativeManager.GetTracker(); tracker != nil {
+		if current := tracker.GetCurrent(); current != nil {
+			name = current.Name
+		}
+	}
+	m.campaign.RecordRoll(name, result.Crit, result.Fumble)
+}
+
+// flushCampaign saves the rolls counted since the campaign was last saved,
+// warning (once) if it can't
+func (m *Model) flushCampaign() {
+	if err := m.campaign.Flush(); err != nil {
+		if !m.campaignSaveFailed {
+			m.addHistory(fmt.Sprintf("Warning: failed to save campaign: %s", err))
+		}
+		m.campaignSaveFailed = true
+		return
+	}
+	m.campaignSaveFailed = false
+}
+
+// campaignRecap totals up the campaign record: sessions, time in and out of
+// combat, encounters and who rolled the natural 20s
+func (m *Model) campaignRecap() {
+	r := m.campaign.Recap(time.Now())
+	m.addHistory(fmt.Sprintf("📊 Campaign: %d session(s), %s played", r.Sessions, timer.FormatDurationShort(r.PlayTime)))
+	m.addHistory(fmt.Sprintf("  Combat %s, talk %s", timer.FormatDurationShort(r.CombatTime), timer.FormatDurationShort(r.TalkTime())))
+	m.addHistory(fmt.Sprintf("  %d encounter(s), %d round(s), %d damage dealt, %d taken, %d initiative(s) rolled", r.Encounters, r.Rounds, r.DamageDealt, r.DamageTaken, r.Initiatives))
+	m.addHistory(fmt.Sprintf("  %d roll(s), %d natural 20(s), %d natural 1(s)", r.Rolls, r.Crits, r.Fumbles))
+	for _, n := range r.Naturals {
+		m.addHistory(fmt.Sprintf("    %s: %d crit(s), %d fumble(s)", n.Name, n.Crits, n.Fumbles))
+	}
+	if m.campaign.Path() == "" {
+		m.addHistory("  Only this session is counted; set TAVERNSHELL_CAMPAIGN (or --campaign) to keep a record across sessions")
+	}
+}
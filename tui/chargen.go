@@ -0,0 +1,156 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/angusmclean/tavernshell/core/dice"
+	"github.com/angusmclean/tavernshell/core/party"
+	"github.com/angusmclean/tavernshell/core/rolllog"
+)
+
+// chargenRun is a character being made with 'chargen', a prompt at a time:
+// the method, where each score goes, then a file to save the sheet to
+type chargenRun struct {
+	name  string
+	build *party.Build // nil until a method is picked
+	rolls int          // ability score rolls made, so the summary can be noted on the last
+}
+
+// handleChargen starts creating a character
+// Usage: chargen <name> [method]
+func (m *Model) handleChargen(args []string) {
+	if len(args) == 0 {
+		m.addHistory("Usage: chargen <name> [method] - Roll up a character's ability scores, assign them, and add them to the party (e.g., 'chargen Aria 4d6')")
+		return
+	}
+
+	name := strings.Join(args, " ")
+	var method *party.Method
+	if len(args) > 1 {
+		if found, err := party.ParseMethod(args[len(args)-1]); err == nil {
+			name, method = strings.Join(args[:len(args)-1], " "), &found
+		}
+	}
+	if c := m.roster.Get(name); c != nil {
+		m.addHistory(fmt.Sprintf("Error: %s is already in the party ('party remove %s' first, or pick another name)", c.Name, c.Name))
+		return
+	}
+
+	m.chargen = &chargenRun{name: name}
+	m.addHistory(fmt.Sprintf("🎲 Creating %s (Esc to stop)", name))
+	if method == nil {
+		m.addHistory("How should the ability scores be generated?")
+		for i, method := range party.Methods {
+			m.addHistory(fmt.Sprintf("  %d. %s - %s", i+1, method.Name, method.Description))
+		}
+		return
+	}
+	m.generateScores(*method)
+}
+
+// answerChargen handles input while a character is being made
+func (m *Model) answerChargen(input string) {
+	run := m.chargen
+	input = strings.TrimSpace(input)
+	stop := false
+	switch strings.ToLower(input) {
+	case "q", "quit", "stop", "n", "no":
+		stop = true
+	}
+
+	switch {
+	case stop && (run.build == nil || !run.build.Finished()):
+		m.chargen = nil
+		m.addHistory(fmt.Sprintf("Stopped creating %s", run.name))
+
+	case run.build == nil:
+		method, err := party.ParseMethod(input)
+		if err != nil {
+			m.addHistory(fmt.Sprintf("Error: %s", err))
+			return
+		}
+		m.generateScores(method)
+
+	case !run.build.Finished():
+		if err := run.build.Assign(input); err != nil {
+			m.addHistory(fmt.Sprintf("Error: %s", err))
+			return
+		}
+		m.promptChargen()
+
+	default:
+		// The sheet file, or n to skip saving one
+		if !stop {
+			c := m.roster.Get(run.name)
+			if err := c.WriteSheet(input); err != nil {
+				m.addHistory(fmt.Sprintf("Error: %s", err))
+				return
+			}
+			m.addHistory(fmt.Sprintf("Saved %s's sheet to %s ('party import %s' brings them back)", c.Name, input, input))
+		}
+		m.chargen = nil
+	}
+}
+
+// generateScores makes the character's ability scores by the chosen method,
+// rolling each one into the history
+func (m *Model) generateScores(method party.Method) {
+	run := m.chargen
+	scores := method.Array
+	if method.Notation != "" {
+		expr, err := dice.Parse(method.Notation)
+		if err != nil {
+			m.addHistory(fmt.Sprintf("Error: %s", err))
+			return
+		}
+		scores = nil
+		for i := 0; i < 6; i++ {
+			result, err := dice.RollExpression(expr)
+			if err != nil {
+				m.addHistory(fmt.Sprintf("Error: %s", err))
+				return
+			}
+			m.recordRoll(result)
+			run.rolls++
+			m.addHistory(fmt.Sprintf("🎲 %s%s", rollLabel(run.name), m.formatDiceResult(result)))
+			m.addRollDetail(result)
+			scores = append(scores, result.Total)
+		}
+	}
+	run.build = party.NewBuild(run.name, method, scores)
+	m.promptChargen()
+}
+
+// promptChargen asks where the next score goes, or finishes the character
+// once they're all assigned
+func (m *Model) promptChargen() {
+	build := m.chargen.build
+	if score, ok := build.Next(); ok {
+		m.addHistory(fmt.Sprintf("Scores left: %s. Where does the %d go? (%s)", joinInts(build.Scores), score, strings.Join(build.Unassigned(), ", ")))
+		return
+	}
+
+	summary := build.Summary()
+	m.addHistory("✨ " + summary)
+	if m.chargen.rolls > 0 {
+		m.rollLog.Annotate(summary)
+	} else {
+		// Nothing was rolled, so the scores go in the log to carry the note
+		total := 0
+		for _, score := range build.Method.Array {
+			total += score
+		}
+		m.rollLog.AddEntry(rolllog.Entry{
+			Time:     time.Now(),
+			Notation: build.Method.Name,
+			Dice:     "[" + joinInts(build.Method.Array) + "]",
+			Total:    total,
+			Note:     summary,
+		})
+	}
+	m.roster.Add(build.Character())
+	m.addHistory(fmt.Sprintf("Added %s to the party (init %+d; set AC and HP in a sheet)", m.colorName(build.Name), party.AbilityModifier(build.Abilities["dex"])))
+	m.addHistory("Save a character sheet? Enter a file name (e.g., 'aria.json'), or n to skip")
+}
@@ -0,0 +1,37 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestChargenArrayNoted(t *testing.T) {
+	m := runCommands("chargen Aria array", "str", "dex", "con", "int", "wis", "cha", "n")
+	if m.roster.Get("Aria") == nil {
+		t.Fatalf("Expected Aria in the party, got %q", lastLine(m))
+	}
+	entries := m.rollLog.Entries()
+	if len(entries) != 1 {
+		t.Fatalf("Expected the array in the roll log, got %d entries", len(entries))
+	}
+	e := entries[0]
+	if e.Notation != "array" || e.Dice != "[15, 14, 13, 12, 10, 8]" || e.Total != 72 {
+		t.Errorf("Expected the standard array for 72, got %s %s for %d", e.Notation, e.Dice, e.Total)
+	}
+	if !strings.HasPrefix(e.Note, "Aria (array): STR 15") {
+		t.Errorf("Expected the summary as the note, got %q", e.Note)
+	}
+}
+
+func TestChargenNameTaken(t *testing.T) {
+	m := runCommands("chargen Aria array", "str", "dex", "con", "int", "wis", "cha", "n", "chargen aria 4d6")
+	if !strings.Contains(lastLine(m), "already in the party") {
+		t.Errorf("Expected the name to be refused, got %q", lastLine(m))
+	}
+	if m.chargen != nil {
+		t.Error("Expected no character to be in the making")
+	}
+	if c := m.roster.Get("Aria"); c == nil || c.Abilities["str"] != 15 {
+		t.Errorf("Expected the first Aria kept, got %v", c)
+	}
+}
@@ -0,0 +1,104 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+)
+
+// handleChecklist processes checklist commands
+func (m *Model) handleChecklist(args []string) {
+	if len(args) == 0 {
+		m.addHistory("Usage: checklist <command> - Commands: run/r <name>, list/l")
+		return
+	}
+
+	subCmd := strings.ToLower(args[0])
+
+	switch {
+	case strings.HasPrefix("run", subCmd):
+		if len(args) < 2 {
+			m.addHistory("Usage: checklist run <name> (e.g., 'checklist run session-start')")
+			return
+		}
+		name := strings.Join(args[1:], " ")
+		list := m.checklistManager.Get(name)
+		if list == nil {
+			m.addHistory(fmt.Sprintf("Error: checklist '%s' not found", name))
+			return
+		}
+		m.checklistRun = list.Start()
+		m.addHistory(fmt.Sprintf("☐ Checklist %s (y = done, n = skip, q = stop)", list.Name))
+		m.promptChecklistStep()
+
+	case strings.HasPrefix("list", subCmd):
+		m.addHistory("Checklists:")
+		for _, list := range m.checklistManager.List() {
+			steps := make([]string, len(list.Steps))
+			for i, step := range list.Steps {
+				steps[i] = step.Text
+			}
+			m.addHistory(fmt.Sprintf("  %s: %s", list.Name, strings.Join(steps, ", ")))
+		}
+
+	default:
+		m.addHistory(fmt.Sprintf("Unknown checklist command: %s", subCmd))
+	}
+}
+
+// LoadChecklists loads checklists from a JSON file, replacing built-in ones with the same name
+func (m *Model) LoadChecklists(path string) error {
+	if err := m.checklistManager.Load(path); err != nil {
+		return err
+	}
+	m.watcher.watch(path, reloadChecklists)
+	return nil
+}
+
+// promptChecklistStep shows the step waiting for an answer
+func (m *Model) promptChecklistStep() {
+	step, n, ok := m.checklistRun.Current()
+	if !ok {
+		return
+	}
+	line := fmt.Sprintf("☐ %d/%d: %s?", n, len(m.checklistRun.Checklist.Steps), step.Text)
+	if step.Command != "" {
+		line += fmt.Sprintf(" (y runs '%s')", step.Command)
+	}
+	m.addHistory(line)
+}
+
+// answerChecklist handles input while a checklist is running:
+// y confirms the step (running its command), n skips it, q stops the checklist
+func (m *Model) answerChecklist(input string) {
+	run := m.checklistRun
+	step, _, _ := run.Current()
+
+	switch strings.ToLower(strings.TrimSpace(input)) {
+	case "y", "yes":
+		run.Answer(true)
+		m.addHistory(fmt.Sprintf("✓ %s", step.Text))
+		if step.Command != "" {
+			// The command runs as if typed, so it mustn't be read as an answer
+			m.checklistRun = nil
+			m.handleBatch(step.Command)
+			m.checklistRun = run
+		}
+	case "n", "no":
+		run.Answer(false)
+		m.addHistory(fmt.Sprintf("✗ Skipped: %s", step.Text))
+	case "q", "quit", "stop":
+		m.checklistRun = nil
+		m.addHistory(fmt.Sprintf("Checklist %s stopped (%d done, %d skipped)", run.Checklist.Name, run.Done, run.Skipped))
+		return
+	default:
+		m.addHistory("Answer y (done), n (skip), or q (stop the checklist)")
+		return
+	}
+
+	if run.Finished() {
+		m.checklistRun = nil
+		m.addHistory(fmt.Sprintf("Checklist %s finished: %d done, %d skipped", run.Checklist.Name, run.Done, run.Skipped))
+		return
+	}
+	m.promptChecklistStep()
+}
@@ -0,0 +1,34 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/angusmclean/tavernshell/core/dice"
+)
+
+// handleChoose picks one of a list of options at random
+// Usage: choose <option> <option>... (or comma-separated, for options with spaces)
+func (m *Model) handleChoose(args []string) {
+	options := dice.SplitChoices(strings.Join(args, " "))
+	if len(options) < 2 {
+		m.addHistory("Usage: choose <option> <option>... (e.g., 'choose goblin orc kobold' or 'choose red dragon, lich')")
+		return
+	}
+	choice, err := dice.Choose(options)
+	if err != nil {
+		m.addHistory(fmt.Sprintf("Error: %s", err))
+		return
+	}
+	m.addHistory(fmt.Sprintf("🎲 Choose from %s → %s", strings.Join(options, ", "), choice))
+}
+
+// handleFlip flips a coin
+func (m *Model) handleFlip() {
+	side, err := dice.Flip()
+	if err != nil {
+		m.addHistory(fmt.Sprintf("Error: %s", err))
+		return
+	}
+	m.addHistory(fmt.Sprintf("🪙 Flip → %s", side))
+}
@@ -0,0 +1,85 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/angusmclean/tavernshell/core/dice"
+)
+
+// handleDice processes custom dice commands
+func (m *Model) handleDice(args []string) {
+	if len(args) == 0 {
+		m.addHistory("Usage: dice <command> - Commands: define <name> <face>,<face>,..., list/l, delete/d <name> (roll with 'r d<name>')")
+		return
+	}
+
+	subCmd := strings.ToLower(args[0])
+
+	switch {
+	case subCmd == "define" || subCmd == "def":
+		if len(args) < 3 {
+			m.addHistory("Usage: dice define <name> <face>,<face>,... (e.g., 'dice define hitdir N,NE,E,SE,S,SW,W,NW')")
+			return
+		}
+		die, err := m.customDice.Define(args[1], strings.Split(strings.Join(args[2:], " "), ","))
+		if err != nil {
+			m.addHistory(fmt.Sprintf("Error: %s", err))
+			return
+		}
+		m.addHistory(fmt.Sprintf("Defined d%s (%d faces: %s). Roll it with 'r d%s'.", die.Name, len(die.Faces), strings.Join(die.Faces, ", "), die.Name))
+
+	case strings.HasPrefix("list", subCmd):
+		dice := m.customDice.List()
+		if len(dice) == 0 {
+			m.addHistory("No custom dice (use 'dice define <name> <face>,<face>,...')")
+			return
+		}
+		m.addHistory("Custom dice:")
+		for _, die := range dice {
+			m.addHistory(fmt.Sprintf("  d%s: %s", die.Name, strings.Join(die.Faces, ", ")))
+		}
+
+	case strings.HasPrefix("delete", subCmd) && len(args) >= 2:
+		// The die may be named as it's rolled, "dhitdir" for hitdir
+		name := args[1]
+		if _, rolled, ok := dice.ParseCustom(name); ok && m.customDice.Get(name) == nil && m.customDice.Get(rolled) != nil {
+			name = rolled
+		}
+		if err := m.customDice.Delete(name); err != nil {
+			m.addHistory(fmt.Sprintf("Error: %s", err))
+			return
+		}
+		m.addHistory(fmt.Sprintf("Deleted d%s", name))
+
+	default:
+		m.addHistory(fmt.Sprintf("Unknown dice command: %s", subCmd))
+	}
+}
+
+// isCustomRoll reports whether notation rolls a defined custom die, like "2dhitdir"
+func (m *Model) isCustomRoll(notation string) bool {
+	_, name, ok := dice.ParseCustom(notation)
+	return ok && m.customDice.Get(name) != nil
+}
+
+// rollCustom rolls custom dice (times over, for "3x dhitdir"), showing the
+// faces and recording each roll like any other
+func (m *Model) rollCustom(notation, label string, times int) {
+	for i := 0; i < times; i++ {
+		faces, err := m.customDice.Roll(notation)
+		if err != nil {
+			m.addHistory(fmt.Sprintf("Error: %s", err))
+			return
+		}
+		result := faces.Result()
+		result.Label = label
+		result.Expression.Label = label
+		m.recordRoll(result)
+		if label != "" {
+			m.addHistory(fmt.Sprintf("🎲 %s (%s)", faces, label))
+		} else {
+			m.addHistory(fmt.Sprintf("🎲 %s", faces))
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/angusmclean/tavernshell/core/dice"
+)
+
+func TestCustomRollRecorded(t *testing.T) {
+	defer dice.SetRoller(dice.CurrentRoller())
+
+	dice.SetRoller(dice.NewSequenceRoller(2, 5))
+	m := runCommands("dice define hitdir N,NE,E,SE,S,SW,W,NW", "r 2x dhitdir # scatter")
+	entries := m.rollLog.Entries()
+	if len(entries) != 2 {
+		t.Fatalf("Expected both rolls in the roll log, got %d", len(entries))
+	}
+	if e := entries[1]; e.Notation != "1dhitdir" || e.Dice != "[S]" || e.Note != "scatter" {
+		t.Errorf("Expected 1dhitdir [S] noted scatter, got %+v", e)
+	}
+	if m.lastRoll == nil || m.lastRoll.DiceString() != "[S]" {
+		t.Errorf("Expected the last roll to be the custom one, got %v", m.lastRoll)
+	}
+	m.handleBatch("share last")
+	if !strings.Contains(lastLine(m), "can't be shared") {
+		t.Errorf("Expected share to refuse a custom roll, got %q", lastLine(m))
+	}
+}
+
+func TestCustomDiceDelete(t *testing.T) {
+	tests := []struct {
+		command string
+		deleted string // the die left undefined
+		kept    string // the die still defined
+	}{
+		{"dice delete dart", "dart", "art"},
+		{"dice delete ddart", "dart", "art"},
+		{"dice delete dhitdir", "hitdir", "dart"},
+		{"dice delete hitdir", "hitdir", "dart"},
+	}
+	for _, tt := range tests {
+		m := runCommands("dice define dart hit,miss", "dice define art a,b", "dice define hitdir N,S", tt.command)
+		if m.customDice.Get(tt.deleted) != nil {
+			t.Errorf("%s: expected d%s to be deleted", tt.command, tt.deleted)
+		}
+		if m.customDice.Get(tt.kept) == nil {
+			t.Errorf("%s: expected d%s to be kept", tt.command, tt.kept)
+		}
+	}
+}
@@ -0,0 +1,232 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/angusmclean/tavernshell/core/tracker/number"
+	"github.com/angusmclean/tavernshell/core/tracker/timer"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// otherOwner groups trackers that don't belong to anyone in particular
+const otherOwner = "Other"
+
+// handleDashboard toggles the dashboard: every tracker, timer, the initiative
+// state and today's notes on one screen, for between combats
+// Usage: dashboard
+func (m *Model) handleDashboard() {
+	m.dashboard = !m.dashboard
+	if m.dashboard {
+		m.addHistory("Dashboard open ('dashboard' or Esc to close)")
+	} else {
+		m.addHistory("Dashboard closed")
+	}
+}
+
+// buildDashboard lays out the dashboard, at most height lines of at most width
+func (m Model) buildDashboard(width, height int) []string {
+	var lines []string
+	section := func(title string) {
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, m.cache.render(&roundHeaderStyle, title))
+	}
+
+	section("Trackers")
+	owners, trackers := m.trackersByOwner()
+	if len(owners) == 0 {
+		lines = append(lines, "  None (t add <name> <current> <max>)")
+	}
+	nameWidth := 0
+	for _, owner := range owners {
+		nameWidth = max(nameWidth, len(owner))
+	}
+	for _, owner := range owners {
+		var values []string
+		for _, t := range trackers[owner] {
+			values = append(values, t.label+" "+t.tracker.Value())
+		}
+		padded := fmt.Sprintf("%-*s", nameWidth, owner)
+		if owner != otherOwner {
+			padded = m.colorName(owner) + padded[len(owner):]
+		}
+		lines = append(lines, fmt.Sprintf("  %s  %s", padded, strings.Join(values, "  ·  ")))
+	}
+
+	section("Timers")
+	timers := m.timerManager.GetActive()
+	if len(timers) == 0 {
+		lines = append(lines, "  None")
+	}
+	for _, t := range timers {
+		left := timer.FormatDurationShort(t.Remaining()) + " left"
+		if t.IsRoundBased() {
+			left = fmt.Sprintf("%d round(s) left", t.Rounds)
+		}
+		label := t.Label
+		if label == "" {
+			label = "Alarm"
+		}
+		lines = append(lines, fmt.Sprintf("  %s: %s", label, left))
+	}
+
+	section("Initiative")
+	lines = append(lines, "  "+m.initiativeSummary())
+
+	section("Today's notes")
+	notes := m.todaysNotes()
+	if len(notes) == 0 {
+		lines = append(lines, "  None ('annotate last \"<note>\"' or 'r <dice> # <label>')")
+	}
+	lines = append(lines, notes...)
+
+	if height < 1 {
+		return nil
+	}
+	if len(lines) > height {
+		hidden := len(lines) - height + 1
+		lines = append(lines[:height-1], fmt.Sprintf("  ... %d more line(s); make the terminal taller to see them", hidden))
+	}
+	for i, line := range lines {
+		lines[i] = ansi.Truncate(m.glyphs(line), width, "...")
+	}
+	return lines
+}
+
+// dashboardView renders the whole screen as the dashboard, with the latest
+// output line above the input so commands typed there still show a result
+func (m Model) dashboardView(titleBar, separator, inputLine, helpText string) string {
+	latest := ""
+	if total := m.history.Total(); total > 0 {
+		if entries, err := m.history.Range(total-1, total); err == nil && len(entries) == 1 {
+			latest = ansi.Truncate(m.glyphs(entries[0].Text), m.width, "...")
+		}
+	}
+
+	// Title and separator above, separator, latest output, input and help below
+	lines := m.buildDashboard(m.width, m.height-6)
+	var b strings.Builder
+	b.WriteString(titleBar + "\n" + separator + "\n")
+	for i := 0; i < m.height-6; i++ {
+		if i < len(lines) {
+			b.WriteString(lines[i])
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString(separator + "\n")
+	b.WriteString(latest + "\n")
+	b.WriteString(inputLine + "\n")
+	b.WriteString(helpText)
+	return b.String()
+}
+
+// ownedTracker is a tracker as the dashboard shows it under its owner
+type ownedTracker struct {
+	tracker *number.Tracker
+	label   string // what it tracks for the owner, e.g. "HP" or "Slots"
+}
+
+// trackersByOwner groups every tracker by who it belongs to: the participant
+// it's linked to, or the first word of a name like "Aria Slots"; the rest
+// go under "Other" (listed last)
+func (m Model) trackersByOwner() ([]string, map[string][]ownedTracker) {
+	linked := m.linkedTrackers()
+	groups := make(map[string][]ownedTracker)
+	for _, t := range m.numberTrackerManager.List() {
+		owner, label := trackerOwner(t.Name, linked)
+		groups[owner] = append(groups[owner], ownedTracker{tracker: t, label: label})
+	}
+
+	owners := make([]string, 0, len(groups))
+	for owner := range groups {
+		if owner != otherOwner {
+			owners = append(owners, owner)
+		}
+	}
+	sort.Strings(owners)
+	if _, ok := groups[otherOwner]; ok {
+		owners = append(owners, otherOwner)
+	}
+	return owners, groups
+}
+
+// linkedTrackers maps the trackers linked to participants (by lowercase
+// name) to the participant they belong to
+func (m Model) linkedTrackers() map[string]string {
+	linked := make(map[string]string)
+	if tracker := m.initiativeManager.GetTracker(); tracker != nil {
+		for _, p := range tracker.Participants {
+			if p.Tracker != "" {
+				linked[strings.ToLower(p.Tracker)] = p.Name
+			}
+		}
+	}
+	return linked
+}
+
+// trackerOwner works out who a tracker belongs to and what it tracks for
+// them (see trackersByOwner), given the linked trackers
+func trackerOwner(name string, linked map[string]string) (owner, label string) {
+	if participant, ok := linked[strings.ToLower(name)]; ok {
+		label = "HP"
+		if len(name) > len(participant) && strings.EqualFold(name[:len(participant)], participant) {
+			label = strings.TrimSpace(name[len(participant):]) // "Troll HP" is Troll's "HP"
+		}
+		return participant, label
+	}
+	if first, rest, ok := strings.Cut(name, " "); ok {
+		return first, strings.TrimSpace(rest)
+	}
+	return otherOwner, name
+}
+
+// initiativeSummary describes the initiative in a line: round, whose turn,
+// who's next, and how many are still in the fight
+func (m Model) initiativeSummary() string {
+	tracker := m.initiativeManager.GetTracker()
+	if !m.initiativeManager.IsActive() || tracker == nil || !tracker.HasParticipants() {
+		return "Not in combat ('i start' to begin)"
+	}
+
+	active, out := 0, 0
+	for _, p := range tracker.Participants {
+		if p.IsActive {
+			active++
+		} else {
+			out++
+		}
+	}
+	parts := []string{fmt.Sprintf("Round %d", tracker.Round)}
+	if current := tracker.GetCurrent(); current != nil {
+		turn := current.Name
+		if side := tracker.CurrentSide(); side != "" {
+			turn = side
+		}
+		parts = append(parts, "Turn: "+turn)
+	}
+	if upcoming := m.buildUpcoming(); upcoming != "" {
+		parts = append(parts, upcoming)
+	}
+	parts = append(parts, fmt.Sprintf("%d active, %d out", active, out))
+	return strings.Join(parts, "  ·  ")
+}
+
+// todaysNotes lists the rolls noted today (annotations and labels), oldest first
+func (m Model) todaysNotes() []string {
+	year, month, day := time.Now().Date()
+	var notes []string
+	for _, e := range m.rollLog.Entries() {
+		if e.Note == "" {
+			continue
+		}
+		if y, mo, d := e.Time.Local().Date(); y != year || mo != month || d != day {
+			continue
+		}
+		notes = append(notes, fmt.Sprintf("  %s %s = %d — %s", e.Time.Local().Format("15:04"), e.Notation, e.Total, e.Note))
+	}
+	return notes
+}
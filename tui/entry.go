@@ -0,0 +1,148 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/angusmclean/tavernshell/core/config"
+	"github.com/angusmclean/tavernshell/core/tracker/rotation"
+)
+
+// maxEntryNumbers is how many trailing numbers an entry can have: initiative, HP and AC
+const maxEntryNumbers = 3
+
+// entryFormat describes an entry line, for prompts and errors
+const entryFormat = "<name> <initiative> [hp] [ac]"
+
+// handleEntry adds one participant in initiative entry mode:
+// "<name> <initiative> [hp] [ac]" ("... <side>" in side mode, "<name> <bonus> ..." in cyclic mode)
+// A name with a number in it is quoted: "Goblin 2" 12 7
+// Participants entered with HP get a linked HP tracker
+func (m *Model) handleEntry(input string) {
+	m.category = categoryInitiative
+	// Check for exit commands
+	if input == "" || strings.ToLower(input) == "done" || strings.ToLower(input) == "end" {
+		m.initiativeEntryMode = false
+		m.addHistory("Initiative setup complete. Use 'i n' to advance turns.")
+		m.runTurnScripts()
+		return
+	}
+
+	parts := splitArgs(input)
+	mode := rotation.ModeStandard
+	if tracker := m.initiativeManager.GetTracker(); tracker != nil {
+		mode = tracker.Mode
+	}
+	side := ""
+	if mode == rotation.ModeSide {
+		if len(parts) < 3 {
+			m.addHistory(fmt.Sprintf("Format: %s <side> (or 'done' to finish)", entryFormat))
+			return
+		}
+		side = parts[len(parts)-1]
+		parts = parts[:len(parts)-1]
+	}
+
+	// Trailing numbers are initiative, then HP and AC; everything before them is the name
+	numbers := 0
+	for numbers < maxEntryNumbers && numbers < len(parts)-1 {
+		if _, err := strconv.Atoi(parts[len(parts)-1-numbers]); err != nil {
+			break
+		}
+		numbers++
+	}
+	if numbers == 0 {
+		m.addHistory(fmt.Sprintf("Format: %s (or 'done' to finish)", entryFormat))
+		return
+	}
+
+	// A number left at the end of the name can't be told from the numbers
+	// after it ("Orc 2 15 13 9"), so a name like that has to be quoted
+	nameParts := parts[:len(parts)-numbers]
+	if _, err := strconv.Atoi(nameParts[len(nameParts)-1]); err == nil {
+		m.addHistory(fmt.Sprintf("Error: can't tell where the name ends in '%s'; quote a name with a number in it (e.g., '\"Goblin 2\" 12 7')", input))
+		return
+	}
+	values := make([]int, maxEntryNumbers)
+	for i, part := range parts[len(parts)-numbers:] {
+		values[i], _ = strconv.Atoi(part)
+	}
+	initiative, hp, ac := values[0], values[1], values[2]
+	name := strings.Join(nameParts, " ")
+	if m.inInitiative(name) {
+		m.addHistory(fmt.Sprintf("Error: %s is already in initiative (quote a numbered name to tell them apart, e.g. '\"%s 2\" %d')", name, name, initiative))
+		return
+	}
+
+	notes := ""
+	if hp > 0 {
+		notes += fmt.Sprintf(", %d HP", hp)
+	}
+	if ac > 0 {
+		notes += fmt.Sprintf(", AC %d", ac)
+	}
+
+	switch {
+	case mode == rotation.ModeCyclic:
+		// In cyclic mode the number is the initiative bonus
+		rolled, err := m.initiativeManager.AddWithBonus(name, initiative)
+		if err != nil {
+			m.addHistory(fmt.Sprintf("Error: %s", err))
+			return
+		}
+		m.addHistory(fmt.Sprintf("Added %s (bonus %+d, rolled %d%s)", name, initiative, rolled, notes))
+		initiative = rolled
+	case side != "":
+		m.initiativeManager.AddToSide(name, initiative, side)
+		m.addHistory(fmt.Sprintf("Added %s to %s (initiative %d%s)", name, side, initiative, notes))
+	default:
+		m.initiativeManager.Add(name, initiative)
+		m.addHistory(fmt.Sprintf("Added %s (initiative %d%s)", name, initiative, notes))
+	}
+	m.initiativeManager.SetHP(name, hp)
+	m.initiativeManager.SetAC(name, ac)
+	m.recordInitiative(name, initiative)
+	if hp > 0 && !m.disabled[config.Trackers] {
+		m.linkHPTracker(name, hp)
+	}
+}
+
+// inInitiative returns true if a participant with the name (case-insensitive)
+// is already in initiative
+func (m *Model) inInitiative(name string) bool {
+	tracker := m.initiativeManager.GetTracker()
+	if tracker == nil {
+		return false
+	}
+	for _, p := range tracker.Participants {
+		if strings.EqualFold(p.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// pasteEntries adds a participant for each line of a multi-line paste
+func (m *Model) pasteEntries(text string) {
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || !m.initiativeEntryMode {
+			continue
+		}
+		m.handleBatch(line)
+	}
+}
+
+// linkHPTracker links a participant to the tracker named after them,
+// creating one at hp if there isn't one
+func (m *Model) linkHPTracker(name string, hp int) {
+	t := m.findTrackerFor(name)
+	if t == nil {
+		t = m.numberTrackerManager.Add(name, hp, hp)
+		m.category = categoryTracker
+		m.addHistory(fmt.Sprintf("Added tracker: [%s] %d/%d", t.Name, t.Current, t.Max))
+		m.category = categoryInitiative
+	}
+	m.initiativeManager.Link(name, t.Name)
+}
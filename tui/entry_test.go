@@ -0,0 +1,93 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/angusmclean/tavernshell/core/tracker/rotation"
+)
+
+// runCommands runs commands against a fresh model, as if typed
+func runCommands(commands ...string) *Model {
+	m := NewModel()
+	for _, command := range commands {
+		m.handleBatch(command)
+	}
+	return &m
+}
+
+// lastLine returns the text of the newest history entry
+func lastLine(m *Model) string {
+	recent := m.history.Recent(1)
+	if len(recent) == 0 {
+		return ""
+	}
+	return recent[0].Text
+}
+
+// participant returns the participant with the given name, or nil
+func participant(m *Model, name string) *rotation.Participant {
+	tracker := m.initiativeManager.GetTracker()
+	if tracker == nil {
+		return nil
+	}
+	for _, p := range tracker.Participants {
+		if p.Name == name {
+			return p
+		}
+	}
+	return nil
+}
+
+func TestEntryPositional(t *testing.T) {
+	m := runCommands("i s", "Goblin 12 7 15", `"Goblin 2" 11 9`, "Orc Chief 8", `"Orc 2" 15 13`, "done")
+
+	tests := []struct {
+		name       string
+		initiative int
+		hp, ac     int
+	}{
+		{"Goblin", 12, 7, 15},
+		{"Goblin 2", 11, 9, 0},
+		{"Orc Chief", 8, 0, 0},
+		{"Orc 2", 15, 13, 0},
+	}
+	for _, tt := range tests {
+		p := participant(m, tt.name)
+		if p == nil {
+			t.Errorf("Expected participant %q", tt.name)
+			continue
+		}
+		if p.Initiative != tt.initiative || p.HP != tt.hp || p.AC != tt.ac {
+			t.Errorf("%s: expected initiative %d, %d HP, AC %d, got %d, %d HP, AC %d",
+				tt.name, tt.initiative, tt.hp, tt.ac, p.Initiative, p.HP, p.AC)
+		}
+	}
+	if tr := m.numberTrackerManager.Get("Goblin 2"); tr == nil || tr.Max != 9 {
+		t.Errorf("Expected a 9 HP tracker for Goblin 2, got %v", tr)
+	}
+}
+
+func TestEntryAmbiguousName(t *testing.T) {
+	m := runCommands("i s", "Orc 2 15 13 9")
+	if !strings.Contains(lastLine(m), "quote a name") {
+		t.Errorf("Expected the entry to be refused, got %q", lastLine(m))
+	}
+	if n := len(m.initiativeManager.GetTracker().Participants); n != 0 {
+		t.Errorf("Expected no participants, got %d", n)
+	}
+}
+
+func TestEntryDuplicateName(t *testing.T) {
+	// Unquoted, "Goblin 3 9" is Goblin at initiative 3 with 9 HP
+	m := runCommands("i s", "Goblin 12 7", "Goblin 3 9")
+	if !strings.Contains(lastLine(m), "already in initiative") {
+		t.Errorf("Expected the duplicate to be refused, got %q", lastLine(m))
+	}
+	if n := len(m.initiativeManager.GetTracker().Participants); n != 1 {
+		t.Errorf("Expected 1 participant, got %d", n)
+	}
+	if tr := m.numberTrackerManager.Get("Goblin"); tr == nil || tr.Max != 7 {
+		t.Errorf("Expected Goblin's tracker to stay at 7 HP, got %v", tr)
+	}
+}
@@ -0,0 +1,86 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/angusmclean/tavernshell/core/dice"
+	"github.com/angusmclean/tavernshell/core/environment"
+)
+
+// handleEnv processes environmental effect commands: weather and terrain
+// that checks pick up by their label (e.g. 'r d20+3 vs 15 # perception')
+// Usage: env add "<name>" <+/-N|advantage|disadvantage> [tags]..., env list/l, env remove/rm <name>, env clear
+func (m *Model) handleEnv(args []string) {
+	if len(args) == 0 {
+		m.addHistory("Usage: env add \"<name>\" <+/-N|advantage|disadvantage> [tags] - Commands: list/l, remove/rm <name>, clear")
+		return
+	}
+
+	switch strings.ToLower(args[0]) {
+	case "add", "a":
+		if len(args) < 3 {
+			m.addHistory("Usage: env add \"<name>\" <+/-N|advantage|disadvantage> [tags] (e.g., 'env add \"heavy rain\" -2 perception disadvantage ranged')")
+			return
+		}
+		effect, err := environment.ParseEffect(args[1], args[2:])
+		if err != nil {
+			m.addHistory(fmt.Sprintf("Error: %s", err))
+			return
+		}
+		m.environmentManager.Add(effect)
+		m.addHistory(fmt.Sprintf("🌦 Environment: %s", effect))
+		if !m.rules().Advantage() {
+			for _, r := range effect.Rules {
+				if r.Advantage || r.Disadvantage {
+					m.addHistory(fmt.Sprintf("Warning: the %s ruleset has no advantage or disadvantage, so checks only take the bonus or penalty", m.rules().Name()))
+					break
+				}
+			}
+		}
+
+	case "list", "l":
+		effects := m.environmentManager.List()
+		if len(effects) == 0 {
+			m.addHistory("No environmental effects")
+			return
+		}
+		m.addHistory("Environment:")
+		for _, effect := range effects {
+			m.addHistory("  " + effect.String())
+		}
+
+	case "remove", "rm":
+		if len(args) < 2 {
+			m.addHistory("Usage: env remove <name>")
+			return
+		}
+		name := strings.Join(args[1:], " ")
+		if err := m.environmentManager.Remove(name); err != nil {
+			m.addHistory(fmt.Sprintf("Error: %s", err))
+			return
+		}
+		m.addHistory(fmt.Sprintf("Removed environmental effect '%s'", name))
+
+	case "clear":
+		m.environmentManager.Clear()
+		m.addHistory("Cleared the environment")
+
+	default:
+		m.addHistory(fmt.Sprintf("Unknown env command: %s", args[0]))
+	}
+}
+
+// applyEnvironment adjusts a check for the environmental effects on it, found
+// by its label or saved roll name, returning a note of what applied ("" if none)
+func (m *Model) applyEnvironment(expr *dice.Expression, name string) string {
+	adj := m.environmentManager.For(expr.Label + " " + name)
+	if adj.IsZero() {
+		return ""
+	}
+	if !m.rules().Advantage() {
+		adj.Advantage, adj.Disadvantage = false, false
+	}
+	adj.Apply(expr)
+	return "🌦 " + adj.String()
+}
@@ -0,0 +1,123 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/angusmclean/tavernshell/core/tracker/timer"
+)
+
+// exploration is a running exploration: an encounter check rolled on a
+// table every time its repeating timer goes off
+type exploration struct {
+	timer  *timer.Timer
+	table  string
+	checks int // checks rolled so far
+}
+
+// handleExplore processes exploration commands
+func (m *Model) handleExplore(args []string) {
+	if len(args) == 0 {
+		m.addHistory("Usage: explore <command> - Commands: start <interval> <table>, check, stop (or just 'explore' for status)")
+		if m.exploring != nil {
+			m.addHistory("  " + m.exploreStatus())
+		}
+		return
+	}
+
+	subCmd := strings.ToLower(args[0])
+
+	switch {
+	case strings.HasPrefix("start", subCmd):
+		if len(args) < 3 {
+			m.addHistory("Usage: explore start <interval> <table> (e.g., 'explore start 10m forest' or 'explore start 10r dungeon')")
+			return
+		}
+		name := strings.Join(args[2:], " ")
+		table := m.tableManager.Get(name)
+		if table == nil {
+			m.addHistory(fmt.Sprintf("Error: table '%s' not loaded (use 'table load <file>')", name))
+			return
+		}
+
+		var check *timer.Timer
+		label := "Explore " + table.Name
+		if rounds, ok := parseRounds(args[1]); ok {
+			check = timer.NewRoundTimer(rounds, label)
+		} else {
+			interval, used, err := timer.ParseDuration(args[1:2])
+			if err != nil || used != 1 || interval <= 0 {
+				m.addHistory(fmt.Sprintf("Error: invalid interval '%s' (e.g., 10m, 1h or 10r)", args[1]))
+				return
+			}
+			check = timer.NewTimer(interval, label)
+		}
+		check.Repeat = true
+
+		m.stopExploring()
+		m.timerManager.Add(check)
+		m.exploring = &exploration{timer: check, table: table.Name}
+		m.addHistory(fmt.Sprintf("🧭 Exploring: rolling on '%s' every %s ('explore stop' to end)", table.Name, check.Length()))
+
+	case strings.HasPrefix("check", subCmd):
+		if m.exploring == nil {
+			m.addHistory("Error: not exploring (use 'explore start <interval> <table>')")
+			return
+		}
+		m.exploreCheck(true)
+
+	case strings.HasPrefix("stop", subCmd) || subCmd == "end":
+		if m.exploring == nil {
+			m.addHistory("Not exploring")
+			return
+		}
+		status := m.exploreStatus()
+		m.stopExploring()
+		m.addHistory("🧭 Stopped exploring: " + status)
+
+	case strings.HasPrefix("status", subCmd):
+		if m.exploring == nil {
+			m.addHistory("Not exploring")
+			return
+		}
+		m.addHistory("🧭 " + m.exploreStatus())
+
+	default:
+		m.addHistory(fmt.Sprintf("Unknown explore command: %s", subCmd))
+	}
+}
+
+// exploreCheck rolls the exploration's encounter check, posting the result
+// Quiet results ("nothing", "-") are only posted when asked for
+func (m *Model) exploreCheck(always bool) {
+	m.category = categoryTable
+	m.exploring.checks++
+	result, err := m.tableManager.Roll(m.exploring.table, 0)
+	if err != nil {
+		m.addHistory(fmt.Sprintf("Error: exploring: %s", err))
+		return
+	}
+	if result.Entry != nil && result.Entry.IsQuiet() && !always {
+		return
+	}
+	m.addHistory(fmt.Sprintf("🧭 Check %d: %s", m.exploring.checks, result))
+}
+
+// exploreStatus describes the running exploration, e.g.
+// "'forest' every 10m0s, 3 checks so far, next in 4m"
+func (m *Model) exploreStatus() string {
+	e := m.exploring
+	next := timer.FormatDurationShort(e.timer.Remaining())
+	if e.timer.IsRoundBased() {
+		next = fmt.Sprintf("%d round(s)", e.timer.Rounds)
+	}
+	return fmt.Sprintf("'%s' every %s, %d check(s) so far, next in %s", e.table, e.timer.Length(), e.checks, next)
+}
+
+// stopExploring stops the running exploration, if any
+func (m *Model) stopExploring() {
+	if m.exploring != nil {
+		m.timerManager.Remove(m.exploring.timer.ID)
+		m.exploring = nil
+	}
+}
@@ -0,0 +1,145 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// maxFindRecent caps how many past commands and history lines 'find' shows
+const maxFindRecent = 5
+
+// findSection is one category of 'find' results
+type findSection struct {
+	title string
+	hits  []string
+}
+
+// handleFind searches trackers, participants, party, macros, tables, rolls
+// (and their notes), past commands and history for a word, listing each hit with a command to act on it
+// Usage: find <text>
+func (m *Model) handleFind(args []string) {
+	if len(args) == 0 {
+		m.addHistory("Usage: find <text> - Search trackers, initiative, party, macros, tables, rolls, commands and history")
+		return
+	}
+	pattern := strings.Join(args, " ")
+	needle := strings.ToLower(pattern)
+	contains := func(s string) bool {
+		return strings.Contains(strings.ToLower(s), needle)
+	}
+
+	var sections []findSection
+	add := func(title string, hits []string) {
+		if len(hits) > 0 {
+			sections = append(sections, findSection{title, hits})
+		}
+	}
+
+	var hits []string
+	for _, t := range m.numberTrackerManager.Search(pattern) {
+		hits = append(hits, fmt.Sprintf("%s → t adj %s -N", trackerListing(t), t.Name))
+	}
+	add("Trackers", hits)
+
+	hits = nil
+	if tracker := m.initiativeManager.GetTracker(); tracker != nil {
+		for _, p := range tracker.Participants {
+			if !contains(p.Name) {
+				continue
+			}
+			line := fmt.Sprintf("%s (init %d)", p.Name, p.Initiative)
+			if !p.IsActive {
+				line += " (out)"
+			}
+			if p.Tracker != "" {
+				line += fmt.Sprintf(" → t adj %s -N", p.Tracker)
+			} else if p.IsActive {
+				line += fmt.Sprintf(" → i kill %s", p.Name)
+			}
+			hits = append(hits, line)
+		}
+	}
+	add("Initiative", hits)
+
+	hits = nil
+	for _, c := range m.roster.List() {
+		if contains(c.Name) {
+			hits = append(hits, fmt.Sprintf("%s: AC %d, HP %d, init %+d", c.Name, c.AC, c.HP, c.Initiative))
+		}
+	}
+	add("Party", hits)
+
+	hits = nil
+	for _, mc := range m.macroManager.List() {
+		if contains(mc.Name) || contains(strings.Join(mc.Steps(), "; ")) {
+			hits = append(hits, fmt.Sprintf("%s: %s → macro %s", mc.Name, strings.Join(mc.Steps(), "; "), mc.Name))
+		}
+	}
+	add("Macros", hits)
+
+	hits = nil
+	for _, t := range m.tableManager.List() {
+		matched := contains(t.Name)
+		for _, e := range t.Entries {
+			if contains(e.Text) {
+				hits = append(hits, fmt.Sprintf("%s: %s → table roll %s", t.Name, e.Text, t.Name))
+				matched = false // the entry already points at the table
+			}
+		}
+		if matched {
+			hits = append(hits, fmt.Sprintf("%s (d%d) → table roll %s", t.Name, t.Sides(), t.Name))
+		}
+	}
+	add("Tables", hits)
+
+	hits = nil
+	for _, e := range m.rollLog.Entries() {
+		if contains(e.Note) || contains(e.Notation) {
+			hits = append(hits, logListing(e))
+		}
+	}
+	add("Rolls", hits)
+
+	// Commands typed earlier, newest first and without repeats, to re-run
+	hits = nil
+	seen := make(map[string]bool)
+	for i := len(m.commandHistory) - 1; i >= 0 && len(hits) < maxFindRecent; i-- {
+		command := m.commandHistory[i]
+		if seen[command] || isFindCommand(command) || !contains(command) {
+			continue
+		}
+		seen[command] = true
+		hits = append(hits, fmt.Sprintf("→ %s", command))
+	}
+	add("Commands", hits)
+
+	hits = nil
+	entries := m.history.Recent(m.history.Len())
+	for i := len(entries) - 1; i >= 0 && len(hits) < maxFindRecent; i-- {
+		text := ansi.Strip(entries[i].Text)
+		if contains(text) {
+			hits = append(hits, fmt.Sprintf("%s %s", entries[i].Time.Format("15:04"), strings.TrimSpace(text)))
+		}
+	}
+	add("History", hits)
+
+	if len(sections) == 0 {
+		m.addHistory(fmt.Sprintf("Nothing matching '%s'", pattern))
+		return
+	}
+	m.addHistory(fmt.Sprintf("🔍 Matches for '%s':", pattern))
+	for _, s := range sections {
+		m.addHistory(fmt.Sprintf("  %s:", s.title))
+		for _, hit := range s.hits {
+			m.addHistory("    " + hit)
+		}
+	}
+}
+
+// isFindCommand reports whether an input line is itself a 'find'
+func isFindCommand(input string) bool {
+	fields := strings.Fields(strings.ToLower(input))
+	return len(fields) > 0 && len(fields[0]) >= 2 && strings.HasPrefix("find", fields[0])
+}
@@ -0,0 +1,147 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/angusmclean/tavernshell/core/config"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// trackerBarRow returns the screen row of the tracker bar: below the title,
+// separator, timer bar, blank and separator, or straight below the title and
+// separator with timers turned off
+func (m Model) trackerBarRow() int {
+	if m.disabled[config.Timers] {
+		return 2
+	}
+	return 5
+}
+
+// focusTracker moves keyboard focus through the pinned trackers by step (1 or -1)
+// Stepping past either end returns focus to the input line
+func (m *Model) focusTracker(step int) {
+	pinned := m.numberTrackerManager.GetPinned()
+	index := -1
+	for i, t := range pinned {
+		if t.Name == m.focusedTracker {
+			index = i
+		}
+	}
+
+	switch {
+	case len(pinned) == 0:
+		index = -1
+	case index == -1 && step < 0:
+		index = len(pinned) - 1
+	default:
+		index += step
+	}
+
+	m.trackerEntry = ""
+	if index < 0 || index >= len(pinned) {
+		m.focusedTracker = ""
+		m.textInput.Focus()
+		return
+	}
+	m.focusedTracker = pinned[index].Name
+	m.textInput.Blur()
+}
+
+// unfocusTracker returns keyboard focus to the input line
+func (m *Model) unfocusTracker() {
+	m.focusedTracker = ""
+	m.trackerEntry = ""
+	m.textInput.Focus()
+}
+
+// handleTrackerKey handles a key press while a pinned tracker has focus
+// "-7" Enter adjusts down, "+3" Enter adjusts up, "30" Enter sets the value,
+// and Up/Down adjust by one immediately
+func (m *Model) handleTrackerKey(msg tea.KeyMsg) tea.Cmd {
+	tracker := m.numberTrackerManager.Get(m.focusedTracker)
+	if tracker == nil || !tracker.Pinned {
+		m.unfocusTracker()
+		return nil
+	}
+
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return tea.Quit
+	case tea.KeyEsc:
+		m.unfocusTracker()
+	case tea.KeyTab:
+		m.focusTracker(1)
+	case tea.KeyShiftTab:
+		m.focusTracker(-1)
+	case tea.KeyUp:
+		m.applyTrackerEntry("+1")
+	case tea.KeyDown:
+		m.applyTrackerEntry("-1")
+	case tea.KeyEnter:
+		m.applyTrackerEntry(m.trackerEntry)
+	case tea.KeyBackspace:
+		if m.trackerEntry != "" {
+			m.trackerEntry = m.trackerEntry[:len(m.trackerEntry)-1]
+		}
+	case tea.KeyRunes:
+		for _, r := range msg.Runes {
+			switch {
+			case r == '+' || r == '=' || r == '-':
+				// A sign starts (or replaces the sign of) a relative entry
+				sign := "+"
+				if r == '-' {
+					sign = "-"
+				}
+				m.trackerEntry = sign + strings.TrimLeft(m.trackerEntry, "+-")
+			case r >= '0' && r <= '9' && len(m.trackerEntry) < 6:
+				m.trackerEntry += string(r)
+			}
+		}
+	}
+	return nil
+}
+
+// applyTrackerEntry applies a typed entry to the focused tracker, echoing the change to history
+// Signed entries adjust the value; unsigned entries set it
+func (m *Model) applyTrackerEntry(entry string) {
+	m.trackerEntry = ""
+	tracker := m.numberTrackerManager.Get(m.focusedTracker)
+	if tracker == nil || strings.Trim(entry, "+-") == "" {
+		return
+	}
+	value, err := strconv.Atoi(entry)
+	if err != nil {
+		return
+	}
+
+	m.category = categoryTracker
+	if err := checkWritable(tracker); err != nil {
+		m.addHistory(fmt.Sprintf("Error: %s", err))
+		return
+	}
+	before := tracker.Current
+	if entry[0] == '+' || entry[0] == '-' {
+		tracker.Adjust(value)
+	} else {
+		tracker.Set(value)
+	}
+	m.addTrackerHistory(tracker, fmt.Sprintf("[%s] %d/%d (%+d)", tracker.Name, tracker.Current, tracker.Max, tracker.Current-before))
+	m.checkDamage(tracker, before)
+}
+
+// clickTracker focuses the pinned tracker under a mouse click, if any
+func (m *Model) clickTracker(x, y int) {
+	pinned := m.numberTrackerManager.GetPinned()
+	if len(pinned) == 0 || y != m.trackerBarRow() {
+		return
+	}
+	index := x / (m.trackerSlotWidth(len(pinned)) + 3) // 3 for " | "
+	if index >= len(pinned) {
+		return
+	}
+	m.focusedTracker = pinned[index].Name
+	m.trackerEntry = ""
+	m.textInput.Blur()
+}
@@ -0,0 +1,53 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/angusmclean/tavernshell/core/dice"
+)
+
+// handleForce queues die results for the next dice rolled, for demos and
+// screenshots (it isn't listed in the help)
+// Usage: force <value>..., force clear, or force to see what's queued
+func (m *Model) handleForce(args []string) {
+	if len(args) == 0 {
+		queued := dice.Forced()
+		if len(queued) == 0 {
+			m.addHistory("No forced dice queued (use 'force 20' to make the next die a 20)")
+			return
+		}
+		m.addHistory(fmt.Sprintf("Forced dice queued: %s", joinInts(queued)))
+		return
+	}
+	if strings.EqualFold(args[0], "clear") {
+		dice.ClearForced()
+		m.addHistory("Cleared forced dice")
+		return
+	}
+
+	var values []int
+	for _, arg := range strings.FieldsFunc(strings.Join(args, " "), func(r rune) bool { return r == ' ' || r == ',' }) {
+		v, err := strconv.Atoi(arg)
+		if err != nil {
+			m.addHistory(fmt.Sprintf("Error: '%s' isn't a die result", arg))
+			return
+		}
+		values = append(values, v)
+	}
+	if err := dice.Force(values...); err != nil {
+		m.addHistory(fmt.Sprintf("Error: %s", err))
+		return
+	}
+	m.addHistory(fmt.Sprintf("Forced dice queued: %s (used by the next dice rolled)", joinInts(dice.Forced())))
+}
+
+// joinInts lists numbers as "20, 1"
+func joinInts(values []int) string {
+	strs := make([]string, len(values))
+	for i, v := range values {
+		strs[i] = strconv.Itoa(v)
+	}
+	return strings.Join(strs, ", ")
+}
@@ -0,0 +1,42 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/angusmclean/tavernshell/core/dice"
+)
+
+// handleGurps rolls a GURPS-style success roll: 3d6 at or under a skill, with
+// the margin and the critical thresholds at that skill
+// Usage: gurps <skill>[+/-modifiers] [# label]
+func (m *Model) handleGurps(args []string) {
+	if len(args) == 0 {
+		m.addHistory("Usage: gurps <skill> - Roll 3d6 under a skill, with modifiers (e.g., 'gurps 14', 'gurps 12-2 # Stealth')")
+		return
+	}
+	input, label := dice.SplitLabel(strings.Join(args, " "))
+	skill, err := dice.ParseSkill(input)
+	if err != nil {
+		m.addHistory(fmt.Sprintf("Error: %s", err))
+		return
+	}
+
+	// Environmental effects on the label shift the skill; 3d6 has no advantage
+	adj := m.environmentManager.For(label)
+	skill += adj.Amount
+
+	roll, err := dice.RollAgainst(skill)
+	if err != nil {
+		m.addHistory(fmt.Sprintf("Error: %s", err))
+		return
+	}
+	roll.Result.Label = label
+	m.recordRoll(roll.Result)
+	m.addHistory(fmt.Sprintf("🎲 %s → %s", m.formatDiceResult(roll.Result), formatDegree(roll.Degree(), roll.String())))
+	m.addRollDetail(roll.Result)
+	m.addHistory("  " + dice.RollUnderThresholds(skill))
+	if adj.Amount != 0 {
+		m.addHistory(fmt.Sprintf("  🌦 %+d (%s)", adj.Amount, strings.Join(adj.Sources, ", ")))
+	}
+}
@@ -0,0 +1,147 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/angusmclean/tavernshell/core/history"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// History categories, used to filter exports
+const (
+	categorySystem     = "system"
+	categoryRoll       = "roll"
+	categoryAlarm      = "alarm"
+	categoryInitiative = "initiative"
+	categoryTracker    = "tracker"
+	categoryTable      = "table"
+	categoryBuff       = "buff"
+)
+
+// historyCategories lists every category in display order
+var historyCategories = []string{
+	categoryRoll, categoryAlarm, categoryInitiative, categoryTracker, categoryTable, categoryBuff, categorySystem,
+}
+
+// historyFilter selects which history entries to export
+type historyFilter struct {
+	Since      time.Time       // zero means no time limit
+	Categories map[string]bool // nil means all categories
+	Private    bool            // include DM-private (whispered) entries
+}
+
+// matches returns true if the entry passes the filter
+func (f historyFilter) matches(e history.Entry) bool {
+	if !f.Since.IsZero() && e.Time.Before(f.Since) {
+		return false
+	}
+	if e.Private && !f.Private {
+		return false
+	}
+	return f.Categories == nil || f.Categories[e.Category]
+}
+
+// parseCategory maps user input like "rolls" or "init" to a history category
+func parseCategory(name string) (string, error) {
+	name = strings.ToLower(name)
+	for _, c := range historyCategories {
+		if name == c || name == c+"s" || (len(name) >= 3 && strings.HasPrefix(c, name)) {
+			return c, nil
+		}
+	}
+	return "", fmt.Errorf("unknown category '%s' (expected one of: %s)", name, strings.Join(historyCategories, ", "))
+}
+
+// handleExport processes the export command
+// Usage: export [--since <duration>] [--only <category,...>] [--private] <file>
+// Whispered (DM-private) lines are left out unless --private is given
+func (m *Model) handleExport(args []string) {
+	usage := "Usage: export [--since 1h] [--only rolls,initiative] [--private] <file>"
+
+	filter := historyFilter{}
+	path := ""
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--since":
+			if i+1 >= len(args) {
+				m.addHistory(usage)
+				return
+			}
+			i++
+			d, err := time.ParseDuration(strings.Trim(args[i], `"'`))
+			if err != nil || d <= 0 {
+				m.addHistory(fmt.Sprintf("Error: invalid duration '%s' (use format like: 30m, 1h, 2h30m)", args[i]))
+				return
+			}
+			filter.Since = time.Now().Add(-d)
+
+		case "--only":
+			if i+1 >= len(args) {
+				m.addHistory(usage)
+				return
+			}
+			i++
+			filter.Categories = make(map[string]bool)
+			for _, name := range strings.Split(args[i], ",") {
+				category, err := parseCategory(name)
+				if err != nil {
+					m.addHistory(fmt.Sprintf("Error: %s", err))
+					return
+				}
+				filter.Categories[category] = true
+			}
+
+		case "--private":
+			filter.Private = true
+
+		default:
+			if path != "" {
+				m.addHistory(usage)
+				return
+			}
+			path = args[i]
+		}
+	}
+	if path == "" {
+		m.addHistory(usage)
+		return
+	}
+
+	count, err := m.exportHistory(path, filter)
+	if err != nil {
+		m.addHistory(fmt.Sprintf("Error: %s", err))
+		return
+	}
+	m.addHistory(fmt.Sprintf("Exported %d line(s) to %s", count, path))
+}
+
+// exportHistory writes matching history entries to a file as timestamped plain text
+// Entries spilled to the history log are included
+func (m *Model) exportHistory(path string, filter historyFilter) (int, error) {
+	entries, err := m.history.Range(0, m.history.Total())
+	if err != nil {
+		return 0, err
+	}
+
+	var b strings.Builder
+	count := 0
+	for _, e := range entries {
+		if !filter.matches(e) {
+			continue
+		}
+		text := ansi.Strip(e.Text)
+		if e.Private {
+			text = "(whisper) " + text
+		}
+		b.WriteString(fmt.Sprintf("[%s] %s\n", e.Time.Format("15:04:05"), text))
+		count++
+	}
+
+	if err := os.WriteFile(path, []byte(b.String()), 0644); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
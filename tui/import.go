@@ -0,0 +1,62 @@
+package tui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/angusmclean/tavernshell/core/tracker/rotation"
+	"github.com/atotto/clipboard"
+)
+
+// importCSV starts initiative from CSV rows of name,initiative,hp,ac,
+// read from a file, or from the clipboard if path is ""
+func (m *Model) importCSV(path string) {
+	var r io.Reader
+	source := "clipboard"
+	if path == "" {
+		text, err := clipboard.ReadAll()
+		if err != nil {
+			m.addHistory(fmt.Sprintf("Error: failed to read clipboard: %s", err))
+			return
+		}
+		r = strings.NewReader(text)
+	} else {
+		f, err := os.Open(path)
+		if err != nil {
+			m.addHistory(fmt.Sprintf("Error: %s", err))
+			return
+		}
+		defer f.Close()
+		r = f
+		source = path
+	}
+
+	encounter, err := rotation.ParseCSV(r)
+	if err != nil {
+		m.addHistory(fmt.Sprintf("Error: %s: %s", source, err))
+		return
+	}
+	if err := m.startEncounter(encounter); err != nil {
+		m.addHistory(fmt.Sprintf("Error: %s: %s", source, err))
+		return
+	}
+	m.addHistory(fmt.Sprintf("Imported %d participants from %s. Use 'i n' to advance turns.", len(encounter.Participants), source))
+}
+
+// startEncounter starts initiative from a prepared encounter
+func (m *Model) startEncounter(encounter *rotation.Encounter) error {
+	if err := m.initiativeManager.StartEncounter(encounter); err != nil {
+		return err
+	}
+	m.initiativeEntryMode = false
+	m.numberTrackerManager.StartBaselines()
+	m.startStats(encounter.Difficulty)
+	for _, p := range m.initiativeManager.GetTracker().Participants {
+		if p.Summoner == "" {
+			m.recordInitiative(p.Name, p.Initiative)
+		}
+	}
+	return nil
+}
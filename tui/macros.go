@@ -0,0 +1,292 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/angusmclean/tavernshell/core/dice"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// handleRecord processes macro recording commands
+func (m *Model) handleRecord(args []string) {
+	if len(args) == 0 {
+		m.addHistory("Usage: record <command> - Commands: start, stop <name>, cancel")
+		return
+	}
+
+	subCmd := strings.ToLower(args[0])
+
+	switch {
+	case strings.HasPrefix("start", subCmd):
+		if err := m.macroManager.StartRecording(); err != nil {
+			m.addHistory(fmt.Sprintf("Error: %s", err))
+			return
+		}
+		m.addHistory("⏺ Recording. Commands you enter are captured until 'record stop <name>'.")
+
+	case strings.HasPrefix("stop", subCmd) && len(subCmd) >= 2:
+		if len(args) < 2 {
+			m.addHistory("Usage: record stop <name>")
+			return
+		}
+		name := strings.Join(args[1:], " ")
+		macro, err := m.macroManager.StopRecording(name)
+		if err != nil && macro == nil {
+			m.addHistory(fmt.Sprintf("Error: %s", err))
+			return
+		}
+		m.addHistory(fmt.Sprintf("Saved macro '%s' (%d commands). Run it with 'macro %s'.", macro.Name, len(macro.Commands), macro.Name))
+		if err != nil {
+			m.addHistory(fmt.Sprintf("Warning: failed to save macro file: %s", err))
+		}
+
+	case strings.HasPrefix("cancel", subCmd):
+		m.macroManager.CancelRecording()
+		m.addHistory("Recording cancelled")
+
+	default:
+		m.addHistory(fmt.Sprintf("Unknown record command: %s", subCmd))
+	}
+}
+
+// isRecordCommand returns true if command controls recording (and so isn't captured)
+func isRecordCommand(command string) bool {
+	fields := strings.Fields(strings.ToLower(command))
+	return len(fields) > 0 && strings.HasPrefix("record", fields[0]) && len(fields[0]) >= 4
+}
+
+// handleMacro processes macro commands; a bare name runs that macro
+func (m *Model) handleMacro(args []string) tea.Cmd {
+	if len(args) == 0 {
+		m.addHistory("Usage: macro <command> - Commands: run/r <name> [var=value] [flag], define <name> \"<cmd>; <cmd>\", add <name> <dice>, list/l, show <name>, delete/d <name> (or 'macro <name>' to run)")
+		return nil
+	}
+
+	subCmd := strings.ToLower(args[0])
+	name := strings.Join(args[1:], " ")
+
+	switch {
+	case strings.HasPrefix("run", subCmd) && len(args) >= 2:
+		return m.runMacro(args[1:])
+
+	case subCmd == "define" || subCmd == "def":
+		if len(args) < 3 {
+			m.addHistory("Usage: macro define <name> \"<cmd>; <cmd>\" (e.g., 'macro define attack \"r d20+5; r {if crit}4d6{else}2d6{end}+3; t adj $target -7\"')")
+			return nil
+		}
+		macro, err := m.macroManager.Define(args[1], splitCommands(strings.Join(args[2:], " ")))
+		if err != nil && macro == nil {
+			m.addHistory(fmt.Sprintf("Error: %s", err))
+			return nil
+		}
+		m.addHistory(fmt.Sprintf("Defined macro '%s' (%d commands)", macro.Name, len(macro.Commands)))
+		if vars := macro.Variables(); len(vars) > 0 {
+			m.addHistory(fmt.Sprintf("  Variables: %s (pass as 'macro %s name=value' or just 'name' for {if})", strings.Join(vars, ", "), macro.Name))
+		}
+		if err != nil {
+			m.addHistory(fmt.Sprintf("Warning: failed to save macro file: %s", err))
+		}
+
+	case subCmd == "add":
+		if len(args) < 3 {
+			m.addHistory("Usage: macro add <name> <dice> (e.g., 'macro add smite 2d8+1d6+4', then 'r smite')")
+			return nil
+		}
+		macro, err := m.macroManager.AddRoll(args[1], strings.Join(args[2:], ""))
+		if err != nil && macro == nil {
+			m.addHistory(fmt.Sprintf("Error: %s", err))
+			return nil
+		}
+		m.addHistory(fmt.Sprintf("Saved roll '%s' (%s). Roll it with 'r %s'.", macro.Name, macro.Roll, macro.Name))
+		if err != nil {
+			m.addHistory(fmt.Sprintf("Warning: failed to save macro file: %s", err))
+		}
+
+	case strings.HasPrefix("list", subCmd) && len(args) == 1:
+		macros := m.macroManager.List()
+		if len(macros) == 0 {
+			m.addHistory("No macros (use 'record start' to make one)")
+			return nil
+		}
+		m.addHistory("Macros:")
+		for _, macro := range macros {
+			m.addHistory(fmt.Sprintf("  %s: %s", macro.Name, strings.Join(macro.Steps(), "; ")))
+		}
+
+	case subCmd == "show":
+		if len(args) < 2 {
+			m.addHistory("Usage: macro show <name>")
+			return nil
+		}
+		macro := m.macroManager.Get(name)
+		if macro == nil {
+			m.addHistory(fmt.Sprintf("Error: macro '%s' not found", name))
+			return nil
+		}
+		m.addHistory(fmt.Sprintf("Macro '%s':", macro.Name))
+		for i, command := range macro.Steps() {
+			m.addHistory(fmt.Sprintf("  %d. %s", i+1, command))
+		}
+		if vars := macro.Variables(); len(vars) > 0 {
+			m.addHistory(fmt.Sprintf("  Variables: %s", strings.Join(vars, ", ")))
+		}
+
+	case strings.HasPrefix("delete", subCmd) && len(args) >= 2:
+		if err := m.macroManager.Delete(name); err != nil {
+			m.addHistory(fmt.Sprintf("Error: %s", err))
+			return nil
+		}
+		m.addHistory(fmt.Sprintf("Deleted macro '%s'", name))
+
+	default:
+		return m.runMacro(args)
+	}
+	return nil
+}
+
+// runMacro replays a macro's commands in order, echoing each one
+// args are the macro name followed by its variables: "target=Goblin" sets
+// $target, and a bare word like "crit" makes {if crit} true
+func (m *Model) runMacro(args []string) tea.Cmd {
+	// The name may have spaces, so take the longest run of args that names a macro
+	n := len(args)
+	for n > 0 && m.macroManager.Get(strings.Join(args[:n], " ")) == nil {
+		n--
+	}
+	if n == 0 {
+		m.addHistory(fmt.Sprintf("Error: macro '%s' not found", args[0]))
+		return nil
+	}
+	macro := m.macroManager.Get(strings.Join(args[:n], " "))
+
+	vars := m.storedVars() // values kept with 'store' can be passed over
+	for _, arg := range args[n:] {
+		if key, value, ok := strings.Cut(arg, "="); ok {
+			vars[key] = value
+		} else {
+			vars[arg] = "true"
+		}
+	}
+	commands, err := macro.Expand(vars)
+	if err != nil {
+		m.addHistory(fmt.Sprintf("Error: macro '%s': %s", macro.Name, err))
+		return nil
+	}
+
+	return m.playMacro(fmt.Sprintf("Macro '%s'", macro.Name), commands)
+}
+
+// playMacro runs expanded macro commands in order, echoing each one under a
+// heading (e.g. "Macro 'attack'")
+func (m *Model) playMacro(heading string, commands []string) tea.Cmd {
+	if m.playingMacro {
+		m.addHistory("Error: macros can't run other macros")
+		return nil
+	}
+
+	m.playingMacro = true
+	defer func() { m.playingMacro = false }()
+	m.addHistory("▶ " + heading)
+	for _, command := range commands {
+		m.category = categorySystem
+		m.addHistory("➤ " + command)
+		if cmd := m.handleCommand(command); cmd != nil {
+			return cmd // only quit returns a command
+		}
+	}
+	return nil
+}
+
+// parseRoll parses dice notation, or the name of a saved roll (see 'macro add')
+// Returns the saved roll's name, or "" for plain notation
+func (m *Model) parseRoll(notation string) (*dice.Expression, string, error) {
+	if bare, _ := dice.SplitLabel(notation); strings.Contains(bare, "@") {
+		return nil, "", m.unknownRef(bare)
+	}
+	expr, err := dice.Parse(notation)
+	if err == nil {
+		return expr, "", m.checkRules(expr)
+	}
+	notation, label := dice.SplitLabel(notation)
+	macro := m.macroManager.Get(notation)
+	if macro == nil || macro.Roll == "" {
+		if _, name, ok := dice.ParseCustom(notation); ok {
+			return nil, "", fmt.Errorf("custom die '%s' not found (see 'dice list')", name)
+		}
+		return nil, "", err
+	}
+	expr, rollErr := dice.Parse(macro.Roll)
+	if rollErr != nil {
+		return nil, "", fmt.Errorf("roll '%s': %w", macro.Name, rollErr)
+	}
+	if label != "" {
+		expr.Label = label
+	}
+	return expr, macro.Name, m.checkRules(expr)
+}
+
+// addRollError shows an error reading a roll; a dice notation error gets the
+// notation with a caret under the mistake
+func (m *Model) addRollError(err error) {
+	m.addHistory(fmt.Sprintf("Error: %s", err))
+	var parseErr *dice.ParseError
+	if errors.As(err, &parseErr) {
+		for _, line := range parseErr.Caret() {
+			m.addHistory("    " + line)
+		}
+	}
+}
+
+// rollLabel prefixes a roll's output with the name of the saved roll it came from
+func rollLabel(name string) string {
+	if name == "" {
+		return ""
+	}
+	return name + " → "
+}
+
+// completeMacro completes the macro name being typed after 'r' (saved rolls)
+// or 'macro' (any macro), extending it as far as the matches agree and
+// listing them when there's more than one
+func (m *Model) completeMacro() {
+	input := m.textInput.Value()
+	command, prefix, ok := strings.Cut(input, " ")
+	if !ok {
+		return
+	}
+	command = strings.ToLower(command)
+	var names []string
+	switch {
+	case command == "r" || command == "roll":
+		names = m.macroManager.Complete(prefix, true)
+	case strings.HasPrefix("macro", command) && len(command) >= 2:
+		names = m.macroManager.Complete(prefix, false)
+	}
+	if len(names) == 0 {
+		return
+	}
+
+	common := names[0]
+	for _, name := range names[1:] {
+		for !strings.HasPrefix(strings.ToLower(name), strings.ToLower(common)) {
+			common = common[:len(common)-1]
+		}
+	}
+	if len(names) == 1 {
+		common += " "
+	}
+	if len(common) > len(prefix) || len(names) == 1 {
+		m.textInput.SetValue(command + " " + common)
+		m.textInput.CursorEnd()
+	}
+	if len(names) > 1 {
+		m.addHistory(strings.Join(names, "  "))
+	}
+}
+
+// LoadMacros loads saved macros from a file, saving new ones back to it
+func (m Model) LoadMacros(path string) error {
+	return m.macroManager.Open(path)
+}
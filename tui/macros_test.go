@@ -0,0 +1,18 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMacroShowUsage(t *testing.T) {
+	m := runCommands(`macro define show "r d20"`, "macro show")
+	if !strings.HasPrefix(lastLine(m), "Usage: macro show") {
+		t.Errorf("Expected usage, got %q", lastLine(m))
+	}
+
+	m = runCommands(`macro define attack "r d20+5"`, "macro show attack")
+	if !strings.Contains(lastLine(m), "r d20+5") {
+		t.Errorf("Expected the macro's steps, got %q", lastLine(m))
+	}
+}
@@ -0,0 +1,78 @@
+package tui
+
+import "github.com/charmbracelet/lipgloss"
+
+// inputMode says how the input line is read
+type inputMode int
+
+const (
+	modeCommand   inputMode = iota // regular commands
+	modeEntry                      // initiative entry: participants until 'done'
+	modeChecklist                  // checklist: y/n answers until it finishes
+	modeResume                     // resume the last session: a y/n answer
+	modeChargen                    // character creation: answers to its prompts
+)
+
+// modePrompt is how the input line looks in a mode
+type modePrompt struct {
+	badge string          // shown before the prompt ("" for none)
+	glyph string          // prompt symbol
+	style *lipgloss.Style // prompt and badge color
+	hint  string          // shown in the status line
+}
+
+// modePrompts gives each input mode a distinct prompt, so it's clear when
+// regular commands won't work
+var modePrompts = map[inputMode]modePrompt{
+	modeCommand:   {glyph: "➤ ", style: &promptStyle},
+	modeEntry:     {badge: "ENTRY", glyph: "» ", style: &entryPromptStyle, hint: "  Entering initiative: '<name> <initiative> [hp] [ac]'  ·  'done' or Esc to finish"},
+	modeChecklist: {badge: "CHECKLIST", glyph: "? ", style: &checklistPromptStyle, hint: "  Checklist: y = done, n = skip  ·  q or Esc to stop"},
+	modeResume:    {badge: "RESUME", glyph: "? ", style: &checklistPromptStyle, hint: "  Resume last session: y = resume, n or Esc = start fresh"},
+	modeChargen:   {badge: "CHARGEN", glyph: "? ", style: &checklistPromptStyle, hint: "  Creating a character: answer the prompt above  ·  q or Esc to stop"},
+}
+
+// inputMode returns the mode the input line is in
+func (m *Model) inputMode() inputMode {
+	switch {
+	case m.resumeState != nil:
+		return modeResume
+	case m.checklistRun != nil:
+		return modeChecklist
+	case m.chargen != nil:
+		return modeChargen
+	case m.initiativeEntryMode:
+		return modeEntry
+	default:
+		return modeCommand
+	}
+}
+
+// leaveMode returns the input line to regular commands, finishing initiative
+// entry or stopping a checklist; it returns false if there was no mode to leave
+func (m *Model) leaveMode() bool {
+	switch m.inputMode() {
+	case modeResume:
+		m.answerResume("n")
+	case modeChecklist:
+		m.answerChecklist("q")
+	case modeChargen:
+		m.answerChargen("q")
+	case modeEntry:
+		m.handleEntry("done")
+	default:
+		return false
+	}
+	m.textInput.Reset()
+	m.scrollOffset = 0
+	return true
+}
+
+// renderPrompt renders the prompt (and mode badge) for the input line
+func (m *Model) renderPrompt() string {
+	prompt := modePrompts[m.inputMode()]
+	rendered := m.cache.render(prompt.style, m.glyphs(prompt.glyph))
+	if prompt.badge != "" {
+		rendered = m.cache.render(&badgeStyle, " "+prompt.badge+" ") + " " + rendered
+	}
+	return rendered
+}
@@ -1,37 +1,106 @@
 package tui
 
 import (
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
 	"time"
+	"unicode"
 
+	"github.com/angusmclean/tavernshell/core/campaign"
+	"github.com/angusmclean/tavernshell/core/checklist"
+	"github.com/angusmclean/tavernshell/core/config"
 	"github.com/angusmclean/tavernshell/core/dice"
+	"github.com/angusmclean/tavernshell/core/environment"
+	"github.com/angusmclean/tavernshell/core/history"
+	"github.com/angusmclean/tavernshell/core/macro"
+	"github.com/angusmclean/tavernshell/core/party"
+	"github.com/angusmclean/tavernshell/core/preset"
+	"github.com/angusmclean/tavernshell/core/rolllog"
+	"github.com/angusmclean/tavernshell/core/session"
+	"github.com/angusmclean/tavernshell/core/table"
+	"github.com/angusmclean/tavernshell/core/tracker/modifier"
 	"github.com/angusmclean/tavernshell/core/tracker/number"
 	"github.com/angusmclean/tavernshell/core/tracker/rotation"
 	"github.com/angusmclean/tavernshell/core/tracker/timer"
+	"github.com/angusmclean/tavernshell/core/tracker/turns"
+	"github.com/angusmclean/tavernshell/web"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 )
 
-const maxHistory = 100
+// Initiative panel text width bounds: never narrower than minInitiativeWidth,
+// and never wider than maxInitiativePercent of the terminal
+const (
+	minInitiativeWidth   = 25
+	maxInitiativePercent = 40
+)
+
+const welcomeMessage = "Welcome to TavernShell! Type 'h' for help."
 
 // tickMsg is sent every second to update timers
 type tickMsg time.Time
 
 // Model represents the TUI application state
 type Model struct {
-	textInput            textinput.Model   // text input component
-	history              []string          // command history/results (displayed output)
-	commandHistory       []string          // command history (for up/down arrow navigation)
-	historyIndex         int               // current position in command history (-1 = not navigating)
-	timerManager         *timer.Manager    // manages active timers
-	initiativeManager    *rotation.Manager // manages initiative/rotation tracker
-	numberTrackerManager *number.Manager   // manages number trackers
-	width                int               // terminal width
-	height               int               // terminal height
-	initiativeEntryMode  bool              // true when entering initiative participants
+	textInput            textinput.Model      // text input component
+	history              *history.Buffer      // command history/results (displayed output)
+	scrollOffset         int                  // lines scrolled back from the newest history entry
+	category             string               // category stamped on new history entries
+	private              bool                 // true while running a whispered (DM-only) command
+	ascii                bool                 // draw ASCII symbols instead of Unicode and emoji
+	percentileD10s       bool                 // show d100 dice as the tens and ones d10s that read them
+	commandHistory       []string             // command history (for up/down arrow navigation)
+	historyLines         int                  // how many output lines and commands are kept in memory
+	historyIndex         int                  // current position in command history (-1 = not navigating)
+	timerManager         *timer.Manager       // manages active timers
+	initiativeManager    *rotation.Manager    // manages initiative/rotation tracker
+	numberTrackerManager *number.Manager      // manages number trackers
+	modifierManager      *modifier.Manager    // manages temporary modifiers (buffs)
+	environmentManager   *environment.Manager // manages environmental effects on checks
+	tableManager         *table.Manager       // manages random tables
+	campaign             *campaign.Log        // campaign record (initiative history)
+	macroManager         *macro.Manager       // records and stores macros
+	playingMacro         bool                 // true while a macro is being replayed
+	roster               *party.Roster        // imported player characters
+	stats                *encounterStats      // measurements for the running encounter (nil without initiative)
+	shelvedStats         statsShelf           // measurements for encounters swapped out by 'i resume'
+	checklistManager     *checklist.Manager   // available checklists
+	presetManager        *preset.Manager      // spell and ability presets for 'cast'
+	checklistRun         *checklist.Run       // checklist being stepped through (nil when none)
+	chargen              *chargenRun          // character being made with 'chargen' (nil when none)
+	width                int                  // terminal width
+	height               int                  // terminal height
+	initiativeEntryMode  bool                 // true when entering initiative participants
+	focusedTracker       string               // pinned tracker with keyboard focus ("" when the input has focus)
+	trackerEntry         string               // value being typed into the focused tracker (e.g. "-7")
+	lastRoll             *dice.Result         // most recent roll result (for receipts)
+	lastRollTime         time.Time            // when lastRoll was rolled
+	rollLog              *rolllog.Log         // every roll made this session
+	customDice           *dice.CustomDice     // dice with labeled faces, defined this session
+	pendingDeath         string               // participant awaiting "mark dead? (y/n)" after massive damage ("" if none)
+	dashboard            bool                 // show the dashboard (trackers, timers, initiative, notes) instead of the history
+	compactInitiative    bool                 // initiative panel shows names and initiative only (Ctrl+T toggles)
+	verbosity            verbosity            // how much of each roll the history shows
+	stored               map[string]int       // roll totals kept with 'store', used as $name
+	publicOnly           bool                 // rendering for players (a snapshot): obscured trackers show only their condition
+	exploring            *exploration         // encounter checks on a timer (nil when not exploring)
+	delve                *turns.Delve         // dungeon turns being counted (nil when not delving)
+	watcher              *fileWatcher         // data files to reload when they change on disk
+	cache                *renderCache         // rendered segments reused between frames
+	statusTitle          bool                 // keep the terminal title set to the status line
+	recapRounds          bool                 // start each new round with a one-line recap ('i recap')
+	statusFile           string               // file to keep the status line in ("" if none)
+	disabled             config.Disabled      // subsystems turned off with --disable
+	webView              *web.Server          // read-only web view for players (nil if not serving)
+	lastStatus           string               // status line last published
+	sessionFile          string               // file the session is saved to ("" if none)
+	resumeState          *session.State       // previous session waiting for "resume? (y/n)" (nil when not asking)
+	sessionSaveFailed    bool                 // true after a failed save, so the warning isn't repeated every command
+	campaignSaveFailed   bool                 // true after a failed save of counted rolls, so the warning isn't repeated every tick
 }
 
 // NewModel creates a new TUI model
@@ -44,16 +113,42 @@ func NewModel() Model {
 	ti.Prompt = ""
 	ti.TextStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("15"))
 
-	return Model{
+	h := history.NewBuffer(config.DefaultHistoryLines)
+	h.Add(history.Entry{Time: time.Now(), Category: categorySystem, Text: welcomeMessage})
+
+	m := Model{
 		textInput:            ti,
-		history:              []string{"Welcome to TavernShell! Type 'h' for help."},
+		history:              h,
+		category:             categorySystem,
 		commandHistory:       []string{},
 		historyIndex:         -1,
 		timerManager:         timer.NewManager(),
 		initiativeManager:    rotation.NewManager(),
 		numberTrackerManager: number.NewManager(),
+		modifierManager:      modifier.NewManager(),
+		environmentManager:   environment.NewManager(),
+		tableManager:         table.NewManager(),
+		campaign:             campaign.NewLog(),
+		macroManager:         macro.NewManager(),
+		rollLog:              rolllog.NewLog(config.DefaultRollLogSize),
+		customDice:           dice.NewCustomDice(),
+		historyLines:         config.DefaultHistoryLines,
+		watcher:              newFileWatcher(),
+		roster:               party.NewRoster(),
+		checklistManager:     checklist.NewManager(),
+		presetManager:        preset.NewManager(),
 		initiativeEntryMode:  false,
+		ascii:                !unicodeSupported(),
+		recapRounds:          true,
+		cache:                newRenderCache(),
 	}
+	m.campaign.StartSession(time.Now())
+
+	// Startup health check: mention anything the terminal can't draw
+	if report := m.terminalReport(); report != "" {
+		m.addHistory(report)
+	}
+	return m
 }
 
 // Init initializes the model
@@ -78,20 +173,52 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case tickMsg:
 		// Check for expired timers
-		expired := m.timerManager.GetExpired()
-		for _, t := range expired {
-			if t.Label != "" {
-				m.addHistory(fmt.Sprintf("⏰ Alarm '%s' finished (%s)", t.Label, timer.FormatDuration(t.Duration)))
-			} else {
-				m.addHistory(fmt.Sprintf("⏰ Alarm finished (%s)", timer.FormatDuration(t.Duration)))
+		finished := m.finishTimers(m.timerManager.GetExpired())
+		m.category = categoryBuff
+		m.announceExpiredModifiers(m.modifierManager.GetExpired())
+		m.reloadChangedFiles()
+		m.flushCampaign()
+		m.publishWebView()
+		// Return another tick command to keep updating
+		return m, tea.Batch(tickCmd(), m.updateStatus(), finished)
+
+	case tea.MouseMsg:
+		// Mouse wheel scrolls history; clicking a pinned tracker focuses it
+		switch msg.Button {
+		case tea.MouseButtonWheelUp:
+			m.scrollHistory(3)
+		case tea.MouseButtonWheelDown:
+			m.scrollHistory(-3)
+		case tea.MouseButtonLeft:
+			if msg.Action == tea.MouseActionPress {
+				m.clickTracker(msg.X, msg.Y)
 			}
 		}
-		// Return another tick command to keep updating
-		return m, tickCmd()
+		return m, nil
 
 	case tea.KeyMsg:
+		// A focused tracker takes keys until focus returns to the input
+		if m.focusedTracker != "" {
+			cmd := m.handleTrackerKey(msg)
+			if cmd == nil {
+				m.saveSession()
+			}
+			return m, cmd
+		}
+
 		switch msg.Type {
-		case tea.KeyCtrlC, tea.KeyEsc:
+		case tea.KeyCtrlC:
+			return m, tea.Quit
+
+		case tea.KeyEsc:
+			// Esc leaves a prompt mode (initiative entry, checklist) before it quits
+			if m.leaveMode() {
+				return m, nil
+			}
+			if m.dashboard {
+				m.handleDashboard()
+				return m, nil
+			}
 			return m, tea.Quit
 
 		case tea.KeyEnter:
@@ -99,13 +226,17 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if input != "" {
 				// Add to command history
 				m.commandHistory = append(m.commandHistory, input)
-				if len(m.commandHistory) > maxHistory {
+				if len(m.commandHistory) > m.historyLines {
 					m.commandHistory = m.commandHistory[1:]
 				}
 
-				cmd := m.handleCommand(input)
+				cmd := m.handleBatch(input)
+				if cmd == nil {
+					m.saveSession()
+				}
 				m.textInput.Reset()
 				m.historyIndex = -1 // Reset history navigation
+				m.scrollOffset = 0  // Jump back to the newest output
 				return m, cmd
 			}
 
@@ -139,6 +270,52 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 
+		case tea.KeyTab, tea.KeyShiftTab:
+			// With an empty input, Tab moves focus to the pinned trackers;
+			// otherwise it completes macro names
+			if m.textInput.Value() == "" {
+				if msg.Type == tea.KeyTab {
+					m.focusTracker(1)
+				} else {
+					m.focusTracker(-1)
+				}
+			} else if msg.Type == tea.KeyTab {
+				m.completeMacro()
+			}
+			return m, nil
+
+		case tea.KeyRunes:
+			// A multi-line paste during initiative entry adds a participant per line
+			if msg.Paste && m.initiativeEntryMode && strings.Contains(string(msg.Runes), "\n") {
+				m.pasteEntries(string(msg.Runes))
+				m.textInput.Reset()
+				m.scrollOffset = 0
+				return m, nil
+			}
+			var cmd tea.Cmd
+			m.textInput, cmd = m.textInput.Update(msg)
+			return m, cmd
+
+		case tea.KeyCtrlT:
+			// Toggle the initiative panel between compact and detailed
+			m.compactInitiative = !m.compactInitiative
+			if m.compactInitiative {
+				m.addHistory("Initiative panel: compact (Ctrl+T for detail)")
+			} else {
+				m.addHistory("Initiative panel: detailed (Ctrl+T for compact)")
+			}
+			return m, nil
+
+		case tea.KeyPgUp:
+			// Scroll history back half a screen
+			m.scrollHistory(max(m.height/2, 1))
+			return m, nil
+
+		case tea.KeyPgDown:
+			// Scroll history forward half a screen
+			m.scrollHistory(-max(m.height/2, 1))
+			return m, nil
+
 		default:
 			// Let textinput handle all other keys (left, right, backspace, characters, etc.)
 			var cmd tea.Cmd
@@ -150,72 +327,309 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// handleBatch runs one or more commands separated by semicolons, in order
+// e.g. "t adjust HP -7; i next; a 1m lair"
+// Each command in a batch is echoed before its output; quitting stops the batch
+func (m *Model) handleBatch(input string) tea.Cmd {
+	commands := splitCommands(input)
+	for _, command := range commands {
+		// While recording, typed commands are captured for the macro
+		if m.macroManager.Recording() && !isRecordCommand(command) {
+			m.macroManager.Record(command)
+		}
+		m.category = categorySystem
+		if len(commands) > 1 {
+			m.addHistory("➤ " + command)
+		}
+		cmd := m.handleCommand(command)
+		m.snapshotNewTrackers()
+		if cmd != nil {
+			return cmd // only quit returns a command
+		}
+	}
+	return nil
+}
+
 // handleCommand processes a command and updates history
 func (m *Model) handleCommand(input string) tea.Cmd {
+	m.category = categorySystem
+
+	// "/w <command>" runs a command whose output is private to the DM
+	if rest, ok := whisper(input); ok {
+		m.private = true
+		defer func() { m.private = false }()
+		input = rest
+	}
+
+	// The resume prompt waits for an answer before anything else
+	if m.resumeState != nil {
+		m.answerResume(input)
+		return nil
+	}
+
+	// A running checklist reads answers until it finishes
+	if m.checklistRun != nil {
+		m.category = categorySystem
+		m.answerChecklist(input)
+		return nil
+	}
+
+	// Character creation reads answers until the character is made
+	if m.chargen != nil {
+		m.answerChargen(input)
+		return nil
+	}
+
 	// Special handling for initiative entry mode
 	if m.initiativeEntryMode {
-		// Check for exit commands
-		if input == "" || strings.ToLower(input) == "done" || strings.ToLower(input) == "end" {
-			m.initiativeEntryMode = false
-			m.addHistory("Initiative setup complete. Use 'i n' to advance turns.")
-			return nil
-		}
-		// Parse "name initiative" format
-		parts := strings.Fields(input)
-		if len(parts) < 2 {
-			m.addHistory("Format: <name> <initiative> (or 'done' to finish)")
-			return nil
-		}
-		initiative, err := strconv.Atoi(parts[len(parts)-1])
-		if err != nil {
-			m.addHistory("Invalid initiative value. Format: <name> <initiative>")
-			return nil
-		}
-		name := strings.Join(parts[:len(parts)-1], " ")
-		m.initiativeManager.Add(name, initiative)
-		m.addHistory(fmt.Sprintf("Added %s (initiative %d)", name, initiative))
+		m.handleEntry(input)
 		return nil
 	}
 
-	parts := strings.Fields(input)
+	parts := splitArgs(input)
+
+	// "--terse" or "--verbose" sets how much of this command's rolls is shown,
+	// and 'verbosity' goes on to set it for good
+	parts, level, ok := splitVerbosity(parts)
+	if ok {
+		if len(parts) == 0 || !isVerbosity(parts[0]) {
+			defer func(saved verbosity) { m.verbosity = saved }(m.verbosity)
+		}
+		m.verbosity = level
+	}
+
+	// "$damage" is a total kept with 'store', "@HP" a tracker's current value
+	parts = m.substituteStored(parts)
+	parts = m.substituteRefs(parts)
 	if len(parts) == 0 {
 		return nil
 	}
 
 	cmd := strings.ToLower(parts[0])
 
+	// A bare y or n answers a massive damage prompt; anything else leaves it open
+	if m.pendingDeath != "" && len(parts) == 1 {
+		switch cmd {
+		case "y", "yes":
+			m.answerDeath(true)
+			return nil
+		case "n", "no":
+			m.answerDeath(false)
+			return nil
+		}
+	}
+
+	// Commands of a subsystem turned off with --disable are refused
+	if s, ok := m.disabledFor(parts); ok {
+		m.addHistory(fmt.Sprintf("Error: '%s' is part of %s, turned off for this session (--disable %s)", parts[0], s, m.disabled))
+		return nil
+	}
+
 	// Support single-letter shortcuts
 	switch {
 	case strings.HasPrefix("roll", cmd):
+		m.category = categoryRoll
 		m.handleRoll(parts[1:])
 		return nil
 	case strings.HasPrefix("alarm", cmd) || cmd == "a":
+		m.category = categoryAlarm
 		m.handleTimer(parts[1:])
 		return nil
 	case strings.HasPrefix("initiative", cmd) || strings.HasPrefix("init", cmd) || cmd == "i":
-		m.handleInitiative(parts[1:])
-		return nil
+		m.category = categoryInitiative
+		return m.handleInitiative(parts[1:])
 	case strings.HasPrefix("tracker", cmd) || strings.HasPrefix("track", cmd) || cmd == "t":
+		m.category = categoryTracker
 		m.handleTrack(parts[1:])
 		return nil
+	case strings.HasPrefix("table", cmd) && len(cmd) >= 2:
+		m.category = categoryTable
+		m.handleTable(parts[1:])
+		return nil
+	case strings.HasPrefix("buff", cmd) && len(cmd) >= 2:
+		m.category = categoryBuff
+		m.handleBuff(parts[1:])
+		return nil
+	case cmd == "env" || cmd == "environment":
+		m.category = categoryBuff
+		m.handleEnv(parts[1:])
+		return nil
+	case strings.HasPrefix("sync", cmd) && len(cmd) >= 2:
+		m.category = categoryInitiative
+		m.handleSync()
+		return nil
+	case strings.HasPrefix("record", cmd) && len(cmd) >= 4:
+		m.handleRecord(parts[1:])
+		return nil
+	case strings.HasPrefix("macro", cmd) && len(cmd) >= 2:
+		return m.handleMacro(parts[1:])
+	case strings.HasPrefix("party", cmd) && len(cmd) >= 2:
+		m.category = categoryInitiative
+		m.handleParty(parts[1:])
+		return nil
+	case strings.HasPrefix("share", cmd) && len(cmd) >= 3:
+		m.category = categoryRoll
+		m.handleShare(parts[1:])
+		return nil
+	case strings.HasPrefix("show", cmd) && len(cmd) >= 3:
+		m.category = categoryRoll
+		m.handleShow(parts[1:])
+		return nil
+	case strings.HasPrefix("stats", cmd) && len(cmd) >= 3:
+		m.category = categoryRoll
+		m.handleStats(parts[1:])
+		return nil
+	case cmd == "sim" || cmd == "simulate":
+		m.category = categoryRoll
+		m.handleSim(parts[1:])
+		return nil
+	case cmd == "avg" || cmd == "min" || cmd == "max":
+		m.category = categoryRoll
+		m.handleFixed(cmd, parts[1:])
+		return nil
+	case cmd == "turns" || cmd == "turn":
+		m.category = categoryTable
+		return m.handleTurns(parts[1:])
+	case strings.HasPrefix("explore", cmd) && len(cmd) >= 4:
+		m.category = categoryTable
+		m.handleExplore(parts[1:])
+		return nil
+	case strings.HasPrefix("store", cmd) && len(cmd) >= 3:
+		m.category = categoryRoll
+		m.handleStore(parts[1:])
+		return nil
+	case cmd == "choose" || cmd == "pick":
+		m.category = categoryRoll
+		m.handleChoose(parts[1:])
+		return nil
+	case cmd == "flip":
+		m.category = categoryRoll
+		m.handleFlip()
+		return nil
+	case cmd == "atk" || cmd == "attack":
+		m.category = categoryRoll
+		m.handleAttack(parts[1:])
+		return nil
+	case cmd == "gurps":
+		m.category = categoryRoll
+		m.handleGurps(parts[1:])
+		return nil
+	case strings.HasPrefix("dashboard", cmd) && len(cmd) >= 4:
+		m.handleDashboard()
+		return nil
+	case strings.HasPrefix("dice", cmd) && len(cmd) >= 3:
+		m.category = categoryRoll
+		m.handleDice(parts[1:])
+		return nil
+	case strings.HasPrefix("rules", cmd) && len(cmd) >= 3:
+		m.handleRules(parts[1:])
+		return nil
+	case cmd == "force":
+		m.category = categoryRoll
+		m.handleForce(parts[1:])
+		return nil
+	case strings.HasPrefix("selftest", cmd) && len(cmd) >= 4:
+		m.category = categoryRoll
+		m.handleSelfTest(parts[1:])
+		return nil
+	case strings.HasPrefix("log", cmd) && len(cmd) >= 3:
+		m.category = categoryRoll
+		m.handleLog(parts[1:])
+		return nil
+	case strings.HasPrefix("annotate", cmd) && len(cmd) >= 3:
+		m.category = categoryRoll
+		m.handleAnnotate(parts[1:])
+		return nil
+	case strings.HasPrefix("receipt", cmd):
+		m.category = categoryRoll
+		m.handleReceipt(parts[1:])
+		return nil
+	case strings.HasPrefix("export", cmd) && len(cmd) >= 2:
+		m.handleExport(parts[1:])
+		return nil
+	case strings.HasPrefix("verbosity", cmd) && len(cmd) >= 4:
+		m.handleVerbosity(parts[1:])
+		return nil
 	case strings.HasPrefix("help", cmd):
 		m.handleHelp()
 		return nil
 	case strings.HasPrefix("quit", cmd):
 		return tea.Quit
+	case strings.HasPrefix("chargen", cmd) && len(cmd) >= 3:
+		m.handleChargen(parts[1:])
+		return nil
+	case cmd == "cast":
+		m.category = categoryRoll
+		m.handleCast(parts[1:])
+		return nil
+	case strings.HasPrefix("preset", cmd) && len(cmd) >= 3:
+		m.category = categoryRoll
+		m.handlePreset(parts[1:])
+		return nil
+	case strings.HasPrefix("checklist", cmd) && len(cmd) >= 2:
+		m.handleChecklist(parts[1:])
+		return nil
+	case strings.HasPrefix("find", cmd) && len(cmd) >= 2:
+		m.category = categorySystem
+		m.handleFind(parts[1:])
+		return nil
 	case strings.HasPrefix("clear", cmd):
-		m.history = []string{"Welcome to TavernShell! Type 'h' for help."}
+		m.history.Reset()
+		m.history.Add(history.Entry{Time: time.Now(), Category: categorySystem, Text: welcomeMessage})
 		return nil
 	default:
-		// Try to parse the entire input as a dice roll
-		expr, err := dice.Parse(input)
+		// Try to parse the entire input as a dice roll, with $name and @name
+		// filled in and any --terse taken out
+		m.category = categoryRoll
+		line := strings.Join(parts, " ")
+		unlabeled, _ := dice.SplitLabel(line)
+		if raw, _ := dice.SplitLabel(input); strings.IndexByte(raw, '@') > 0 {
+			// "d20+@STRmod"
+			if _, err := dice.Parse(unlabeled); err == nil {
+				m.handleRoll(parts)
+				return nil
+			}
+			if i := strings.IndexByte(raw, '@'); strings.ContainsAny(raw[i-1:i], "+-") && strings.Contains(unlabeled, "@") {
+				m.addRollError(m.unknownRef(unlabeled))
+				return nil
+			}
+		}
+		if notation, target := splitRoute(unlabeled); target != "" {
+			if _, err := dice.Parse(notation); err == nil {
+				m.handleRoll(parts)
+				return nil
+			}
+		}
+		if m.isCustomRoll(unlabeled) {
+			_, label := dice.SplitLabel(line)
+			m.rollCustom(unlabeled, label, 1)
+			return nil
+		}
+		if notation, _, check, _ := dice.SplitCheck(unlabeled); check {
+			if _, err := dice.Parse(notation); err == nil {
+				m.handleRoll(parts)
+				return nil
+			}
+		}
+		expr, err := dice.Parse(line)
 		if err != nil {
-			// Not a valid dice roll, show unknown command error
+			// Dice notation with a mistake a few characters in gets the
+			// parse error; anything else is an unknown command
+			var parseErr *dice.ParseError
+			if errors.As(err, &parseErr) && parseErr.Pos >= 2 {
+				m.addRollError(err)
+				return nil
+			}
 			m.addHistory(fmt.Sprintf("Unknown command: %s (type 'h' for help)", cmd))
 			return nil
 		}
+		if err := m.checkRules(expr); err != nil {
+			m.addHistory(fmt.Sprintf("Error: %s", err))
+			return nil
+		}
 
 		// It's a valid dice roll! Execute it
+		m.applyBuffs(expr, "", "")
 		result, err := dice.RollExpression(expr)
 		if err != nil {
 			m.addHistory(fmt.Sprintf("Error: %s", err))
@@ -223,26 +637,142 @@ func (m *Model) handleCommand(input string) tea.Cmd {
 		}
 
 		// Format and display the result
-		m.addHistory(fmt.Sprintf("🎲 %s", formatDiceResult(result)))
+		m.recordRoll(result)
+		m.addHistory(fmt.Sprintf("🎲 %s", m.formatDiceResult(result)))
+		m.addRollDetail(result)
 		return nil
 	}
 }
 
+// whisper strips a "/w" or "/whisper" prefix, reporting whether it was present
+func whisper(input string) (string, bool) {
+	for _, prefix := range []string{"/whisper", "/w"} {
+		if rest, ok := strings.CutPrefix(input, prefix); ok && (rest == "" || rest[0] == ' ') {
+			return strings.TrimSpace(rest), true
+		}
+	}
+	return input, false
+}
+
+// splitCommands splits input into commands on semicolons outside double quotes,
+// dropping empty commands
+func splitCommands(input string) []string {
+	var commands []string
+	start, inQuotes := 0, false
+	for i, r := range input {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+		case r == ';' && !inQuotes:
+			if command := strings.TrimSpace(input[start:i]); command != "" {
+				commands = append(commands, command)
+			}
+			start = i + 1
+		}
+	}
+	if command := strings.TrimSpace(input[start:]); command != "" {
+		commands = append(commands, command)
+	}
+	return commands
+}
+
+// splitArgs splits input on whitespace, keeping double-quoted phrases together
+// e.g. `i summon Wizard "Spirit Guardians"` -> [i summon Wizard Spirit Guardians]
+func splitArgs(input string) []string {
+	var args []string
+	var current strings.Builder
+	inQuotes, hasArg := false, false
+	for _, r := range input {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+			hasArg = true
+		case unicode.IsSpace(r) && !inQuotes:
+			if hasArg {
+				args = append(args, current.String())
+				current.Reset()
+				hasArg = false
+			}
+		default:
+			current.WriteRune(r)
+			hasArg = true
+		}
+	}
+	if hasArg {
+		args = append(args, current.String())
+	}
+	return args
+}
+
 // handleRoll processes a roll command
 func (m *Model) handleRoll(args []string) {
 	if len(args) == 0 {
-		m.addHistory("Usage: r/roll <dice> (e.g., 'r 2d6+3', 'r d20!', 'r 4d6kh3')")
+		m.addHistory("Usage: r/roll <dice> (e.g., 'r 2d6+3', 'r d20!', 'r 4d6kh3', 'r 6x4d6kh3', 'r d20+7 vs 15')")
 		return
 	}
 
-	notation := args[0]
+	// Notation may contain spaces, e.g. "2d6 – 1" pasted from a sourcebook,
+	// and end in a label: "2d6+3 # greatsword damage"
+	input, label := dice.SplitLabel(strings.Join(args, " "))
+	notation, target := splitRoute(input)
+
+	// "d20+7 vs 15" rolls against a target number
+	notation, dc, check, err := dice.SplitCheck(notation)
+	if err != nil {
+		m.addHistory(fmt.Sprintf("Error: %s", err))
+		return
+	}
+	if check && target != "" {
+		m.addHistory("Error: a roll against a target number can't also be sent to a tracker")
+		return
+	}
 
-	// Parse the expression
-	expr, err := dice.Parse(notation)
+	// "6x4d6kh3" rolls the same dice several times
+	count, notation, err := dice.SplitRepeat(notation)
 	if err != nil {
 		m.addHistory(fmt.Sprintf("Error: %s", err))
 		return
 	}
+	if m.isCustomRoll(notation) {
+		if target != "" || check {
+			m.addHistory("Error: custom dice have no total to send to a tracker or check against a target")
+			return
+		}
+		m.rollCustom(notation, label, count)
+		return
+	}
+
+	// Put the label back for parseRoll to carry into the result
+	if label != "" {
+		notation += " # " + label
+	}
+	if count > 1 {
+		if target != "" {
+			m.addHistory("Error: a repeated roll can't be sent to a tracker; roll each one with 'r <dice> -> <target>'")
+			return
+		}
+		m.rollRepeated(count, notation, dc, check)
+		return
+	}
+
+	if target != "" {
+		m.rollTo(notation, target)
+		return
+	}
+
+	// Parse the expression (or look up a saved roll)
+	expr, name, err := m.parseRoll(notation)
+	if err != nil {
+		m.addRollError(err)
+		return
+	}
+
+	// Checks take any environmental effects on them, and every roll its buffs
+	environ := ""
+	if check {
+		environ = m.applyEnvironment(expr, name)
+	}
+	m.applyBuffs(expr, name, "")
 
 	// Roll the dice
 	result, err := dice.RollExpression(expr)
@@ -252,121 +782,550 @@ func (m *Model) handleRoll(args []string) {
 	}
 
 	// Format and display the result with styling
-	m.addHistory(fmt.Sprintf("🎲 %s", formatDiceResult(result)))
+	m.recordRoll(result)
+	line := fmt.Sprintf("🎲 %s%s", rollLabel(name), m.formatDiceResult(result))
+	if check {
+		line += " → " + formatCheck(m.check(result, dc))
+	}
+	m.addHistory(line)
+	m.addRollDetail(result)
+	if environ != "" {
+		m.addHistory("  " + environ)
+	}
+}
+
+// check makes a check of a roll against a target number, with degrees of
+// success if the campaign's ruleset has them
+func (m *Model) check(result *dice.Result, dc int) dice.Check {
+	return dice.Check{Result: result, Target: dc, Degrees: m.rules().DegreesOfSuccess()}
+}
+
+// formatCheck colors a check's outcome: green for a success, red for a
+// failure, and highlighted when critical
+func formatCheck(c dice.Check) string {
+	return formatDegree(c.Degree(), c.String())
+}
+
+// formatDegree colors an outcome's description by its degree of success
+func formatDegree(d dice.Degree, text string) string {
+	switch d {
+	case dice.CriticalSuccess:
+		return critCheckStyle.Render(text)
+	case dice.Success:
+		return critStyle.Render(text)
+	case dice.CriticalFailure:
+		return fumbleCheckStyle.Render(text)
+	default:
+		return fumbleStyle.Render(text)
+	}
+}
+
+// rollRepeated rolls the same dice count times, listing each result and then
+// the total, highest and lowest (or, against a target number, how many succeeded)
+func (m *Model) rollRepeated(count int, notation string, dc int, check bool) {
+	expr, name, err := m.parseRoll(notation)
+	if err != nil {
+		m.addRollError(err)
+		return
+	}
+
+	environ := ""
+	if check {
+		environ = m.applyEnvironment(expr, name)
+	}
+	m.applyBuffs(expr, name, "")
+	m.addHistory(fmt.Sprintf("🎲 %dx %s%s:", count, rollLabel(name), expr.String()))
+	if environ != "" {
+		m.addHistory("  " + environ)
+	}
+	total, highest, lowest, successes, criticals := 0, 0, 0, 0, 0
+	for i := 1; i <= count; i++ {
+		result, err := dice.RollExpression(expr)
+		if err != nil {
+			m.addHistory(fmt.Sprintf("Error: %s", err))
+			return
+		}
+		m.recordRoll(result)
+		line := fmt.Sprintf("  %d. %s", i, m.formatDiceResult(result))
+		if check {
+			c := m.check(result, dc)
+			if c.Success() {
+				successes++
+			}
+			if c.Critical() {
+				criticals++
+			}
+			line += " → " + formatCheck(c)
+		}
+		m.addHistory(line)
+		m.addRollDetail(result)
+
+		total += result.Total
+		if i == 1 || result.Total > highest {
+			highest = result.Total
+		}
+		if i == 1 || result.Total < lowest {
+			lowest = result.Total
+		}
+	}
+	if check {
+		summary := fmt.Sprintf("  %d of %d succeeded (vs %d)", successes, count, dc)
+		if criticals > 0 {
+			summary += fmt.Sprintf(", %d critical", criticals)
+		}
+		m.addHistory(summary)
+		return
+	}
+	m.addHistory(fmt.Sprintf("  Total %d (highest %d, lowest %d)", total, highest, lowest))
+}
+
+// recordRoll remembers a roll so later commands (like receipts) can refer to it,
+// and adds it to the roll log
+func (m *Model) recordRoll(result *dice.Result) {
+	m.lastRoll = result
+	m.lastRollTime = time.Now()
+	m.rollLog.Add(result, m.lastRollTime)
+	m.countRoll(result)
+}
+
+// handleReceipt processes a receipt command
+func (m *Model) handleReceipt(args []string) {
+	if len(args) == 0 || strings.ToLower(args[0]) != "last" {
+		m.addHistory("Usage: receipt last - Show a pasteable receipt for the most recent roll")
+		return
+	}
+	if m.lastRoll == nil {
+		m.addHistory("No rolls yet")
+		return
+	}
+	for _, line := range strings.Split(m.lastRoll.Receipt(m.lastRollTime), "\n") {
+		m.addHistory(line)
+	}
 }
 
 // handleTimer processes an alarm command
 func (m *Model) handleTimer(args []string) {
 	if len(args) == 0 {
-		m.addHistory("Usage: a/alarm <duration> [name] (e.g., 'a 5m', 'a 1h concentration')")
+		m.addHistory("Usage: a/alarm <duration> [name] [--then \"<command>\"] (e.g., 'a 5m', 'a 10r Haste', 'a half an hour torch'), or a pin/unpin <name>")
 		return
 	}
 
-	durationStr := args[0]
-	duration, err := time.ParseDuration(durationStr)
-	if err != nil {
-		m.addHistory(fmt.Sprintf("Error: invalid duration '%s' (use format like: 1h, 5m, 30s, 1h30m)", durationStr))
+	switch strings.ToLower(args[0]) {
+	case "pin":
+		if len(args) < 2 {
+			m.addHistory("Usage: a pin <name> - Keep an alarm in the first slot")
+			return
+		}
+		t, err := m.timerManager.Pin(strings.Join(args[1:], " "))
+		if err != nil {
+			m.addHistory(fmt.Sprintf("Error: %s", err))
+			return
+		}
+		m.addHistory(fmt.Sprintf("📌 Pinned alarm '%s' to the first slot", t.Label))
 		return
-	}
-
-	if duration <= 0 {
-		m.addHistory("Error: duration must be positive")
+	case "unpin":
+		if t := m.timerManager.Unpin(); t != nil {
+			m.addHistory(fmt.Sprintf("Unpinned alarm '%s'", t.Label))
+		} else {
+			m.addHistory("No alarm is pinned")
+		}
 		return
 	}
 
-	// Optional label is everything after the duration
-	label := ""
-	if len(args) > 1 {
-		label = strings.Join(args[1:], " ")
+	// "--then <commands>" runs commands when the alarm finishes
+	then := ""
+	for i, arg := range args {
+		if arg == "--then" {
+			then = strings.Join(args[i+1:], " ")
+			args = args[:i]
+			if then == "" || len(args) == 0 {
+				m.addHistory("Usage: a <duration> [name] --then \"<command>; <command>\"")
+				return
+			}
+			break
+		}
 	}
 
-	newTimer := timer.NewTimer(duration, label)
+	var newTimer *timer.Timer
+	if rounds, ok := parseRounds(args[0]); ok {
+		// Round-based alarms count down as initiative rounds advance
+		newTimer = timer.NewRoundTimer(rounds, strings.Join(args[1:], " "))
+	} else {
+		// Duration may be Go syntax ("1h30m") or a phrase ("half an hour")
+		duration, used, err := timer.ParseDuration(args)
+		if err != nil {
+			m.addHistory(fmt.Sprintf("Error: %s", err))
+			return
+		}
+
+		if duration <= 0 {
+			m.addHistory("Error: duration must be positive")
+			return
+		}
+
+		// Optional label is everything after the duration
+		newTimer = timer.NewTimer(duration, strings.Join(args[used:], " "))
+	}
+	newTimer.Then = then
 	m.timerManager.Add(newTimer)
 
-	if label != "" {
-		m.addHistory(fmt.Sprintf("⏰ Started alarm '%s' for %s", label, timer.FormatDuration(duration)))
-	} else {
-		m.addHistory(fmt.Sprintf("⏰ Started alarm for %s", timer.FormatDuration(duration)))
+	line := fmt.Sprintf("⏰ Started alarm for %s", newTimer.Length())
+	if newTimer.Label != "" {
+		line = fmt.Sprintf("⏰ Started alarm '%s' for %s", newTimer.Label, newTimer.Length())
+	}
+	if then != "" {
+		line += fmt.Sprintf(", then: %s", then)
+	}
+	m.addHistory(line)
+	if newTimer.IsRoundBased() && !m.initiativeManager.IsActive() {
+		m.addHistory("It counts down as initiative rounds advance ('i start' to begin).")
+	}
+}
+
+// finishTimers announces finished alarms and runs their follow-up commands,
+// returning the commands those ask for (a 'quit', say)
+func (m *Model) finishTimers(expired []*timer.Timer) tea.Cmd {
+	var cmds []tea.Cmd
+	for _, t := range expired {
+		if m.exploring != nil && t == m.exploring.timer {
+			m.exploreCheck(false)
+			continue
+		}
+		m.category = categoryAlarm
+		if t.Label != "" {
+			m.addHistory(fmt.Sprintf("⏰ Alarm '%s' finished (%s)", t.Label, t.Length()))
+		} else {
+			m.addHistory(fmt.Sprintf("⏰ Alarm finished (%s)", t.Length()))
+		}
+		for _, command := range splitCommands(t.Then) {
+			m.category = categoryAlarm
+			m.addHistory("➤ " + command)
+			cmds = append(cmds, m.handleCommand(command))
+		}
 	}
+	return tea.Batch(cmds...)
 }
 
 // handleInitiative processes initiative commands
-func (m *Model) handleInitiative(args []string) {
+func (m *Model) handleInitiative(args []string) tea.Cmd {
 	if len(args) == 0 {
-		m.addHistory("Usage: i/init <command> - Commands: start/s, next/n, add/a, kill/k, end/e")
-		return
+		m.addHistory("Usage: i/init <command> - Commands: start/s [standard|side|popcorn|cyclic], next/n [name], add/a, used/u, summon, drop, script, kill/k, end/e, resume [n], recent, recap [on|off], difficulty <level>, snapshot <file>")
+		return nil
 	}
 
 	subCmd := strings.ToLower(args[0])
 
 	switch {
 	case strings.HasPrefix("start", subCmd) || subCmd == "s":
-		m.initiativeManager.Start()
+		mode := m.rules().InitiativeMode()
+		if len(args) > 1 {
+			var err error
+			mode, err = rotation.ParseMode(args[1])
+			if err != nil {
+				m.addHistory(fmt.Sprintf("Error: %s", err))
+				return nil
+			}
+		}
+		m.initiativeManager.StartWithMode(mode)
+		m.numberTrackerManager.StartBaselines()
+		m.startStats("")
 		m.initiativeEntryMode = true
-		m.addHistory("Starting initiative. Enter '<name> <initiative>' for each participant.")
-		m.addHistory("Type 'done' when finished.")
+		switch mode {
+		case rotation.ModeSide:
+			m.addHistory("Starting side initiative. Enter '<name> <initiative> <side>' for each participant.")
+			m.addHistory("Each side acts on its best initiative. Type 'done' when finished.")
+		case rotation.ModeCyclic:
+			m.addHistory("Starting cyclic initiative. Enter '<name> <bonus>' for each participant.")
+			m.addHistory("Everyone re-rolls d20+bonus at the top of each round. Type 'done' when finished.")
+		case rotation.ModePopcorn:
+			m.addHistory("Starting popcorn initiative. Enter '<name> <initiative>' for each participant.")
+			m.addHistory("Use 'i next <name>' to hand the turn on. Type 'done' when finished.")
+		default:
+			m.addHistory("Starting initiative. Enter '<name> <initiative> [hp] [ac]' for each participant, quoting a name with a number in it (or paste several lines).")
+			m.addHistory("Type 'done' when finished.")
+		}
 
 	case strings.HasPrefix("next", subCmd) || subCmd == "n":
 		if !m.initiativeManager.IsActive() {
 			m.addHistory("No active initiative. Use 'i start' to begin.")
-			return
+			return nil
 		}
-		m.initiativeManager.Next()
-		tracker := m.initiativeManager.GetTracker()
-		if tracker != nil {
-			current := tracker.GetCurrent()
-			if current != nil {
-				m.addHistory(fmt.Sprintf("Turn: %s (Initiative %d) - Round %d", current.Name, current.Initiative, tracker.Round))
+		round := m.currentRound()
+		if len(args) > 1 {
+			name := strings.Join(args[1:], " ")
+			if err := m.initiativeManager.NextTo(name); err != nil {
+				m.addHistory(fmt.Sprintf("Error: %s", err))
+				return nil
 			}
+		} else {
+			m.initiativeManager.Next()
+		}
+		var cmds []tea.Cmd
+		for r := round; r < m.currentRound(); r++ {
+			cmds = append(cmds, m.onNewRound())
 		}
+		if m.currentRound() > round {
+			m.announceRoundRecap()
+		}
+		m.announceTurn()
+		m.runTurnScripts()
+		return tea.Batch(cmds...)
 
 	case strings.HasPrefix("add", subCmd) || subCmd == "a":
 		if !m.initiativeManager.IsActive() {
 			m.addHistory("No active initiative. Use 'i start' to begin.")
-			return
+			return nil
 		}
 		m.initiativeEntryMode = true
-		m.addHistory("Enter '<name> <initiative>' or 'done' to finish.")
+		m.addHistory("Enter '<name> <initiative> [hp] [ac]' or 'done' to finish.")
 
 	case strings.HasPrefix("kill", subCmd) || subCmd == "k":
 		if !m.initiativeManager.IsActive() {
 			m.addHistory("No active initiative.")
-			return
+			return nil
 		}
 		if len(args) < 2 {
 			m.addHistory("Usage: i kill <name>")
-			return
+			return nil
 		}
 		name := strings.Join(args[1:], " ")
+		var summons []string
+		if tracker := m.initiativeManager.GetTracker(); tracker != nil {
+			summons = tracker.SummonsOf(name)
+		}
 		err := m.initiativeManager.MarkOut(name)
 		if err != nil {
 			m.addHistory(fmt.Sprintf("Error: %s", err))
 		} else {
 			m.addHistory(fmt.Sprintf("%s is out of combat", name))
+			if len(summons) > 0 {
+				m.addHistory(fmt.Sprintf("Dismissed: %s", strings.Join(summons, ", ")))
+			}
+		}
+
+	case strings.HasPrefix("used", subCmd):
+		if !m.initiativeManager.IsActive() {
+			m.addHistory("No active initiative.")
+			return nil
+		}
+		if len(args) < 3 {
+			m.addHistory("Usage: i used <name> <action|bonus|reaction>")
+			return nil
+		}
+		action, err := rotation.ParseAction(args[len(args)-1])
+		if err != nil {
+			m.addHistory(fmt.Sprintf("Error: %s", err))
+			return nil
+		}
+		name := strings.Join(args[1:len(args)-1], " ")
+		if err := m.initiativeManager.Use(name, action); err != nil {
+			m.addHistory(fmt.Sprintf("Error: %s", err))
+			return nil
+		}
+		m.addHistory(fmt.Sprintf("%s used their %s", name, action))
+
+	case strings.HasPrefix("summon", subCmd) && len(subCmd) >= 2:
+		if !m.initiativeManager.IsActive() {
+			m.addHistory("No active initiative. Use 'i start' to begin.")
+			return nil
+		}
+		if len(args) < 3 {
+			m.addHistory(`Usage: i summon <summoner> <name> (e.g., 'i summon Wizard "Spirit Guardians"')`)
+			return nil
+		}
+		owner := args[1]
+		name := strings.Join(args[2:], " ")
+		if err := m.initiativeManager.Summon(owner, name); err != nil {
+			m.addHistory(fmt.Sprintf("Error: %s", err))
+			return nil
+		}
+		m.addHistory(fmt.Sprintf("%s summoned %s (acts on %s's turn)", owner, name, owner))
+
+	case strings.HasPrefix("script", subCmd) && len(subCmd) >= 2:
+		m.handleScript(args[1:])
+
+	case strings.HasPrefix("drop", subCmd) && len(subCmd) >= 2:
+		if !m.initiativeManager.IsActive() {
+			m.addHistory("No active initiative.")
+			return nil
+		}
+		if len(args) < 2 {
+			m.addHistory("Usage: i drop <summoner> - Dismiss everything they summoned (e.g., concentration lost)")
+			return nil
+		}
+		owner := strings.Join(args[1:], " ")
+		dismissed := m.initiativeManager.Dismiss(owner)
+		if len(dismissed) == 0 {
+			m.addHistory(fmt.Sprintf("%s has nothing summoned", owner))
+			return nil
+		}
+		m.addHistory(fmt.Sprintf("%s dropped: %s", owner, strings.Join(dismissed, ", ")))
+
+	case strings.HasPrefix("resume", subCmd) && len(subCmd) >= 3:
+		n := 1
+		if len(args) > 1 {
+			var err error
+			if n, err = strconv.Atoi(args[1]); err != nil {
+				m.addHistory("Usage: i resume [n] (see 'i recent')")
+				return nil
+			}
+		}
+		shelved := m.initiativeManager.GetTracker()
+		tracker, err := m.initiativeManager.Resume(n)
+		if err != nil {
+			m.addHistory(fmt.Sprintf("Error: %s", err))
+			return nil
+		}
+		m.initiativeEntryMode = false
+		m.numberTrackerManager.StartBaselines()
+		m.resumeStats(shelved, tracker)
+		m.addHistory(fmt.Sprintf("Resumed initiative: %s", trackerSummary(tracker)))
+		m.announceTurn()
+
+	case strings.HasPrefix("difficulty", subCmd) && len(subCmd) >= 2:
+		if len(args) < 2 {
+			m.addHistory("Usage: i difficulty <easy|medium|hard|deadly> - What the encounter was built as, rated at 'i end'")
+			return nil
+		}
+		m.setDifficulty(args[1])
+
+	case strings.HasPrefix("import", subCmd) && len(subCmd) >= 3:
+		if len(args) < 2 || strings.ToLower(args[1]) != "csv" {
+			m.addHistory("Usage: i import csv [file] - Rows of name,initiative,hp,ac (from the clipboard if no file)")
+			return nil
+		}
+		m.importCSV(strings.Join(args[2:], " "))
+
+	case strings.HasPrefix("snapshot", subCmd) && len(subCmd) >= 2:
+		plain := len(args) > 1 && args[1] == "--plain"
+		if plain {
+			args = args[1:]
+		}
+		if len(args) < 2 {
+			m.addHistory("Usage: i snapshot [--plain] <file> - Save the initiative panel to share with players (--plain for no colors)")
+			return nil
+		}
+		path := strings.Join(args[1:], " ")
+		if err := m.snapshotInitiative(path, plain); err != nil {
+			m.addHistory(fmt.Sprintf("Error: %s", err))
+			return nil
+		}
+		m.addHistory(fmt.Sprintf("📸 Saved the initiative panel to %s", path))
+
+	case subCmd == "recap":
+		m.handleRecap(args[1:])
+
+	case strings.HasPrefix("recent", subCmd) && len(subCmd) >= 3:
+		recent := m.initiativeManager.Recent()
+		if len(recent) == 0 {
+			m.addHistory("No recent initiative")
+			return nil
+		}
+		m.addHistory("Recent initiative (use 'i resume <n>'):")
+		for i, t := range recent {
+			m.addHistory(fmt.Sprintf("  %d. %s", i+1, trackerSummary(t)))
 		}
 
 	case strings.HasPrefix("end", subCmd) || subCmd == "e":
+		m.finishStats()
 		m.initiativeManager.End()
-		m.addHistory("Initiative ended.")
+		m.numberTrackerManager.EndBaselines()
+		m.addHistory("Initiative ended. Use 'i resume' to bring it back.")
 
 	default:
 		m.addHistory(fmt.Sprintf("Unknown initiative command: %s", subCmd))
 	}
+	return nil
+}
+
+// trackerSummary describes an initiative tracker in one line, e.g. "Round 3: Aria, Goblin"
+func trackerSummary(t *rotation.Tracker) string {
+	var names []string
+	for _, p := range t.Participants {
+		if p.Summoner == "" {
+			names = append(names, p.Name)
+		}
+	}
+	summary := fmt.Sprintf("Round %d", t.Round)
+	if t.Mode != rotation.ModeStandard {
+		summary += fmt.Sprintf(" (%s)", t.Mode)
+	}
+	return summary + ": " + strings.Join(names, ", ")
+}
+
+// currentRound returns the current initiative round (0 if there is none)
+func (m *Model) currentRound() int {
+	tracker := m.initiativeManager.GetTracker()
+	if tracker == nil {
+		return 0
+	}
+	return tracker.Round
+}
+
+// onNewRound runs bookkeeping that happens at the top of each round,
+// returning the commands finished alarms ask for
+func (m *Model) onNewRound() tea.Cmd {
+	m.closeRound()
+	m.announceExpiredModifiers(m.modifierManager.AdvanceRound())
+	finished := m.finishTimers(m.timerManager.AdvanceRound())
+
+	if tracker := m.initiativeManager.GetTracker(); tracker != nil && tracker.Mode == rotation.ModeCyclic {
+		var order []string
+		for _, p := range tracker.Participants {
+			if p.IsActive && p.Summoner == "" {
+				order = append(order, fmt.Sprintf("%s %d", p.Name, p.Initiative))
+			}
+		}
+		m.addHistory(fmt.Sprintf("🎲 Initiative re-rolled for round %d: %s", tracker.Round, strings.Join(order, ", ")))
+	}
+	return finished
+}
+
+// announceTurn adds the current turn to history
+func (m *Model) announceTurn() {
+	tracker := m.initiativeManager.GetTracker()
+	if tracker == nil {
+		return
+	}
+	current := tracker.GetCurrent()
+	if current == nil {
+		return
+	}
+
+	if side := tracker.CurrentSide(); side != "" {
+		var members []string
+		for _, p := range tracker.Participants {
+			if p.IsActive && p.Side == side {
+				members = append(members, m.colorName(p.Name))
+			}
+		}
+		m.addHistory(fmt.Sprintf("Turn: %s (%s) - Round %d", side, strings.Join(members, ", "), tracker.Round))
+		return
+	}
+	m.addHistory(fmt.Sprintf("Turn: %s (Initiative %d) - Round %d", m.colorName(current.Name), current.Initiative, tracker.Round))
 }
 
 // handleTrack processes tracker commands
 func (m *Model) handleTrack(args []string) {
 	if len(args) == 0 {
-		m.addHistory("Usage: t/tracker <command> - Commands: add/a, set/s, adjust/adj, list/l, pin/p, unpin/u, pinall/pa, delete/d, deleteall/da, search/f")
+		m.addHistory("Usage: t/tracker <command> - Commands: add/a, set/s, adjust/adj, list/l, pin/p, unpin/u, pinall/pa, delete/d, deleteall/da, search/f, obscure, reveal")
 		return
 	}
 
 	subCmd := strings.ToLower(args[0])
 
 	switch {
+	case (strings.HasPrefix("add", subCmd) || subCmd == "a") && len(args) >= 3 && strings.HasPrefix(args[2], "="):
+		tracker, err := m.numberTrackerManager.Derive(args[1], strings.TrimPrefix(strings.Join(args[2:], " "), "="))
+		if err != nil {
+			m.addHistory(fmt.Sprintf("Error: %s", err))
+			return
+		}
+		m.addHistory(fmt.Sprintf("Added derived tracker: %s (updates with its sources)", tracker))
+
 	case strings.HasPrefix("add", subCmd) || subCmd == "a":
 		if len(args) < 4 {
-			m.addHistory("Usage: track add <name> <current> <max>")
+			m.addHistory("Usage: track add <name> <current> <max> (or 'track add <name> = <formula>', e.g. 'track add Carry = STRx15')")
 			return
 		}
 		name := args[1]
@@ -381,41 +1340,17 @@ func (m *Model) handleTrack(args []string) {
 
 	case strings.HasPrefix("set", subCmd) || subCmd == "s":
 		if len(args) < 3 {
-			m.addHistory("Usage: track set <name> <value>")
-			return
-		}
-		name := args[1]
-		value, err := strconv.Atoi(args[2])
-		if err != nil {
-			m.addHistory("Error: value must be a number")
+			m.addHistory("Usage: track set <name> <value> (e.g., '30', or '50%' of max; the name may be a pattern like 'Goblin*' or 'party')")
 			return
 		}
-		tracker := m.numberTrackerManager.Get(name)
-		if tracker == nil {
-			m.addHistory(fmt.Sprintf("Tracker '%s' not found", name))
-			return
-		}
-		tracker.Set(value)
-		m.addHistory(fmt.Sprintf("[%s] %d/%d", tracker.Name, tracker.Current, tracker.Max))
+		m.changeTrackers(args[1], args[2], false)
 
 	case strings.HasPrefix("adjust", subCmd) || subCmd == "adj":
 		if len(args) < 3 {
-			m.addHistory("Usage: track adjust <name> <delta> (e.g., '+5' or '-10')")
-			return
-		}
-		name := args[1]
-		delta, err := strconv.Atoi(args[2])
-		if err != nil {
-			m.addHistory("Error: delta must be a number (e.g., +5 or -10)")
-			return
-		}
-		tracker := m.numberTrackerManager.Get(name)
-		if tracker == nil {
-			m.addHistory(fmt.Sprintf("Tracker '%s' not found", name))
+			m.addHistory("Usage: track adjust <name> <delta> (e.g., '+5', '-10' or '+25%' of max; the name may be a pattern like 'Goblin*' or 'party')")
 			return
 		}
-		tracker.Adjust(delta)
-		m.addHistory(fmt.Sprintf("[%s] %d/%d", tracker.Name, tracker.Current, tracker.Max))
+		m.changeTrackers(args[1], args[2], true)
 
 	case strings.HasPrefix("list", subCmd) || subCmd == "l":
 		trackers := m.numberTrackerManager.List()
@@ -425,11 +1360,7 @@ func (m *Model) handleTrack(args []string) {
 		}
 		m.addHistory("Trackers:")
 		for _, t := range trackers {
-			pinned := ""
-			if t.Pinned {
-				pinned = " (pinned)"
-			}
-			m.addHistory(fmt.Sprintf("  [%s] %d/%d%s", t.Name, t.Current, t.Max, pinned))
+			m.addTrackerHistory(t, "  "+trackerListing(t))
 		}
 
 	case strings.HasPrefix("pin", subCmd) || subCmd == "p":
@@ -460,6 +1391,25 @@ func (m *Model) handleTrack(args []string) {
 		tracker.Unpin()
 		m.addHistory(fmt.Sprintf("Unpinned [%s]", tracker.Name))
 
+	case strings.HasPrefix("obscure", subCmd) || strings.HasPrefix("reveal", subCmd):
+		if len(args) < 2 {
+			m.addHistory(fmt.Sprintf("Usage: track %s <name>", subCmd))
+			return
+		}
+		name := args[1]
+		tracker := m.numberTrackerManager.Get(name)
+		if tracker == nil {
+			m.addHistory(fmt.Sprintf("Tracker '%s' not found", name))
+			return
+		}
+		if strings.HasPrefix("obscure", subCmd) {
+			tracker.Obscure()
+			m.addHistory(fmt.Sprintf("Obscured [%s]: players will only see %s", tracker.Name, tracker.PublicString()))
+		} else {
+			tracker.Reveal()
+			m.addHistory(fmt.Sprintf("Revealed [%s]", tracker.Name))
+		}
+
 	case strings.HasPrefix("pinall", subCmd) || subCmd == "pa":
 		count := m.numberTrackerManager.PinAll()
 		m.addHistory(fmt.Sprintf("Pinned %d tracker(s)", count))
@@ -494,11 +1444,7 @@ func (m *Model) handleTrack(args []string) {
 		}
 		m.addHistory(fmt.Sprintf("Trackers matching '%s':", pattern))
 		for _, t := range results {
-			pinned := ""
-			if t.Pinned {
-				pinned = " (pinned)"
-			}
-			m.addHistory(fmt.Sprintf("  [%s] %d/%d%s", t.Name, t.Current, t.Max, pinned))
+			m.addTrackerHistory(t, "  "+trackerListing(t))
 		}
 
 	default:
@@ -506,39 +1452,139 @@ func (m *Model) handleTrack(args []string) {
 	}
 }
 
+// trackerListing formats a tracker for 't list' and 't search'
+func trackerListing(t *number.Tracker) string {
+	line := fmt.Sprintf("[%s] %s", t.Name, t.Value())
+	if t.Pinned {
+		line += " (pinned)"
+	}
+	if t.Obscured {
+		line += fmt.Sprintf(" (obscured: %s)", t.Condition())
+	}
+	if t.IsDerived() {
+		line += fmt.Sprintf(" (= %s)", t.Formula)
+	}
+	return line
+}
+
+// checkWritable returns an error for a derived tracker, which only changes
+// with the trackers it's worked out from
+func checkWritable(t *number.Tracker) error {
+	if t.IsDerived() {
+		return fmt.Errorf("[%s] is worked out from %s and can't be changed directly", t.Name, t.Formula)
+	}
+	return nil
+}
+
+// addTrackerHistory adds a line showing a tracker's exact values
+// Lines for obscured trackers are whispered, so they stay out of player-facing output
+func (m *Model) addTrackerHistory(t *number.Tracker, line string) {
+	if t.Obscured && !m.private {
+		m.private = true
+		defer func() { m.private = false }()
+	}
+	m.addHistory(line)
+}
+
 // handleHelp shows available commands
 func (m *Model) handleHelp() {
 	help := []string{
 		"Available Commands:",
-		"  r/roll <dice>           - Roll dice with modifiers, advantage, keep/drop",
-		"  a/alarm <time> [name]   - Start a countdown alarm (e.g., 'a 5m', 'a 1h concentration')",
-		"  i/init <cmd>            - Initiative tracking (start/s, next/n, add/a, kill/k, end/e)",
+		"  r/roll <dice>           - Roll dice with modifiers, (dis)advantage, keep/drop",
+		"  <dice> vs <N>           - Roll against a target number: SUCCESS/FAILURE and the margin (or 'dcN')",
+		"  <dice> # <label>        - Label a roll so it's easy to tell apart (e.g., 'r 2d6+3 # greatsword damage')",
+		"  <dice> -> <target>      - Roll damage and subtract it from a tracker (e.g., '2d6+3 -> Goblin')",
+		"  a/alarm <time> [name]   - Start a countdown alarm (e.g., 'a 5m', 'a 1h concentration', 'a 10r Haste')",
+		"    ... --then \"<cmd>\"      - Run commands when it finishes (e.g., 'a 10r Haste --then \"t d HasteBuff\"')",
+		"  i/init <cmd>            - Initiative tracking (start/s [mode], next/n [name], add/a, used/u, summon, drop, script, kill/k, end/e, resume, import csv [file])",
 		"  t/tracker <cmd>         - Number trackers (add/a, set/s, adjust/adj, list/l, pin/p, etc.)",
 		"  h/help                  - Show this help message",
+		"  table <cmd>             - Random tables (load <file>, list/l, roll/r <name> [+N])",
+		"  explore start 10m forest - Roll on a table every 10m (or 10r); quiet results stay quiet; explore stop",
+		"  turns start [every 2] [t] - Dungeon turns: turns light Torch, turns next burns lights & checks",
+		"  buff <who> <+N> [tag] [dur] - Temporary modifier (e.g., 'buff Aria +2 attack 10r')",
+		"  env add \"<name>\" <+/-N|dis|adv> [tags] - Weather/terrain on checks by label (list, remove, clear)",
+		"  sync                    - Link initiative participants to matching trackers",
+		"  stats <dice>            - Chart the min, max, mean and spread of a roll (e.g., 'stats 4d6kh3')",
+		"  stats campaign          - Recap the campaign: sessions, combat time, damage, natural 20s by player",
+		"  sim [n] <dice> [vs N]   - Roll n times (default 100,000) for the success rate and spread",
+		"  avg/min/max <dice>      - Average (rounded down, as in 5e), minimum or maximum without rolling",
+		"  atk d20+5 vs 15 dmg 1d8 - Attack and damage: damage only on a hit, dice doubled on a crit",
+		"  gurps <skill>           - Roll 3d6 under a skill, GURPS-style, with the margin (e.g., 'gurps 12-2')",
+		"  dashboard               - Toggle a one-screen overview of trackers, timers, initiative and today's notes",
+		"  selftest dice           - Roll big samples of each die and check they're fair (chi-squared)",
+		"  rules [ruleset <name>]  - Campaign rules: 5e, osr or pf2e (also 'rules massive on|off')",
+		"  log show/clear/export   - Every roll this session (log show [n], log export rolls.csv or .json)",
+		"  annotate last \"<note>\"  - Note why the last roll mattered (shown in the log, exports and find)",
+		"  receipt last            - Show a pasteable receipt for the most recent roll",
+		"  share last / show <code> - Get a code for the last roll; 'show' displays someone else's",
+		"  export [opts] <file>    - Export history (--since 1h, --only rolls,initiative, --private)",
+		"  /w <command>            - Whisper: output is DM-only and left out of exports",
+		"  <cmd>; <cmd>; ...       - Run several commands in one go (e.g., 't adj HP -7; i n; a 1m lair')",
+		"  record start/stop <name> - Record the commands you enter as a macro",
+		"  macro <name> [var=val]  - Replay a macro (also: macro define/show/delete <name>, macro list)",
+		"  macro add <name> <dice> - Save a named roll, then 'r <name>' (Tab completes names)",
+		"  party <cmd>             - Party roster (import <file>, list/l, check/c <skill|ability save> [DC], init/i, color <name> <color>)",
+		"  chargen <name> [method] - Roll up ability scores (4d6, 3d6, heroic, array), assign them, add to the party",
+		"  checklist run <name>    - Step through reminders with y/n (e.g., 'checklist run session-start')",
+		"  cast <name> [level]     - Roll a spell or ability preset (e.g., 'cast fireball 5'; preset list, preset load <file>)",
+		"  find <text>             - Search trackers, initiative, party, macros, tables, rolls, commands and history",
 		"  c/clear                 - Clear history",
-		"  q/quit                  - Exit (or press Ctrl+C/Esc)",
+		"  q/quit                  - Exit (or press Ctrl+C/Esc; Esc first leaves initiative entry, a checklist or chargen)",
 		"",
 		"Dice Examples:",
 		"  r 2d6                   - Roll 2 six-sided dice",
 		"  r d20+5                 - Roll d20 and add 5",
 		"  r d20!                  - Roll d20 with advantage (roll twice, keep highest)",
+		"  r d20?                  - Roll d20 with disadvantage (roll twice, keep lowest)",
+		"  r d20!!!                - Triple advantage for Elven Accuracy (or d20adv3)",
 		"  r 4d6kh3                - Roll 4d6, keep highest 3",
+		"  r 2d6+1d8+3             - Combine dice groups and constants",
+		"  r 2d6ro<3               - Reroll dice under 3 once (r1 rerolls 1s until they aren't)",
+		"  r 8d6min2               - Count any die under 2 as a 2 (Elemental Adept)",
+		"  r half(8d6)             - Halve the total, rounding down (8d6/2; double(...) or *2 doubles)",
+		"  dice define hitdir N,NE,E,SE,S,SW,W,NW - Make a die with labeled faces, then 'r dhitdir'",
+		"  r d%                    - Percentile roll (d100)",
+		"  r d66                   - Digit dice: a d6 for the tens and a d6 for the ones (d88, d666 too)",
+		"  r 6x4d6kh3              - Roll 4d6kh3 six times, with a total (also 'r 3x d20+7')",
+		"  store dmg = 2d10+5      - Roll and keep the total as $dmg for later commands (store list)",
+		"  @<tracker>, @lastroll   - A tracker's current value or the last roll's total (e.g., 'r d20+@STRmod')",
+		"  choose a b c / flip     - Pick one option at random (commas for options with spaces); flip a coin",
+		"  verbosity terse|verbose - Show just roll totals, or every die itemized (--terse on one command)",
 		"",
 		"Alarm Examples:",
 		"  a 5m                    - Start a 5-minute alarm",
 		"  a 1h concentration      - Start a 1-hour alarm named 'concentration'",
+		"  a half an hour torch    - Durations can be spoken too ('90 minutes', 'an hour and a half')",
+		"  a pin concentration     - Keep 'concentration' in the first slot (a unpin to release)",
 		"",
 		"Initiative Examples:",
 		"  i start                 - Start initiative entry (or 'i s')",
 		"  i add                   - Add more participants (or 'i a')",
 		"  i next                  - Advance to next turn (or 'i n')",
+		"  Goblin 12 7 15          - (during entry) Add Goblin at initiative 12 with 7 HP (tracked) and AC 15",
+		"  \"Goblin 2\" 12 7         - (during entry) Quote a name with a number in it",
+		"  i start side            - Side initiative: enter '<name> <init> <side>'",
+		"  i start popcorn         - Popcorn initiative: 'i next Goblin' picks who goes next",
+		"  i start cyclic          - Cyclic initiative: enter '<name> <bonus>', re-rolled every round",
+		"  i summon Wizard \"Spirit Guardians\" - Add a summon that acts on Wizard's turn",
+		"  i drop Wizard           - Dismiss Wizard's summons (e.g., concentration lost)",
+		"  i used Goblin reaction  - Spend Goblin's reaction until their next turn (or 'i u')",
 		"  i kill Goblin           - Mark Goblin as out of combat (or 'i k')",
+		"  i recap off             - Stop the one-line recap at the top of each round ('i recap on' brings it back)",
 		"  i end                   - End initiative (or 'i e')",
+		"  i resume [n]            - Bring back a recently ended initiative ('i recent' lists them)",
+		"  i difficulty hard       - Note what the encounter was built as; 'i end' rates how it played",
+		"  i snapshot round.ans    - Save the initiative panel to a file for remote players (--plain: no colors)",
+		"  Ctrl+T                  - Toggle the initiative panel between detailed (HP, AC, buffs) and compact",
+		"  party init              - Roll the imported party into initiative with their HP and AC",
 		"",
 		"Tracker Examples:",
 		"  t add HP 35 45          - Create HP tracker at 35/45 (or 't a HP 35 45')",
+		"  t add Carry = STRx15    - Derived tracker, kept up to date from STR (HP/2, HP.max/2, [Aria HP]+5)",
 		"  t set HP 40             - Set HP to 40 (or 't s HP 40')",
 		"  t adjust HP -10         - Subtract 10 from HP (or 't adj HP -10')",
+		"  t adjust party +50%     - Everyone in the party regains half their max (also 't set Ogre 50%', 't adj Goblin* -7')",
 		"  t pin HP                - Pin HP to top display (or 't p HP')",
 		"  t unpin HP              - Unpin HP from display (or 't u HP')",
 		"  t list                  - List all trackers (or 't l')",
@@ -546,6 +1592,7 @@ func (m *Model) handleHelp() {
 		"  t delete HP             - Delete HP tracker (or 't d HP')",
 		"  t deleteall             - Delete all trackers (or 't da')",
 		"  t search HP             - Search for trackers (or 't f HP')",
+		"  t obscure Ogre          - Players only see Ogre as Healthy/Bloodied/Critical (t reveal to undo)",
 	}
 	for _, line := range help {
 		m.addHistory(line)
@@ -554,9 +1601,72 @@ func (m *Model) handleHelp() {
 
 // addHistory adds a line to the history
 func (m *Model) addHistory(line string) {
-	m.history = append(m.history, line)
-	if len(m.history) > maxHistory {
-		m.history = m.history[len(m.history)-maxHistory:]
+	entry := history.Entry{Time: time.Now(), Category: m.category, Text: line, Private: m.private}
+	if err := m.history.Add(entry); err != nil {
+		m.history.Add(history.Entry{Time: time.Now(), Category: categorySystem, Text: fmt.Sprintf("Warning: %s", err)})
+	}
+}
+
+// SetLimits applies memory limits: how much history and how many rolls
+// are kept, and when the history log is rotated
+func (m *Model) SetLimits(l config.Limits) error {
+	if err := l.Validate(); err != nil {
+		return err
+	}
+	m.historyLines = l.HistoryLines
+	if len(m.commandHistory) > l.HistoryLines {
+		m.commandHistory = m.commandHistory[len(m.commandHistory)-l.HistoryLines:]
+	}
+	m.rollLog.SetLimit(l.RollLogSize)
+	m.history.SetSpillLimit(l.HistoryLogRotate)
+	return m.history.Resize(l.HistoryLines)
+}
+
+// LogHistoryTo spills history that scrolls out of memory to a file,
+// so it can still be scrolled back to and exported
+func (m Model) LogHistoryTo(path string) error {
+	return m.history.SpillTo(path)
+}
+
+// LoadEncounter starts initiative from an encounter file
+func (m Model) LoadEncounter(path string) error {
+	encounter, err := rotation.LoadEncounter(path)
+	if err != nil {
+		return err
+	}
+	m.category = categoryInitiative
+	if err := m.startEncounter(encounter); err != nil {
+		return err
+	}
+	m.addHistory(fmt.Sprintf("Loaded encounter %s (%d participants). Use 'i n' to advance turns.", path, len(encounter.Participants)))
+	return nil
+}
+
+// LoadTable loads a random table file
+func (m Model) LoadTable(path string) error {
+	t, err := m.tableManager.LoadFile(path)
+	if err != nil {
+		return err
+	}
+	m.watcher.watch(path, reloadTable)
+	m.category = categoryTable
+	m.addHistory(fmt.Sprintf("Loaded table '%s' (d%d, %d entries)", t.Name, t.Sides(), len(t.Entries)))
+	return nil
+}
+
+// Close releases resources held by the model (the history log)
+func (m Model) Close() error {
+	return m.history.Close()
+}
+
+// scrollHistory scrolls the history view back (positive) or forward (negative)
+func (m *Model) scrollHistory(lines int) {
+	m.scrollOffset += lines
+	if maxOffset := m.history.Total() - 1; m.scrollOffset > maxOffset {
+		m.scrollOffset = maxOffset
+	}
+	if m.scrollOffset < 0 {
+		m.scrollOffset = 0
 	}
 }
 
@@ -564,15 +1674,6 @@ func (m *Model) addHistory(line string) {
 func (m Model) buildTimerBar() string {
 	activeTimers := m.timerManager.GetActive()
 
-	timerStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("214")).
-		Width(0) // Don't let lipgloss add extra width
-
-	emptyStyle := lipgloss.NewStyle().
-		Faint(true).
-		Foreground(lipgloss.Color("241")).
-		Width(0)
-
 	// Calculate width per timer slot
 	separatorWidth := 3                              // " | "
 	availableWidth := m.width - (2 * separatorWidth) // space for 2 separators
@@ -594,9 +1695,15 @@ func (m Model) buildTimerBar() string {
 			// Format: [T] label time [bar]
 			// Use [T] instead of emoji to avoid width issues
 			icon := "[T]"
+			if t.Pinned {
+				icon = "[P]"
+			}
 			timeStr := fmt.Sprintf("%s/%s",
 				timer.FormatDurationShort(remaining),
 				timer.FormatDurationShort(t.Duration))
+			if t.IsRoundBased() {
+				timeStr = fmt.Sprintf("%dr/%dr", t.Rounds, t.TotalRounds)
+			}
 
 			// Calculate available space
 			// icon (3) + space (1) + timeStr + space (1) + [bar]
@@ -660,19 +1767,14 @@ func (m Model) buildTimerBar() string {
 			}
 			textLen += len(timeStr) + 1 + 1 + len(bar) + 1 // space + [ + bar + ]
 
-			if textLen < slotWidth {
-				timerText += strings.Repeat(" ", slotWidth-textLen)
-			}
+			timerText += m.cache.pad(textLen, slotWidth)
 
-			parts = append(parts, timerStyle.Render(timerText))
+			parts = append(parts, m.cache.render(&timerStyle, timerText))
 		} else {
 			// Empty slot - pad to slot width
 			emptyText := "[T] (empty)"
-			textLen := len(emptyText)
-			if textLen < slotWidth {
-				emptyText += strings.Repeat(" ", slotWidth-textLen)
-			}
-			parts = append(parts, emptyStyle.Render(emptyText))
+			emptyText += m.cache.pad(len(emptyText), slotWidth)
+			parts = append(parts, m.cache.render(&emptyTimerStyle, emptyText))
 		}
 	}
 
@@ -682,28 +1784,15 @@ func (m Model) buildTimerBar() string {
 // buildTrackerBar builds a horizontal display of pinned trackers with progress bars
 func (m Model) buildTrackerBar() string {
 	pinnedTrackers := m.numberTrackerManager.GetPinned()
-	if len(pinnedTrackers) == 0 {
+	if len(pinnedTrackers) == 0 || m.disabled[config.Trackers] {
 		return ""
 	}
 
-	trackerStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("cyan")).
-		Width(0)
-
 	numTrackers := len(pinnedTrackers)
-	if numTrackers == 0 {
-		return ""
-	}
-
-	// Calculate width per tracker slot
-	separatorWidth := 3 // " | "
-	availableWidth := m.width - ((numTrackers - 1) * separatorWidth)
-	if availableWidth < 30 {
-		availableWidth = 30 // minimum width
-	}
-	slotWidth := availableWidth / numTrackers
+	slotWidth := m.trackerSlotWidth(numTrackers)
 
 	var parts []string
+	linked := m.linkedTrackers()
 
 	for _, tracker := range pinnedTrackers {
 		// Calculate percentage filled
@@ -721,17 +1810,36 @@ func (m Model) buildTrackerBar() string {
 		// Build the tracker display
 		// Format: [name] current/max [bar]
 		icon := fmt.Sprintf("[%s]", tracker.Name)
-		valueStr := fmt.Sprintf("%d/%d", tracker.Current, tracker.Max)
+		valueStr := tracker.Value()
+		if tracker.IsDerived() {
+			valueStr = "=" + valueStr // read-only
+		}
+		if delta, ok := tracker.Delta(); ok && delta != 0 {
+			// Change this encounter, e.g. "▼12" for damage taken
+			if delta < 0 {
+				valueStr += fmt.Sprintf(" ▼%d", -delta)
+			} else {
+				valueStr += fmt.Sprintf(" ▲%d", delta)
+			}
+		}
+		if spark := sparkline(tracker.History, tracker.Max); spark != "" {
+			// Recent values, so the trajectory shows at a glance
+			valueStr += " " + m.glyphs(spark)
+		}
+		focused := tracker.Name == m.focusedTracker
+		if focused {
+			valueStr += " > " + m.trackerEntry + "_"
+		}
 
 		// Calculate available space for bar
 		// icon + space + valueStr + space + [bar]
-		baseWidth := len(icon) + 1 + len(valueStr) + 1 + 2 // +2 for []
+		baseWidth := len(icon) + 1 + ansi.StringWidth(valueStr) + 1 + 2 // +2 for []
 
 		barWidth := slotWidth - baseWidth
 		if barWidth < 10 {
 			barWidth = 10
 			// If we need more space, truncate name
-			maxNameLen := slotWidth - 2 - 1 - len(valueStr) - 1 - barWidth - 2 // -2 for [], -1 for spaces
+			maxNameLen := slotWidth - 2 - 1 - ansi.StringWidth(valueStr) - 1 - barWidth - 2 // -2 for [], -1 for spaces
 			if maxNameLen < 0 {
 				maxNameLen = 0
 			}
@@ -762,20 +1870,61 @@ func (m Model) buildTrackerBar() string {
 		trackerText := fmt.Sprintf("%s %s [%s]", icon, valueStr, bar)
 
 		// Pad to slot width
-		textLen := len(icon) + 1 + len(valueStr) + 1 + 1 + len(bar) + 1 // spaces + [ + bar + ]
-		if textLen < slotWidth {
-			trackerText += strings.Repeat(" ", slotWidth-textLen)
+		textLen := len(icon) + 1 + ansi.StringWidth(valueStr) + 1 + 1 + len(bar) + 1 // spaces + [ + bar + ]
+		trackerText += m.cache.pad(textLen, slotWidth)
+
+		owner, _ := trackerOwner(tracker.Name, linked)
+		if focused {
+			parts = append(parts, m.cache.render(&focusedTrackerStyle, trackerText))
+		} else if nameStyle := m.playerStyle(owner, &trackerStyle); nameStyle != nil {
+			// A player's tracker has its name in their color
+			parts = append(parts, m.cache.render(nameStyle, icon)+m.cache.render(&trackerStyle, trackerText[len(icon):]))
+		} else {
+			parts = append(parts, m.cache.render(&trackerStyle, trackerText))
 		}
-
-		parts = append(parts, trackerStyle.Render(trackerText))
 	}
 
 	return strings.Join(parts, " | ")
 }
 
+// sparkLevels are the block heights a sparkline is drawn with, lowest first
+var sparkLevels = []rune("▁▂▃▄▅▆▇█")
+
+// sparkline draws values as a row of blocks scaled against top
+// ("" until there has been a change to show)
+func sparkline(values []int, top int) string {
+	if len(values) < 2 {
+		return ""
+	}
+	if top <= 0 {
+		for _, v := range values {
+			top = max(top, v)
+		}
+	}
+	spark := make([]rune, len(values))
+	for i, v := range values {
+		level := 0
+		if top > 0 {
+			level = v * (len(sparkLevels) - 1) / top
+		}
+		spark[i] = sparkLevels[min(max(level, 0), len(sparkLevels)-1)]
+	}
+	return string(spark)
+}
+
+// trackerSlotWidth returns the width of each slot in the tracker bar
+func (m Model) trackerSlotWidth(numTrackers int) int {
+	separatorWidth := 3 // " | "
+	availableWidth := m.width - ((numTrackers - 1) * separatorWidth)
+	if availableWidth < 30 {
+		availableWidth = 30 // minimum width
+	}
+	return availableWidth / numTrackers
+}
+
 // buildInitiativePanel builds the right-side initiative panel
 func (m Model) buildInitiativePanel() []string {
-	if !m.initiativeManager.IsActive() {
+	if !m.initiativeManager.IsActive() || m.disabled[config.Initiative] {
 		return nil
 	}
 
@@ -784,49 +1933,55 @@ func (m Model) buildInitiativePanel() []string {
 		return nil
 	}
 
-	var lines []string
+	lines := make([]string, 0, len(tracker.Participants)+2)
 
 	// Round header
-	headerStyle := lipgloss.NewStyle().
-		Bold(true).
-		Foreground(lipgloss.Color("green"))
-	lines = append(lines, headerStyle.Render(fmt.Sprintf("Round %d", tracker.Round)))
-	lines = append(lines, strings.Repeat("─", 25))
+	header := fmt.Sprintf("Round %d", tracker.Round)
+	if tracker.Mode != rotation.ModeStandard {
+		header += fmt.Sprintf(" (%s)", tracker.Mode)
+	}
+	lines = append(lines, m.cache.render(&roundHeaderStyle, header))
+	width := m.initiativeWidth()
+	lines = append(lines, strings.Repeat("─", width))
 
 	// Participants
-	currentStyle := lipgloss.NewStyle().
-		Bold(true).
-		Foreground(lipgloss.Color("yellow")).
-		Background(lipgloss.Color("236"))
-
-	activeStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("15"))
-
-	inactiveStyle := lipgloss.NewStyle().
-		Faint(true).
-		Foreground(lipgloss.Color("241"))
-
+	currentSide := tracker.CurrentSide()
+	lastSide := ""
 	for i, p := range tracker.Participants {
 		var line string
 		isCurrent := (i == tracker.CurrentTurn)
+		if tracker.Mode == rotation.ModeSide {
+			isCurrent = p.IsActive && p.Side == currentSide
+			if i == 0 || p.Side != lastSide {
+				lines = append(lines, m.cache.render(&sideStyle, fmt.Sprintf("[%s]", p.Side)))
+				lastSide = p.Side
+			}
+		}
 
-		// Format: "  Name (init)"
-		text := fmt.Sprintf("  %s (%d)", p.Name, p.Initiative)
+		// Summons are listed under their summoner and share their turn
+		text := ansi.Truncate(m.participantText(p), width, "...")
+		if p.Summoner != "" {
+			lines = append(lines, m.cache.render(&activeStyle, text))
+			continue
+		}
 
-		// Truncate if too long
-		if len(text) > 25 {
-			text = text[:22] + "..."
+		// Action economy: "A·R" means the bonus action is spent
+		if p.IsActive && p.Used != 0 {
+			text += " " + actionEconomy(p)
 		}
 
 		if !p.IsActive {
 			// Inactive/dead
-			line = inactiveStyle.Render(text + " ✗")
+			line = m.cache.render(&inactiveStyle, text+" ✗")
 		} else if isCurrent {
 			// Current turn
-			line = currentStyle.Render("▶ " + text[2:])
+			line = m.renderNamed(&currentStyle, "▶ "+text[2:], p.Name)
+		} else if p.Acted {
+			// Already acted this round (popcorn)
+			line = m.cache.render(&inactiveStyle, text+" ✓")
 		} else {
 			// Active but not current
-			line = activeStyle.Render(text)
+			line = m.renderNamed(&activeStyle, text, p.Name)
 		}
 
 		lines = append(lines, line)
@@ -835,39 +1990,143 @@ func (m Model) buildInitiativePanel() []string {
 	return lines
 }
 
+// participantText formats a participant's panel line: "  Name (init)", plus
+// (unless the panel is compact) HP and an HP bar when a tracker is linked, AC,
+// and any buffs on them; summons are shown as "  └ Name" under their summoner
+func (m Model) participantText(p *rotation.Participant) string {
+	if p.Summoner != "" {
+		return fmt.Sprintf("  └ %s", p.Name)
+	}
+	text := fmt.Sprintf("  %s (%d)", p.Name, p.Initiative)
+	if m.compactInitiative {
+		return text
+	}
+	if p.Tracker != "" {
+		if t := m.numberTrackerManager.Get(p.Tracker); t != nil && t.Obscured && m.publicOnly {
+			text += " " + t.Condition()
+		} else if t != nil {
+			text += fmt.Sprintf(" %d/%d %s", t.Current, t.Max, hpBar(t.Current, t.Max))
+		}
+	}
+	if p.AC > 0 {
+		text += fmt.Sprintf(" AC%d", p.AC)
+	}
+	var buffs []string
+	for _, mod := range m.modifierManager.List() {
+		if mod.Target != "" && strings.EqualFold(mod.Target, p.Name) && !mod.IsExpired() {
+			buff := fmt.Sprintf("%+d", mod.Amount)
+			if mod.Tag != "" {
+				buff += " " + mod.Tag
+			}
+			buffs = append(buffs, buff)
+		}
+	}
+	if len(buffs) > 0 {
+		text += " " + strings.Join(buffs, ", ")
+	}
+	return text
+}
+
+// hpBarWidth is how many cells the initiative panel's HP bars have
+const hpBarWidth = 5
+
+// hpBar draws a small bar for the initiative panel, e.g. "███░░" at 60%
+func hpBar(current, maximum int) string {
+	filled := 0
+	if maximum > 0 {
+		filled = min(max((current*hpBarWidth+maximum-1)/maximum, 0), hpBarWidth) // any HP left shows
+	}
+	return strings.Repeat("█", filled) + strings.Repeat("░", hpBarWidth-filled)
+}
+
+// initiativeWidth returns the width of the initiative panel's text column,
+// sized to the longest participant line within the panel's bounds
+func (m Model) initiativeWidth() int {
+	width := minInitiativeWidth
+	if tracker := m.initiativeManager.GetTracker(); tracker != nil {
+		for _, p := range tracker.Participants {
+			width = max(width, ansi.StringWidth(m.participantText(p)))
+		}
+	}
+	return min(width, max(minInitiativeWidth, m.width*maxInitiativePercent/100))
+}
+
+// actionEconomy renders a participant's remaining actions as "ABR", with spent ones shown as "·"
+func actionEconomy(p *rotation.Participant) string {
+	var b strings.Builder
+	for _, a := range []struct {
+		action rotation.Action
+		letter string
+	}{
+		{rotation.ActionMain, "A"},
+		{rotation.ActionBonus, "B"},
+		{rotation.ActionReaction, "R"},
+	} {
+		if p.HasUsed(a.action) {
+			b.WriteString("·")
+		} else {
+			b.WriteString(a.letter)
+		}
+	}
+	return b.String()
+}
+
+// buildUpcoming builds the "Next: A → B → C" turn-order preview for the status line
+func (m Model) buildUpcoming() string {
+	if !m.initiativeManager.IsActive() {
+		return ""
+	}
+	tracker := m.initiativeManager.GetTracker()
+	if tracker == nil {
+		return ""
+	}
+
+	upcoming := tracker.Upcoming(3)
+	if len(upcoming) == 0 {
+		return ""
+	}
+
+	names := make([]string, len(upcoming))
+	for i, p := range upcoming {
+		if tracker.Mode == rotation.ModeSide {
+			names[i] = p.Side
+		} else {
+			names[i] = p.Name
+		}
+	}
+	return "Next: " + strings.Join(names, " → ")
+}
+
 // View renders the TUI
 func (m Model) View() string {
 	if m.height == 0 {
 		return "Loading..."
 	}
+	if m.width < minTerminalWidth || m.height < minTerminalHeight {
+		return m.tooSmallView()
+	}
 
-	// Styles
-	titleStyle := lipgloss.NewStyle().
-		Bold(true).
-		Foreground(lipgloss.Color("205"))
-
-	promptStyle := lipgloss.NewStyle().
-		Bold(true).
-		Foreground(lipgloss.Color("86"))
-
-	helpStyle := lipgloss.NewStyle().
-		Faint(true).
-		Foreground(lipgloss.Color("241"))
+	// Full-width segments only change when the terminal is resized
+	m.cache.resize(m)
+	separator := m.cache.separator
 
 	// Build the title bar
-	titleBar := titleStyle.Render("⚔️  TavernShell")
+	titleBar := m.cache.render(&titleStyle, m.glyphs("⚔️  TavernShell"))
 
 	// Build timer display (horizontal)
-	timerBar := m.buildTimerBar()
+	timerBar := m.glyphs(m.buildTimerBar())
 
 	// Build tracker bar
-	trackerBar := m.buildTrackerBar()
+	trackerBar := m.glyphs(m.buildTrackerBar())
 
 	// Build initiative panel
 	initiativePanel := m.buildInitiativePanel()
+	for i, line := range initiativePanel {
+		initiativePanel[i] = m.glyphs(line)
+	}
 
-	// Calculate widths for initiative panel
-	initiativePanelWidth := 28
+	// Calculate widths for initiative panel (room for markers like " ✗" or " A·R")
+	initiativePanelWidth := m.initiativeWidth() + 5
 	hasInitiative := len(initiativePanel) > 0
 	mainWidth := m.width
 	if hasInitiative {
@@ -875,8 +2134,32 @@ func (m Model) View() string {
 	}
 
 	// Build the input line with help text
-	inputLine := promptStyle.Render("➤ ") + m.textInput.View()
-	helpText := helpStyle.Render("  Ctrl+C or 'q' to quit")
+	inputLine := m.renderPrompt() + m.textInput.View()
+	help := "  Ctrl+C or 'q' to quit"
+	if upcoming := m.buildUpcoming(); upcoming != "" {
+		help = "  " + upcoming + "  ·  Ctrl+C or 'q' to quit"
+	}
+	if m.macroManager.Recording() {
+		help = "  ⏺ Recording macro: 'record stop <name>' to save, 'record cancel' to discard"
+	}
+	if m.dashboard {
+		help = "  Dashboard: commands still work  ·  'dashboard' or Esc to close"
+	}
+	if m.pendingDeath != "" {
+		help = fmt.Sprintf("  💀 Massive damage: y marks %s dead, n leaves them unconscious", m.pendingDeath)
+	}
+	if hint := modePrompts[m.inputMode()].hint; hint != "" {
+		help = hint
+	}
+	if m.focusedTracker != "" {
+		help = fmt.Sprintf("  Editing [%s]: -7/+3 Enter to adjust, 30 Enter to set, ↑/↓ ±1, Tab next, Esc done", m.focusedTracker)
+	} else if m.scrollOffset > 0 {
+		help = fmt.Sprintf("  ↑ Scrolled back %d line(s)  ·  PgDn or Enter to return", m.scrollOffset)
+	}
+	helpText := m.cache.render(&helpStyle, m.glyphs(help))
+	if m.dashboard {
+		return m.dashboardView(titleBar, separator, inputLine, helpText)
+	}
 
 	// Calculate available height for history
 	headerLines := 2       // title + separator
@@ -884,43 +2167,71 @@ func (m Model) View() string {
 	if trackerBar != "" {
 		timerTrackerLines += 4 // blank line + separator + tracker bar + separator
 	}
+	if m.disabled[config.Timers] {
+		timerTrackerLines = 0
+		if trackerBar != "" {
+			timerTrackerLines = 2 // tracker bar + separator
+		}
+	}
 	footerLines := 2 // input + help
 	availableHeight := m.height - headerLines - timerTrackerLines - footerLines
 
-	// Get the history lines to display (most recent at bottom)
-	var historyLines []string
-	if len(m.history) > 0 {
-		start := 0
-		if len(m.history) > availableHeight {
-			start = len(m.history) - availableHeight
+	// Get the history lines to display (most recent at bottom, unless scrolled back)
+	historyLines := make([]string, 0, max(availableHeight, 0))
+	end := m.history.Total() - m.scrollOffset
+	if end < availableHeight {
+		// Scrolled to the top: show the oldest full screen
+		end = min(availableHeight, m.history.Total())
+	}
+	entries, err := m.history.Range(end-availableHeight, end)
+	if err != nil {
+		historyLines = append(historyLines, fmt.Sprintf("Error: %s", err))
+	}
+	for _, e := range entries {
+		if e.Private {
+			// Whispered lines are only for the DM's eyes
+			historyLines = append(historyLines, m.cache.render(&whisperStyle, m.glyphs("🔒 "+e.Text)))
+			continue
 		}
-		historyLines = m.history[start:]
+		historyLines = append(historyLines, m.glyphs(e.Text))
 	}
 
-	// Build the full view
+	// Build the full view, sized up front for a full screen (with room for
+	// multi-byte glyphs and styling) so the builder doesn't regrow mid-frame
 	var b strings.Builder
+	b.Grow(m.height * (m.width + 1) * 2)
 
 	// Title
 	b.WriteString(titleBar)
 	b.WriteString("\n")
-	b.WriteString(strings.Repeat("─", m.width))
-	b.WriteString("\n")
-
-	// Timer bar (always shown)
-	b.WriteString(timerBar)
+	b.WriteString(separator)
 	b.WriteString("\n")
 
-	// Tracker bar (if any pinned trackers)
-	if trackerBar != "" {
-		b.WriteString("\n")
-		b.WriteString(strings.Repeat("─", m.width))
-		b.WriteString("\n")
-		b.WriteString(trackerBar)
-		b.WriteString("\n")
-		b.WriteString(strings.Repeat("─", m.width))
-		b.WriteString("\n")
+	if m.disabled[config.Timers] {
+		// No timer bar: the tracker bar (if any pinned trackers) goes straight under the title
+		if trackerBar != "" {
+			b.WriteString(trackerBar)
+			b.WriteString("\n")
+			b.WriteString(separator)
+			b.WriteString("\n")
+		}
 	} else {
+		// Timer bar (shown unless timers are turned off)
+		b.WriteString(timerBar)
 		b.WriteString("\n")
+
+		// Tracker bar (if any pinned trackers)
+		if trackerBar != "" {
+			b.WriteString("\n")
+			b.WriteString(separator)
+			b.WriteString("\n")
+			b.WriteString(trackerBar)
+			b.WriteString("\n")
+			b.WriteString(separator)
+			b.WriteString("\n")
+		} else {
+			b.WriteString("\n")
+		}
 	}
 
 	// Main content area - split if initiative is active
@@ -955,8 +2266,6 @@ func (m Model) View() string {
 			// Pad or truncate main line to mainWidth
 			if len(mainLine) > mainWidth {
 				mainLine = mainLine[:mainWidth]
-			} else if len(mainLine) < mainWidth {
-				mainLine += strings.Repeat(" ", mainWidth-len(mainLine))
 			}
 
 			// Get initiative panel line if available
@@ -966,6 +2275,7 @@ func (m Model) View() string {
 			}
 
 			b.WriteString(mainLine)
+			b.WriteString(m.cache.pad(len(mainLine), mainWidth))
 			b.WriteString(" ")
 			b.WriteString(initLine)
 			b.WriteString("\n")
@@ -995,8 +2305,14 @@ func (m Model) View() string {
 	return b.String()
 }
 
+// SetPercentileD10s shows d100 dice as a tens and a ones d10 (e.g. 37 (30+7)),
+// the way percentile rolls are called out at the table
+func (m *Model) SetPercentileD10s(split bool) {
+	m.percentileD10s = split
+}
+
 // formatDiceResult formats a dice result with styled output for dropped dice
-func formatDiceResult(r *dice.Result) string {
+func (m *Model) formatDiceResult(r *dice.Result) string {
 	if r == nil {
 		return "<nil result>"
 	}
@@ -1005,38 +2321,39 @@ func formatDiceResult(r *dice.Result) string {
 
 	// Faint style for dropped dice
 	faintStyle := lipgloss.NewStyle().Faint(true)
+	naturals := m.rules().Naturals()
 
-	// Build the notation
-	notation := fmt.Sprintf("%dd%d", r.Expression.Count, r.Expression.Sides)
-	if r.Expression.Advantage {
-		notation += "!"
-	}
-	if r.Expression.Operation != nil {
-		notation += formatOperation(r.Expression.Operation)
-	}
-	if r.Expression.Modifier != 0 {
-		if r.Expression.Modifier > 0 {
-			notation += fmt.Sprintf("+%d", r.Expression.Modifier)
-		} else {
-			notation += fmt.Sprintf("%d", r.Expression.Modifier)
+	// Terse rolls show just the total, and any natural 20 or 1
+	if m.verbosity == verbosityTerse {
+		if r.Label != "" {
+			b.WriteString(m.colorPlayers(r.Label, &faintStyle) + ": ")
 		}
+		b.WriteString(fmt.Sprintf("%d", r.Total))
+		if r.Crit && naturals {
+			b.WriteString(" " + critStyle.Render("Critical!"))
+		}
+		if r.Fumble && naturals {
+			b.WriteString(" " + fumbleStyle.Render("Fumble!"))
+		}
+		return b.String()
 	}
 
-	b.WriteString(notation)
-	b.WriteString(": [")
+	b.WriteString(r.Expression.String())
+	if r.Label != "" {
+		b.WriteString(" " + m.colorPlayers("("+r.Label+")", &faintStyle))
+	}
+	b.WriteString(": ")
 
-	// Show all dice with styling for dropped ones
-	diceStrs := make([]string, len(r.Rolls))
-	for i, die := range r.Rolls {
-		if die.Kept {
-			diceStrs[i] = fmt.Sprintf("%d", die.Value)
+	// Show all dice with styling for dropped ones, one bracket per dice group
+	b.WriteString(formatDice(r.Rolls, faintStyle, m.percentileD10s, naturals))
+	for _, g := range r.Groups {
+		if g.Group.Negative {
+			b.WriteString(" - ")
 		} else {
-			// Use faint styling for dropped dice
-			diceStrs[i] = faintStyle.Render(fmt.Sprintf("‹%d›", die.Value))
+			b.WriteString(" + ")
 		}
+		b.WriteString(formatDice(g.Rolls, faintStyle, m.percentileD10s, naturals))
 	}
-	b.WriteString(strings.Join(diceStrs, ", "))
-	b.WriteString("]")
 
 	// Show modifier if present
 	if r.Expression.Modifier != 0 {
@@ -1047,60 +2364,63 @@ func formatDiceResult(r *dice.Result) string {
 		}
 	}
 
-	// Show total
+	// Show total, after the total before halving or doubling and any buffs
+	if note := r.ScaleNote(); note != "" {
+		b.WriteString(" " + faintStyle.Render("("+note+")"))
+	}
+	if r.Bonus != 0 {
+		b.WriteString(fmt.Sprintf(" %+d %s", r.Bonus, faintStyle.Render("("+r.BonusNote+")")))
+	}
 	b.WriteString(fmt.Sprintf(" = %d", r.Total))
 
 	// Add description if there are dropped dice
-	hasDropped := false
-	for _, die := range r.Rolls {
-		if !die.Kept {
-			hasDropped = true
-			break
-		}
+	if notes := r.DropNotes(); len(notes) > 0 {
+		b.WriteString(" ")
+		b.WriteString(faintStyle.Render("(" + strings.Join(notes, "; ") + ")"))
 	}
 
-	if hasDropped {
-		b.WriteString(" ")
-		b.WriteString(faintStyle.Render("("))
-		if r.Expression.Advantage {
-			b.WriteString(faintStyle.Render("advantage"))
-		} else if r.Expression.Operation != nil {
-			b.WriteString(faintStyle.Render(formatOperationDescription(r.Expression.Operation)))
-		}
-		b.WriteString(faintStyle.Render(")"))
+	// Call out natural 20s and 1s, if the ruleset does
+	if r.Crit && naturals {
+		b.WriteString(" " + critStyle.Render("Critical!"))
+	}
+	if r.Fumble && naturals {
+		b.WriteString(" " + fumbleStyle.Render("Fumble!"))
 	}
 
 	return b.String()
 }
 
-// formatOperation formats an operation for display in notation
-func formatOperation(op *dice.Operation) string {
-	switch op.Type {
-	case dice.OpKeepHighest:
-		return fmt.Sprintf("kh%d", op.Count)
-	case dice.OpKeepLowest:
-		return fmt.Sprintf("kl%d", op.Count)
-	case dice.OpDropHighest:
-		return fmt.Sprintf("dh%d", op.Count)
-	case dice.OpDropLowest:
-		return fmt.Sprintf("dl%d", op.Count)
-	default:
-		return ""
-	}
-}
-
-// formatOperationDescription returns a human-readable description of an operation
-func formatOperationDescription(op *dice.Operation) string {
-	switch op.Type {
-	case dice.OpKeepHighest:
-		return fmt.Sprintf("kept highest %d", op.Count)
-	case dice.OpKeepLowest:
-		return fmt.Sprintf("kept lowest %d", op.Count)
-	case dice.OpDropHighest:
-		return fmt.Sprintf("dropped highest %d", op.Count)
-	case dice.OpDropLowest:
-		return fmt.Sprintf("dropped lowest %d", op.Count)
-	default:
-		return ""
+// formatDice formats one group's dice in brackets, with dropped dice faint,
+// rerolled dice struck through, and natural 20s and 1s on kept d20s in green
+// and red; digit dice (d66) show the dice read as their digits, and with
+// d10s, d100 dice also show their tens and ones dice
+func formatDice(rolls []dice.Die, faintStyle lipgloss.Style, d10s, naturals bool) string {
+	diceStrs := make([]string, len(rolls))
+	for i, die := range rolls {
+		value := die.ValueString()
+		if d10s && die.Sides == 100 {
+			tens, ones := dice.PercentileDice(die.Value)
+			value = fmt.Sprintf("%d (%02d+%d)", die.Value, tens, ones)
+		}
+		// A die raised by a minimum shows what it rolled, faintly
+		raisedFrom := ""
+		if die.Original != 0 {
+			raisedFrom = faintStyle.Render(fmt.Sprintf("%d→", die.Original))
+		}
+		switch {
+		case naturals && die.Kept && die.Sides == 20 && die.Natural() == 20:
+			diceStrs[i] = critStyle.Render(value)
+		case naturals && die.Kept && die.Sides == 20 && die.Natural() == 1:
+			diceStrs[i] = fumbleStyle.Render(value)
+		case die.Kept:
+			diceStrs[i] = value
+		case die.Rerolled:
+			diceStrs[i] = faintStyle.Strikethrough(true).Render("‹" + value + "›")
+		default:
+			// Use faint styling for dropped dice
+			diceStrs[i] = faintStyle.Render("‹" + value + "›")
+		}
+		diceStrs[i] = raisedFrom + diceStrs[i]
 	}
+	return "[" + strings.Join(diceStrs, ", ") + "]"
 }
@@ -0,0 +1,179 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/angusmclean/tavernshell/core/dice"
+	"github.com/angusmclean/tavernshell/core/tracker/modifier"
+)
+
+// handleBuff processes temporary modifier commands
+func (m *Model) handleBuff(args []string) {
+	if len(args) == 0 {
+		m.addHistory("Usage: buff <target> <+/-N> [tag] [10r|1m] - Commands: list/l, remove/rm <target> [tag], clear")
+		return
+	}
+
+	switch strings.ToLower(args[0]) {
+	case "list", "l":
+		mods := m.modifierManager.List()
+		if len(mods) == 0 {
+			m.addHistory("No active modifiers")
+			return
+		}
+		m.addHistory("Modifiers:")
+		for _, mod := range mods {
+			m.addHistory("  " + mod.String())
+		}
+		return
+
+	case "remove", "rm":
+		if len(args) < 2 {
+			m.addHistory("Usage: buff remove <target> [tag]")
+			return
+		}
+		tag := ""
+		if len(args) > 2 {
+			tag = args[2]
+		}
+		removed := m.modifierManager.Remove(modifierTarget(args[1]), tag)
+		if removed == 0 {
+			m.addHistory(fmt.Sprintf("No modifiers on '%s'", args[1]))
+			return
+		}
+		m.addHistory(fmt.Sprintf("Removed %d modifier(s) from %s", removed, args[1]))
+		return
+
+	case "clear":
+		m.modifierManager.Clear()
+		m.addHistory("Cleared all modifiers")
+		return
+	}
+
+	if len(args) < 2 {
+		m.addHistory("Usage: buff <target> <+/-N> [tag] [10r|1m] (e.g., 'buff Aria +2 attack 10r')")
+		return
+	}
+
+	amount, err := strconv.Atoi(args[1])
+	if err != nil {
+		m.addHistory("Error: amount must be a number (e.g., +2 or -1)")
+		return
+	}
+
+	mod := modifier.NewModifier(modifierTarget(args[0]), amount, "")
+	for _, arg := range args[2:] {
+		if rounds, ok := parseRounds(arg); ok {
+			mod.ForRounds(rounds)
+		} else if d, err := time.ParseDuration(arg); err == nil && d > 0 {
+			mod.ForDuration(d)
+		} else if mod.Tag == "" {
+			mod.Tag = arg
+		} else {
+			m.addHistory(fmt.Sprintf("Error: unexpected '%s' (use a single tag and a duration like 10r or 1m)", arg))
+			return
+		}
+	}
+
+	m.modifierManager.Add(mod)
+	m.addHistory(fmt.Sprintf("✨ Added modifier: %s", mod))
+}
+
+// modifierTarget maps "all" or "*" to an untargeted modifier
+func modifierTarget(name string) string {
+	if name == "*" || strings.EqualFold(name, "all") {
+		return ""
+	}
+	return name
+}
+
+// parseRounds parses a round count like "10r"
+func parseRounds(s string) (int, bool) {
+	if !strings.HasSuffix(strings.ToLower(s), "r") {
+		return 0, false
+	}
+	rounds, err := strconv.Atoi(s[:len(s)-1])
+	if err != nil || rounds <= 0 {
+		return 0, false
+	}
+	return rounds, true
+}
+
+// announceExpiredModifiers adds expiry notices for modifiers to history
+func (m *Model) announceExpiredModifiers(expired []*modifier.Modifier) {
+	for _, mod := range expired {
+		m.addHistory(fmt.Sprintf("⌛ Modifier expired: %s", mod))
+	}
+}
+
+// applyBuffs adds the buffs on a roll to it, as a bonus outside its notation
+// that the result shows with where it came from. Who rolled is a buffed name
+// the label mentions ("# Aria perception"), or whoever's turn it is; the
+// kind of roll is the tag the label mentions, unless kind names it
+// ("attack" for atk). Buffs without a tag are for rolls, checks and attacks,
+// so damage and stored rolls only take buffs tagged for them
+func (m *Model) applyBuffs(expr *dice.Expression, name, kind string) {
+	text := expr.Label + " " + name
+	actor, tag := "", kind
+	if kind == "store" {
+		tag = ""
+	}
+	for _, mod := range m.modifierManager.List() {
+		if actor == "" && mod.Target != "" && mentions(text, mod.Target) {
+			actor = mod.Target
+		}
+		if tag == "" && mod.Tag != "" && mentions(text, mod.Tag) {
+			tag = mod.Tag
+		}
+	}
+	if actor == "" {
+		if tracker := m.initiativeManager.GetTracker(); tracker != nil && !m.initiativeEntryMode {
+			if current := tracker.GetCurrent(); current != nil {
+				actor = current.Name
+			}
+		}
+	}
+
+	untagged := kind == "" || kind == "attack"
+	var sources []string
+	for _, mod := range m.modifierManager.For(actor, tag) {
+		if mod.Tag == "" && !untagged {
+			continue
+		}
+		expr.Bonus += mod.Amount
+		sources = append(sources, modifierName(mod))
+	}
+	if len(sources) > 0 {
+		expr.BonusNote = strings.Join(sources, ", ")
+	}
+}
+
+// modifierName names a modifier by who and what it applies to, e.g.
+// "Aria +2 attack" or "everyone -1"
+func modifierName(mod *modifier.Modifier) string {
+	who := mod.Target
+	if who == "" {
+		who = "everyone"
+	}
+	name := fmt.Sprintf("%s %+d", who, mod.Amount)
+	if mod.Tag != "" {
+		name += " " + mod.Tag
+	}
+	return name
+}
+
+// mentions reports whether text has name in it as a whole word or words
+// (case-insensitive)
+func mentions(text, name string) bool {
+	lower, name := strings.ToLower(text), strings.ToLower(name)
+	for i := 0; i+len(name) <= len(lower); i++ {
+		end := i + len(name)
+		if lower[i:end] == name && (i == 0 || !isWordByte(lower[i-1])) && (end == len(lower) || !isWordByte(lower[end])) {
+			return true
+		}
+	}
+	return false
+}
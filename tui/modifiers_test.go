@@ -0,0 +1,83 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/angusmclean/tavernshell/core/dice"
+)
+
+func TestBuffChangesRoll(t *testing.T) {
+	defer dice.SetRoller(dice.CurrentRoller())
+
+	tests := []struct {
+		commands []string
+		expected int
+		buffs    string // the bonus's note, "" if no buff applies
+	}{
+		{[]string{"r d20+3 # Aria perception"}, 13, ""},
+		{[]string{"buff Aria +2", "r d20+3 # Aria perception"}, 15, "Aria +2"},
+		{[]string{"buff Aria +2 attack", "r d20+3 # Aria perception"}, 13, ""},
+		{[]string{"buff Aria +2 attack", "r d20+3 # aria attack"}, 15, "Aria +2 attack"},
+		{[]string{"buff all -1", "buff Aria +2 attack", "r d20+3 # Aria attack"}, 14, "everyone -1, Aria +2 attack"},
+		{[]string{"buff Aria +2", "r d20+3 # Arianna"}, 13, ""},
+		{[]string{"buff Aria +2", "r d20+3 # Aria vs 14"}, 15, "Aria +2"},
+		{[]string{"buff Aria +2", "d20+3 # Aria"}, 15, "Aria +2"},
+
+		// Untagged buffs are for rolls, checks and attacks, not damage or stored rolls
+		{[]string{"t add Goblin 20 20", "buff all +2", "r d20+3 -> Goblin"}, 13, ""},
+		{[]string{"buff all +2", "store x = d20+3"}, 13, ""},
+		{[]string{"buff all +2 damage", "t add Goblin 20 20", "r d20+3 -> Goblin"}, 15, "everyone +2 damage"},
+	}
+	for _, tt := range tests {
+		dice.SetRoller(dice.NewSequenceRoller(10))
+		m := runCommands(tt.commands...)
+		if m.lastRoll == nil || m.lastRoll.Total != tt.expected {
+			t.Errorf("%v: expected total %d, got %v", tt.commands, tt.expected, m.lastRoll)
+			continue
+		}
+		if m.lastRoll.BonusNote != tt.buffs {
+			t.Errorf("%v: expected buffs %q, got %q", tt.commands, tt.buffs, m.lastRoll.BonusNote)
+		}
+		// The notation is what was rolled; the buffs are a term of their own
+		if notation := m.lastRoll.Expression.String(); notation != "1d20+3" {
+			t.Errorf("%v: expected the notation 1d20+3, got %s", tt.commands, notation)
+		}
+	}
+}
+
+func TestBuffInReceiptAndShare(t *testing.T) {
+	defer dice.SetRoller(dice.CurrentRoller())
+
+	dice.SetRoller(dice.NewSequenceRoller(10))
+	m := runCommands("buff Aria +2", "r d20+3 # Aria")
+	if !strings.Contains(lastLine(m), "+3 +2") || !strings.Contains(lastLine(m), "Aria +2") {
+		t.Errorf("Expected the buff shown as a term of its own, got %q", lastLine(m))
+	}
+	receipt := m.lastRoll.Receipt(m.lastRollTime)
+	if !strings.Contains(receipt, "Roll:  1d20+3\n") || !strings.Contains(receipt, "Bonus: +2 (Aria +2)\n") {
+		t.Errorf("Expected the receipt to list the notation and bonus apart, got:\n%s", receipt)
+	}
+	decoded, _, err := dice.DecodeShare(m.lastRoll.ShareCode(m.lastRollTime))
+	if err != nil {
+		t.Fatalf("DecodeShare failed: %v", err)
+	}
+	if decoded.Total != 15 || decoded.Bonus != 2 || decoded.Expression.String() != "1d20+3" {
+		t.Errorf("Expected 1d20+3 with +2 for 15, got %s with %+d for %d", decoded.Expression, decoded.Bonus, decoded.Total)
+	}
+}
+
+func TestBuffChangesAttack(t *testing.T) {
+	defer dice.SetRoller(dice.CurrentRoller())
+
+	// The current turn's participant is the attacker when the label names no one
+	dice.SetRoller(dice.NewSequenceRoller(10, 4))
+	m := runCommands("i s", "Aria 15", "Goblin 10", "done", "buff Aria +2 attack", "buff Aria +1 damage",
+		"atk d20+3 vs 15 dmg 1d6+2")
+	if got := m.rollLog.Len(); got != 2 {
+		t.Fatalf("Expected the attack to hit and roll damage (2 rolls), got %d", got)
+	}
+	if m.lastRoll.Total != 7 {
+		t.Errorf("Expected 7 damage (4+2+1), got %d", m.lastRoll.Total)
+	}
+}
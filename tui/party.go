@@ -0,0 +1,314 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/angusmclean/tavernshell/core/dice"
+	"github.com/angusmclean/tavernshell/core/party"
+	"github.com/angusmclean/tavernshell/core/tracker/rotation"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// partySide is the side the party joins in side initiative
+const partySide = "party"
+
+// handleParty processes party roster commands
+func (m *Model) handleParty(args []string) {
+	if len(args) == 0 {
+		m.addHistory("Usage: party <command> - Commands: import <file>, list/l, check/c <skill|ability save> [DC], init/i, color <name> <color>, remove <name>")
+		return
+	}
+
+	subCmd := strings.ToLower(args[0])
+
+	switch {
+	case strings.HasPrefix("import", subCmd) && len(subCmd) >= 2:
+		if len(args) < 2 {
+			m.addHistory("Usage: party import <file.json>")
+			return
+		}
+		if err := m.ImportParty(strings.Join(args[1:], " ")); err != nil {
+			m.addHistory(fmt.Sprintf("Error: %s", err))
+		}
+
+	case strings.HasPrefix("list", subCmd) || subCmd == "l":
+		characters := m.roster.List()
+		if len(characters) == 0 {
+			m.addHistory("No characters in the party. Use 'party import <file>' to add some.")
+			return
+		}
+		m.addHistory("Party:")
+		for _, c := range characters {
+			m.addHistory(fmt.Sprintf("  %s: AC %d, HP %d, init %+d, %s", m.colorName(c.Name), c.AC, c.HP, c.Initiative, c.Color))
+		}
+
+	case strings.HasPrefix("check", subCmd) || subCmd == "c":
+		m.groupCheck(args[1:])
+
+	case strings.HasPrefix("init", subCmd) || subCmd == "i":
+		m.partyInitiative()
+
+	case strings.HasPrefix("color", subCmd) && len(subCmd) >= 3:
+		if len(args) < 3 {
+			m.addHistory(fmt.Sprintf("Usage: party color <name> <color> - Colors: %s, 0-255 or #rrggbb", strings.Join(party.ColorNames(), ", ")))
+			return
+		}
+		name, color := strings.Join(args[1:len(args)-1], " "), args[len(args)-1]
+		if _, err := party.ColorCode(color); err != nil {
+			m.addHistory(fmt.Sprintf("Error: %s", err))
+			return
+		}
+		if !m.roster.SetColor(name, strings.ToLower(color)) {
+			m.addHistory(fmt.Sprintf("Error: '%s' is not in the party", name))
+			return
+		}
+		m.addHistory(fmt.Sprintf("%s is now %s", m.colorName(m.roster.Get(name).Name), color))
+
+	case strings.HasPrefix("remove", subCmd) && len(subCmd) >= 3:
+		if len(args) < 2 {
+			m.addHistory("Usage: party remove <name>")
+			return
+		}
+		name := strings.Join(args[1:], " ")
+		if !m.roster.Remove(name) {
+			m.addHistory(fmt.Sprintf("Error: '%s' is not in the party", name))
+			return
+		}
+		m.addHistory(fmt.Sprintf("Removed %s from the party", name))
+
+	default:
+		m.addHistory(fmt.Sprintf("Unknown party command: %s", subCmd))
+	}
+}
+
+// ImportParty adds the characters in a JSON character sheet file to the party roster
+func (m *Model) ImportParty(path string) error {
+	characters, err := m.roster.Import(path)
+	if err != nil {
+		return err
+	}
+	names := make([]string, len(characters))
+	for i, c := range characters {
+		names[i] = c.Name
+	}
+	m.watcher.watch(path, reloadParty)
+	m.addHistory(fmt.Sprintf("Imported %s into the party", strings.Join(names, ", ")))
+	return nil
+}
+
+// groupCheck rolls a skill check or saving throw for every character
+// "check perception 15" or "check dex save 14"; the group succeeds if at least half succeed
+func (m *Model) groupCheck(args []string) {
+	characters := m.roster.List()
+	if len(characters) == 0 {
+		m.addHistory("No characters in the party. Use 'party import <file>' to add some.")
+		return
+	}
+	if len(args) == 0 {
+		m.addHistory("Usage: party check <skill> [DC] or party check <ability> save [DC]")
+		return
+	}
+
+	dc := 0
+	if n, err := strconv.Atoi(args[len(args)-1]); err == nil && len(args) > 1 {
+		dc = n
+		args = args[:len(args)-1]
+	}
+	save := len(args) > 1 && strings.HasPrefix("save", strings.ToLower(args[len(args)-1]))
+	if save {
+		args = args[:len(args)-1]
+	}
+	check := strings.ToLower(strings.Join(args, " "))
+
+	label := check
+	if save {
+		label += " save"
+	} else {
+		// Everyone rolls the same skill, even if "a" would be acrobatics for
+		// one character and athletics for another
+		skill, err := party.SkillName(characters, check)
+		if err != nil {
+			m.addHistory(fmt.Sprintf("Error: %s", err))
+			return
+		}
+		if skill != "" {
+			label, check = skill, skill
+		}
+	}
+	if dc > 0 {
+		m.addHistory(fmt.Sprintf("Group %s (DC %d):", label, dc))
+	} else {
+		m.addHistory(fmt.Sprintf("Group %s:", label))
+	}
+
+	passed := 0
+	for _, c := range characters {
+		var bonus int
+		if save {
+			bonus, _ = c.Save(check)
+		} else {
+			bonus = c.Skills[check]
+		}
+		// Buffs on them for this kind of check ('buff Aria +2 stealth')
+		bonus += m.modifierManager.Total(c.Name, check)
+		result, err := dice.RollExpression(&dice.Expression{Count: 1, Sides: 20, Modifier: bonus})
+		if err != nil {
+			m.addHistory(fmt.Sprintf("Error: %s", err))
+			return
+		}
+
+		line := fmt.Sprintf("  %s: %d (%d%+d)", m.colorName(c.Name), result.Total, result.KeptTotal, bonus)
+		if dc > 0 {
+			if result.Total >= dc {
+				passed++
+				line += " ✓"
+			} else {
+				line += " ✗"
+			}
+		}
+		m.addHistory(line)
+	}
+
+	if dc > 0 {
+		outcome := "fails"
+		if passed*2 >= len(characters) {
+			outcome = "succeeds"
+		}
+		m.addHistory(fmt.Sprintf("The party %s (%d/%d passed)", outcome, passed, len(characters)))
+	}
+}
+
+// partyInitiative rolls initiative for every character and adds them to the
+// order with their HP and AC, starting initiative if it isn't running
+func (m *Model) partyInitiative() {
+	characters := m.roster.List()
+	if len(characters) == 0 {
+		m.addHistory("No characters in the party. Use 'party import <file>' to add some.")
+		return
+	}
+	if !m.initiativeManager.IsActive() {
+		m.initiativeManager.Start()
+		m.numberTrackerManager.StartBaselines()
+		m.startStats("")
+		m.initiativeEntryMode = true
+		m.addHistory("Starting initiative. Enter '<name> <initiative>' for each other participant.")
+		m.addHistory("Type 'done' when finished.")
+	}
+	tracker := m.initiativeManager.GetTracker()
+	joined := make(map[string]bool)
+	for _, p := range tracker.Participants {
+		joined[strings.ToLower(p.Name)] = true
+	}
+
+	for _, c := range characters {
+		if joined[strings.ToLower(c.Name)] {
+			m.addHistory(fmt.Sprintf("Skipped %s (already in initiative)", m.colorName(c.Name)))
+			continue
+		}
+		var initiative int
+		if tracker.Mode == rotation.ModeCyclic {
+			rolled, err := m.initiativeManager.AddWithBonus(c.Name, c.Initiative)
+			if err != nil {
+				m.addHistory(fmt.Sprintf("Error: %s", err))
+				return
+			}
+			initiative = rolled
+		} else {
+			result, err := dice.RollExpression(&dice.Expression{Count: 1, Sides: 20, Modifier: c.Initiative})
+			if err != nil {
+				m.addHistory(fmt.Sprintf("Error: %s", err))
+				return
+			}
+			initiative = result.Total
+			if tracker.Mode == rotation.ModeSide {
+				m.initiativeManager.AddToSide(c.Name, initiative, partySide)
+			} else {
+				m.initiativeManager.Add(c.Name, initiative)
+			}
+		}
+		m.initiativeManager.SetHP(c.Name, c.HP)
+		m.initiativeManager.SetAC(c.Name, c.AC)
+		m.addHistory(fmt.Sprintf("Added %s (bonus %+d, rolled %d, HP %d, AC %d)", m.colorName(c.Name), c.Initiative, initiative, c.HP, c.AC))
+		m.recordInitiative(c.Name, initiative)
+	}
+}
+
+// playerStyle returns the style a party member's name is drawn in, on top of
+// the line's style (nil if the name isn't in the party)
+func (m Model) playerStyle(name string, on *lipgloss.Style) *lipgloss.Style {
+	c := m.roster.Get(name)
+	if c == nil || c.Color == "" {
+		return nil
+	}
+	code, err := party.ColorCode(c.Color)
+	if err != nil {
+		return nil
+	}
+	return m.cache.playerStyle(code, on)
+}
+
+// colorName draws a name in its player's color, if it's a party member's
+func (m Model) colorName(name string) string {
+	if style := m.playerStyle(name, nil); style != nil {
+		return m.cache.render(style, name)
+	}
+	return name
+}
+
+// colorPlayers draws each party member's name in text in their color, and
+// the rest of the text in base (nil leaves it unstyled)
+func (m Model) colorPlayers(text string, base *lipgloss.Style) string {
+	plain := func(s string) string {
+		if base == nil || s == "" {
+			return s
+		}
+		return base.Render(s)
+	}
+
+	// Longest names first, so "Mary Jane" wins over "Mary"
+	characters := m.roster.List()
+	sort.SliceStable(characters, func(i, j int) bool {
+		return len(characters[i].Name) > len(characters[j].Name)
+	})
+
+	var b strings.Builder
+	start := 0
+	for i := 0; i < len(text); i++ {
+		if i > 0 && isWordByte(text[i-1]) {
+			continue
+		}
+		for _, c := range characters {
+			end := i + len(c.Name)
+			if end > len(text) || !strings.EqualFold(text[i:end], c.Name) || (end < len(text) && isWordByte(text[end])) {
+				continue
+			}
+			b.WriteString(plain(text[start:i]))
+			b.WriteString(m.colorName(text[i:end]))
+			start, i = end, end-1
+			break
+		}
+	}
+	b.WriteString(plain(text[start:]))
+	return b.String()
+}
+
+// renderNamed renders a line in style with the first mention of name drawn
+// in its player's color
+func (m Model) renderNamed(style *lipgloss.Style, text, name string) string {
+	nameStyle := m.playerStyle(name, style)
+	i := strings.Index(text, name)
+	if nameStyle == nil || i < 0 {
+		return m.cache.render(style, text)
+	}
+	end := i + len(name)
+	return m.cache.render(style, text[:i]) + m.cache.render(nameStyle, name) + m.cache.render(style, text[end:])
+}
+
+// isWordByte reports whether b can be part of a word, for matching names
+func isWordByte(b byte) bool {
+	return b == '_' || b >= 0x80 || unicode.IsLetter(rune(b)) || unicode.IsDigit(rune(b))
+}
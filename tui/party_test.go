@@ -0,0 +1,36 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// partyFile writes a character sheet file for the test to import
+func partyFile(t *testing.T, sheet string) string {
+	path := filepath.Join(t.TempDir(), "party.json")
+	if err := os.WriteFile(path, []byte(sheet), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestPartyInitTwice(t *testing.T) {
+	path := partyFile(t, `[{"name": "Aria", "hp": 30}, {"name": "Borin", "hp": 40}]`)
+	m := runCommands("party import "+path, "party init", "done", "party init")
+	if n := len(m.initiativeManager.GetTracker().Participants); n != 2 {
+		t.Errorf("Expected 2 participants, got %d", n)
+	}
+	if !strings.Contains(lastLine(m), "already in initiative") {
+		t.Errorf("Expected the second party init to skip the party, got %q", lastLine(m))
+	}
+}
+
+func TestPartyCheckAmbiguousSkill(t *testing.T) {
+	path := partyFile(t, `[{"name": "Aria", "skills": {"acrobatics": 5}}, {"name": "Borin", "skills": {"athletics": 6}}]`)
+	m := runCommands("party import "+path, "party check a 10")
+	if !strings.Contains(lastLine(m), "could be acrobatics, athletics") {
+		t.Errorf("Expected the ambiguous skill to be refused, got %q", lastLine(m))
+	}
+}
@@ -0,0 +1,129 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/angusmclean/tavernshell/core/macro"
+)
+
+// handleCast rolls a spell or ability preset, at a level if it grows with one
+// Usage: cast <name> [level] [vs <N> | -> <target>]
+func (m *Model) handleCast(args []string) {
+	if len(args) == 0 {
+		m.addHistory("Usage: cast <name> [level] - Roll a spell or ability preset (e.g., 'cast fireball 5', 'cast cure 3', 'cast fireball -> Goblin'; 'preset list')")
+		return
+	}
+
+	// The longest run of words naming a preset, then an optional level; the
+	// rest ("vs 15", "-> Goblin") goes along with the roll
+	words := 0
+	for words < len(args) && startsWithLetter(args[words]) {
+		words++
+	}
+	err := fmt.Errorf("no preset named '%s' (see 'preset list')", args[0])
+	for i := words; i > 0; i-- {
+		p, findErr := m.presetManager.Find(strings.Join(args[:i], " "))
+		if findErr != nil {
+			err = findErr
+			continue
+		}
+
+		rest := args[i:]
+		level := 0
+		if len(rest) > 0 {
+			if n, err := strconv.Atoi(rest[0]); err == nil {
+				level, rest = n, rest[1:]
+			}
+		}
+		notation, err := p.Notation(level)
+		if err != nil {
+			m.addHistory(fmt.Sprintf("Error: %s", err))
+			return
+		}
+
+		// $mod and the like come from 'store', @name from trackers
+		notation = m.substituteRef(macro.Substitute(notation, m.storedVars()))
+		if ref := unresolvedRef(notation); ref != "" {
+			m.addHistory(fmt.Sprintf("Error: %s adds %s, which has no value (e.g., 'store %s = 3')", p.Name, ref, strings.TrimLeft(ref, "$@")))
+			return
+		}
+
+		label := p.Name
+		if level > p.Level {
+			label += fmt.Sprintf(" at level %d", level)
+		}
+		m.handleRoll(append(append([]string{notation}, rest...), "#", label))
+		return
+	}
+	m.addHistory(fmt.Sprintf("Error: %s", err))
+}
+
+// startsWithLetter reports whether s starts with a letter ("" doesn't)
+func startsWithLetter(s string) bool {
+	r, _ := utf8.DecodeRuneInString(s)
+	return unicode.IsLetter(r)
+}
+
+// unresolvedRef returns the first $name or @name left in notation ("" if none)
+func unresolvedRef(notation string) string {
+	i := strings.IndexAny(notation, "$@")
+	if i < 0 {
+		return ""
+	}
+	end := i + 1
+	for end < len(notation) && macro.IsName(notation[end:end+1]) {
+		end++
+	}
+	return notation[i:end]
+}
+
+// handlePreset lists the presets or loads more from a file
+// Usage: preset [list], preset load <file>
+func (m *Model) handlePreset(args []string) {
+	if len(args) == 0 || strings.HasPrefix("list", strings.ToLower(args[0])) {
+		m.addHistory("Presets ('cast <name> [level]'):")
+		for _, p := range m.presetManager.List() {
+			line := fmt.Sprintf("  %s: %s", p.Name, p.Roll)
+			if p.Upcast != "" {
+				line += fmt.Sprintf(" (level %d, +%s per level above)", p.Level, p.Upcast)
+			} else if p.Level > 0 {
+				line += fmt.Sprintf(" (level %d)", p.Level)
+			}
+			if p.Description != "" {
+				line += " - " + p.Description
+			}
+			m.addHistory(line)
+		}
+		return
+	}
+
+	switch strings.ToLower(args[0]) {
+	case "load":
+		if len(args) < 2 {
+			m.addHistory("Usage: preset load <file> (JSON: {\"hex\": \"1d6\", \"fireball\": {\"roll\": \"8d6\", \"level\": 3, \"upcast\": \"1d6\"}})")
+			return
+		}
+		path := strings.Join(args[1:], " ")
+		if err := m.LoadPresets(path); err != nil {
+			m.addHistory(fmt.Sprintf("Error: %s", err))
+			return
+		}
+		m.addHistory(fmt.Sprintf("Loaded presets from %s", path))
+	default:
+		m.addHistory(fmt.Sprintf("Unknown preset command: %s", args[0]))
+	}
+}
+
+// LoadPresets loads spell and ability presets from a JSON file, replacing
+// built-in ones with the same name
+func (m *Model) LoadPresets(path string) error {
+	if err := m.presetManager.Load(path); err != nil {
+		return err
+	}
+	m.watcher.watch(path, reloadPresets)
+	return nil
+}
@@ -0,0 +1,30 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/angusmclean/tavernshell/core/dice"
+)
+
+func TestCast(t *testing.T) {
+	defer dice.SetRoller(dice.CurrentRoller())
+
+	tests := []struct {
+		command  string
+		expected string // what the last line starts with
+	}{
+		{`cast ""`, "Error: no preset named ''"},
+		{`cast "" 5`, "Error: no preset named ''"},
+		{"cast 5", "Error: no preset named '5'"},
+		{"cast nope", "Error: no preset named 'nope'"},
+		{"cast fire bolt", "🎲"},
+	}
+	for _, tt := range tests {
+		dice.SetRoller(dice.NewSequenceRoller(4))
+		m := runCommands(tt.command)
+		if !strings.HasPrefix(lastLine(m), tt.expected) {
+			t.Errorf("%s: expected %q, got %q", tt.command, tt.expected, lastLine(m))
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/angusmclean/tavernshell/core/tracker/timer"
+)
+
+// recapAlarmWindow is how close an alarm has to be to make the round recap
+const recapAlarmWindow = time.Minute
+
+// roundRecap describes the round just starting in one line, e.g.
+// "📣 Round 3: 4 active · ending this round: Aria +1 attack, Bless · alarms under 1m: Torch 42s"
+func (m *Model) roundRecap() string {
+	tracker := m.initiativeManager.GetTracker()
+	if tracker == nil {
+		return ""
+	}
+	active := 0
+	for _, p := range tracker.Participants {
+		if p.IsActive && p.Summoner == "" {
+			active++
+		}
+	}
+	parts := []string{fmt.Sprintf("📣 Round %d: %d active", tracker.Round, active)}
+
+	// Effects in their last round run out at the top of the next one
+	var ending, alarms []string
+	for _, mod := range m.modifierManager.List() {
+		if mod.Rounds == 1 {
+			ending = append(ending, modifierName(mod))
+		}
+	}
+	for _, t := range m.timerManager.GetActive() {
+		switch {
+		case t.IsRoundBased() && t.Rounds == 1:
+			ending = append(ending, timerName(t))
+		case !t.IsRoundBased() && t.Remaining() < recapAlarmWindow:
+			alarms = append(alarms, fmt.Sprintf("%s %s", timerName(t), timer.FormatDurationShort(t.Remaining())))
+		}
+	}
+	if len(ending) > 0 {
+		parts = append(parts, "ending this round: "+strings.Join(ending, ", "))
+	}
+	if len(alarms) > 0 {
+		parts = append(parts, "alarms under 1m: "+strings.Join(alarms, ", "))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// timerName names a timer by its label, or its length if it has none
+func timerName(t *timer.Timer) string {
+	if t.Label != "" {
+		return t.Label
+	}
+	return "alarm (" + t.Length() + ")"
+}
+
+// announceRoundRecap adds the recap of the round just starting to history,
+// unless it's turned off
+func (m *Model) announceRoundRecap() {
+	if !m.recapRounds {
+		return
+	}
+	if recap := m.roundRecap(); recap != "" {
+		m.addHistory(recap)
+	}
+}
+
+// handleRecap turns the top-of-round recap on or off
+// Usage: i recap [on|off]
+func (m *Model) handleRecap(args []string) {
+	if len(args) == 0 {
+		state := "off"
+		if m.recapRounds {
+			state = "on"
+		}
+		m.addHistory(fmt.Sprintf("Round recap is %s. Usage: i recap [on|off]", state))
+		return
+	}
+	switch strings.ToLower(args[0]) {
+	case "on":
+		m.recapRounds = true
+		m.addHistory("Round recap on: each new round starts with a one-line recap")
+	case "off":
+		m.recapRounds = false
+		m.addHistory("Round recap off")
+	default:
+		m.addHistory("Usage: i recap [on|off]")
+	}
+}
+
+// SetRoundRecap turns the top-of-round recap on or off (it starts on)
+func (m *Model) SetRoundRecap(on bool) {
+	m.recapRounds = on
+}
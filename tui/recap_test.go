@@ -0,0 +1,49 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRoundRecap(t *testing.T) {
+	m := runCommands("i s", "Aria 15", "Goblin 10", "Orc 5", "done", "i kill Orc",
+		"buff Aria +1 attack 2r", "buff all -1 3r", "a 2r Bless", "a 30s Torch", "a 10m Rest")
+
+	m.handleBatch("i n")
+	m.handleBatch("i n")
+	recap := m.roundRecap()
+	for _, want := range []string{
+		"📣 Round 2: 2 active",
+		"ending this round: Aria +1 attack, Bless",
+		"alarms under 1m: Torch ",
+	} {
+		if !strings.Contains(recap, want) {
+			t.Errorf("Expected %q in %q", want, recap)
+		}
+	}
+	for _, unwanted := range []string{"everyone", "Rest"} {
+		if strings.Contains(recap, unwanted) {
+			t.Errorf("Expected no %q in %q", unwanted, recap)
+		}
+	}
+
+	// The recap is announced once per new round, and not when it's off
+	count := func() int {
+		n := 0
+		for _, e := range m.history.Recent(m.history.Len()) {
+			if strings.HasPrefix(e.Text, "📣") {
+				n++
+			}
+		}
+		return n
+	}
+	if n := count(); n != 1 {
+		t.Errorf("Expected 1 recap, got %d", n)
+	}
+	m.handleBatch("i recap off")
+	m.handleBatch("i n")
+	m.handleBatch("i n")
+	if n := count(); n != 1 {
+		t.Errorf("Expected no recap with it off, got %d in all", n)
+	}
+}
@@ -0,0 +1,108 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/angusmclean/tavernshell/core/macro"
+)
+
+// substituteRefs fills in @name with the current value of the number tracker
+// of that name, and @lastroll with the last roll's total, so 'r d20+@STRmod'
+// and 't adj HP -@lastroll' use the values as they are when the command runs
+// Free text (notes, searches, a roll's # label) is left alone, and so is a
+// reference to nothing, for parseRoll to report
+func (m *Model) substituteRefs(parts []string) []string {
+	if len(parts) == 0 || definesCommands(parts) || freeText(parts) {
+		return parts
+	}
+	substituted := make([]string, len(parts))
+	label := false
+	for i, part := range parts {
+		label = label || strings.HasPrefix(part, "#")
+		if label {
+			substituted[i] = part
+			continue
+		}
+		substituted[i] = m.substituteRef(part)
+	}
+	return substituted
+}
+
+// freeText returns true for a command whose arguments are text rather than
+// values, like a note on a roll or a search
+func freeText(parts []string) bool {
+	cmd := strings.ToLower(parts[0])
+	return (strings.HasPrefix("annotate", cmd) && len(cmd) >= 3) ||
+		(strings.HasPrefix("find", cmd) && len(cmd) >= 2) ||
+		cmd == "choose" || cmd == "pick"
+}
+
+// unknownRef returns an error for an @name left in notation, which
+// substituteRef couldn't fill in (nil if there is none)
+func (m *Model) unknownRef(notation string) error {
+	i := strings.IndexByte(notation, '@')
+	if i < 0 {
+		return nil
+	}
+	end := i + 1
+	for end < len(notation) && macro.IsName(notation[end:end+1]) {
+		end++
+	}
+	name := notation[i+1 : end]
+	if strings.EqualFold(name, "lastroll") {
+		return fmt.Errorf("no roll yet for @lastroll")
+	}
+	return fmt.Errorf("no tracker '%s'", name)
+}
+
+// substituteRef fills in the references in one argument; a negative value
+// folds into the sign before it, so d20+@STRmod reads d20-1 rather than d20+-1
+func (m *Model) substituteRef(text string) string {
+	if !strings.Contains(text, "@") {
+		return text
+	}
+	var b []byte
+	for i := 0; i < len(text); {
+		if text[i] == '@' {
+			n := 1
+			for n < len(text[i:]) && macro.IsName(text[i+n:i+n+1]) {
+				n++
+			}
+			if value, ok := m.refValue(text[i+1 : i+n]); ok {
+				if last := len(b) - 1; value < 0 && last >= 0 {
+					switch b[last] {
+					case '+':
+						b[last], value = '-', -value
+					case '-':
+						b[last], value = '+', -value
+					}
+				}
+				b = strconv.AppendInt(b, int64(value), 10)
+				i += n
+				continue
+			}
+		}
+		b = append(b, text[i])
+		i++
+	}
+	return string(b)
+}
+
+// refValue returns the value an @name reference stands for
+func (m *Model) refValue(name string) (int, bool) {
+	if name == "" {
+		return 0, false
+	}
+	if strings.EqualFold(name, "lastroll") {
+		if m.lastRoll == nil {
+			return 0, false
+		}
+		return m.lastRoll.Total, true
+	}
+	if t := m.numberTrackerManager.Get(name); t != nil {
+		return t.Current, true
+	}
+	return 0, false
+}
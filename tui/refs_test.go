@@ -0,0 +1,43 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/angusmclean/tavernshell/core/dice"
+)
+
+func TestSubstituteRefs(t *testing.T) {
+	m := runCommands("t add STR 3 3", "t add Penalty -2 5")
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"r d20+@STR", "r d20+3"},
+		{"r d20+@Penalty", "r d20-2"},
+		{"r d20-@Penalty", "r d20+2"},
+		{"r d20+@STR # hits @STR", "r d20+3 # hits @STR"},
+		{"r d20+@nope", "r d20+@nope"},
+		{`annotate last "@STR was here"`, `annotate last "@STR was here"`},
+		{"find @STR", "find @STR"},
+		{"macro add smack = r d20+@STR", "macro add smack = r d20+@STR"},
+	}
+	for _, tt := range tests {
+		got := strings.Join(m.substituteRefs(splitArgs(tt.input)), " ")
+		if expected := strings.Join(splitArgs(tt.expected), " "); got != expected {
+			t.Errorf("%s: expected %q, got %q", tt.input, expected, got)
+		}
+	}
+}
+
+func TestUnknownRefInRoll(t *testing.T) {
+	defer dice.SetRoller(dice.CurrentRoller())
+	dice.SetRoller(dice.NewSequenceRoller(10))
+
+	for _, input := range []string{"r d20+@nope", "d20+@nope", "atk d20+@nope vs 12 dmg 1d6"} {
+		m := runCommands(input)
+		if got := lastLine(m); got != "Error: no tracker 'nope'" {
+			t.Errorf("%s: expected \"Error: no tracker 'nope'\", got %q", input, got)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// fileWatcher remembers the data files loaded into the session, so edits
+// made to them mid-session can be picked up without a restart
+type fileWatcher struct {
+	files []*watchedFile
+	mu    sync.Mutex
+}
+
+// watchedFile is a loaded file and how to load it again
+type watchedFile struct {
+	path    string
+	modTime time.Time
+	reload  func(m *Model, path string) (string, error) // returns a notice for the history
+}
+
+// newFileWatcher creates a watcher with no files
+func newFileWatcher() *fileWatcher {
+	return &fileWatcher{}
+}
+
+// watch starts watching a file that was just loaded (a file already
+// watched keeps its original reload)
+func (w *fileWatcher) watch(path string, reload func(m *Model, path string) (string, error)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, f := range w.files {
+		if f.path == path {
+			f.modTime = fileModTime(path)
+			return
+		}
+	}
+	w.files = append(w.files, &watchedFile{path: path, modTime: fileModTime(path), reload: reload})
+}
+
+// changed returns the watched files modified since they were last loaded
+func (w *fileWatcher) changed() []*watchedFile {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var changed []*watchedFile
+	for _, f := range w.files {
+		if t := fileModTime(f.path); t.After(f.modTime) {
+			f.modTime = t // a broken edit isn't retried until the file changes again
+			changed = append(changed, f)
+		}
+	}
+	return changed
+}
+
+// fileModTime returns a file's modification time (zero if it can't be read)
+func fileModTime(path string) time.Time {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
+
+// reloadChangedFiles reloads any macro, table, checklist, preset or party file that
+// changed on disk, noting each in the history; a file that no longer loads
+// is reported and the previous version kept
+func (m *Model) reloadChangedFiles() {
+	m.category = categorySystem
+	if reloaded, err := m.macroManager.Reload(); err != nil {
+		m.addHistory(fmt.Sprintf("Error: reloading macros: %s", err))
+	} else if reloaded {
+		m.addHistory(fmt.Sprintf("🔄 Reloaded macros (%d)", len(m.macroManager.List())))
+	}
+
+	for _, f := range m.watcher.changed() {
+		notice, err := f.reload(m, f.path)
+		if err != nil {
+			m.addHistory(fmt.Sprintf("Error: reloading %s: %s (keeping the previous version)", f.path, err))
+			continue
+		}
+		m.addHistory("🔄 " + notice)
+	}
+}
+
+// reloadTable loads a table file again
+func reloadTable(m *Model, path string) (string, error) {
+	t, err := m.tableManager.LoadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Reloaded table '%s' from %s (d%d, %d entries)", t.Name, path, t.Sides(), len(t.Entries)), nil
+}
+
+// reloadChecklists loads a checklist file again, replacing what it loaded before
+func reloadChecklists(m *Model, path string) (string, error) {
+	if err := m.checklistManager.Load(path); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Reloaded checklists from %s", path), nil
+}
+
+// reloadPresets loads a preset file again, replacing what it loaded before
+func reloadPresets(m *Model, path string) (string, error) {
+	if err := m.presetManager.Load(path); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Reloaded presets from %s", path), nil
+}
+
+// reloadParty loads a character sheet file again, updating the characters in
+// it and dropping those taken out of it
+func reloadParty(m *Model, path string) (string, error) {
+	characters, err := m.roster.Import(path)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Reloaded %d character(s) from %s", len(characters), path), nil
+}
@@ -0,0 +1,178 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// maxRenderedSegments bounds the render cache; it is cleared when full
+const maxRenderedSegments = 512
+
+// Styles used every frame, built once rather than on each render
+var (
+	titleStyle = lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("205"))
+
+	promptStyle = lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("86"))
+
+	entryPromptStyle = lipgloss.NewStyle().
+				Bold(true).
+				Foreground(lipgloss.Color("214"))
+
+	checklistPromptStyle = lipgloss.NewStyle().
+				Bold(true).
+				Foreground(lipgloss.Color("141"))
+
+	badgeStyle = lipgloss.NewStyle().
+			Bold(true).
+			Reverse(true)
+
+	helpStyle = lipgloss.NewStyle().
+			Faint(true).
+			Foreground(lipgloss.Color("241"))
+
+	critStyle = lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("42"))
+
+	fumbleStyle = lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("196"))
+
+	// Critical successes and failures on a check stand out from plain ones
+	critCheckStyle   = critStyle.Reverse(true)
+	fumbleCheckStyle = fumbleStyle.Reverse(true)
+
+	whisperStyle = lipgloss.NewStyle().
+			Italic(true).
+			Foreground(lipgloss.Color("140"))
+
+	timerStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("214")).
+			Width(0) // Don't let lipgloss add extra width
+
+	emptyTimerStyle = lipgloss.NewStyle().
+			Faint(true).
+			Foreground(lipgloss.Color("241")).
+			Width(0)
+
+	trackerStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("cyan")).
+			Width(0)
+
+	focusedTrackerStyle = lipgloss.NewStyle().
+				Bold(true).
+				Reverse(true).
+				Foreground(lipgloss.Color("cyan"))
+
+	roundHeaderStyle = lipgloss.NewStyle().
+				Bold(true).
+				Foreground(lipgloss.Color("green"))
+
+	currentStyle = lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("yellow")).
+			Background(lipgloss.Color("236"))
+
+	activeStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("15"))
+
+	inactiveStyle = lipgloss.NewStyle().
+			Faint(true).
+			Foreground(lipgloss.Color("241"))
+
+	sideStyle = lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("241"))
+)
+
+// renderKey identifies a rendered segment: a style and the text it styled
+type renderKey struct {
+	style *lipgloss.Style
+	text  string
+}
+
+// renderCache keeps segments between frames: most of the screen is the
+// same from one frame to the next, and styling text is the slowest part
+// of building a view
+type renderCache struct {
+	width     int    // terminal width the rules and padding were built for
+	ascii     bool   // whether the rules were built in ASCII mode
+	separator string // full-width horizontal rule
+	blank     string // full-width run of spaces, sliced for padding
+
+	rendered map[renderKey]string
+	players  map[playerKey]*lipgloss.Style // player name styles
+}
+
+// playerKey identifies a player name style: a color on top of a line's style
+type playerKey struct {
+	code string
+	on   *lipgloss.Style
+}
+
+// newRenderCache creates an empty render cache
+func newRenderCache() *renderCache {
+	return &renderCache{
+		width:    -1,
+		rendered: make(map[renderKey]string),
+		players:  make(map[playerKey]*lipgloss.Style),
+	}
+}
+
+// resize rebuilds the full-width segments if the width or glyph mode changed
+func (c *renderCache) resize(m Model) {
+	if c.width == m.width && c.ascii == m.ascii {
+		return
+	}
+	c.width = m.width
+	c.ascii = m.ascii
+	c.separator = m.glyphs(strings.Repeat("─", max(m.width, 0)))
+	c.blank = strings.Repeat(" ", max(m.width, 0))
+}
+
+// pad returns spaces to pad a line of width n out to width (from the cached blank run)
+func (c *renderCache) pad(n, width int) string {
+	if n >= width {
+		return ""
+	}
+	if width-n > len(c.blank) {
+		return strings.Repeat(" ", width-n)
+	}
+	return c.blank[:width-n]
+}
+
+// render styles text, reusing the result from earlier frames when possible
+func (c *renderCache) render(style *lipgloss.Style, text string) string {
+	key := renderKey{style, text}
+	if s, ok := c.rendered[key]; ok {
+		return s
+	}
+	if len(c.rendered) >= maxRenderedSegments {
+		clear(c.rendered)
+	}
+	s := style.Render(text)
+	c.rendered[key] = s
+	return s
+}
+
+// playerStyle returns the style a player's name is drawn in: their color,
+// bold, on top of the line's style (nil for none, e.g. keeping the current
+// turn's background); styles are kept so each is a stable key for render
+func (c *renderCache) playerStyle(code string, on *lipgloss.Style) *lipgloss.Style {
+	key := playerKey{code, on}
+	if style, ok := c.players[key]; ok {
+		return style
+	}
+	style := lipgloss.NewStyle()
+	if on != nil {
+		style = *on
+	}
+	style = style.Bold(true).Foreground(lipgloss.Color(code))
+	c.players[key] = &style
+	return &style
+}
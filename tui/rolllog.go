@@ -0,0 +1,90 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/angusmclean/tavernshell/core/rolllog"
+)
+
+// defaultLogShow is how many rolls 'log show' lists without a count
+const defaultLogShow = 10
+
+// handleLog processes roll log commands
+// Usage: log show [n] | log clear | log export <file.csv|file.json>
+func (m *Model) handleLog(args []string) {
+	if len(args) == 0 {
+		m.addHistory("Usage: log <command> - Commands: show [n], clear, export <file.csv|file.json>")
+		return
+	}
+
+	switch subCmd := strings.ToLower(args[0]); {
+	case strings.HasPrefix("show", subCmd):
+		n := defaultLogShow
+		if len(args) > 1 {
+			count, err := strconv.Atoi(args[1])
+			if err != nil || count < 1 {
+				m.addHistory(fmt.Sprintf("Error: invalid count '%s'", args[1]))
+				return
+			}
+			n = count
+		}
+		entries := m.rollLog.Entries()
+		if len(entries) == 0 {
+			m.addHistory("No rolls logged yet")
+			return
+		}
+		if len(entries) > n {
+			entries = entries[len(entries)-n:]
+		}
+		m.addHistory(fmt.Sprintf("Last %d of %d roll(s):", len(entries), m.rollLog.Len()))
+		for _, e := range entries {
+			m.addHistory("  " + logListing(e))
+		}
+
+	case strings.HasPrefix("clear", subCmd):
+		count := m.rollLog.Len()
+		m.rollLog.Clear()
+		m.addHistory(fmt.Sprintf("Cleared %d roll(s) from the log", count))
+
+	case strings.HasPrefix("export", subCmd):
+		if len(args) < 2 {
+			m.addHistory("Usage: log export <file.csv|file.json>")
+			return
+		}
+		path := strings.Join(args[1:], " ")
+		if err := m.rollLog.Export(path); err != nil {
+			m.addHistory(fmt.Sprintf("Error: %s", err))
+			return
+		}
+		m.addHistory(fmt.Sprintf("Exported %d roll(s) to %s", m.rollLog.Len(), path))
+
+	default:
+		m.addHistory(fmt.Sprintf("Unknown log command: %s", subCmd))
+	}
+}
+
+// handleAnnotate attaches a note to the most recent roll in the roll log
+// Usage: annotate last "<note>"
+func (m *Model) handleAnnotate(args []string) {
+	if len(args) < 2 || strings.ToLower(args[0]) != "last" {
+		m.addHistory("Usage: annotate last \"<note>\" - Add a note to the most recent roll (e.g., 'annotate last \"the killing blow\"')")
+		return
+	}
+	entry, err := m.rollLog.Annotate(strings.Join(args[1:], " "))
+	if err != nil {
+		m.addHistory(fmt.Sprintf("Error: %s", err))
+		return
+	}
+	m.addHistory(fmt.Sprintf("📝 %s", logListing(entry)))
+}
+
+// logListing formats a logged roll for 'log show', 'annotate' and 'find'
+func logListing(e rolllog.Entry) string {
+	line := fmt.Sprintf("%s %s: %s = %d", e.Time.Format("15:04:05"), e.Notation, e.Dice, e.Total)
+	if e.Note != "" {
+		line += fmt.Sprintf(" — %q", e.Note)
+	}
+	return line
+}
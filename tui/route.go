@@ -0,0 +1,78 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/angusmclean/tavernshell/core/config"
+	"github.com/angusmclean/tavernshell/core/dice"
+	"github.com/angusmclean/tavernshell/core/tracker/number"
+)
+
+// splitRoute splits "2d6+3 -> Goblin" into the notation and the target ("" if none)
+func splitRoute(input string) (string, string) {
+	for _, arrow := range []string{"->", "→"} {
+		if notation, target, ok := strings.Cut(input, arrow); ok {
+			return strings.TrimSpace(notation), strings.TrimSpace(target)
+		}
+	}
+	return input, ""
+}
+
+// routeTracker finds the tracker a roll is routed to: a tracker with that name,
+// the tracker linked to a participant with that name, or one named "<name> HP"
+func (m *Model) routeTracker(target string) (*number.Tracker, error) {
+	if t := m.numberTrackerManager.Get(target); t != nil {
+		return t, nil
+	}
+	if tracker := m.initiativeManager.GetTracker(); tracker != nil {
+		for _, p := range tracker.Participants {
+			if strings.EqualFold(p.Name, target) && p.Tracker != "" {
+				if t := m.numberTrackerManager.Get(p.Tracker); t != nil {
+					return t, nil
+				}
+			}
+		}
+	}
+	if t := m.findTrackerFor(target); t != nil {
+		return t, nil
+	}
+	return nil, fmt.Errorf("no tracker for '%s' (use 't add' or 'sync' to create one)", target)
+}
+
+// rollTo rolls notation and subtracts the total from the target's tracker
+// The target is checked first, so nothing is rolled for a typo
+func (m *Model) rollTo(notation, target string) {
+	if m.disabled[config.Trackers] {
+		m.addHistory(fmt.Sprintf("Error: rolling to a tracker is part of %s, turned off for this session (--disable %s)", config.Trackers, m.disabled))
+		return
+	}
+	tracker, err := m.routeTracker(target)
+	if err == nil {
+		err = checkWritable(tracker)
+	}
+	if err != nil {
+		m.addHistory(fmt.Sprintf("Error: %s", err))
+		return
+	}
+	expr, name, err := m.parseRoll(notation)
+	if err != nil {
+		m.addRollError(err)
+		return
+	}
+	m.applyBuffs(expr, name, "damage")
+	result, err := dice.RollExpression(expr)
+	if err != nil {
+		m.addHistory(fmt.Sprintf("Error: %s", err))
+		return
+	}
+
+	m.recordRoll(result)
+	m.addHistory(fmt.Sprintf("🎲 %s%s", rollLabel(name), m.formatDiceResult(result)))
+	m.addRollDetail(result)
+	m.category = categoryTracker
+	before := tracker.Current
+	tracker.Adjust(-result.Total)
+	m.addTrackerHistory(tracker, fmt.Sprintf("[%s] %d/%d (%+d)", tracker.Name, tracker.Current, tracker.Max, -result.Total))
+	m.checkDamage(tracker, before)
+}
@@ -0,0 +1,161 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/angusmclean/tavernshell/core/campaign"
+	"github.com/angusmclean/tavernshell/core/dice"
+	"github.com/angusmclean/tavernshell/core/ruleset"
+	"github.com/angusmclean/tavernshell/core/tracker/number"
+	"github.com/angusmclean/tavernshell/core/tracker/rotation"
+)
+
+// handleRules shows or changes the campaign's ruleset and optional rules
+// Usage: rules [ruleset <name> | massive on|off]
+func (m *Model) handleRules(args []string) {
+	rules := m.campaign.Rules()
+	if len(args) == 0 {
+		r := m.rules()
+		m.addHistory(fmt.Sprintf("Ruleset: %s (%s)", r.Name(), r.Description()))
+		if r.MassiveDamage() {
+			m.addHistory(fmt.Sprintf("  massive %s - Damage leaving max HP or more past 0 HP kills outright", onOff(rules.MassiveDamage)))
+		}
+		if r.DegreesOfSuccess() {
+			m.addHistory("  Checks against a DC have degrees of success: critical by 10, natural 20s and 1s shift a step")
+		}
+		note, _ := r.AtZeroHP()
+		m.addHistory(fmt.Sprintf("  At 0 HP a creature %s", note))
+		m.addHistory(fmt.Sprintf("  Rulesets: %s ('rules ruleset <name>')", strings.Join(ruleset.Names(), ", ")))
+		return
+	}
+	if len(args) != 2 {
+		m.addHistory("Usage: rules [ruleset <name> | massive on|off] - Show or change the campaign's rules")
+		return
+	}
+
+	setting := strings.ToLower(args[0])
+	switch {
+	case strings.HasPrefix("ruleset", setting) && len(setting) >= 4:
+		r, err := ruleset.Get(args[1])
+		if err != nil {
+			m.addHistory(fmt.Sprintf("Error: %s", err))
+			return
+		}
+		rules.Ruleset = r.Name()
+		m.addHistory(fmt.Sprintf("Ruleset: %s (%s)", r.Name(), r.Description()))
+
+	case strings.HasPrefix("massive", setting):
+		switch strings.ToLower(args[1]) {
+		case "on":
+			rules.MassiveDamage = true
+		case "off":
+			rules.MassiveDamage = false
+		default:
+			m.addHistory("Usage: rules massive on|off")
+			return
+		}
+		m.addHistory(fmt.Sprintf("Massive damage rule %s", onOff(rules.MassiveDamage)))
+
+	default:
+		m.addHistory(fmt.Sprintf("Unknown rule: %s (expected ruleset or massive)", setting))
+		return
+	}
+	if err := m.campaign.SetRules(rules); err != nil {
+		m.addHistory(fmt.Sprintf("Warning: failed to save campaign: %s", err))
+	}
+}
+
+// rules returns the campaign's ruleset (the default if it names none we know)
+func (m *Model) rules() ruleset.Ruleset {
+	if r, err := ruleset.Get(m.campaign.Rules().Ruleset); err == nil {
+		return r
+	}
+	r, _ := ruleset.Get(ruleset.Default)
+	return r
+}
+
+// checkRules rejects a roll the ruleset doesn't allow
+func (m *Model) checkRules(expr *dice.Expression) error {
+	if m.rules().Advantage() {
+		return nil
+	}
+	uses := expr.Advantage || expr.Disadvantage
+	for _, g := range expr.Groups {
+		uses = uses || g.Advantage || g.Disadvantage
+	}
+	if uses {
+		return fmt.Errorf("the %s ruleset has no advantage or disadvantage; roll 2d20kh1 or 2d20kl1 instead", m.rules().Name())
+	}
+	return nil
+}
+
+// onOff names a setting's state
+func onOff(on bool) string {
+	if on {
+		return "on"
+	}
+	return "off"
+}
+
+// checkDamage applies the ruleset to damage dealt to a participant's tracker
+// (which stood at before): massive damage, or dropping to 0 HP, may ask
+// whether to mark them dead
+func (m *Model) checkDamage(tracker *number.Tracker, before int) {
+	damage := before - tracker.Current
+	if damage <= 0 {
+		return
+	}
+	p := m.participantFor(tracker)
+	if p == nil || !p.IsActive {
+		return
+	}
+
+	r := m.rules()
+	if r.MassiveDamage() && m.campaign.Rules().MassiveDamage && campaign.IsMassiveDamage(before, damage, tracker.Max) {
+		m.pendingDeath = p.Name
+		m.addTrackerHistory(tracker, fmt.Sprintf("💀 Massive damage: %s took %d (%d past 0 HP, max %d). Dead, not unconscious? (y/n)",
+			p.Name, damage, damage-max(before, 0), tracker.Max))
+		return
+	}
+	if before <= 0 || tracker.Current > 0 {
+		return
+	}
+	note, dies := r.AtZeroHP()
+	if dies {
+		m.pendingDeath = p.Name
+		m.addTrackerHistory(tracker, fmt.Sprintf("💀 %s drops to 0 HP and %s under %s rules. Mark them dead? (y/n)", p.Name, note, r.Name()))
+		return
+	}
+	m.addTrackerHistory(tracker, fmt.Sprintf("%s drops to 0 HP and %s", p.Name, note))
+}
+
+// answerDeath handles the y/n answer to a prompt to mark a participant dead
+func (m *Model) answerDeath(yes bool) {
+	name := m.pendingDeath
+	m.pendingDeath = ""
+	if !yes {
+		m.addHistory(fmt.Sprintf("%s is unconscious at 0 HP", name))
+		return
+	}
+	m.category = categoryInitiative
+	if err := m.initiativeManager.MarkOut(name); err != nil {
+		m.addHistory(fmt.Sprintf("Error: %s", err))
+		return
+	}
+	m.addHistory(fmt.Sprintf("💀 %s is dead", name))
+}
+
+// participantFor finds the initiative participant a tracker belongs to, if any
+func (m *Model) participantFor(t *number.Tracker) *rotation.Participant {
+	tracker := m.initiativeManager.GetTracker()
+	if !m.initiativeManager.IsActive() || tracker == nil {
+		return nil
+	}
+	for _, p := range tracker.Participants {
+		if strings.EqualFold(p.Tracker, t.Name) || (p.Tracker == "" && m.findTrackerFor(p.Name) == t) {
+			return p
+		}
+	}
+	return nil
+}
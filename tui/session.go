@@ -0,0 +1,95 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/angusmclean/tavernshell/core/session"
+)
+
+// OpenSession saves the session's trackers and initiative to a file after
+// every command and when quitting, so they survive a restart or a crash
+// If the file holds a previous session, the user is asked whether to resume it
+func (m *Model) OpenSession(path string) error {
+	state, err := session.Load(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	m.sessionFile = path
+	if state == nil || state.Empty() {
+		return nil
+	}
+
+	m.resumeState = state
+	ended := "ended"
+	if state.Interrupted() {
+		ended = "was interrupted"
+	}
+	m.addHistory(fmt.Sprintf("💾 Your last session %s at %s (%s).", ended, state.Saved.Format("Jan 2 15:04"), state.Summary()))
+	m.addHistory("Resume last session? (y/n)")
+	return nil
+}
+
+// answerResume handles the answer to "Resume last session?"
+func (m *Model) answerResume(input string) {
+	state := m.resumeState
+	switch strings.ToLower(strings.TrimSpace(input)) {
+	case "y", "yes":
+		m.resumeState = nil
+		m.numberTrackerManager.Restore(state.Trackers)
+		if state.Initiative != nil && state.Initiative.HasParticipants() {
+			m.initiativeManager.Restore(state.Initiative)
+			m.startStats("")
+		}
+		m.addHistory(fmt.Sprintf("Resumed last session (%s)", state.Summary()))
+	case "n", "no":
+		m.resumeState = nil
+		m.addHistory("Starting a fresh session")
+	default:
+		m.addHistory("Answer y (resume the last session) or n (start fresh)")
+		return
+	}
+	m.saveSession()
+}
+
+// saveSession saves the session after a command, warning (once) if it can't
+// Until the program ends it is saved as interrupted, so a crash is noticed on
+// the next start
+func (m *Model) saveSession() {
+	if err := m.writeSession(false); err != nil {
+		if !m.sessionSaveFailed {
+			m.addHistory(fmt.Sprintf("Warning: failed to save session: %s", err))
+		}
+		m.sessionSaveFailed = true
+		return
+	}
+	m.sessionSaveFailed = false
+}
+
+// EndSession saves the session as having ended normally, and notes the end
+// of the sitting in the campaign; call it once the program has quit (by
+// command, Ctrl+C or SIGTERM)
+func (m Model) EndSession() error {
+	return errors.Join(m.writeSession(true), m.campaign.EndSession(time.Now()))
+}
+
+// writeSession writes the session's state to the session file, if there is one
+func (m *Model) writeSession(clean bool) error {
+	// Until the user answers, the file still holds the session they may resume
+	if m.sessionFile == "" || m.resumeState != nil {
+		return nil
+	}
+
+	state := &session.State{
+		Saved:    time.Now(),
+		Clean:    clean,
+		Trackers: m.numberTrackerManager.List(),
+	}
+	if m.initiativeManager.IsActive() {
+		state.Initiative = m.initiativeManager.GetTracker()
+	}
+	return session.Save(m.sessionFile, state)
+}
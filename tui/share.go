@@ -0,0 +1,47 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/angusmclean/tavernshell/core/dice"
+	"github.com/atotto/clipboard"
+)
+
+// handleShare processes a share command, printing a code for the last roll
+// that another TavernShell can show with 'show <code>'
+func (m *Model) handleShare(args []string) {
+	if len(args) == 0 || strings.ToLower(args[0]) != "last" {
+		m.addHistory("Usage: share last - Get a code for the most recent roll (others see it with 'show <code>')")
+		return
+	}
+	if m.lastRoll == nil {
+		m.addHistory("No rolls yet")
+		return
+	}
+	if m.lastRoll.Expression.Custom != "" {
+		m.addHistory("Error: custom dice rolls can't be shared (the other side doesn't have the die)")
+		return
+	}
+	code := m.lastRoll.ShareCode(m.lastRollTime)
+	if err := clipboard.WriteAll(code); err == nil {
+		m.addHistory(fmt.Sprintf("📜 %s (copied to clipboard)", code))
+		return
+	}
+	m.addHistory(fmt.Sprintf("📜 %s", code))
+}
+
+// handleShow displays a roll decoded from a share code
+func (m *Model) handleShow(args []string) {
+	if len(args) == 0 {
+		m.addHistory("Usage: show <code> - Display a roll shared with 'share last'")
+		return
+	}
+	result, at, err := dice.DecodeShare(strings.Join(args, ""))
+	if err != nil {
+		m.addHistory(fmt.Sprintf("Error: %s", err))
+		return
+	}
+	m.addHistory(fmt.Sprintf("📜 Shared roll from %s: %s", at.Local().Format("2006-01-02 15:04:05"), m.formatDiceResult(result)))
+	m.addRollDetail(result)
+}
@@ -0,0 +1,33 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// snapshotWidth is the widest a snapshot lets a participant line be
+const snapshotWidth = 200
+
+// snapshotInitiative writes the initiative panel, as players may see it, to a
+// file for sharing with remote players: with the panel's colors as ANSI
+// escapes, or as plain text
+func (m *Model) snapshotInitiative(path string, plain bool) error {
+	// Render from a copy, wide enough that no line is cut short and with
+	// obscured trackers showing only their condition
+	snap := *m
+	snap.width = snapshotWidth * 100 / maxInitiativePercent
+	snap.publicOnly = true
+	lines := snap.buildInitiativePanel()
+	if len(lines) == 0 {
+		return fmt.Errorf("no initiative in progress (use 'i start')")
+	}
+
+	text := m.glyphs(strings.Join(lines, "\n") + "\n")
+	if plain {
+		text = ansi.Strip(text)
+	}
+	return os.WriteFile(path, []byte(text), 0644)
+}
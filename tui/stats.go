@@ -0,0 +1,154 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/angusmclean/tavernshell/core/dice"
+)
+
+// statsChartRows caps how many lines the 'stats' bar chart takes
+const statsChartRows = 20
+
+// handleStats charts the spread of totals a dice expression can roll
+// Usage: stats <dice>
+func (m *Model) handleStats(args []string) {
+	if len(args) == 0 {
+		m.addHistory("Usage: stats <dice> - Show the min, max, mean and spread of a roll (e.g., 'stats 4d6kh3'), or 'stats campaign' for a recap")
+		return
+	}
+	if len(args) == 1 && strings.EqualFold(args[0], "campaign") {
+		m.campaignRecap()
+		return
+	}
+	expr, err := dice.Parse(strings.Join(args, " "))
+	if err != nil {
+		m.addRollError(err)
+		return
+	}
+	stats, err := dice.Analyze(expr)
+	if err != nil {
+		m.addHistory(fmt.Sprintf("Error: %s", err))
+		return
+	}
+
+	m.addHistory(fmt.Sprintf("📊 %s", stats.Summary()))
+	for _, line := range stats.Chart(statsChartRows, max(m.width-30, 10)) {
+		m.addHistory("  " + m.glyphs(line))
+	}
+}
+
+// handleSim rolls dice many times, reporting how often they meet a target
+// number and the spread of totals that actually came up
+// Usage: sim [count] <dice> [vs <target>]
+func (m *Model) handleSim(args []string) {
+	if len(args) == 0 {
+		m.addHistory("Usage: sim [count] <dice> [vs <target>] - Roll 100,000 times (or count, up to 1,000,000) and chart the results (e.g., 'sim 100000 2d6+1d8 vs 15')")
+		return
+	}
+	count, counted := dice.DefaultSimulations, false
+	if n, err := strconv.Atoi(strings.ReplaceAll(args[0], ",", "")); err == nil && len(args) > 1 {
+		count, counted = n, true
+		args = args[1:]
+	}
+	notation, target, check, err := dice.SplitCheck(strings.Join(args, " "))
+	if err != nil {
+		m.addHistory(fmt.Sprintf("Error: %s", err))
+		return
+	}
+	expr, name, err := m.parseRoll(notation)
+	if err != nil {
+		m.addRollError(err)
+		return
+	}
+	if !counted {
+		// Fewer rolls by default for a roll of many dice, to keep it quick
+		count = min(count, dice.MaxRolls(expr))
+	}
+
+	start := time.Now()
+	sim, err := dice.Simulate(expr, count, dice.SimulationRoller(), target, check)
+	if err != nil {
+		m.addHistory(fmt.Sprintf("Error: %s", err))
+		return
+	}
+	m.addHistory(fmt.Sprintf("📊 %s%s (in %s)", rollLabel(name), sim.Summary(), time.Since(start).Round(time.Millisecond)))
+	for _, line := range sim.Chart(statsChartRows, max(m.width-30, 10)) {
+		m.addHistory("  " + m.glyphs(line))
+	}
+}
+
+// handleFixed works out a roll's average (rounded down, as in 5e stat blocks),
+// minimum or maximum without rolling, e.g. for quick monster damage
+// Usage: avg|min|max <dice>
+func (m *Model) handleFixed(kind string, args []string) {
+	if len(args) == 0 {
+		m.addHistory(fmt.Sprintf("Usage: %s <dice> - Work out a roll's %s without rolling (e.g., '%s 8d6')", kind, fixedNames[kind], kind))
+		return
+	}
+	expr, name, err := m.parseRoll(strings.Join(args, " "))
+	if err != nil {
+		m.addRollError(err)
+		return
+	}
+	stats, err := dice.Analyze(expr)
+	if err != nil {
+		m.addHistory(fmt.Sprintf("Error: %s", err))
+		return
+	}
+
+	value := stats.Average()
+	switch kind {
+	case "min":
+		value = stats.Min
+	case "max":
+		value = stats.Max
+	}
+	line := fmt.Sprintf("📊 %s%s: %s %d", rollLabel(name), expr.String(), fixedNames[kind], value)
+	if kind == "avg" && stats.Simulated {
+		line += " (estimated from simulated rolls)"
+	}
+	m.addHistory(line)
+}
+
+// fixedNames names what each of avg, min and max works out
+var fixedNames = map[string]string{
+	"avg": "average",
+	"min": "minimum",
+	"max": "maximum",
+}
+
+// handleSelfTest checks that the dice roller is fair, rolling a large sample
+// of each common die and testing the spread of faces with chi-squared
+// Usage: selftest dice
+func (m *Model) handleSelfTest(args []string) {
+	if len(args) != 1 || !strings.HasPrefix("dice", strings.ToLower(args[0])) {
+		m.addHistory("Usage: selftest dice - Roll large samples of each die and check they're fair (chi-squared)")
+		return
+	}
+
+	roller := dice.CurrentRoller()
+	suspicious := 0
+	m.addHistory("🧪 Dice self-test: does each face come up equally often?")
+	for _, sides := range dice.SelfTestSides {
+		u, err := dice.CheckUniformity(roller, sides, sides*dice.SelfTestRollsPerFace)
+		if err != nil {
+			m.addHistory(fmt.Sprintf("  Error: d%d: %s", sides, err))
+			suspicious++
+			continue
+		}
+		verdict := "✓ fair"
+		if !u.Fair() {
+			verdict = "✗ suspicious"
+			suspicious++
+		}
+		m.addHistory(fmt.Sprintf("  %s  %s", u.Summary(), verdict))
+	}
+	if suspicious == 0 {
+		m.addHistory("All dice look fair (a fair die fails a check only once in a thousand runs)")
+	} else {
+		m.addHistory(fmt.Sprintf("%d of %d dice look unfair; run it again, since a fair die fails now and then", suspicious, len(dice.SelfTestSides)))
+	}
+}
@@ -0,0 +1,76 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/angusmclean/tavernshell/core/tracker/timer"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// statusLine summarizes the round and the first alarm for a terminal title or
+// tmux status bar, e.g. "R4 · Bless 2r"
+// The alarm is the one first in the timer bar: the pinned one, or else the one closest to finishing
+func (m Model) statusLine() string {
+	var parts []string
+	if round := m.currentRound(); round > 0 && m.initiativeManager.IsActive() {
+		parts = append(parts, fmt.Sprintf("R%d", round))
+	}
+	if active := m.timerManager.GetActive(); len(active) > 0 {
+		t := active[0]
+		left := timer.FormatDurationShort(t.Remaining())
+		if t.IsRoundBased() {
+			left = fmt.Sprintf("%dr", t.Rounds)
+		}
+		if t.Label != "" {
+			left = t.Label + " " + left
+		}
+		parts = append(parts, left)
+	}
+	if len(parts) == 0 {
+		return "TavernShell"
+	}
+	return m.glyphs(strings.Join(parts, " · "))
+}
+
+// ShowStatusInTitle keeps the terminal title set to the status line
+func (m *Model) ShowStatusInTitle() {
+	m.statusTitle = true
+}
+
+// WriteStatusTo keeps the status line in a file, e.g. for tmux:
+//
+//	set -g status-right '#(cat /tmp/tavernshell.status)'
+func (m *Model) WriteStatusTo(path string) error {
+	if err := os.WriteFile(path, []byte(m.statusLine()+"\n"), 0644); err != nil {
+		return fmt.Errorf("failed to write status file: %w", err)
+	}
+	m.statusFile = path
+	return nil
+}
+
+// updateStatus publishes the status line if it changed since the last tick
+func (m *Model) updateStatus() tea.Cmd {
+	if !m.statusTitle && m.statusFile == "" {
+		return nil
+	}
+	status := m.statusLine()
+	if status == m.lastStatus {
+		return nil
+	}
+	m.lastStatus = status
+
+	if m.statusFile != "" {
+		if err := os.WriteFile(m.statusFile, []byte(status+"\n"), 0644); err != nil {
+			// Stop trying rather than warning every second
+			m.category = categorySystem
+			m.addHistory(fmt.Sprintf("Warning: status file disabled: %s", err))
+			m.statusFile = ""
+		}
+	}
+	if m.statusTitle {
+		return tea.SetWindowTitle(status)
+	}
+	return nil
+}
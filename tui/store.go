@@ -0,0 +1,141 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/angusmclean/tavernshell/core/dice"
+	"github.com/angusmclean/tavernshell/core/macro"
+)
+
+// handleStore rolls dice and keeps the total under a name, for later
+// commands to use as $name
+// Usage: store <name> = <dice or number>, store list, store delete <name>, store clear
+func (m *Model) handleStore(args []string) {
+	if len(args) == 0 || (len(args) == 1 && strings.EqualFold(args[0], "list")) {
+		m.listStored()
+		return
+	}
+
+	switch strings.ToLower(args[0]) {
+	case "delete", "d":
+		if len(args) < 2 {
+			m.addHistory("Usage: store delete <name>")
+			return
+		}
+		name := strings.TrimPrefix(args[1], "$")
+		if _, ok := m.stored[name]; !ok {
+			m.addHistory(fmt.Sprintf("Error: nothing stored as $%s", name))
+			return
+		}
+		delete(m.stored, name)
+		m.addHistory(fmt.Sprintf("Forgot $%s", name))
+		return
+	case "clear":
+		m.stored = nil
+		m.addHistory("Cleared all stored values")
+		return
+	}
+
+	// "damage = 2d10+5", "damage=2d10+5" or "damage 2d10+5"
+	name, notation, ok := strings.Cut(strings.Join(args, " "), "=")
+	if !ok {
+		name, notation = args[0], strings.Join(args[1:], " ")
+	}
+	name = strings.TrimPrefix(strings.TrimSpace(name), "$")
+	notation = strings.TrimSpace(macro.Substitute(notation, m.storedVars()))
+	if notation == "" {
+		m.addHistory("Usage: store <name> = <dice> (e.g., 'store damage = 2d10+5', then 't adj HP -$damage')")
+		return
+	}
+	if !macro.IsName(name) || unicode.IsDigit(rune(name[0])) {
+		m.addHistory(fmt.Sprintf("Error: '%s' can't be a name (use letters, digits and _, starting with a letter)", name))
+		return
+	}
+
+	total, err := strconv.Atoi(notation)
+	if err != nil {
+		expr, rollName, err := m.parseRoll(notation)
+		if err != nil {
+			m.addRollError(err)
+			return
+		}
+		m.applyBuffs(expr, rollName, "store")
+		result, err := dice.RollExpression(expr)
+		if err != nil {
+			m.addHistory(fmt.Sprintf("Error: %s", err))
+			return
+		}
+		m.recordRoll(result)
+		m.addHistory(fmt.Sprintf("🎲 %s%s", rollLabel(rollName), m.formatDiceResult(result)))
+		m.addRollDetail(result)
+		total = result.Total
+	}
+
+	if m.stored == nil {
+		m.stored = make(map[string]int)
+	}
+	m.stored[name] = total
+	m.addHistory(fmt.Sprintf("  Stored $%s = %d", name, total))
+}
+
+// listStored shows the values kept with 'store'
+func (m *Model) listStored() {
+	if len(m.stored) == 0 {
+		m.addHistory("Nothing stored (use 'store <name> = <dice>', then $name in later commands)")
+		return
+	}
+	names := make([]string, 0, len(m.stored))
+	for name := range m.stored {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for i, name := range names {
+		names[i] = fmt.Sprintf("$%s = %d", name, m.stored[name])
+	}
+	m.addHistory("Stored: " + strings.Join(names, ", "))
+}
+
+// storedVars returns the values kept with 'store' as variables
+func (m *Model) storedVars() map[string]string {
+	vars := make(map[string]string, len(m.stored))
+	for name, value := range m.stored {
+		vars[name] = strconv.Itoa(value)
+	}
+	return vars
+}
+
+// substituteStored fills in $name with values kept by 'store', except in
+// macro and turn script definitions, which fill theirs in when they run, and
+// in 'store' itself, which names the values it replaces or deletes
+func (m *Model) substituteStored(parts []string) []string {
+	if len(m.stored) == 0 || len(parts) == 0 || definesCommands(parts) || isStore(parts[0]) {
+		return parts
+	}
+
+	vars := m.storedVars()
+	substituted := make([]string, len(parts))
+	for i, part := range parts {
+		substituted[i] = macro.Substitute(part, vars)
+	}
+	return substituted
+}
+
+// isStore returns true if cmd is the 'store' command
+func isStore(cmd string) bool {
+	cmd = strings.ToLower(cmd)
+	return strings.HasPrefix("store", cmd) && len(cmd) >= 3
+}
+
+// definesCommands returns true for a macro or turn script definition, whose
+// commands fill in $name and @name when they run rather than when they're saved
+func definesCommands(parts []string) bool {
+	cmd := strings.ToLower(parts[0])
+	if strings.HasPrefix("macro", cmd) && len(cmd) >= 2 {
+		return true
+	}
+	return (strings.HasPrefix("initiative", cmd) || cmd == "i") && len(parts) > 1 && strings.EqualFold(parts[1], "script")
+}
@@ -0,0 +1,53 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/angusmclean/tavernshell/core/dice"
+)
+
+func TestStoredInBareRoll(t *testing.T) {
+	defer dice.SetRoller(dice.CurrentRoller())
+
+	tests := []struct {
+		command  string
+		expected int
+	}{
+		{"d20+$bonus", 15},
+		{"d20+$bonus # Aria attack", 15},
+		{"d20+5 vs $dc", 15},
+		{"d20-$bonus", 5},
+	}
+	for _, tt := range tests {
+		dice.SetRoller(dice.NewSequenceRoller(10))
+		m := runCommands("store bonus = 5", "store dc = 14", tt.command)
+		if m.lastRoll == nil || m.lastRoll.Total != tt.expected {
+			t.Errorf("%s: expected total %d, got %v (%q)", tt.command, tt.expected, m.lastRoll, lastLine(m))
+		}
+	}
+}
+
+func TestStoreNamesStoredValues(t *testing.T) {
+	defer dice.SetRoller(dice.CurrentRoller())
+
+	m := runCommands("store dc = 15", "store bonus = 5", "store delete $dc")
+	if _, ok := m.stored["dc"]; ok {
+		t.Errorf("Expected $dc to be deleted, got %q", lastLine(m))
+	}
+
+	m = runCommands("store dc = 15", "store $dc = 12")
+	if m.stored["dc"] != 12 || len(m.stored) != 1 {
+		t.Errorf("Expected $dc replaced with 12, got %v", m.stored)
+	}
+
+	// The value stored can still use other stored values
+	dice.SetRoller(dice.NewSequenceRoller(8))
+	m = runCommands("store bonus = 5", "store dc = d20+$bonus")
+	if m.stored["dc"] != 13 {
+		t.Errorf("Expected $dc = 13, got %v (%q)", m.stored, lastLine(m))
+	}
+	if !strings.Contains(lastLine(m), "Stored $dc = 13") {
+		t.Errorf("Expected 'Stored $dc = 13', got %q", lastLine(m))
+	}
+}
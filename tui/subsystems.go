@@ -0,0 +1,48 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/angusmclean/tavernshell/core/config"
+)
+
+// Disable turns subsystems off for the session: their commands are refused,
+// and their bars and panels are left out of the layout
+func (m *Model) Disable(d config.Disabled) {
+	m.disabled = d
+}
+
+// commandSubsystems returns the subsystems a command uses, among those that
+// can be turned off
+func commandSubsystems(parts []string) []config.Subsystem {
+	cmd := strings.ToLower(parts[0])
+	switch {
+	case strings.HasPrefix("alarm", cmd) || cmd == "a",
+		strings.HasPrefix("buff", cmd) && len(cmd) >= 2,
+		strings.HasPrefix("explore", cmd) && len(cmd) >= 4:
+		return []config.Subsystem{config.Timers}
+	case strings.HasPrefix("initiative", cmd) || strings.HasPrefix("init", cmd) || cmd == "i",
+		strings.HasPrefix("sync", cmd) && len(cmd) >= 2:
+		return []config.Subsystem{config.Initiative}
+	case strings.HasPrefix("tracker", cmd) || strings.HasPrefix("track", cmd) || cmd == "t":
+		return []config.Subsystem{config.Trackers}
+	case cmd == "turns" || cmd == "turn":
+		// Dungeon turns count down round timers and light trackers
+		return []config.Subsystem{config.Timers, config.Trackers}
+	case strings.HasPrefix("party", cmd) && len(cmd) >= 2 && len(parts) > 1:
+		if sub := strings.ToLower(parts[1]); strings.HasPrefix("init", sub) || sub == "i" {
+			return []config.Subsystem{config.Initiative}
+		}
+	}
+	return nil
+}
+
+// disabledFor returns the first subsystem a command uses that's turned off
+func (m *Model) disabledFor(parts []string) (config.Subsystem, bool) {
+	for _, s := range commandSubsystems(parts) {
+		if m.disabled[s] {
+			return s, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,65 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/angusmclean/tavernshell/core/config"
+)
+
+// runDisabled runs commands against a fresh model with subsystems turned off
+func runDisabled(t *testing.T, disable string, commands ...string) *Model {
+	d, err := config.ParseDisabled(disable)
+	if err != nil {
+		t.Fatalf("ParseDisabled failed: %v", err)
+	}
+	m := NewModel()
+	m.Disable(d)
+	for _, command := range commands {
+		m.handleBatch(command)
+	}
+	return &m
+}
+
+func TestDisabledCommands(t *testing.T) {
+	tests := []struct {
+		disable string
+		command string
+	}{
+		{"timers", "a 5m Torch"},
+		{"timers", "buff Aria +2"},
+		{"timers", "explore start 10m forest"},
+		{"timers", "turns start"},
+		{"trackers", "t add HP 10"},
+		{"trackers", "turns next"},
+		{"trackers", "2d6 -> Goblin"},
+		{"trackers", "r 2d6 -> Goblin"},
+		{"initiative", "i s"},
+		{"initiative", "sync"},
+		{"initiative", "party init"},
+		{"initiative", "party i"},
+	}
+	for _, tt := range tests {
+		m := runDisabled(t, tt.disable, tt.command)
+		if line := lastLine(m); !strings.Contains(line, "turned off for this session") {
+			t.Errorf("%s with %s off: expected it refused, got %q", tt.command, tt.disable, line)
+		}
+	}
+}
+
+func TestDisabledTrackersEntry(t *testing.T) {
+	// Initiative still takes HP, without making a tracker for it
+	m := runDisabled(t, "trackers", "i s", "Goblin 12 7", "done")
+	if p := participant(m, "Goblin"); p == nil || p.HP != 7 {
+		t.Errorf("Expected Goblin with 7 HP, got %v", p)
+	}
+	if tr := m.numberTrackerManager.Get("Goblin"); tr != nil {
+		t.Errorf("Expected no tracker with trackers off, got %v", tr)
+	}
+
+	// Other party commands and everything else still work
+	m = runDisabled(t, "initiative", "party list")
+	if strings.Contains(lastLine(m), "turned off") {
+		t.Errorf("Expected party list to work, got %q", lastLine(m))
+	}
+}
@@ -0,0 +1,64 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/angusmclean/tavernshell/core/tracker/number"
+)
+
+// handleSync reconciles initiative participants with number trackers
+// Participants are linked to trackers named after them ("Goblin" or "Goblin HP"),
+// and participants with known HP but no tracker get a new one
+func (m *Model) handleSync() {
+	if !m.initiativeManager.IsActive() {
+		m.addHistory("No active initiative. Use 'i start' to begin.")
+		return
+	}
+	tracker := m.initiativeManager.GetTracker()
+	if tracker == nil {
+		return
+	}
+
+	var linked, created []string
+	for _, p := range tracker.Participants {
+		if p.Summoner != "" {
+			continue
+		}
+		if p.Tracker != "" && m.numberTrackerManager.Get(p.Tracker) != nil {
+			continue
+		}
+
+		if t := m.findTrackerFor(p.Name); t != nil {
+			m.initiativeManager.Link(p.Name, t.Name)
+			linked = append(linked, fmt.Sprintf("%s ↔ %s", p.Name, t.Name))
+			continue
+		}
+		if p.HP > 0 {
+			t := m.numberTrackerManager.Add(p.Name, p.HP, p.HP)
+			m.initiativeManager.Link(p.Name, t.Name)
+			created = append(created, fmt.Sprintf("%s (%d HP)", t.Name, p.HP))
+		}
+	}
+
+	if len(linked) == 0 && len(created) == 0 {
+		m.addHistory("Nothing to sync: every participant is already linked or has no tracker or HP")
+		return
+	}
+	if len(linked) > 0 {
+		m.addHistory(fmt.Sprintf("Linked: %s", strings.Join(linked, ", ")))
+	}
+	if len(created) > 0 {
+		m.addHistory(fmt.Sprintf("Created trackers: %s", strings.Join(created, ", ")))
+	}
+}
+
+// findTrackerFor finds a number tracker named after a participant
+func (m *Model) findTrackerFor(name string) *number.Tracker {
+	for _, candidate := range []string{name, name + " HP"} {
+		if t := m.numberTrackerManager.Get(candidate); t != nil {
+			return t
+		}
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// handleTable processes random table commands
+func (m *Model) handleTable(args []string) {
+	if len(args) == 0 {
+		m.addHistory("Usage: table <command> - Commands: load <file>, list/l, roll/r <name> [+/-N]")
+		return
+	}
+
+	subCmd := strings.ToLower(args[0])
+
+	switch {
+	case strings.HasPrefix("load", subCmd) && len(subCmd) >= 2:
+		if len(args) < 2 {
+			m.addHistory("Usage: table load <file>")
+			return
+		}
+		path := strings.Join(args[1:], " ")
+		t, err := m.tableManager.LoadFile(path)
+		if err != nil {
+			m.addHistory(fmt.Sprintf("Error: %s", err))
+			return
+		}
+		m.watcher.watch(path, reloadTable)
+		m.addHistory(fmt.Sprintf("Loaded table '%s' (d%d, %d entries)", t.Name, t.Sides(), len(t.Entries)))
+
+	case strings.HasPrefix("list", subCmd):
+		tables := m.tableManager.List()
+		if len(tables) == 0 {
+			m.addHistory("No tables loaded (use 'table load <file>')")
+			return
+		}
+		m.addHistory("Tables:")
+		for _, t := range tables {
+			m.addHistory(fmt.Sprintf("  %s (d%d, %d entries)", t.Name, t.Sides(), len(t.Entries)))
+		}
+
+	case strings.HasPrefix("roll", subCmd):
+		if len(args) < 2 {
+			m.addHistory("Usage: table roll <name> [+/-N] (e.g., 'table roll treasure +25')")
+			return
+		}
+		modifier := 0
+		if len(args) > 2 {
+			var err error
+			modifier, err = strconv.Atoi(args[2])
+			if err != nil {
+				m.addHistory("Error: modifier must be a number (e.g., +25 or -10)")
+				return
+			}
+		}
+		result, err := m.tableManager.Roll(args[1], modifier)
+		if err != nil {
+			m.addHistory(fmt.Sprintf("Error: %s", err))
+			return
+		}
+		m.addHistory(fmt.Sprintf("📜 %s", result))
+
+	default:
+		m.addHistory(fmt.Sprintf("Unknown table command: %s", subCmd))
+	}
+}
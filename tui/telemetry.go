@@ -0,0 +1,228 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/angusmclean/tavernshell/core/campaign"
+	"github.com/angusmclean/tavernshell/core/tracker/number"
+	"github.com/angusmclean/tavernshell/core/tracker/rotation"
+)
+
+// encounterStats measures an encounter while initiative runs
+type encounterStats struct {
+	difficulty string         // intended difficulty ("" if not set)
+	snapshot   map[string]int // tracker values at the start of the current round
+	damage     []int          // tracker drops in each finished round
+	taken      int            // the party's share of those drops
+	started    time.Time
+}
+
+// statsShelf keeps the measurements of encounters swapped out by 'i resume'
+type statsShelf map[*rotation.Tracker]*encounterStats
+
+// startStats starts measuring a new encounter built at difficulty ("" if unknown)
+func (m *Model) startStats(difficulty string) {
+	m.stats = &encounterStats{snapshot: m.snapshotTrackers(), started: time.Now()}
+	if difficulty != "" {
+		m.setDifficulty(difficulty)
+	}
+}
+
+// resumeStats swaps the measurements of the encounter shelved by 'i resume'
+// (nil if none was running) for those of the one resumed, so an encounter
+// swapped back in picks up its rounds where it left off; one that had ended
+// was already recorded, and is measured anew
+func (m *Model) resumeStats(shelved, resumed *rotation.Tracker) {
+	if m.shelvedStats == nil {
+		m.shelvedStats = make(statsShelf)
+	}
+	if shelved != nil && m.stats != nil {
+		m.shelvedStats[shelved] = m.stats
+	}
+	stats, ok := m.shelvedStats[resumed]
+	if !ok {
+		m.startStats("")
+	} else {
+		m.stats = stats
+		delete(m.shelvedStats, resumed)
+	}
+
+	// Encounters that have dropped off the recent list can't come back
+	recent := make(map[*rotation.Tracker]bool)
+	for _, t := range m.initiativeManager.Recent() {
+		recent[t] = true
+	}
+	for t := range m.shelvedStats {
+		if !recent[t] {
+			delete(m.shelvedStats, t)
+		}
+	}
+}
+
+// setDifficulty records the current encounter's intended difficulty, and
+// reminds the DM how past encounters built that way actually played
+func (m *Model) setDifficulty(level string) {
+	difficulty, err := campaign.ParseDifficulty(level)
+	if err != nil {
+		m.addHistory(fmt.Sprintf("Error: %s", err))
+		return
+	}
+	if m.stats == nil {
+		m.addHistory("No active initiative. Use 'i start' to begin.")
+		return
+	}
+	m.stats.difficulty = difficulty
+	m.addHistory(fmt.Sprintf("📊 Encounter built as %s", difficulty))
+	if summary, count := m.campaign.PlayedAs(difficulty); count > 0 {
+		m.addHistory(fmt.Sprintf("📊 Your last %d %s encounters played as: %s", count, difficulty, summary))
+	}
+}
+
+// snapshotTrackers returns every tracker's current value, by name
+func (m *Model) snapshotTrackers() map[string]int {
+	snapshot := make(map[string]int)
+	for _, t := range m.numberTrackerManager.List() {
+		snapshot[t.Name] = t.Current
+	}
+	return snapshot
+}
+
+// snapshotNewTrackers adds trackers made since the round began (e.g. HP
+// trackers from initiative entry) to the snapshot at their current value, so
+// they count from when they were made; it runs after every command
+func (m *Model) snapshotNewTrackers() {
+	if m.stats == nil {
+		return
+	}
+	for name, value := range m.snapshotTrackers() {
+		if _, ok := m.stats.snapshot[name]; !ok {
+			m.stats.snapshot[name] = value
+		}
+	}
+}
+
+// closeRound adds up the HP lost since the round began, by the trackers of
+// everyone in initiative; spell slots, ki and the like don't count
+func (m *Model) closeRound() {
+	if m.stats == nil {
+		return
+	}
+	party := make(map[string]bool)
+	for _, t := range m.partyTrackers() {
+		party[t.Name] = true
+	}
+	damage := 0
+	for _, t := range m.participantTrackers(false) {
+		before, ok := m.stats.snapshot[t.Name]
+		if !ok {
+			before = t.Current
+		}
+		if t.Current < before {
+			damage += before - t.Current
+			if party[t.Name] {
+				m.stats.taken += before - t.Current
+			}
+		}
+	}
+	m.stats.damage = append(m.stats.damage, damage)
+	m.stats.snapshot = m.snapshotTrackers()
+}
+
+// partyTrackers returns the trackers of the party: imported characters and
+// anyone on the party side
+func (m *Model) partyTrackers() []*number.Tracker {
+	return m.participantTrackers(true)
+}
+
+// participantTrackers returns the HP trackers of everyone in initiative, or
+// only of the party
+func (m *Model) participantTrackers(partyOnly bool) []*number.Tracker {
+	names := make(map[string]bool)
+	for _, c := range m.roster.List() {
+		names[strings.ToLower(c.Name)] = true
+	}
+
+	var trackers []*number.Tracker
+	seen := make(map[string]bool)
+	if tracker := m.initiativeManager.GetTracker(); tracker != nil {
+		for _, p := range tracker.Participants {
+			if partyOnly && !names[strings.ToLower(p.Name)] && p.Side != partySide {
+				continue
+			}
+			t := m.numberTrackerManager.Get(p.Tracker)
+			if t == nil {
+				t = m.findTrackerFor(p.Name)
+			}
+			if t != nil && !seen[t.Name] {
+				seen[t.Name] = true
+				trackers = append(trackers, t)
+			}
+		}
+	}
+	return trackers
+}
+
+// partySpent returns the share of the party's tracked resources used up this
+// encounter (false if none of the party has a tracker)
+func (m *Model) partySpent() (float64, bool) {
+	lost, start := 0, 0
+	for _, t := range m.partyTrackers() {
+		if !t.HasBaseline || t.Baseline <= 0 {
+			continue
+		}
+		start += t.Baseline
+		if t.Current < t.Baseline {
+			lost += t.Baseline - t.Current
+		}
+	}
+	if start == 0 {
+		return 0, false
+	}
+	return float64(lost) / float64(start), true
+}
+
+// finishStats reports how the encounter went and records it in the campaign
+// Call it before the encounter's tracker baselines are cleared
+func (m *Model) finishStats() {
+	if m.stats == nil {
+		return
+	}
+	m.closeRound()
+	result := campaign.EncounterResult{
+		Time:          time.Now(),
+		Intended:      m.stats.difficulty,
+		Rounds:        len(m.stats.damage),
+		DamageByRound: m.stats.damage,
+		DamageTaken:   m.stats.taken,
+		Duration:      time.Since(m.stats.started).Round(time.Second),
+	}
+	m.stats = nil
+
+	total := 0
+	rounds := make([]string, len(result.DamageByRound))
+	for i, d := range result.DamageByRound {
+		total += d
+		rounds[i] = fmt.Sprintf("%d", d)
+	}
+	m.addHistory(fmt.Sprintf("📊 Encounter: %d rounds, damage per round %s (avg %.1f)",
+		result.Rounds, strings.Join(rounds, " / "), float64(total)/float64(max(result.Rounds, 1))))
+
+	spent, ok := m.partySpent()
+	if !ok {
+		m.addHistory("📊 No party trackers found, so difficulty can't be rated ('party import' or a 'party' side)")
+	} else {
+		result.PartySpent = spent
+		result.Actual = campaign.RateDifficulty(spent)
+		line := fmt.Sprintf("📊 The party spent %.0f%% of their resources: played as %s", spent*100, result.Actual)
+		if verdict := result.Verdict(); verdict != "" {
+			line += fmt.Sprintf(" (built as %s: %s)", result.Intended, verdict)
+		}
+		m.addHistory(line)
+	}
+
+	if err := m.campaign.RecordEncounter(result); err != nil {
+		m.addHistory(fmt.Sprintf("Warning: failed to save campaign: %s", err))
+	}
+}
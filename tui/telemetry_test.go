@@ -0,0 +1,68 @@
+package tui
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRoundDamageCountsHP(t *testing.T) {
+	// HP trackers made during entry, after 'i s' started measuring; ki and
+	// the like don't count, even when made mid-round below their maximum
+	m := runCommands("i s", "Ogre 10 59", "Aria 15 31", "done",
+		"t adj Ogre -10", "t adj Aria -5", "t add Ki 3 5", "t adj Ki -1",
+		"i n", "i n")
+	if m.stats == nil || len(m.stats.damage) != 1 {
+		t.Fatalf("Expected one finished round, got %v", m.stats)
+	}
+	if m.stats.damage[0] != 15 {
+		t.Errorf("Expected 15 damage in round 1, got %d", m.stats.damage[0])
+	}
+
+	// A tracker made mid-round counts from its value when it was made
+	m.handleBatch("i a")
+	m.handleBatch("Wolf 5")
+	m.handleBatch("done")
+	m.handleBatch("t add Wolf 6 11")
+	m.handleBatch("t adj Wolf -2")
+	m.handleBatch("i n")
+	m.handleBatch("i n")
+	m.handleBatch("i n")
+	if len(m.stats.damage) != 2 || m.stats.damage[1] != 2 {
+		t.Errorf("Expected 2 damage in round 2, got %v", m.stats.damage)
+	}
+}
+
+func TestRoundDamageKeptAcrossResume(t *testing.T) {
+	m := runCommands("i s", "Ogre 10 59", "Aria 15 31", "done", "t adj Ogre -10", "i n", "i n", "i e",
+		"i s", "Wolf 12 11", "done", "t adj Wolf -3", "i n")
+	if m.stats == nil || len(m.stats.damage) != 1 || m.stats.damage[0] != 3 {
+		t.Fatalf("Expected the wolf's encounter to have 3 damage in round 1, got %v", m.stats)
+	}
+
+	// Swapping the ended encounter back in measures it anew, and swapping
+	// back to the wolf's picks up where it left off
+	m.handleBatch("i resume")
+	if len(m.stats.damage) != 0 {
+		t.Errorf("Expected the resumed encounter measured anew, got %v", m.stats.damage)
+	}
+	m.handleBatch("i resume")
+	if len(m.stats.damage) != 1 || m.stats.damage[0] != 3 {
+		t.Errorf("Expected the wolf's encounter back with 3 damage in round 1, got %v", m.stats.damage)
+	}
+}
+
+func TestRoundDamageTakenByParty(t *testing.T) {
+	m := runCommands("i s side", "Ogre 10 59 monsters", "Aria 15 31 party", "done",
+		"t adj Ogre -10", "t adj Aria -5", "i n", "i n")
+	if m.stats == nil || len(m.stats.damage) != 1 {
+		t.Fatalf("Expected one finished round, got %v", m.stats)
+	}
+	if m.stats.damage[0] != 15 || m.stats.taken != 5 {
+		t.Errorf("Expected 15 damage with 5 taken by the party, got %d with %d", m.stats.damage[0], m.stats.taken)
+	}
+
+	m.handleBatch("i end")
+	if r := m.campaign.Recap(time.Now()); r.DamageDealt != 10 || r.DamageTaken != 5 {
+		t.Errorf("Expected 10 damage dealt and 5 taken in the recap, got %d and %d", r.DamageDealt, r.DamageTaken)
+	}
+}
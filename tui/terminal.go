@@ -0,0 +1,88 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// Smallest terminal the full layout fits in; anything smaller gets a warning panel
+const (
+	minTerminalWidth  = 60
+	minTerminalHeight = 12
+)
+
+// asciiGlyphs swaps the symbols and emoji we draw for plain ASCII,
+// for terminals that can't render them (the Linux console, non-UTF-8 locales)
+var asciiGlyphs = strings.NewReplacer(
+	"⚔️", "><", "⚔", "><",
+	"🎲", "*", "⏰", "!", "⌛", "~", "🔒", "(w)", "🤨", "?!", "📜", "#", "📌", "^", "✨", "+", "⏺", "(rec)", "📊", "%", "🔍", "?", "📝", "#", "🔄", "(r)", "🧪", "(t)", "🌦", "(e)", "🧭", "(x)", "🪙", "(o)", "📸", "(s)", "🕯", "(i)", "🔥", "(f)", "👣", "(m)", "💀", "x_x", "—", "-", "☐", "[ ]", "»", ">>",
+	"➤", ">", "▶", ">", "└", "`-", "─", "-", "█", "#", "░", ".",
+	"▼", "v", "▲", "^", "✗", "x", "✓", "+",
+	"▁", "_", "▂", "_", "▃", "-", "▄", "-", "▅", "=", "▆", "=", "▇", "#",
+	"→", "->", "↔", "<->", "↑", "^", "↓", "v", "±", "+/-",
+	"·", ".", "‹", "(", "›", ")", "–", "-",
+)
+
+// unicodeSupported guesses from TERM and the locale whether the terminal can
+// draw Unicode symbols and emoji
+func unicodeSupported() bool {
+	switch os.Getenv("TERM") {
+	case "linux", "dumb", "vt100", "vt220":
+		return false
+	}
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if locale := os.Getenv(name); locale != "" {
+			locale = strings.ToLower(locale)
+			return strings.Contains(locale, "utf-8") || strings.Contains(locale, "utf8")
+		}
+	}
+	// No locale set; most terminals still handle UTF-8
+	return true
+}
+
+// terminalReport describes any limits detected in the terminal ("" if none)
+func (m Model) terminalReport() string {
+	var limits []string
+	switch lipgloss.ColorProfile() {
+	case termenv.Ascii:
+		limits = append(limits, "no colors")
+	case termenv.ANSI:
+		limits = append(limits, "16 colors")
+	}
+	if m.ascii {
+		limits = append(limits, "ASCII symbols")
+	}
+	if len(limits) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("Limited terminal detected (TERM=%s): using %s", os.Getenv("TERM"), strings.Join(limits, ", "))
+}
+
+// glyphs returns s as the terminal should draw it (ASCII symbols in ASCII mode)
+func (m Model) glyphs(s string) string {
+	if !m.ascii {
+		return s
+	}
+	return asciiGlyphs.Replace(s)
+}
+
+// SetASCII forces plain ASCII symbols instead of Unicode and emoji
+func (m *Model) SetASCII(ascii bool) {
+	m.ascii = ascii
+}
+
+// tooSmallView is shown instead of the layout when the terminal is too small for it
+func (m Model) tooSmallView() string {
+	warningStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("203"))
+
+	message := fmt.Sprintf("Terminal too small (%dx%d)\nTavernShell needs at least %dx%d\n\nEnlarge the window, or Ctrl+C to quit",
+		m.width, m.height, minTerminalWidth, minTerminalHeight)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center,
+		warningStyle.Render(message), lipgloss.WithWhitespaceChars(" "))
+}
@@ -0,0 +1,235 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/angusmclean/tavernshell/core/dice"
+	"github.com/angusmclean/tavernshell/core/tracker/timer"
+	"github.com/angusmclean/tavernshell/core/tracker/turns"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// handleTurns processes dungeon turn commands
+func (m *Model) handleTurns(args []string) tea.Cmd {
+	if len(args) == 0 {
+		m.addHistory("Usage: turns <command> - Commands: start [every N] [table], next/n [count], light <tracker> [turns], douse <tracker>, status, stop")
+		return nil
+	}
+
+	subCmd := strings.ToLower(args[0])
+
+	switch {
+	case strings.HasPrefix("start", subCmd):
+		checkEvery, table := turns.DefaultCheckEvery, ""
+		rest := args[1:]
+		if len(rest) >= 2 && strings.EqualFold(rest[0], "every") {
+			n, err := strconv.Atoi(rest[1])
+			if err != nil || n < 0 {
+				m.addHistory("Error: 'every' takes a number of turns (0 for no wandering-monster checks)")
+				return nil
+			}
+			checkEvery, rest = n, rest[2:]
+		}
+		if len(rest) > 0 {
+			name := strings.Join(rest, " ")
+			t := m.tableManager.Get(name)
+			if t == nil {
+				m.addHistory(fmt.Sprintf("Error: table '%s' not loaded (use 'table load <file>')", name))
+				return nil
+			}
+			table = t.Name
+		}
+		var lights []string
+		if m.delve != nil {
+			lights = m.delve.Lights // torches already lit stay lit
+		}
+		m.delve = turns.NewDelve(checkEvery, table)
+		for _, light := range lights {
+			m.delve.AddLight(light)
+		}
+		m.addHistory(fmt.Sprintf("🕯 Dungeon turns started: %s ('turns next' as each 10 minutes pass)", m.delveChecks()))
+
+	case strings.HasPrefix("next", subCmd):
+		if m.delve == nil {
+			m.addHistory("Error: not counting dungeon turns (use 'turns start')")
+			return nil
+		}
+		count := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n < 1 {
+				m.addHistory("Error: count must be a positive number")
+				return nil
+			}
+			count = n
+		}
+		var cmds []tea.Cmd
+		for i := 0; i < count; i++ {
+			cmds = append(cmds, m.nextTurn())
+		}
+		return tea.Batch(cmds...)
+
+	case strings.HasPrefix("light", subCmd) && len(subCmd) >= 2:
+		if m.delve == nil {
+			m.addHistory("Error: not counting dungeon turns (use 'turns start')")
+			return nil
+		}
+		if len(args) < 2 {
+			m.addHistory("Usage: turns light <tracker> [turns] (e.g., 'turns light Torch', 'turns light \"Aria Lantern\" 24')")
+			return nil
+		}
+		m.lightSource(args[1], args[2:])
+
+	case strings.HasPrefix("douse", subCmd):
+		if m.delve == nil || len(args) < 2 {
+			m.addHistory("Usage: turns douse <tracker>")
+			return nil
+		}
+		if !m.delve.RemoveLight(args[1]) {
+			m.addHistory(fmt.Sprintf("Error: '%s' isn't burning", args[1]))
+			return nil
+		}
+		m.addHistory(fmt.Sprintf("🕯 Doused %s; its tracker keeps the turns left", args[1]))
+
+	case strings.HasPrefix("status", subCmd):
+		if m.delve == nil {
+			m.addHistory("Not counting dungeon turns")
+			return nil
+		}
+		m.addHistory("🕯 " + m.delveStatus())
+
+	case strings.HasPrefix("stop", subCmd) || subCmd == "end":
+		if m.delve == nil {
+			m.addHistory("Not counting dungeon turns")
+			return nil
+		}
+		m.addHistory(fmt.Sprintf("🕯 Dungeon turns stopped after %s", m.delveStatus()))
+		m.delve = nil
+
+	default:
+		m.addHistory(fmt.Sprintf("Unknown turns command: %s", subCmd))
+	}
+	return nil
+}
+
+// nextTurn moves the delve on a turn: light sources burn down, a turn's worth
+// of rounds comes off round-based alarms and buffs, and a wandering-monster
+// check is rolled when one is due; it returns the commands finished alarms ask for
+func (m *Model) nextTurn() tea.Cmd {
+	checkDue := m.delve.Advance()
+	m.addHistory(fmt.Sprintf("🕯 Turn %d (%s in)", m.delve.Turn, timer.FormatDurationShort(m.delve.Elapsed())))
+
+	for _, name := range append([]string(nil), m.delve.Lights...) {
+		light := m.numberTrackerManager.Get(name)
+		if light == nil {
+			m.delve.RemoveLight(name) // deleted since it was lit
+			continue
+		}
+		light.Adjust(-1)
+		switch {
+		case light.Current <= 0:
+			light.Set(0)
+			m.delve.RemoveLight(name)
+			m.addHistory(fmt.Sprintf("  🔥 %s burns out", light.Name))
+		case light.Current == 1:
+			m.addHistory(fmt.Sprintf("  🔥 %s is guttering: 1 turn left", light.Name))
+		}
+	}
+
+	finished := m.finishTimers(m.timerManager.AdvanceRounds(turns.RoundsPerTurn))
+	m.category = categoryBuff
+	m.announceExpiredModifiers(m.modifierManager.AdvanceRounds(turns.RoundsPerTurn))
+	m.category = categoryTable
+	if checkDue {
+		m.wanderingMonsterCheck()
+	}
+	return finished
+}
+
+// wanderingMonsterCheck rolls on the delve's wandering-monster table, or 1d6
+// with a monster on a 1 when it has none
+func (m *Model) wanderingMonsterCheck() {
+	if m.delve.Table != "" {
+		result, err := m.tableManager.Roll(m.delve.Table, 0)
+		if err != nil {
+			m.addHistory(fmt.Sprintf("Error: wandering monsters: %s", err))
+			return
+		}
+		m.addHistory(fmt.Sprintf("  👣 Wandering monsters: %s", result))
+		return
+	}
+	result, err := dice.RollExpression(&dice.Expression{Count: 1, Sides: 6})
+	if err != nil {
+		m.addHistory(fmt.Sprintf("Error: %s", err))
+		return
+	}
+	if result.Total == 1 {
+		m.addHistory("  👣 Wandering monster! (rolled 1 on 1d6)")
+	} else {
+		m.addHistory(fmt.Sprintf("  👣 No wandering monsters (rolled %d on 1d6)", result.Total))
+	}
+}
+
+// lightSource lights a tracker that burns down a turn at a time, creating it
+// (or refilling it) when given a number of turns; torches and lanterns
+// know how long they last
+func (m *Model) lightSource(name string, rest []string) {
+	duration := turns.LightDuration(name)
+	if len(rest) > 0 {
+		n, err := strconv.Atoi(rest[0])
+		if err != nil || n < 1 {
+			m.addHistory("Error: turns must be a positive number")
+			return
+		}
+		duration = n
+	}
+
+	light := m.numberTrackerManager.Get(name)
+	switch {
+	case light != nil && light.IsDerived():
+		m.addHistory(fmt.Sprintf("Error: %s", checkWritable(light)))
+		return
+	case light == nil && duration == 0:
+		m.addHistory(fmt.Sprintf("Error: how many turns does '%s' last? (e.g., 'turns light %s 6')", name, name))
+		return
+	case light == nil:
+		light = m.numberTrackerManager.Add(name, duration, duration)
+	case len(rest) > 0 || light.Current <= 0:
+		if duration == 0 {
+			duration = light.Max
+		}
+		light.Max = max(light.Max, duration)
+		light.Set(duration)
+	}
+	m.delve.AddLight(light.Name)
+	m.addHistory(fmt.Sprintf("🔥 Lit %s: [%s] %d/%d turns", light.Name, light.Name, light.Current, light.Max))
+}
+
+// delveChecks describes the delve's wandering-monster checks
+func (m *Model) delveChecks() string {
+	switch {
+	case m.delve.CheckEvery == 0:
+		return "no wandering-monster checks"
+	case m.delve.Table != "":
+		return fmt.Sprintf("wandering monsters on '%s' every %d turn(s)", m.delve.Table, m.delve.CheckEvery)
+	default:
+		return fmt.Sprintf("wandering monsters on a 1 in 6 every %d turn(s)", m.delve.CheckEvery)
+	}
+}
+
+// delveStatus describes the delve in a line: turns passed, checks and lights
+func (m *Model) delveStatus() string {
+	status := fmt.Sprintf("%d turn(s) (%s), %s", m.delve.Turn, timer.FormatDurationShort(m.delve.Elapsed()), m.delveChecks())
+	var lights []string
+	for _, name := range m.delve.Lights {
+		if light := m.numberTrackerManager.Get(name); light != nil {
+			lights = append(lights, fmt.Sprintf("%s %d turn(s) left", light.Name, light.Current))
+		}
+	}
+	if len(lights) > 0 {
+		status += "; " + strings.Join(lights, ", ")
+	}
+	return status
+}
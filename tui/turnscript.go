@@ -0,0 +1,134 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/angusmclean/tavernshell/core/macro"
+)
+
+// scriptVariables are the variables a turn script can use
+var scriptVariables = []string{"self", "round"}
+
+// handleScript shows or sets the commands run when a participant's turn starts
+// Usage: i script [<name> ["<cmd>; <cmd>" | clear]]
+func (m *Model) handleScript(args []string) {
+	tracker := m.initiativeManager.GetTracker()
+	if !m.initiativeManager.IsActive() || tracker == nil {
+		m.addHistory("No active initiative.")
+		return
+	}
+
+	switch len(args) {
+	case 0:
+		found := false
+		for _, p := range tracker.Participants {
+			if p.Script != "" {
+				if !found {
+					m.addHistory("Turn scripts:")
+					found = true
+				}
+				m.addHistory(fmt.Sprintf("  %s: %s", p.Name, p.Script))
+			}
+		}
+		if !found {
+			m.addHistory("No turn scripts (use 'i script <name> \"<cmd>; <cmd>\"')")
+		}
+		return
+
+	case 1:
+		for _, p := range tracker.Participants {
+			if strings.EqualFold(p.Name, args[0]) {
+				if p.Script == "" {
+					m.addHistory(fmt.Sprintf("%s has no turn script", p.Name))
+				} else {
+					m.addHistory(fmt.Sprintf("%s's turn script: %s", p.Name, p.Script))
+				}
+				return
+			}
+		}
+		m.addHistory(fmt.Sprintf("Error: participant '%s' not found", args[0]))
+		return
+
+	case 2:
+		name, script := args[0], args[1]
+		if strings.EqualFold(script, "clear") {
+			script = ""
+		}
+		if err := checkScript(script); err != nil {
+			m.addHistory(fmt.Sprintf("Error: %s", err))
+			return
+		}
+		if err := m.initiativeManager.SetScript(name, script); err != nil {
+			m.addHistory(fmt.Sprintf("Error: %s", err))
+			return
+		}
+		if script == "" {
+			m.addHistory(fmt.Sprintf("Cleared %s's turn script", name))
+		} else {
+			m.addHistory(fmt.Sprintf("%s's turn will start with: %s", name, script))
+		}
+
+	default:
+		m.addHistory("Usage: i script <name> \"<cmd>; <cmd>\" - Run commands when their turn starts (quote the commands; $self and $round work)")
+	}
+}
+
+// checkScript rejects a turn script with broken {if} tags or variables it
+// won't have a value for
+func checkScript(script string) error {
+	for _, command := range splitCommands(script) {
+		if err := macro.Check(command); err != nil {
+			return err
+		}
+		for _, name := range macro.Variables(command) {
+			if !slices.Contains(scriptVariables, name) {
+				return fmt.Errorf("turn scripts can't use $%s (only $self and $round)", name)
+			}
+		}
+	}
+	return nil
+}
+
+// runTurnScripts runs the turn scripts of whoever's turn just started (the
+// whole side in side mode), and of their summons, through the macro engine
+func (m *Model) runTurnScripts() {
+	tracker := m.initiativeManager.GetTracker()
+	if tracker == nil {
+		return
+	}
+	current := tracker.GetCurrent()
+	if current == nil {
+		return
+	}
+
+	var names []string
+	if side := tracker.CurrentSide(); side != "" {
+		for _, p := range tracker.Participants {
+			if p.IsActive && p.Side == side && p.Summoner == "" {
+				names = append(names, p.Name)
+			}
+		}
+	} else {
+		names = []string{current.Name}
+	}
+	for _, name := range slices.Clone(names) {
+		names = append(names, tracker.SummonsOf(name)...)
+	}
+
+	for _, p := range tracker.Participants {
+		if !p.IsActive || p.Script == "" || !slices.Contains(names, p.Name) {
+			continue
+		}
+		script := &macro.Macro{Name: p.Name, Commands: splitCommands(p.Script)}
+		commands, err := script.Expand(map[string]string{"self": p.Name, "round": strconv.Itoa(tracker.Round)})
+		if err != nil {
+			m.addHistory(fmt.Sprintf("Error: %s's turn script: %s", p.Name, err))
+			continue
+		}
+		m.playMacro(fmt.Sprintf("%s's turn script", p.Name), commands)
+		m.category = categoryInitiative
+	}
+}
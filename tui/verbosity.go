@@ -0,0 +1,96 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/angusmclean/tavernshell/core/dice"
+)
+
+// verbosity is how much of each roll the history shows
+type verbosity int
+
+const (
+	verbosityNormal  verbosity = iota // the dice, modifier and total
+	verbosityTerse                    // just the total
+	verbosityVerbose                  // every die itemized on lines of its own
+)
+
+// verbosityNames maps each verbosity level to its name
+var verbosityNames = map[verbosity]string{
+	verbosityNormal:  "normal",
+	verbosityTerse:   "terse",
+	verbosityVerbose: "verbose",
+}
+
+// String returns the verbosity level's name
+func (v verbosity) String() string {
+	return verbosityNames[v]
+}
+
+// parseVerbosity looks up a verbosity level by name
+func parseVerbosity(name string) (verbosity, bool) {
+	for v, n := range verbosityNames {
+		if strings.EqualFold(name, n) {
+			return v, true
+		}
+	}
+	return verbosityNormal, false
+}
+
+// splitVerbosity takes a "--terse", "--normal" or "--verbose" flag out of a
+// command's words, returning the level it asks for
+func splitVerbosity(parts []string) ([]string, verbosity, bool) {
+	for i, part := range parts {
+		if !strings.HasPrefix(part, "--") {
+			continue
+		}
+		if v, ok := parseVerbosity(part[2:]); ok {
+			rest := append(append([]string(nil), parts[:i]...), parts[i+1:]...)
+			return rest, v, true
+		}
+	}
+	return parts, verbosityNormal, false
+}
+
+// isVerbosity returns true if cmd is the 'verbosity' command
+func isVerbosity(cmd string) bool {
+	cmd = strings.ToLower(cmd)
+	return strings.HasPrefix("verbosity", cmd) && len(cmd) >= 4
+}
+
+// handleVerbosity shows or sets how much of each roll the history shows
+func (m *Model) handleVerbosity(args []string) {
+	if len(args) == 0 {
+		m.addHistory(fmt.Sprintf("Roll verbosity: %s (verbosity terse|normal|verbose; or add --terse or --verbose to one command)", m.verbosity))
+		return
+	}
+	v, ok := parseVerbosity(args[0])
+	if !ok {
+		m.addHistory(fmt.Sprintf("Error: unknown verbosity '%s' (expected terse, normal or verbose)", args[0]))
+		return
+	}
+	m.verbosity = v
+	m.addHistory(fmt.Sprintf("Roll verbosity: %s", v))
+}
+
+// SetVerbosity sets how much of each roll the history shows: terse, normal
+// or verbose
+func (m *Model) SetVerbosity(name string) error {
+	v, ok := parseVerbosity(name)
+	if !ok {
+		return fmt.Errorf("unknown verbosity '%s' (expected terse, normal or verbose)", name)
+	}
+	m.verbosity = v
+	return nil
+}
+
+// addRollDetail itemizes a roll under the line showing it, when verbose
+func (m *Model) addRollDetail(r *dice.Result) {
+	if m.verbosity != verbosityVerbose || r == nil {
+		return
+	}
+	for _, line := range r.Breakdown() {
+		m.addHistory("    " + line)
+	}
+}
@@ -0,0 +1,34 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/angusmclean/tavernshell/core/dice"
+)
+
+func TestVerbosityFlag(t *testing.T) {
+	defer dice.SetRoller(dice.CurrentRoller())
+
+	tests := []struct {
+		commands []string
+		total    int // 0 if nothing is rolled
+		after    verbosity
+	}{
+		{[]string{"d20+5 --terse"}, 15, verbosityNormal},
+		{[]string{"--verbose d20+5"}, 15, verbosityNormal},
+		{[]string{"r d20+5 --terse"}, 15, verbosityNormal},
+		{[]string{"verbosity terse", "d20+5 --verbose"}, 15, verbosityTerse},
+		{[]string{"verbosity terse --verbose"}, 0, verbosityTerse},
+		{[]string{"verb --verbose terse"}, 0, verbosityTerse},
+	}
+	for _, tt := range tests {
+		dice.SetRoller(dice.NewSequenceRoller(10))
+		m := runCommands(tt.commands...)
+		if tt.total != 0 && (m.lastRoll == nil || m.lastRoll.Total != tt.total) {
+			t.Errorf("%v: expected total %d, got %v (%q)", tt.commands, tt.total, m.lastRoll, lastLine(m))
+		}
+		if m.verbosity != tt.after {
+			t.Errorf("%v: expected verbosity %s after, got %s", tt.commands, tt.after, m.verbosity)
+		}
+	}
+}
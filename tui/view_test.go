@@ -0,0 +1,79 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/angusmclean/tavernshell/core/config"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// benchModel builds a busy mid-combat model at the given terminal size
+func benchModel(width, height int) Model {
+	var model tea.Model = NewModel()
+	model, _ = model.Update(tea.WindowSizeMsg{Width: width, Height: height})
+	m := model.(Model)
+
+	commands := []string{
+		"a 10m concentration", "a 3r Bless", "a 1h torch",
+		"t add HP 35 45", "t add Ogre 59 59", "t add Slots 3 4",
+		"t pin HP", "t pin Ogre", "t pin Slots",
+		"i s", "Aria 17 30", "Goblin 12 7", "Ogre 8 59", "Wizard 15 22", "done",
+		"i summon Wizard Familiar", "sync",
+	}
+	for i := 0; i < config.DefaultHistoryLines; i++ {
+		commands = append(commands, fmt.Sprintf("r %dd6+%d", i%4+1, i))
+	}
+	for _, command := range commands {
+		m.handleBatch(command)
+	}
+	return m
+}
+
+func BenchmarkView(b *testing.B) {
+	// Render styles as a color terminal would, not as plain text
+	lipgloss.SetColorProfile(termenv.TrueColor)
+	defer lipgloss.SetColorProfile(termenv.Ascii)
+
+	for _, size := range []struct{ width, height int }{{80, 24}, {300, 100}} {
+		m := benchModel(size.width, size.height)
+		b.Run(fmt.Sprintf("%dx%d", size.width, size.height), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				m.View()
+			}
+		})
+	}
+}
+
+func TestViewFitsTerminal(t *testing.T) {
+	for _, size := range []struct{ width, height int }{{80, 24}, {300, 100}} {
+		m := benchModel(size.width, size.height)
+		// Render twice so the second frame comes from the cache
+		m.View()
+		lines := strings.Split(m.View(), "\n")
+		if len(lines) > size.height {
+			t.Errorf("Expected at most %d lines at %dx%d, got %d", size.height, size.width, size.height, len(lines))
+		}
+	}
+}
+
+func TestDashboardFitsTerminal(t *testing.T) {
+	for _, size := range []struct{ width, height int }{{80, 24}, {300, 100}} {
+		m := benchModel(size.width, size.height)
+		for i := 0; i < 30; i++ {
+			m.handleBatch(fmt.Sprintf("t add \"NPC%d HP\" 10 10", i))
+		}
+		m.handleBatch("dashboard")
+		lines := strings.Split(m.View(), "\n")
+		if len(lines) != size.height {
+			t.Errorf("Expected the dashboard to fill %d lines at %dx%d, got %d", size.height, size.width, size.height, len(lines))
+		}
+		if !strings.Contains(lines[2], "Trackers") {
+			t.Errorf("Expected the dashboard under the title, got %q", lines[2])
+		}
+	}
+}
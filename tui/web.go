@@ -0,0 +1,93 @@
+package tui
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/angusmclean/tavernshell/core/tracker/rotation"
+	"github.com/angusmclean/tavernshell/web"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// webRolls is how many recent rolls the web view shows
+const webRolls = 20
+
+// webScan is how many recent history lines are searched for public rolls
+const webScan = 200
+
+// ServeWebView serves a read-only view of initiative, trackers and public
+// rolls at addr (e.g. ":8080") for players to open in a browser, returning
+// the address it's listening on
+func (m *Model) ServeWebView(addr string) (string, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return "", fmt.Errorf("failed to serve web view: %w", err)
+	}
+	m.webView = web.NewServer()
+	m.publishWebView()
+	go http.Serve(listener, m.webView)
+	m.addHistory(fmt.Sprintf("🌐 Web view for players at http://%s", listener.Addr()))
+	return listener.Addr().String(), nil
+}
+
+// publishWebView updates the web view, if one is being served
+func (m *Model) publishWebView() {
+	if m.webView == nil {
+		return
+	}
+	if err := m.webView.Publish(m.webState()); err != nil {
+		m.category = categorySystem
+		m.addHistory(fmt.Sprintf("Warning: web view not updated: %s", err))
+	}
+}
+
+// webState is what players can see: the initiative panel and trackers with
+// obscured values showing only their condition, and rolls that weren't whispered
+func (m Model) webState() web.State {
+	state := web.State{Initiative: []web.Participant{}, Trackers: []web.Tracker{}, Rolls: []web.Roll{}}
+
+	public := m
+	public.publicOnly = true
+	if tracker := m.initiativeManager.GetTracker(); m.initiativeManager.IsActive() && tracker != nil {
+		state.Round = tracker.Round
+		if tracker.Mode != rotation.ModeStandard {
+			state.Mode = tracker.Mode.String()
+		}
+		currentSide := tracker.CurrentSide()
+		for i, p := range tracker.Participants {
+			current := i == tracker.CurrentTurn
+			if tracker.Mode == rotation.ModeSide {
+				current = p.IsActive && p.Side == currentSide
+			}
+			text := strings.TrimSpace(ansi.Strip(public.participantText(p)))
+			if p.IsActive && p.Used != 0 {
+				text += " " + actionEconomy(p)
+			}
+			state.Initiative = append(state.Initiative, web.Participant{
+				Text:    text,
+				Side:    p.Side,
+				Current: current && p.IsActive,
+				Acted:   p.Acted,
+				Out:     !p.IsActive,
+			})
+		}
+	}
+
+	for _, t := range m.numberTrackerManager.List() {
+		value := t.Value()
+		if t.Obscured {
+			value = t.Condition()
+		}
+		state.Trackers = append(state.Trackers, web.Tracker{Name: t.Name, Value: value})
+	}
+
+	for _, e := range m.history.Recent(webScan) {
+		if e.Category == categoryRoll && !e.Private {
+			state.Rolls = append(state.Rolls, web.Roll{Time: e.Time, Text: ansi.Strip(e.Text)})
+		}
+	}
+	state.Rolls = state.Rolls[max(len(state.Rolls)-webRolls, 0):]
+	return state
+}
@@ -0,0 +1,133 @@
+// Package web serves a read-only view of the table for players: initiative,
+// trackers and the public roll feed, on a page a phone browser can open
+package web
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+//go:embed index.html
+var indexPage []byte
+
+// State is what players can see of the table
+type State struct {
+	Round      int           `json:"round"` // 0 outside initiative
+	Mode       string        `json:"mode,omitempty"`
+	Initiative []Participant `json:"initiative"`
+	Trackers   []Tracker     `json:"trackers"`
+	Rolls      []Roll        `json:"rolls"` // oldest first
+}
+
+// Participant is one line of the initiative order
+type Participant struct {
+	Text    string `json:"text"` // as in the initiative panel: "Aria (18) 24/30 AC 15"
+	Side    string `json:"side,omitempty"`
+	Current bool   `json:"current,omitempty"`
+	Acted   bool   `json:"acted,omitempty"`
+	Out     bool   `json:"out,omitempty"` // dropped or dead
+}
+
+// Tracker is a number tracker, with only its condition if it's obscured
+type Tracker struct {
+	Name  string `json:"name"`
+	Value string `json:"value"` // "24/30", or "bloodied" when obscured
+}
+
+// Roll is a line of the public roll feed
+type Roll struct {
+	Time time.Time `json:"time"`
+	Text string    `json:"text"`
+}
+
+// Server serves the page, the current state as JSON at /state, and a stream
+// of states as server-sent events at /events
+type Server struct {
+	mu      sync.Mutex
+	state   []byte        // current state as JSON
+	changed chan struct{} // closed when the state changes
+}
+
+// NewServer creates a server with an empty state
+func NewServer() *Server {
+	s := &Server{changed: make(chan struct{})}
+	s.Publish(State{})
+	return s
+}
+
+// Publish makes state the current state, waking any open event streams if
+// it's changed
+func (s *Server) Publish(state State) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if bytes.Equal(data, s.state) {
+		return nil
+	}
+	s.state = data
+	close(s.changed)
+	s.changed = make(chan struct{})
+	return nil
+}
+
+// current returns the current state and a channel closed when it changes
+func (s *Server) current() ([]byte, chan struct{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state, s.changed
+}
+
+// ServeHTTP serves the page, /state and /events; anything but GET is refused
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "read-only", http.StatusMethodNotAllowed)
+		return
+	}
+	switch r.URL.Path {
+	case "/":
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write(indexPage)
+	case "/state":
+		state, _ := s.current()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(state)
+	case "/events":
+		s.serveEvents(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// serveEvents streams the state as server-sent events: the current state
+// straight away, then each new one, until the browser goes away
+func (s *Server) serveEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+
+	for {
+		state, changed := s.current()
+		if _, err := fmt.Fprintf(w, "data: %s\n\n", state); err != nil {
+			return
+		}
+		flusher.Flush()
+		select {
+		case <-changed:
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package web
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestServerState(t *testing.T) {
+	s := NewServer()
+	s.Publish(State{Round: 2, Trackers: []Tracker{{Name: "Goblin", Value: "Bloodied"}}})
+
+	tests := []struct {
+		method string
+		path   string
+		status int
+		body   string
+	}{
+		{"GET", "/state", http.StatusOK, `{"round":2,"initiative":null,"trackers":[{"name":"Goblin","value":"Bloodied"}],"rolls":null}`},
+		{"GET", "/", http.StatusOK, "<!DOCTYPE html>"},
+		{"POST", "/state", http.StatusMethodNotAllowed, ""},
+		{"GET", "/secrets", http.StatusNotFound, ""},
+	}
+
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		s.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+		if rec.Code != tt.status {
+			t.Errorf("%s %s: Expected status %d, got %d", tt.method, tt.path, tt.status, rec.Code)
+		}
+		if tt.body != "" && !strings.HasPrefix(rec.Body.String(), tt.body) {
+			t.Errorf("%s %s: Expected body starting %q, got %q", tt.method, tt.path, tt.body, rec.Body.String())
+		}
+	}
+}
+
+func TestServerEvents(t *testing.T) {
+	s := NewServer()
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, "GET", srv.URL+"/events", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET /events failed: %v", err)
+	}
+	defer resp.Body.Close()
+	events := bufio.NewReader(resp.Body)
+
+	// The current state straight away, then each change; publishing the same
+	// state again sends nothing
+	expectEvent(t, events, `data: {"round":0,`)
+	s.Publish(State{Round: 1})
+	expectEvent(t, events, `data: {"round":1,`)
+	s.Publish(State{Round: 1})
+	s.Publish(State{Round: 2})
+	expectEvent(t, events, `data: {"round":2,`)
+}
+
+// expectEvent reads the next event and checks how it starts
+func expectEvent(t *testing.T, events *bufio.Reader, prefix string) {
+	t.Helper()
+	line, err := events.ReadString('\n')
+	if err != nil && err != io.EOF {
+		t.Fatalf("Reading event failed: %v", err)
+	}
+	if !strings.HasPrefix(line, prefix) {
+		t.Errorf("Expected event starting %q, got %q", prefix, line)
+	}
+	events.ReadString('\n') // the blank line ending the event
+}